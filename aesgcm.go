@@ -0,0 +1,102 @@
+package reform
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownKeyVersion is returned by AESGCM.Decrypt for ciphertext encrypted with unknown key version.
+var ErrUnknownKeyVersion = errors.New("reform: unknown key version")
+
+// AESGCM is a Cipher which uses AES-GCM authenticated encryption with versioned keys.
+// Ciphertext contains key version (one byte), random nonce and sealed plaintext, so keys can be rotated:
+// new values are encrypted with the current key, while values encrypted with older keys are still decrypted.
+// As AEADCipher, it binds ciphertext to table, column and primary key, see there.
+// To re-encrypt old values, load and update records with querier using new AESGCM.
+//
+// Keys may come from configuration or be data keys decrypted with KMS at startup:
+//
+//	c, err := reform.NewAESGCM(map[uint8][]byte{1: oldKey, 2: newKey}, 2)
+//	q := db.WithCipher(c)
+type AESGCM struct {
+	aeads   map[uint8]cipher.AEAD
+	current uint8
+}
+
+// NewAESGCM creates new AESGCM cipher with given keys by version and current version used for encryption.
+// Keys should be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewAESGCM(keys map[uint8][]byte, current uint8) (*AESGCM, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("reform: no key for current version %d", current)
+	}
+
+	aeads := make(map[uint8]cipher.AEAD, len(keys))
+	for version, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("reform: key version %d: %w", version, err)
+		}
+		if aeads[version], err = cipher.NewGCM(block); err != nil {
+			return nil, fmt.Errorf("reform: key version %d: %w", version, err)
+		}
+	}
+
+	return &AESGCM{
+		aeads:   aeads,
+		current: current,
+	}, nil
+}
+
+// Encrypt implements Cipher with the current key.
+func (c *AESGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	return c.EncryptWithData(plaintext, nil)
+}
+
+// EncryptWithData implements AEADCipher with the current key.
+func (c *AESGCM) EncryptWithData(plaintext, data []byte) ([]byte, error) {
+	aead := c.aeads[c.current]
+	res := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	res[0] = c.current
+	if _, err := rand.Read(res[1:]); err != nil {
+		return nil, err
+	}
+	return aead.Seal(res, res[1:], plaintext, data), nil
+}
+
+// Decrypt implements Cipher with the key of ciphertext's version.
+func (c *AESGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	return c.DecryptWithData(ciphertext, nil)
+}
+
+// DecryptWithData implements AEADCipher with the key of ciphertext's version.
+func (c *AESGCM) DecryptWithData(ciphertext, data []byte) ([]byte, error) {
+	version, err := c.KeyVersion(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	aead := c.aeads[version]
+	if len(ciphertext) < 1+aead.NonceSize() {
+		return nil, errors.New("reform: ciphertext is too short")
+	}
+	nonce := ciphertext[1 : 1+aead.NonceSize()]
+	return aead.Open(nil, nonce, ciphertext[1+aead.NonceSize():], data)
+}
+
+// KeyVersion returns version of the key used to encrypt given ciphertext.
+// It may be used to find values which should be re-encrypted after key rotation.
+func (c *AESGCM) KeyVersion(ciphertext []byte) (uint8, error) {
+	if len(ciphertext) == 0 {
+		return 0, errors.New("reform: ciphertext is too short")
+	}
+	version := ciphertext[0]
+	if _, ok := c.aeads[version]; !ok {
+		return 0, ErrUnknownKeyVersion
+	}
+	return version, nil
+}
+
+// check interface
+var _ AEADCipher = (*AESGCM)(nil)
@@ -0,0 +1,236 @@
+package reform
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Array wraps a slice for PostgreSQL array column: it is converted to array literal like {1,2,3} when stored
+// and parsed when scanned. Slices of strings, integers, floats and booleans are supported ([]string, []int64, etc.),
+// multidimensional arrays and NULL elements are not. Generated Values and Pointers methods use it for struct fields
+// with "array" label in "reform:" tag:
+//
+//	type Article struct {
+//		ID     int32    `reform:"id,pk"`
+//		Tags   []string `reform:"tags,array"`
+//		Scores []int64  `reform:"scores,array"`
+//	}
+//
+// Nil slice is stored as NULL, and NULL is scanned as nil slice.
+type Array struct {
+	V interface{} // slice for Value, pointer to slice for Scan
+}
+
+// Value implements database/sql/driver.Valuer interface.
+func (a Array) Value() (driver.Value, error) {
+	v := reflect.ValueOf(a.V)
+	if a.V == nil || (v.Kind() == reflect.Slice && v.IsNil()) {
+		return nil, nil
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("reform: Array.Value: expected slice, got %T", a.V)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		e := v.Index(i)
+		switch e.Kind() {
+		case reflect.String:
+			buf.WriteByte('"')
+			for _, r := range e.String() {
+				if r == '"' || r == '\\' {
+					buf.WriteByte('\\')
+				}
+				buf.WriteRune(r)
+			}
+			buf.WriteByte('"')
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			buf.WriteString(strconv.FormatInt(e.Int(), 10))
+		case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			buf.WriteString(strconv.FormatUint(e.Uint(), 10))
+		case reflect.Float32, reflect.Float64:
+			buf.WriteString(strconv.FormatFloat(e.Float(), 'g', -1, e.Type().Bits()))
+		case reflect.Bool:
+			if e.Bool() {
+				buf.WriteByte('t')
+			} else {
+				buf.WriteByte('f')
+			}
+		default:
+			return nil, fmt.Errorf("reform: Array.Value: unsupported element type %s", e.Type())
+		}
+	}
+	buf.WriteByte('}')
+	return buf.String(), nil
+}
+
+// Scan implements database/sql.Scanner interface.
+func (a *Array) Scan(src interface{}) error {
+	v := reflect.ValueOf(a.V)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("reform: Array.Scan: expected non-nil pointer to slice, got %T", a.V)
+	}
+	s := v.Elem()
+
+	var text string
+	switch src := src.(type) {
+	case nil:
+		s.Set(reflect.Zero(s.Type()))
+		return nil
+	case []byte:
+		text = string(src)
+	case string:
+		text = src
+	default:
+		return fmt.Errorf("reform: Array.Scan: unexpected source type %T", src)
+	}
+
+	elems, err := parseArray(text)
+	if err != nil {
+		return err
+	}
+
+	res := reflect.MakeSlice(s.Type(), len(elems), len(elems))
+	for i, elem := range elems {
+		if elem == nil {
+			return fmt.Errorf("reform: Array.Scan: NULL element in %q is not supported", text)
+		}
+		e := res.Index(i)
+		switch e.Kind() {
+		case reflect.String:
+			e.SetString(*elem)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var n int64
+			if n, err = strconv.ParseInt(*elem, 10, e.Type().Bits()); err == nil {
+				e.SetInt(n)
+			}
+		case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			var n uint64
+			if n, err = strconv.ParseUint(*elem, 10, e.Type().Bits()); err == nil {
+				e.SetUint(n)
+			}
+		case reflect.Float32, reflect.Float64:
+			var f float64
+			if f, err = strconv.ParseFloat(*elem, e.Type().Bits()); err == nil {
+				e.SetFloat(f)
+			}
+		case reflect.Bool:
+			var b bool
+			if b, err = strconv.ParseBool(*elem); err == nil {
+				e.SetBool(b)
+			}
+		default:
+			err = fmt.Errorf("unsupported element type %s", e.Type())
+		}
+		if err != nil {
+			return fmt.Errorf("reform: Array.Scan: %s", err)
+		}
+	}
+	s.Set(res)
+	return nil
+}
+
+// String returns array literal representation of wrapped slice for logging.
+func (a Array) String() string {
+	v, err := a.Value()
+	if err != nil {
+		return fmt.Sprintf("<%s>", err)
+	}
+	if v == nil {
+		return "<nil>"
+	}
+	return v.(string)
+}
+
+// parseArray parses one-dimensional PostgreSQL array literal like {a,"b c",NULL}.
+// NULL elements are returned as nil.
+func parseArray(text string) ([]*string, error) {
+	// skip optional dimension decoration like [0:2]=
+	if strings.HasPrefix(text, "[") {
+		if i := strings.Index(text, "="); i > 0 {
+			text = text[i+1:]
+		}
+	}
+	if len(text) < 2 || text[0] != '{' || text[len(text)-1] != '}' {
+		return nil, fmt.Errorf("reform: Array.Scan: unexpected array literal %q", text)
+	}
+	body := text[1 : len(text)-1]
+	if body == "" {
+		return []*string{}, nil
+	}
+
+	var res []*string
+	for i := 0; i <= len(body); i++ {
+		var elem strings.Builder
+		quoted := i < len(body) && body[i] == '"'
+		if quoted {
+			for i++; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' {
+					i++
+				}
+				if i < len(body) {
+					elem.WriteByte(body[i])
+				}
+			}
+			if i >= len(body) {
+				return nil, fmt.Errorf("reform: Array.Scan: unterminated quoted element in %q", text)
+			}
+			i++
+		} else {
+			for ; i < len(body) && body[i] != ','; i++ {
+				if body[i] == '{' || body[i] == '"' {
+					return nil, fmt.Errorf("reform: Array.Scan: unexpected array literal %q", text)
+				}
+				elem.WriteByte(body[i])
+			}
+		}
+		if i < len(body) && body[i] != ',' {
+			return nil, fmt.Errorf("reform: Array.Scan: unexpected array literal %q", text)
+		}
+
+		s := elem.String()
+		if !quoted {
+			s = strings.TrimSpace(s)
+			if strings.EqualFold(s, "NULL") {
+				res = append(res, nil)
+				continue
+			}
+		}
+		res = append(res, &s)
+	}
+	return res, nil
+}
+
+// Any returns condition "column = ANY(arg)" for PostgreSQL: it matches rows where column value is equal
+// to any element of given slice, for example, Any("id", []int64{1, 2, 3}). Unlike IN, it uses a single placeholder.
+func Any(column string, values interface{}) Condition {
+	return Condition{column: column, op: "= ANY", arg: Array{V: values}, paren: true}
+}
+
+// ArrayContains returns condition "column @> arg" for PostgreSQL array column: it matches rows where column value
+// contains all elements of given slice.
+func ArrayContains(column string, values interface{}) Condition {
+	return Condition{column: column, op: "@>", arg: Array{V: values}}
+}
+
+// ArrayOverlaps returns condition "column && arg" for PostgreSQL array column: it matches rows where column value
+// has any elements in common with given slice.
+func ArrayOverlaps(column string, values interface{}) Condition {
+	return Condition{column: column, op: "&&", arg: Array{V: values}}
+}
+
+// check interfaces
+var (
+	_ driver.Valuer = Array{}
+	_ sql.Scanner   = new(Array)
+	_ fmt.Stringer  = Array{}
+)
@@ -0,0 +1,48 @@
+package reform_test
+
+import (
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestArray() {
+	if s.q.Dialect != postgresql.Dialect {
+		s.T().Skip("PostgreSQL-specific test")
+	}
+
+	article := &Article{
+		Tags:   []string{"go", "sql", `quoted "tag", with comma`, `back\slash`, "NULL", ""},
+		Scores: []int64{1, -2, 3},
+	}
+	s.Require().NoError(s.q.Insert(article))
+	empty := &Article{Tags: []string{}}
+	s.Require().NoError(s.q.Insert(empty))
+
+	record, err := s.q.FindByPrimaryKeyFrom(ArticleTable, article.ID)
+	s.Require().NoError(err)
+	s.Equal(article, record)
+
+	record, err = s.q.FindByPrimaryKeyFrom(ArticleTable, empty.ID)
+	s.Require().NoError(err)
+	s.Equal([]string{}, record.(*Article).Tags)
+	s.Nil(record.(*Article).Scores)
+
+	tail, args, err := reform.Where(reform.Any("id", []int32{article.ID, empty.ID, 0})).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	n, err := s.q.Count(ArticleTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(2), n)
+
+	tail, args, err = reform.Where(reform.ArrayContains("tags", []string{"sql", "go"})).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	n, err = s.q.Count(ArticleTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(1), n)
+
+	tail, args, err = reform.Where(reform.ArrayOverlaps("scores", []int64{3, 4})).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	n, err = s.q.Count(ArticleTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(1), n)
+}
@@ -0,0 +1,168 @@
+package reform
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Audit actions stored in action column of audit table.
+const (
+	AuditInsert = "insert"
+	AuditUpdate = "update"
+	AuditUpsert = "upsert"
+	AuditDelete = "delete"
+)
+
+// DefaultAuditTable is a default name of audit table, see Auditor.
+const DefaultAuditTable = "audit_log"
+
+// Auditor configures audit log, see Querier.WithAudit.
+//
+// Audit table should have the following columns (id column and types are up to you):
+//
+//	CREATE TABLE audit_log (
+//	  id serial PRIMARY KEY,
+//	  table_name varchar NOT NULL,
+//	  action varchar NOT NULL,
+//	  record_pk varchar NOT NULL,
+//	  actor varchar NOT NULL,
+//	  created_at timestamp NOT NULL,
+//	  old_values jsonb,
+//	  new_values jsonb
+//	);
+type Auditor struct {
+	// Table is a name of audit table; DefaultAuditTable is used if it is empty.
+	Table string
+
+	// Actor returns who performs changes (user name, service name, etc.) from querier's context.
+	// Empty string is stored if it is nil.
+	Actor func(ctx context.Context) string
+}
+
+// WithAudit returns a copy of querier which records every Insert, Update (and its variants), Upsert and Delete
+// of a single record into audit table: who (see Auditor.Actor), when, which table, record's primary key,
+// and old and new column values as JSON objects (old values are loaded by primary key before change).
+// Values of encrypted and sensitive (see WithSensitiveColumns) columns are replaced with Redacted.
+//
+// Audit row is inserted with the same querier, so it is a part of the same transaction when used with TX:
+// change and its audit record are committed or rolled back together. Use it with TX for that guarantee.
+// Bulk commands (InsertMulti, UpdateColumnsAll, DeleteAll, etc.) and DeleteFrom are not audited.
+func (q *Querier) WithAudit(a *Auditor) *Querier {
+	nq := q.clone()
+	nq.auditor = a
+	return nq
+}
+
+// auditOld returns column values of record stored in SQL database before change if audit is enabled, or nil.
+// Missing row is not an error: nil is returned in that case too.
+func (q *Querier) auditOld(record Record) (map[string]interface{}, error) {
+	if q.auditor == nil {
+		return nil, nil
+	}
+
+	nq := q.Unscoped()
+	nq.auditor = nil
+	table := record.Table()
+	old := table.NewRecord()
+	err := nq.findByPK(old, table, pkArg(record))
+	switch err {
+	case nil:
+		return q.auditValues(old), nil
+	case ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// audit inserts audit row for str with given action and old values if audit is enabled.
+// New values are taken from str unless deleted is true.
+func (q *Querier) audit(action string, str Struct, old map[string]interface{}, deleted bool) error {
+	if q.auditor == nil {
+		return nil
+	}
+
+	var newValues map[string]interface{}
+	if !deleted {
+		newValues = q.auditValues(str)
+	}
+	var pk string
+	if record, ok := str.(Record); ok {
+		values := pkValues(record)
+		p := make([]string, len(values))
+		for i, v := range values {
+			p[i] = fmt.Sprint(auditValue(v))
+		}
+		pk = strings.Join(p, ",")
+	}
+	var actor string
+	if q.auditor.Actor != nil {
+		actor = q.auditor.Actor(q.ctx)
+	}
+
+	table := q.auditor.Table
+	if table == "" {
+		table = DefaultAuditTable
+	}
+	columns := []string{"table_name", "action", "record_pk", "actor", "created_at", "old_values", "new_values"}
+	for i, c := range columns {
+		columns[i] = q.QuoteIdentifier(c)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		q.quoteTable(table),
+		strings.Join(columns, ", "),
+		strings.Join(q.Placeholders(1, len(columns)), ", "),
+	)
+	args := []interface{}{
+		str.View().Name(), action, pk, actor, time.Now().UTC(),
+		JSON{V: old}, JSON{V: newValues},
+	}
+	_, err := q.execView(table, query, args...)
+	return err
+}
+
+// auditValues returns column values of str suitable for JSON representation,
+// with values of encrypted and sensitive columns replaced with Redacted.
+func (q *Querier) auditValues(str Struct) map[string]interface{} {
+	view := str.View()
+	var encrypted []bool
+	if ev, ok := view.(EncryptedView); ok {
+		encrypted = ev.EncryptedColumns()
+	}
+	sensitive := q.sensitiveColumns(view)
+
+	columns := view.Columns()
+	res := make(map[string]interface{}, len(columns))
+	for i, v := range str.Values() {
+		if (encrypted != nil && encrypted[i]) || (sensitive != nil && sensitive[i]) {
+			res[columns[i]] = Redacted
+			continue
+		}
+		res[columns[i]] = auditValue(v)
+	}
+	return res
+}
+
+// auditValue unwraps JSON and Array wrappers and calls driver.Valuer for other values.
+func auditValue(v interface{}) interface{} {
+	switch w := v.(type) {
+	case JSON:
+		return w.V
+	case Array:
+		return w.V
+	case driver.Valuer:
+		if isNilValue(w) {
+			return nil
+		}
+		dv, err := w.Value()
+		if err != nil {
+			return fmt.Sprintf("<%s>", err)
+		}
+		return dv
+	default:
+		return v
+	}
+}
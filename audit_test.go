@@ -0,0 +1,67 @@
+package reform_test
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+type actorKey struct{}
+
+func (s *ReformSuite) TestAudit() {
+	ctx := context.WithValue(context.Background(), actorKey{}, "alice")
+	q := s.q.WithContext(ctx).WithAudit(&reform.Auditor{
+		Actor: func(ctx context.Context) string { return ctx.Value(actorKey{}).(string) },
+	}).WithSensitiveColumns(PersonTable, "email")
+
+	email := "audited@example.com"
+	person := &Person{Name: "Audited", Email: &email}
+	s.Require().NoError(q.Insert(person))
+	person.Name = "Audited Again"
+	s.Require().NoError(q.UpdateColumns(person, "name"))
+	s.Require().NoError(q.Delete(person))
+
+	// not audited
+	s.Require().NoError(s.q.Insert(&Person{Name: "Not Audited"}))
+
+	rows, err := s.q.Query("SELECT table_name, action, record_pk, actor, old_values, new_values FROM audit_log ORDER BY id")
+	s.Require().NoError(err)
+	defer rows.Close()
+
+	type entry struct {
+		table, action, pk, actor string
+		old, new                 map[string]interface{}
+	}
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		s.Require().NoError(rows.Scan(&e.table, &e.action, &e.pk, &e.actor, &reform.JSON{V: &e.old}, &reform.JSON{V: &e.new}))
+		entries = append(entries, e)
+	}
+	s.Require().NoError(rows.Err())
+	s.Require().Len(entries, 3)
+
+	pk := strconv.Itoa(int(person.ID))
+	for _, e := range entries {
+		s.Equal("people", e.table)
+		s.Equal(pk, e.pk)
+		s.Equal("alice", e.actor)
+	}
+
+	s.Equal(reform.AuditInsert, entries[0].action)
+	s.Nil(entries[0].old)
+	s.Equal("Audited", entries[0].new["name"])
+	s.Equal(reform.Redacted, entries[0].new["email"])
+
+	s.Equal(reform.AuditUpdate, entries[1].action)
+	s.Equal("Audited", entries[1].old["name"])
+	s.Equal("Audited Again", entries[1].new["name"])
+	s.Equal(reform.Redacted, entries[1].old["email"])
+	s.Equal(reform.Redacted, entries[1].new["email"])
+
+	s.Equal(reform.AuditDelete, entries[2].action)
+	s.Equal("Audited Again", entries[2].old["name"])
+	s.Nil(entries[2].new)
+}
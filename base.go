@@ -1,16 +1,50 @@
 package reform
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"net"
 )
 
 var (
 	// ErrNoRows is returned from various methods when query produced no rows.
-	ErrNoRows = sql.ErrNoRows
+	// It wraps sql.ErrNoRows, so errors.Is(err, sql.ErrNoRows) also works, but err == sql.ErrNoRows doesn't.
+	ErrNoRows = fmt.Errorf("reform: %w", sql.ErrNoRows)
 
 	// ErrNoPK is returned from various methods when primary key is required and not set.
 	ErrNoPK = errors.New("reform: no primary key")
+
+	// ErrMultipleRows is returned from Querier's commands by primary key when more than one row was affected,
+	// if enabled with Querier.WithMultipleRowsError. Otherwise, commands panic in that case.
+	ErrMultipleRows = errors.New("reform: multiple rows affected by primary key")
+
+	// ErrStaleRecord is returned from Querier's update commands for record of LockingTable
+	// when row exists, but its version doesn't match record's version: it was changed concurrently.
+	ErrStaleRecord = errors.New("reform: stale record")
+
+	// ErrInvalidCursor is returned from Querier.SelectPage for Cursor with invalid After token.
+	ErrInvalidCursor = errors.New("reform: invalid cursor")
+
+	// ErrUpsertNotSupported is returned from Querier.Upsert if Dialect doesn't support it (see NoUpsert).
+	ErrUpsertNotSupported = errors.New("reform: upsert is not supported by dialect")
+
+	// ErrRowLockNotSupported is returned from Querier's selectors and finders when querier's row-level locking mode
+	// (see Querier.WithRowLock) is not supported by Dialect (see RowLockingDialect).
+	ErrRowLockNotSupported = errors.New("reform: row-level locking mode is not supported by dialect")
+
+	// ErrNothingToUpdate is returned from Querier's update commands when there are no columns to update.
+	ErrNothingToUpdate = errors.New("reform: nothing to update")
+
+	// ErrUniqueViolation is a kind of ConstraintError for unique constraint (or primary key) violation.
+	// Use errors.Is(err, ErrUniqueViolation) to check for it.
+	ErrUniqueViolation = errors.New("reform: unique constraint violation")
+
+	// ErrForeignKeyViolation is a kind of ConstraintError for foreign key constraint violation.
+	// Use errors.Is(err, ErrForeignKeyViolation) to check for it.
+	ErrForeignKeyViolation = errors.New("reform: foreign key constraint violation")
 )
 
 // View represents SQL database view or table.
@@ -26,7 +60,7 @@ type View interface {
 }
 
 // Table represents SQL database table with single-column primary key.
-// It extends View.
+// It extends View. Tables with composite primary key also implement CompositePKTable.
 type Table interface {
 	View
 
@@ -55,6 +89,7 @@ type Struct interface {
 }
 
 // Record represents a row in SQL database table with single-column primary key.
+// Records with composite primary key also implement CompositePKRecord.
 type Record interface {
 	Struct
 
@@ -72,10 +107,82 @@ type Record interface {
 	// HasPK returns true if record has non-zero primary key set, false otherwise.
 	HasPK() bool
 
-	// SetPK sets record primary key.
+	// SetPK sets record primary key. Querier passes int64 value returned by sql.Result.LastInsertId
+	// for integer primary keys (converting it to key's type, including unsigned ones, is up to SetPK),
+	// and string generated by IDGenerator for GeneratedPKTable.
 	SetPK(pk interface{})
 }
 
+// CompositePKTable is an optional interface for Table with composite (multi-column) primary key.
+// For such tables PKColumnIndex returns an index of the first primary key column.
+type CompositePKTable interface {
+	Table
+
+	// PKColumnIndexes returns indexes of primary key columns for that table in SQL database.
+	PKColumnIndexes() []uint
+}
+
+// LockingTable is an optional interface for Table with optimistic locking version column
+// (field with "lock" label in "reform:" tag).
+// Querier's update commands check that version in SQL database matches record's version
+// and increment it, returning ErrStaleRecord on mismatch.
+type LockingTable interface {
+	Table
+
+	// LockColumnIndex returns an index of optimistic locking version column for that table in SQL database.
+	LockColumnIndex() uint
+}
+
+// SoftDeleteTable is an optional interface for Table with soft delete timestamp column
+// (field with "softdelete" label in "reform:" tag).
+// Querier's Delete and DeleteFrom set that column instead of deleting rows,
+// and selectors and finders skip rows with that column set. Use Querier.Unscoped to disable that.
+type SoftDeleteTable interface {
+	Table
+
+	// SoftDeleteColumnIndex returns an index of soft delete timestamp column for that table in SQL database.
+	SoftDeleteColumnIndex() uint
+}
+
+// GeneratedPKTable is an optional interface for Table with client-generated string primary key
+// (field with "pk" and "generated" labels in "reform:" tag). Querier's Insert and other inserting methods
+// set primary key of records without it using IDGenerator (see Querier.WithIDGenerator)
+// before executing query, instead of receiving it from SQL database.
+type GeneratedPKTable interface {
+	Table
+
+	// GeneratedPK is a marker method: it does nothing.
+	GeneratedPK()
+}
+
+// AutoTimestampsView is an optional interface for View with timestamp columns set automatically
+// (fields with "autocreate" or "autoupdate" label in "reform:" tag).
+// Querier's Insert and other inserting methods set autocreate fields with zero values and all autoupdate fields
+// to the current time in UTC, update methods set autoupdate fields. That happens before BeforeInserter
+// and BeforeUpdater hooks, so they may change those fields.
+type AutoTimestampsView interface {
+	View
+
+	// AutoTimestamps returns automatically set timestamp columns for that view or table in SQL database.
+	AutoTimestamps() []AutoTimestamp
+}
+
+// CompositePKRecord is an optional interface for Record with composite (multi-column) primary key.
+// For such records PKValue, PKPointer and SetPK work with the first primary key field,
+// HasPK returns true if all primary key fields are non-zero.
+// Querier never sets primary key of such records: all primary key columns are inserted as is.
+type CompositePKRecord interface {
+	Record
+
+	// PKValues returns values of primary key for that record in order of Table's PKColumnIndexes.
+	// Returned interface{} values are never untyped nils.
+	PKValues() []interface{}
+
+	// PKPointers returns pointers to primary key fields for that record in order of Table's PKColumnIndexes.
+	// Returned interface{} values are never untyped nils.
+	PKPointers() []interface{}
+}
+
 // BeforeInserter is an optional interface for Record which is used by Querier.Insert.
 // It can be used to set record's timestamp fields, convert timezones, change data precision, etc.
 // Returning error aborts operation.
@@ -97,6 +204,117 @@ type AfterFinder interface {
 	AfterFind() error
 }
 
+// AfterInserter is an optional interface for Record which is used by Querier.Insert.
+// It is called after successful insert with primary key already set.
+// It can be used to populate computed fields, invalidate caches, write audit trail, etc.
+// Returned error is returned by Querier, but doesn't undo insert: use transaction for that.
+type AfterInserter interface {
+	AfterInsert() error
+}
+
+// AfterUpdater is an optional interface for Record which is used by Querier.Update and Querier.UpdateColumns.
+// It is called after successful update.
+// It can be used to populate computed fields, invalidate caches, write audit trail, etc.
+// Returned error is returned by Querier, but doesn't undo update: use transaction for that.
+type AfterUpdater interface {
+	AfterUpdate() error
+}
+
+// AfterDeleter is an optional interface for Record which is used by Querier.Delete.
+// It is called after successful delete.
+// It can be used to invalidate caches, write audit trail, etc.
+// Returned error is returned by Querier, but doesn't undo delete: use transaction for that.
+type AfterDeleter interface {
+	AfterDelete() error
+}
+
+// BeforeInserterContext is an optional interface for Record which is used by Querier.Insert.
+// It is like BeforeInserter, but receives Querier's context (see Querier.WithContext).
+// It is used instead of BeforeInserter if implemented.
+// Returning error aborts operation.
+type BeforeInserterContext interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// BeforeUpdaterContext is an optional interface for Record which is used by Querier.Update and Querier.UpdateColumns.
+// It is like BeforeUpdater, but receives Querier's context (see Querier.WithContext).
+// It is used instead of BeforeUpdater if implemented.
+// Returning error aborts operation.
+type BeforeUpdaterContext interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterFinderContext is an optional interface for Record which is used by Querier's finders and selectors.
+// It is like AfterFinder, but receives Querier's context (see Querier.WithContext).
+// It is used instead of AfterFinder if implemented.
+// Returning error aborts operation.
+type AfterFinderContext interface {
+	AfterFind(ctx context.Context) error
+}
+
+// AfterInserterContext is an optional interface for Record which is used by Querier.Insert.
+// It is like AfterInserter, but receives Querier's context (see Querier.WithContext).
+// It is used instead of AfterInserter if implemented.
+type AfterInserterContext interface {
+	AfterInsert(ctx context.Context) error
+}
+
+// AfterUpdaterContext is an optional interface for Record which is used by Querier.Update and Querier.UpdateColumns.
+// It is like AfterUpdater, but receives Querier's context (see Querier.WithContext).
+// It is used instead of AfterUpdater if implemented.
+type AfterUpdaterContext interface {
+	AfterUpdate(ctx context.Context) error
+}
+
+// AfterDeleterContext is an optional interface for Record which is used by Querier.Delete.
+// It is like AfterDeleter, but receives Querier's context (see Querier.WithContext).
+// It is used instead of AfterDeleter if implemented.
+type AfterDeleterContext interface {
+	AfterDelete(ctx context.Context) error
+}
+
+// BeforeInserterTx is an optional interface for Record which is used by Querier.Insert.
+// It is like BeforeInserterContext, but receives Querier which performs operation (with its context and transaction),
+// so it can run additional queries in the same transaction, for example, to check or lock related rows.
+// It is used instead of BeforeInserterContext and BeforeInserter if implemented.
+// Returning error aborts operation.
+type BeforeInserterTx interface {
+	BeforeInsertTx(q *Querier) error
+}
+
+// BeforeUpdaterTx is an optional interface for Record which is used by Querier.Update and Querier.UpdateColumns.
+// It is like BeforeUpdaterContext, but receives Querier which performs operation (with its context and transaction).
+// It is used instead of BeforeUpdaterContext and BeforeUpdater if implemented.
+// Returning error aborts operation.
+type BeforeUpdaterTx interface {
+	BeforeUpdateTx(q *Querier) error
+}
+
+// AfterInserterTx is an optional interface for Record which is used by Querier.Insert.
+// It is like AfterInserterContext, but receives Querier which performs operation (with its context and transaction),
+// so it can run additional queries in the same transaction, for example, to update denormalized counters.
+// It is used instead of AfterInserterContext and AfterInserter if implemented.
+type AfterInserterTx interface {
+	AfterInsertTx(q *Querier) error
+}
+
+// AfterUpdaterTx is an optional interface for Record which is used by Querier.Update and Querier.UpdateColumns.
+// It is like AfterUpdaterContext, but receives Querier which performs operation (with its context and transaction).
+// It is used instead of AfterUpdaterContext and AfterUpdater if implemented.
+type AfterUpdaterTx interface {
+	AfterUpdateTx(q *Querier) error
+}
+
+// AfterDeleterTx is an optional interface for Record which is used by Querier.Delete.
+// It is like AfterDeleterContext, but receives Querier which performs operation (with its context and transaction).
+// It is used instead of AfterDeleterContext and AfterDeleter if implemented.
+//
+// There is no such variant of AfterFinder: finders call it while result rows are still being read,
+// and many drivers can't run other queries on the same connection at that time.
+type AfterDeleterTx interface {
+	AfterDeleteTx(q *Querier) error
+}
+
 // DBTX is an interface for database connection or transaction.
 // It's implemented by *sql.DB, *sql.Tx, *DB, *TX and *Querier.
 type DBTX interface {
@@ -113,6 +331,22 @@ type DBTX interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
+// DBTXContext is an interface for database connection or transaction with context support.
+// It's implemented by *sql.DB and *sql.Tx.
+type DBTXContext interface {
+	// ExecContext executes a query without returning any rows.
+	// The args are for any placeholder parameters in the query.
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+
+	// QueryContext executes a query that returns rows, typically a SELECT.
+	// The args are for any placeholder parameters in the query.
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+
+	// QueryRowContext executes a query that is expected to return at most one row.
+	// QueryRowContext always returns a non-nil value. Errors are deferred until Row's Scan method is called.
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // LastInsertIdMethod is a method of receiving primary key of last inserted row.
 type LastInsertIdMethod int
 
@@ -122,6 +356,33 @@ const (
 
 	// Returning is method using "RETURNING id" SQL syntax.
 	Returning
+
+	// NoLastInsertId is used by databases without auto-increment columns:
+	// primary key is never received, it should be set by application.
+	NoLastInsertId
+
+	// OutputInserted is method using "OUTPUT INSERTED.id" SQL syntax.
+	OutputInserted
+
+	// ReturningInto is method using "RETURNING id INTO :n" SQL syntax with sql.Out parameter.
+	ReturningInto
+
+	// ThenReturn is method using "THEN RETURN id" SQL syntax (Google Cloud Spanner).
+	ThenReturn
+)
+
+// UpsertMethod is a method of inserting or updating row atomically.
+type UpsertMethod int
+
+const (
+	// OnConflict is method using "INSERT ... ON CONFLICT (columns) DO UPDATE" SQL syntax.
+	OnConflict UpsertMethod = iota
+
+	// OnDuplicateKey is method using "INSERT ... ON DUPLICATE KEY UPDATE" SQL syntax.
+	OnDuplicateKey
+
+	// NoUpsert is used by databases without upsert support: Querier.Upsert returns ErrUpsertNotSupported.
+	NoUpsert
 )
 
 // Dialect represents differences in various SQL dialects.
@@ -140,10 +401,133 @@ type Dialect interface {
 
 	// LastInsertIdMethod returns a method of receiving primary key of last inserted row.
 	LastInsertIdMethod() LastInsertIdMethod
+
+	// UpsertMethod returns a method of inserting or updating row atomically.
+	UpsertMethod() UpsertMethod
+
+	// BoolValue returns representation of boolean value suitable for passing to database driver,
+	// typically true/false or 1/0.
+	BoolValue(b bool) interface{}
+
+	// IsConnectionError returns true if err is a connection-level error which happened
+	// before query was sent to the server (typically driver.ErrBadConn or dial error),
+	// so it is safe to execute query again. It must return false for all other errors,
+	// including constraint violations and errors with unknown query execution status.
+	IsConnectionError(err error) bool
+
+	// MaxPlaceholders returns a maximum number of placeholder parameters in a single query.
+	MaxPlaceholders() int
+}
+
+// RowLockingDialect is an optional interface for Dialect which renders row-level locking clauses
+// differently from standard ones (see RowLock.Clause), or doesn't support them.
+// It is used by Querier.WithRowLock.
+type RowLockingDialect interface {
+	Dialect
+
+	// RowLockClause returns locking clause for given mode, or empty string if that mode is not supported:
+	// Querier returns ErrRowLockNotSupported in that case.
+	RowLockClause(lock RowLock) string
+}
+
+// LimitDialect is an optional interface for Dialect which doesn't support standard "LIMIT n" clause
+// (like SQL Server). It is used by Tail.Limit, Querier.Exists, finders of a single row and Querier.SelectPage.
+type LimitDialect interface {
+	Dialect
+
+	// LimitClause returns clause placed at the end of SELECT query which limits a number of returned rows.
+	// Ordered is true if query already has ORDER BY clause.
+	LimitClause(limit int, ordered bool) string
+}
+
+// ConstraintDialect is an optional interface for Dialect which detects constraint violation errors
+// returned by database driver. It is used by Querier to wrap them into *ConstraintError.
+type ConstraintDialect interface {
+	Dialect
+
+	// ConstraintViolation returns ErrUniqueViolation or ErrForeignKeyViolation and constraint name (if known)
+	// for constraint violation error, or nil kind for other errors.
+	ConstraintViolation(err error) (kind error, constraint string)
+}
+
+// RetryableDialect is an optional interface for Dialect which can detect transaction errors
+// which are expected and should be handled by retrying the whole transaction (like serialization failures).
+// It is used by DB.InTransactionRetry and DB.InTransactionContext.
+type RetryableDialect interface {
+	Dialect
+
+	// IsRetryable returns true if transaction failed with err should be retried.
+	IsRetryable(err error) bool
+}
+
+// SavepointDialect is an optional interface for Dialect which doesn't support standard
+// "SAVEPOINT", "ROLLBACK TO SAVEPOINT" and "RELEASE SAVEPOINT" statements (like SQL Server or Oracle).
+// It is used by TX's savepoint methods.
+type SavepointDialect interface {
+	Dialect
+
+	// SavepointQuery returns query which creates savepoint with given quoted name.
+	SavepointQuery(name string) string
+
+	// RollbackToSavepointQuery returns query which rolls back to savepoint with given quoted name.
+	RollbackToSavepointQuery(name string) string
+
+	// ReleaseSavepointQuery returns query which releases savepoint with given quoted name,
+	// or empty string if savepoints are released only at the end of transaction.
+	ReleaseSavepointQuery(name string) string
+}
+
+// BusyErrorDialect is an optional interface for Dialect which detects statements failed because database is busy
+// (like SQLITE_BUSY). Such statements were not executed, so they are retried according to
+// Querier.WithTransientRetries policy (5 attempts by default) even if they are not idempotent.
+// Statements are retried outside of transactions; busy transactions should be retried as a whole
+// (see DB.InTransactionContext).
+type BusyErrorDialect interface {
+	Dialect
+
+	// IsBusy returns true if statement failed with err because database is busy.
+	IsBusy(err error) bool
+}
+
+// TransientErrorDialect is an optional interface for Dialect which detects database-specific transient errors,
+// like server shutdown during failover, in addition to connection errors detected by Querier itself.
+// It is used by Querier.WithTransientRetries.
+type TransientErrorDialect interface {
+	Dialect
+
+	// IsTransientError returns true if statement failed with err may succeed if executed again
+	// on a new connection. Statement may or may not have been executed.
+	IsTransientError(err error) bool
+}
+
+// CopyInDialect is an optional interface for Dialect which supports bulk loading
+// with "COPY FROM STDIN" protocol via prepared statement (like github.com/lib/pq driver).
+// It is used by Querier.BulkCopy.
+type CopyInDialect interface {
+	Dialect
+
+	// CanCopyIn returns true if database driver supports CopyIn query.
+	// Driver is nil if it is not known (for example, for transaction created with NewTX).
+	CanCopyIn(d driver.Driver) bool
+
+	// CopyIn returns query for bulk loading of rows into given table columns.
+	CopyIn(table string, columns []string) string
+}
+
+// IsConnectionError returns true if err is driver.ErrBadConn or a dial error.
+// Dialects for network databases use it to implement Dialect.IsConnectionError.
+func IsConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
 }
 
 // check interface
 var (
-	_ DBTX = new(sql.DB)
-	_ DBTX = new(sql.Tx)
+	_ DBTX        = new(sql.DB)
+	_ DBTX        = new(sql.Tx)
+	_ DBTXContext = new(sql.DB)
+	_ DBTXContext = new(sql.Tx)
 )
@@ -1,18 +1,25 @@
 package reform_test
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	_ "github.com/ClickHouse/clickhouse-go/v2"
+	_ "github.com/denisenkom/go-mssqldb"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/stdlib"
 	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
+	_ "github.com/sijms/go-ora/v2"
 	_ "github.com/ziutek/mymysql/godrv"
 
 	"github.com/AlekSi/pointer"
@@ -20,14 +27,19 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/AlekSi/reform"
-	"github.com/AlekSi/reform/dialects/mysql"
+	_ "github.com/AlekSi/reform/dialects/clickhouse"
+	_ "github.com/AlekSi/reform/dialects/mysql"
+	_ "github.com/AlekSi/reform/dialects/oracle"
 	"github.com/AlekSi/reform/dialects/postgresql"
-	"github.com/AlekSi/reform/dialects/sqlite3"
+	_ "github.com/AlekSi/reform/dialects/sqlite3"
+	_ "github.com/AlekSi/reform/dialects/sqlserver"
 	"github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
 )
 
 var (
-	DB *reform.DB
+	DB    *reform.DB
+	sqlDB *sql.DB
 )
 
 func TestMain(m *testing.M) {
@@ -54,11 +66,13 @@ func TestMain(m *testing.M) {
 	log.Printf("time.Now()       = %s", now)
 	log.Printf("time.Now().UTC() = %s", now.UTC())
 
-	var dialect reform.Dialect
+	dialect := reform.DialectForDriver(driver)
+	if dialect == nil {
+		log.Fatal("reform: no dialect for driver " + driver)
+	}
+
 	switch driver {
 	case "mysql", "mymysql":
-		dialect = mysql.Dialect
-
 		var tz string
 		err = db.QueryRow("SHOW VARIABLES LIKE 'time_zone'").Scan(&tz, &tz)
 		if err != nil {
@@ -67,8 +81,6 @@ func TestMain(m *testing.M) {
 		log.Printf("MySQL time_zone = %q", tz)
 
 	case "postgres", "pgx":
-		dialect = postgresql.Dialect
-
 		var tz string
 		err = db.QueryRow("SHOW TimeZone").Scan(&tz)
 		if err != nil {
@@ -77,17 +89,13 @@ func TestMain(m *testing.M) {
 		log.Printf("PostgreSQL TimeZone = %q", tz)
 
 	case "sqlite3":
-		dialect = sqlite3.Dialect
-
 		_, err = db.Exec("PRAGMA foreign_keys = ON")
 		if err != nil {
 			log.Fatal(err)
 		}
-
-	default:
-		log.Fatal("reform: no dialect for driver " + driver)
 	}
 
+	sqlDB = db
 	DB = reform.NewDB(db, dialect, nil)
 
 	os.Exit(m.Run())
@@ -137,6 +145,60 @@ func (s *ReformSuite) TestStringer() {
 	s.Equal(expected, project.String())
 }
 
+func (s *ReformSuite) TestStringerRedacted() {
+	secret := &models.Secret{ID: 1, Name: "password", Data: "hunter2"}
+	expected := "ID: 1 (int32), Name: `password` (string), Data: <redacted>, Note: <nil> (*[]uint8)"
+	s.Equal(expected, secret.String())
+	s.Equal(expected, fmt.Sprintf("%v", secret))
+	s.Equal("Secret{"+expected+"}", fmt.Sprintf("%#v", secret))
+	s.NotContains(fmt.Sprintf("%+v %#v", *secret, *secret), "hunter2")
+}
+
+func (s *ReformSuite) TestEqual() {
+	now := time.Now()
+	secret1 := &models.Secret{ID: 1, Name: "password", Data: "hunter2", Note: &[]byte{'o', 'k'}}
+	secret2 := &models.Secret{ID: 1, Name: "password", Data: "hunter2", Note: &[]byte{'o', 'k'}}
+	s.True(secret1.Equal(secret2))
+	s.True(reform.EqualValues([]interface{}{now, &now}, []interface{}{now.UTC(), pointer.ToTime(now.UTC())}))
+
+	secret2.Note = nil
+	s.False(secret1.Equal(secret2))
+	s.False(secret1.Equal(nil))
+	s.True((*models.Secret)(nil).Equal(nil))
+}
+
+func (s *ReformSuite) TestClone() {
+	person, err := s.q.FindByPrimaryKeyFrom(models.PersonTable, 102)
+	s.NoError(err)
+	original := person.(*models.Person)
+	clone := original.Clone()
+	s.Equal(original, clone)
+	s.NotNil(clone.Email)
+	s.False(original.Email == clone.Email)
+
+	*clone.Email = "changed@example.com"
+	s.NotEqual(*original.Email, *clone.Email)
+
+	secret1 := &models.Secret{ID: 1, Note: &[]byte{'o', 'k'}}
+	secret2 := secret1.Clone()
+	s.True(secret1.Equal(secret2))
+	(*secret2.Note)[0] = 'n'
+	s.Equal([]byte("ok"), *secret1.Note)
+	s.Nil((*models.Secret)(nil).Clone())
+
+	event1 := &models.Event{
+		Payload: map[string]interface{}{"items": []interface{}{"a"}},
+		Meta:    &models.EventMeta{Source: "test", Tags: []string{"a"}},
+	}
+	event2 := event1.Clone()
+	s.Equal(event1, event2)
+	event2.Payload["items"].([]interface{})[0] = "b"
+	event2.Meta.Tags[0] = "b"
+	s.Equal(map[string]interface{}{"items": []interface{}{"a"}}, event1.Payload)
+	s.Equal([]string{"a"}, event1.Meta.Tags)
+	s.Nil((&models.Event{}).Clone().Payload)
+}
+
 func (s *ReformSuite) TestNeverNil() {
 	project := new(models.Project)
 
@@ -220,6 +282,449 @@ func (s *ReformSuite) TestInTransaction() {
 	s.NoError(err)
 }
 
+func (s *ReformSuite) TestInTransactionOpts() {
+	err := s.q.Rollback()
+	s.Require().NoError(err)
+	s.q = nil
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var count uint
+	err = DB.InTransactionOpts(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *reform.TX) error {
+		s.Equal(ctx, tx.Context())
+		count, err = tx.Count(models.PersonTable, "")
+		return err
+	})
+	s.NoError(err)
+	s.NotZero(count)
+
+	if DB.Dialect == postgresql.Dialect {
+		err = DB.InTransactionOpts(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}, func(tx *reform.TX) error {
+			return tx.Insert(&models.Person{Name: "Read Only"})
+		})
+		s.Error(err)
+	}
+
+	cancel()
+	err = DB.InTransactionOpts(ctx, nil, func(tx *reform.TX) error {
+		s.Fail("should not be called")
+		return nil
+	})
+	s.Equal(context.Canceled, err)
+}
+
+func (s *ReformSuite) TestBoolValue() {
+	switch s.q.Dialect {
+	case postgresql.Dialect:
+		s.Equal(true, s.q.BoolValue(true))
+		s.Equal(false, s.q.BoolValue(false))
+	default:
+		s.Equal(int64(1), s.q.BoolValue(true))
+		s.Equal(int64(0), s.q.BoolValue(false))
+	}
+
+	var b, n bool
+	err := s.q.QueryRow("SELECT 1 = 1, 1 = 0").Scan(&b, &n)
+	s.NoError(err)
+	s.True(b)
+	s.False(n)
+}
+
+func (s *ReformSuite) TestIsConnectionError() {
+	s.True(s.q.IsConnectionError(driver.ErrBadConn))
+	s.True(s.q.IsConnectionError(fmt.Errorf("wrapped: %w", driver.ErrBadConn)))
+	s.True(s.q.IsConnectionError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+	s.False(s.q.IsConnectionError(&net.OpError{Op: "read", Err: errors.New("connection reset by peer")}))
+	s.False(s.q.IsConnectionError(errors.New("epic error")))
+
+	_, err := s.q.Exec("invalid query")
+	s.Error(err)
+	s.False(s.q.IsConnectionError(err))
+}
+
+func (s *ReformSuite) TestWithConnectionRetries() {
+	err := s.q.Rollback()
+	s.Require().NoError(err)
+	s.q = nil
+
+	rl := reform.NewRecordingLogger()
+	q := DB.WithConnectionRetries(3)
+	q.Logger = rl
+
+	_, err = q.Exec("invalid query")
+	s.Error(err)
+	s.Len(rl.Statements(), 1) // not a connection error, no retries
+
+	person := &models.Person{Email: pointer.ToString(faker.Internet().Email())}
+	err = q.Insert(person)
+	s.NoError(err)
+	err = q.Delete(person)
+	s.NoError(err)
+}
+
+func (s *ReformSuite) TestWithTransientRetries() {
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+	resetErr := &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}
+	f.StubError(models.PersonTable, resetErr)
+
+	rl := &retryLogger{Logger: reform.NewRecordingLogger()}
+	policy := &reform.RetryPolicy{Attempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	q := f.DB.WithTransientRetries(policy)
+	q.Logger = rl
+
+	_, err := q.FindByPrimaryKeyFrom(models.PersonTable, 1)
+	s.Error(err)
+	s.Len(f.Statements(), 3)
+	s.Equal([]int{1, 2}, rl.attempts)
+
+	// not idempotent
+	f.Reset()
+	f.StubError(models.PersonTable, resetErr)
+	_, err = q.Exec(`DELETE FROM "people" WHERE "id" = $1`, 1)
+	s.Error(err)
+	s.Len(f.Statements(), 1)
+
+	f.Reset()
+	f.StubError(models.PersonTable, resetErr)
+	_, err = q.Idempotent().Exec(`DELETE FROM "people" WHERE "id" = $1`, 1)
+	s.Error(err)
+	s.Len(f.Statements(), 3)
+
+	// connection retries share attempts counter
+	f.Reset()
+	f.StubError(models.PersonTable, &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+	_, err = q.WithConnectionRetries(2).Idempotent().Exec(`DELETE FROM "people" WHERE "id" = $1`, 1)
+	s.Error(err)
+	s.Len(f.Statements(), 3)
+
+	// not a transient error
+	f.Reset()
+	f.StubError(models.PersonTable, errors.New("syntax error"))
+	_, err = q.SelectAllFrom(models.PersonTable, "")
+	s.Error(err)
+	s.Len(f.Statements(), 1)
+
+	// transaction
+	f.Reset()
+	tx, err := f.DB.Begin()
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	f.StubError(models.PersonTable, resetErr)
+	_, err = tx.WithTransientRetries(policy).SelectAllFrom(models.PersonTable, "")
+	s.Error(err)
+	s.Len(f.Statements(), 2) // BEGIN and SELECT
+}
+
+func (s *ReformSuite) TestRecordingLogger() {
+	rl := reform.NewRecordingLogger()
+	s.q.Logger = rl
+
+	_, err := s.q.FindByPrimaryKeyFrom(models.PersonTable, 1)
+	s.NoError(err)
+	_, err = s.q.Exec("invalid query")
+	s.Error(err)
+
+	statements := rl.Statements()
+	s.Require().Len(statements, 2)
+	s.Contains(statements[0].Query, "SELECT")
+	s.Equal([]interface{}{1}, statements[0].Args)
+	s.NoError(statements[0].Err)
+	s.Equal("invalid query", statements[1].Query)
+	s.Nil(statements[1].Args)
+	s.Error(statements[1].Err)
+
+	rl.Reset()
+	s.Empty(rl.Statements())
+}
+
+func (s *ReformSuite) TestTagged() {
+	rl := reform.NewRecordingLogger()
+	s.q.Logger = rl
+
+	q := s.q.Tagged("PersonRepo.Find")
+	s.Equal("PersonRepo.Find", q.Tag())
+	_, err := q.FindByPrimaryKeyFrom(models.PersonTable, 1)
+	s.NoError(err)
+	_, err = s.q.FindByPrimaryKeyFrom(models.PersonTable, 1)
+	s.NoError(err)
+	_, err = q.WithTagComments().FindByPrimaryKeyFrom(models.PersonTable, 1)
+	s.NoError(err)
+
+	statements := rl.Statements()
+	s.Require().Len(statements, 3)
+	s.Equal("PersonRepo.Find", statements[0].Tag)
+	s.Equal("", statements[1].Tag)
+	s.Equal(statements[0].Query, statements[1].Query)
+	s.Equal("PersonRepo.Find", statements[2].Tag)
+	s.Equal("/* PersonRepo.Find */ "+statements[0].Query, statements[2].Query)
+
+	rl.Reset()
+	q = s.q.WithTag("checkout-service:create-order */")
+	s.Equal("checkout-service:create-order */", q.Tag())
+	s.NoError(q.Insert(&models.Person{Name: "Tagged"}))
+	_, err = q.DeleteFrom(models.PersonTable, "WHERE name = "+q.Placeholder(1), "Tagged")
+	s.NoError(err)
+
+	statements = rl.Statements()
+	s.Require().NotEmpty(statements)
+	for _, st := range statements {
+		s.Equal("checkout-service:create-order */", st.Tag)
+		s.True(strings.HasPrefix(st.Query, "/* checkout-service:create-order * / */ "), "%s", st.Query)
+	}
+}
+
+// eventsLogger is a StructuredLogger which records events.
+type eventsLogger struct {
+	reform.Logger
+	events []*reform.QueryEvent
+}
+
+func (el *eventsLogger) LogQuery(ctx context.Context, event *reform.QueryEvent) {
+	el.events = append(el.events, event)
+}
+
+func (s *ReformSuite) TestStructuredLogger() {
+	el := &eventsLogger{Logger: reform.NewRecordingLogger()}
+	s.q.Logger = el
+
+	_, err := s.q.Tagged("PersonRepo.Find").FindByPrimaryKeyFrom(models.PersonTable, 1)
+	s.NoError(err)
+	ra, err := s.q.DeleteFrom(models.PersonTable, "WHERE name = "+s.q.Placeholder(1), "Elfrieda Abbott")
+	s.NoError(err)
+	s.Equal(uint(2), ra)
+
+	s.Require().Len(el.events, 2)
+	s.Equal("PersonRepo.Find", el.events[0].Tag)
+	s.Equal("people", el.events[0].View)
+	s.Equal("SELECT", el.events[0].Operation)
+	s.Contains(el.events[0].Query, "SELECT")
+	s.Equal([]interface{}{1}, el.events[0].Args)
+	s.Equal(int64(-1), el.events[0].RowsAffected)
+	s.Contains(el.events[0].Caller, "base_test.go:")
+	s.False(el.events[0].Start.IsZero())
+	s.Equal("", el.events[1].Tag)
+	s.Equal("people", el.events[1].View)
+	s.Equal("DELETE", el.events[1].Operation)
+	s.Equal(int64(2), el.events[1].RowsAffected)
+	s.NoError(el.events[1].Err)
+
+	s.Empty(el.Logger.(*reform.RecordingLogger).Statements()) // After is not called
+
+	q := s.q.WithLogger(nil)
+	s.Nil(q.Logger)
+	s.Equal(el, s.q.Logger)
+
+	rl := reform.NewRecordingLogger()
+	reform.LogEvent(context.Background(), rl, el.events[0])
+	reform.LogEvent(context.Background(), nil, el.events[0])
+	statements := rl.Statements()
+	s.Require().Len(statements, 1)
+	s.Equal("PersonRepo.Find", statements[0].Tag)
+	s.Equal(el.events[0].Query, statements[0].Query)
+}
+
+func (s *ReformSuite) TestInSavepoint() {
+	person1 := &models.Person{Name: "Savepoint 1"}
+	person2 := &models.Person{Name: "Savepoint 2"}
+
+	err := s.q.InSavepoint(func(tx *reform.TX) error {
+		s.NoError(tx.Insert(person1))
+
+		err := tx.InSavepoint(func(tx *reform.TX) error {
+			s.NoError(tx.Insert(person2))
+			return errors.New("epic error")
+		})
+		s.EqualError(err, "epic error")
+		return nil
+	})
+	s.NoError(err)
+
+	s.NoError(s.q.Reload(person1))
+	s.Equal(reform.ErrNoRows, s.q.Reload(person2))
+
+	s.Panics(func() {
+		s.q.InSavepoint(func(tx *reform.TX) error {
+			s.NoError(tx.Delete(person1))
+			panic("epic panic!")
+		})
+	})
+	s.NoError(s.q.Reload(person1))
+
+	s.NoError(s.q.Savepoint("manual"))
+	s.NoError(s.q.Delete(person1))
+	s.NoError(s.q.RollbackToSavepoint("manual"))
+	s.NoError(s.q.ReleaseSavepoint("manual"))
+	s.NoError(s.q.Reload(person1))
+}
+
+func (s *ReformSuite) TestTXCallbacks() {
+	err := s.q.Rollback()
+	s.Require().NoError(err)
+	s.q = nil
+
+	var events []string
+	event := func(e string) func() {
+		return func() { events = append(events, e) }
+	}
+
+	tx, err := DB.Begin()
+	s.Require().NoError(err)
+	tx.OnCommit(event("commit 1"))
+	tx.OnRollback(event("rollback 1"))
+	err = tx.InSavepoint(func(tx *reform.TX) error {
+		tx.OnCommit(event("commit 2"))
+		tx.OnRollback(event("rollback 2"))
+		return errors.New("epic error")
+	})
+	s.EqualError(err, "epic error")
+	s.Equal([]string{"rollback 2"}, events)
+	s.NoError(tx.InSavepoint(func(tx *reform.TX) error {
+		tx.OnCommit(event("commit 3"))
+		return nil
+	}))
+	s.Require().NoError(tx.Commit())
+	s.Equal([]string{"rollback 2", "commit 1", "commit 3"}, events)
+
+	events = nil
+	tx, err = DB.Begin()
+	s.Require().NoError(err)
+	tx.OnCommit(event("commit"))
+	tx.OnRollback(event("rollback"))
+	s.Require().NoError(tx.Rollback())
+	s.Equal([]string{"rollback"}, events)
+	s.Equal(sql.ErrTxDone, tx.Rollback())
+	s.Equal([]string{"rollback"}, events)
+
+	events = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	tx, err = DB.BeginContext(ctx)
+	s.Require().NoError(err)
+	tx.OnCommit(event("commit"))
+	tx.OnRollback(event("rollback"))
+	cancel()
+	s.Error(tx.Commit())
+	s.Equal([]string{"rollback"}, events)
+}
+
+func (s *ReformSuite) TestInTransactionRetry() {
+	err := s.q.Rollback()
+	s.Require().NoError(err)
+	s.q = nil
+
+	person := &models.Person{ID: 42, Email: pointer.ToString(faker.Internet().Email())}
+
+	var calls int
+	err = DB.InTransactionRetry(3, func(tx *reform.TX) error {
+		calls++
+		err := tx.Insert(person)
+		s.NoError(err)
+		return errors.New("epic error")
+	})
+	s.EqualError(err, "epic error")
+	s.Equal(1, calls) // not retryable
+
+	calls = 0
+	err = DB.InTransactionRetry(3, func(tx *reform.TX) error {
+		calls++
+		return tx.Insert(person)
+	})
+	s.NoError(err)
+	s.Equal(1, calls)
+
+	err = DB.Delete(person)
+	s.NoError(err)
+
+	// retryable error
+	retryErr := errors.New("retry me")
+	db := reform.NewDB(sqlDB, retryableDialect{DB.Dialect, retryErr}, DB.Logger)
+	calls = 0
+	start := time.Now()
+	err = db.InTransactionRetry(3, func(tx *reform.TX) error {
+		calls++
+		if calls < 3 {
+			return retryErr
+		}
+		return nil
+	})
+	s.NoError(err)
+	s.Equal(3, calls)
+	s.True(time.Since(start) >= 15*time.Millisecond) // backoff after the first and second attempts
+
+	calls = 0
+	err = db.InTransactionRetry(2, func(tx *reform.TX) error {
+		calls++
+		return retryErr
+	})
+	s.Equal(retryErr, err)
+	s.Equal(2, calls) // attempts exhausted
+}
+
+// retryableDialect wraps Dialect and considers given error retryable.
+type retryableDialect struct {
+	reform.Dialect
+	err error
+}
+
+func (d retryableDialect) IsRetryable(err error) bool {
+	return err == d.err
+}
+
+// retryLogger is a RetryLogger which records retries.
+type retryLogger struct {
+	reform.Logger
+	attempts []int
+}
+
+func (rl *retryLogger) LogRetry(ctx context.Context, attempt int, delay time.Duration, err error) {
+	rl.attempts = append(rl.attempts, attempt)
+}
+
+func (s *ReformSuite) TestInTransactionContext() {
+	err := s.q.Rollback()
+	s.Require().NoError(err)
+	s.q = nil
+
+	retryErr := errors.New("retry me")
+	rl := &retryLogger{Logger: DB.Logger}
+	db := reform.NewDB(sqlDB, retryableDialect{DB.Dialect, retryErr}, rl)
+	policy := &reform.RetryPolicy{Attempts: 4, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	var calls int
+	err = db.InTransactionContext(context.Background(), policy, func(tx *reform.TX) error {
+		calls++
+		if calls < 4 {
+			return retryErr
+		}
+		return nil
+	})
+	s.NoError(err)
+	s.Equal(4, calls)
+	s.Equal([]int{1, 2, 3}, rl.attempts)
+
+	// nil policy disables retries
+	calls = 0
+	err = db.InTransactionContext(context.Background(), nil, func(tx *reform.TX) error {
+		calls++
+		return retryErr
+	})
+	s.Equal(retryErr, err)
+	s.Equal(1, calls)
+
+	// canceled context interrupts waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	policy = &reform.RetryPolicy{Attempts: 3, MinBackoff: time.Hour}
+	err = db.InTransactionContext(ctx, policy, func(tx *reform.TX) error {
+		calls++
+		cancel()
+		return retryErr
+	})
+	s.Equal(retryErr, err)
+	s.Equal(1, calls)
+}
+
 func (s *ReformSuite) TestTimezones() {
 	t1 := time.Now()
 	t2 := t1.UTC()
@@ -0,0 +1,75 @@
+package reform
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// parseBool converts value returned by database driver to bool.
+// It handles bool, integer 0/1, and textual representations used by various drivers.
+func parseBool(src interface{}) (bool, error) {
+	switch src := src.(type) {
+	case bool:
+		return src, nil
+	case int64:
+		switch src {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		}
+	case []byte:
+		return parseBool(string(src))
+	case string:
+		switch src {
+		case "0", "f", "false", "FALSE", "\x00":
+			return false, nil
+		case "1", "t", "true", "TRUE", "\x01":
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("reform: can't convert %#v (%T) to bool", src, src)
+}
+
+// boolScanner is a sql.Scanner for bool fields.
+type boolScanner struct {
+	p *bool
+}
+
+// Scan implements sql.Scanner.
+func (s boolScanner) Scan(src interface{}) error {
+	if src == nil {
+		return fmt.Errorf("reform: can't convert NULL to bool")
+	}
+	b, err := parseBool(src)
+	if err != nil {
+		return err
+	}
+	*s.p = b
+	return nil
+}
+
+// nullBoolScanner is a sql.Scanner for *bool fields.
+type nullBoolScanner struct {
+	p **bool
+}
+
+// Scan implements sql.Scanner.
+func (s nullBoolScanner) Scan(src interface{}) error {
+	if src == nil {
+		*s.p = nil
+		return nil
+	}
+	b, err := parseBool(src)
+	if err != nil {
+		return err
+	}
+	*s.p = &b
+	return nil
+}
+
+// check interfaces
+var (
+	_ sql.Scanner = boolScanner{}
+	_ sql.Scanner = nullBoolScanner{}
+)
@@ -0,0 +1,352 @@
+package reform
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheBackend stores encoded records by key for RecordCache. Implementations should be safe for concurrent use.
+// LRUCache is an in-process implementation; shared backend like Redis is a few lines of code:
+//
+//	type redisBackend struct{ c *redis.Client }
+//
+//	func (b redisBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+//		v, err := b.c.Get(ctx, key).Bytes()
+//		if err == redis.Nil {
+//			return nil, false, nil
+//		}
+//		return v, err == nil, err
+//	}
+//
+//	func (b redisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+//		return b.c.Set(ctx, key, value, ttl).Err()
+//	}
+//
+//	func (b redisBackend) Delete(ctx context.Context, key string) error {
+//		return b.c.Del(ctx, key).Err()
+//	}
+type CacheBackend interface {
+	// Get returns value by key and true, or false if there is no (unexpired) value.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value by key for ttl (zero means without expiration).
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes value by key. Missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// RecordCache configures cache of records found by primary key, see Querier.WithRecordCache.
+type RecordCache struct {
+	// Backend stores cached records.
+	Backend CacheBackend
+
+	// TTL is a time to live of cached records; zero means without expiration
+	// (records are still removed from cache on change or by backend's eviction).
+	TTL time.Duration
+
+	// TableTTL overrides TTL for tables by name; negative value disables cache for table.
+	TableTTL map[string]time.Duration
+
+	// Prefix is prepended to cache keys; "reform:" is used if it is empty.
+	Prefix string
+
+	// versions of keys (by their hashes), incremented on invalidation,
+	// so records read before concurrent change are not stored in cache, see cacheSet.
+	versions [64]uint64
+}
+
+// WithRecordCache returns a copy of querier which caches records found by primary key
+// (FindByPrimaryKeyTo, FindByPrimaryKeyFrom, Reload) in given cache (nil disables it).
+// Records are encoded with MarshalStructJSON (including sensitive fields), so all their field values
+// should survive JSON round trip.
+// AfterFinder hooks are not called for records returned from cache.
+//
+// Cached record is removed from cache when it is changed with the same querier or its transaction:
+// Update (and its variants), Save, Upsert, Delete (and its variants), UpdateColumnsAll and DeleteAll.
+// Changes made by DeleteFrom, Exec or other applications are not tracked:
+// use TTL to limit staleness and RecordCache.Invalidate to remove changed records explicitly.
+//
+// Cache is not used for reads inside transactions, with row locks (see WithRowLock), by Unscoped querier,
+// for tables with encrypted columns, and for tables scoped by TenantScope. Records changed in transaction are also removed from cache
+// after successful Commit, so concurrent reads can't leave old values in cache. Record read concurrently with its change
+// is not stored in cache if change is invalidated by the same RecordCache; that is not guaranteed for other processes
+// sharing cache backend, so use TTL with them.
+func (q *Querier) WithRecordCache(c *RecordCache) *Querier {
+	nq := q.clone()
+	nq.recordCache = c
+	return nq
+}
+
+// key returns cache key for table's record in given schema (may be empty) with given primary key,
+// which is a value or []interface{} for composite primary key.
+func (c *RecordCache) key(schema string, table Table, pk interface{}) string {
+	prefix := c.Prefix
+	if prefix == "" {
+		prefix = "reform:"
+	}
+	if schema != "" {
+		prefix += schema + "."
+	}
+	if values, ok := pk.([]interface{}); ok {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = fmt.Sprint(v)
+		}
+		return prefix + table.Name() + ":" + strings.Join(parts, ":")
+	}
+	return prefix + table.Name() + ":" + fmt.Sprint(pk)
+}
+
+// version returns a pointer to invalidation version of key.
+// Different keys may share version, which only makes caching less effective.
+func (c *RecordCache) version(key string) *uint64 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &c.versions[h.Sum32()%uint32(len(c.versions))]
+}
+
+// delete removes value by key from backend and increments its version.
+func (c *RecordCache) delete(ctx context.Context, key string) error {
+	atomic.AddUint64(c.version(key), 1)
+	return c.Backend.Delete(ctx, key)
+}
+
+// ttl returns TTL for given table, or negative value if caching is disabled for it.
+func (c *RecordCache) ttl(table Table) time.Duration {
+	if ttl, ok := c.TableTTL[table.Name()]; ok {
+		return ttl
+	}
+	return c.TTL
+}
+
+// Invalidate removes table's record with given primary key from cache.
+// For table with composite primary key pk should be []interface{} with values in order of PKColumnIndexes.
+// Records cached by querier with schema (see Querier.WithSchema) are removed by InvalidateSchema.
+func (c *RecordCache) Invalidate(ctx context.Context, table Table, pk interface{}) error {
+	return c.InvalidateSchema(ctx, "", table, pk)
+}
+
+// InvalidateSchema removes table's record in given schema with given primary key from cache, see Invalidate.
+func (c *RecordCache) InvalidateSchema(ctx context.Context, schema string, table Table, pk interface{}) error {
+	return c.delete(ctx, c.key(schema, table, pk))
+}
+
+// cacheEntry describes cache entry for reading table's record.
+type cacheEntry struct {
+	key     string
+	ttl     time.Duration
+	version uint64 // version of key before reading record from SQL database
+}
+
+// cacheKey returns cache entry for reading table's record with given primary key,
+// or false if cache can't be used for that.
+func (q *Querier) cacheKey(table Table, pk interface{}) (cacheEntry, bool) {
+	c := q.recordCache
+	if c == nil || q.unscoped || q.rowLock != 0 {
+		return cacheEntry{}, false
+	}
+	if _, ok := q.dbtx.(*sql.Tx); ok {
+		return cacheEntry{}, false
+	}
+	if _, ok := table.(EncryptedView); ok {
+		return cacheEntry{}, false
+	}
+	if hasInheritance(table) {
+		return cacheEntry{}, false
+	}
+	if column, _, _ := q.tenantColumn(table); column != "" || q.hasScope(table) {
+		return cacheEntry{}, false
+	}
+	ttl := c.ttl(table)
+	if ttl < 0 {
+		return cacheEntry{}, false
+	}
+	key := c.key(q.schema, table, pk)
+	return cacheEntry{key: key, ttl: ttl, version: atomic.LoadUint64(c.version(key))}, true
+}
+
+// cacheGet decodes cached value by key into record and returns true, or returns false on cache miss or error.
+func (q *Querier) cacheGet(key string, record Record) bool {
+	b, ok, err := q.recordCache.Backend.Get(q.ctx, key)
+	if err != nil || !ok {
+		return false
+	}
+
+	// decode into new record to reset all fields
+	table := record.Table()
+	cached := table.NewRecord()
+	if reflect.TypeOf(cached) != reflect.TypeOf(record) {
+		return false
+	}
+	if err = UnmarshalStructJSON(cached, b); err != nil {
+		return false
+	}
+	reflect.ValueOf(record).Elem().Set(reflect.ValueOf(cached).Elem())
+	return true
+}
+
+// cacheSet stores record read from SQL database in cache. Errors are ignored: record is just not cached.
+// Record is not stored (or removed after storing) if its key was invalidated since cacheKey call,
+// so concurrent change can't leave record's old values in cache.
+func (q *Querier) cacheSet(e cacheEntry, record Record) {
+	c := q.recordCache
+	v := c.version(e.key)
+	if atomic.LoadUint64(v) != e.version {
+		return
+	}
+	b, err := MarshalStructJSON(record)
+	if err != nil {
+		return
+	}
+	if c.Backend.Set(q.ctx, e.key, b, e.ttl) == nil && atomic.LoadUint64(v) != e.version {
+		_ = c.Backend.Delete(q.ctx, e.key)
+	}
+}
+
+// cacheInvalidate removes changed record from cache. Inside transaction it also remembers it for removal after Commit.
+func (q *Querier) cacheInvalidate(record Record) error {
+	c := q.recordCache
+	if c == nil {
+		return nil
+	}
+	key := c.key(q.schema, record.Table(), pkArg(record))
+	if q.cacheTxKeys != nil {
+		*q.cacheTxKeys = append(*q.cacheTxKeys, key)
+	}
+	return c.delete(q.ctx, key)
+}
+
+// cacheCommitted removes records changed in committed transaction from cache.
+func (q *Querier) cacheCommitted() error {
+	if q.recordCache == nil || q.cacheTxKeys == nil {
+		return nil
+	}
+	keys := *q.cacheTxKeys
+	*q.cacheTxKeys = nil
+
+	var err error
+	for _, key := range keys {
+		if e := q.recordCache.delete(q.ctx, key); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// LRUCacheStats contains LRUCache metrics.
+type LRUCacheStats struct {
+	Hits      uint64 // number of found values
+	Misses    uint64 // number of missing or expired values
+	Evictions uint64 // number of values removed to keep cache size
+	Size      int    // current number of values
+}
+
+// lruEntry is a value in LRUCache.
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // zero for values without expiration
+}
+
+// LRUCache is an in-process CacheBackend which keeps up to a given number of values,
+// removing the least recently used ones when cache is full. It is safe for concurrent use.
+type LRUCache struct {
+	size int
+
+	m       sync.Mutex
+	lru     *list.List // of *lruEntry, most recently used first
+	entries map[string]*list.Element
+	stats   LRUCacheStats
+}
+
+// NewLRUCache creates new LRUCache holding up to size values.
+func NewLRUCache(size int) *LRUCache {
+	if size <= 0 {
+		panic("reform: LRUCache size should be positive")
+	}
+	return &LRUCache{
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// Get implements CacheBackend.
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	e := c.entries[key]
+	if e == nil {
+		c.stats.Misses++
+		return nil, false, nil
+	}
+	entry := e.Value.(*lruEntry)
+	if !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+		c.lru.Remove(e)
+		delete(c.entries, key)
+		c.stats.Misses++
+		return nil, false, nil
+	}
+	c.lru.MoveToFront(e)
+	c.stats.Hits++
+	return entry.value, true, nil
+}
+
+// Set implements CacheBackend.
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := &lruEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if e := c.entries[key]; e != nil {
+		e.Value = entry
+		c.lru.MoveToFront(e)
+		return nil
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		delete(c.entries, c.lru.Remove(c.lru.Back()).(*lruEntry).key)
+		c.stats.Evictions++
+	}
+	return nil
+}
+
+// Delete implements CacheBackend.
+func (c *LRUCache) Delete(ctx context.Context, key string) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if e := c.entries[key]; e != nil {
+		c.lru.Remove(e)
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// Stats returns cache metrics.
+func (c *LRUCache) Stats() LRUCacheStats {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	res := c.stats
+	res.Size = c.lru.Len()
+	return res
+}
+
+// check interface
+var _ CacheBackend = new(LRUCache)
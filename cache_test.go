@@ -0,0 +1,117 @@
+package reform_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	. "github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func (s *ReformSuite) TestRecordCache() {
+	// cache is not used in transactions
+	s.Require().NoError(s.q.Rollback())
+	s.q = nil
+
+	ctx := context.Background()
+	lru := reform.NewLRUCache(10)
+	rc := &reform.RecordCache{Backend: lru, TTL: time.Minute}
+	db := reform.NewDB(sqlDB, DB.Dialect, DB.Logger)
+	db.Querier = db.WithRecordCache(rc)
+
+	person := &Person{Name: "Cached"}
+	s.Require().NoError(db.Insert(person))
+	defer func() {
+		s.NoError(db.Delete(person))
+		_, err := db.FindByPrimaryKeyFrom(PersonTable, person.ID)
+		s.Equal(reform.ErrNoRows, err)
+	}()
+
+	for i := 0; i < 3; i++ {
+		p, err := db.FindByPrimaryKeyFrom(PersonTable, person.ID)
+		s.Require().NoError(err)
+		s.Equal(person.Name, p.(*Person).Name)
+	}
+	s.Equal(reform.LRUCacheStats{Hits: 2, Misses: 1, Size: 1}, lru.Stats())
+
+	// updated record is removed from cache
+	person.Name = "Updated"
+	s.Require().NoError(db.Update(person))
+	s.Equal(0, lru.Stats().Size)
+	reloaded := &Person{ID: person.ID, Name: "Garbage"}
+	s.Require().NoError(db.Reload(reloaded))
+	s.Equal("Updated", reloaded.Name)
+	s.Require().NoError(db.Reload(reloaded))
+	s.Equal(1, lru.Stats().Size)
+
+	// unscoped querier and transactions don't use cache
+	_, err := db.Unscoped().FindByPrimaryKeyFrom(PersonTable, person.ID)
+	s.NoError(err)
+	s.Require().NoError(db.InTransaction(func(tx *reform.TX) error {
+		_, err := tx.FindByPrimaryKeyFrom(PersonTable, person.ID)
+		return err
+	}))
+	s.Equal(uint64(3), lru.Stats().Hits)
+
+	// stale value cached during transaction is removed after commit
+	key := fmt.Sprintf("reform:people:%d", person.ID)
+	stale, err := reform.MarshalStructJSON(person)
+	s.Require().NoError(err)
+	s.Require().NoError(db.InTransaction(func(tx *reform.TX) error {
+		person.Name = "Committed"
+		if err := tx.Update(person); err != nil {
+			return err
+		}
+		return lru.Set(ctx, key, stale, 0)
+	}))
+	p, err := db.FindByPrimaryKeyFrom(PersonTable, person.ID)
+	s.Require().NoError(err)
+	s.Equal("Committed", p.(*Person).Name)
+
+	// explicit invalidation and disabled table
+	s.NoError(rc.Invalidate(ctx, PersonTable, person.ID))
+	s.Equal(0, lru.Stats().Size)
+	rc.TableTTL = map[string]time.Duration{"people": -1}
+	_, err = db.FindByPrimaryKeyFrom(PersonTable, person.ID)
+	s.NoError(err)
+	s.Equal(0, lru.Stats().Size)
+}
+
+// changingBackend is a CacheBackend which calls change on each Get,
+// simulating concurrent change between cache miss and read from SQL database.
+type changingBackend struct {
+	reform.CacheBackend
+	change func()
+}
+
+func (b changingBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b.change()
+	return b.CacheBackend.Get(ctx, key)
+}
+
+func (s *ReformSuite) TestRecordCacheConcurrentChange() {
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+
+	person := &Person{ID: 1, Name: "Old"}
+	s.Require().NoError(f.Stub(PersonTable, person))
+
+	lru := reform.NewLRUCache(10)
+	rc := &reform.RecordCache{Backend: lru}
+	rc.Backend = changingBackend{CacheBackend: lru, change: func() {
+		s.NoError(rc.Invalidate(context.Background(), PersonTable, person.ID))
+	}}
+	q := f.DB.WithRecordCache(rc)
+
+	_, err := q.FindByPrimaryKeyFrom(PersonTable, person.ID)
+	s.Require().NoError(err)
+	s.Equal(0, lru.Stats().Size)
+
+	rc.Backend = lru
+	_, err = q.FindByPrimaryKeyFrom(PersonTable, person.ID)
+	s.Require().NoError(err)
+	s.Equal(1, lru.Stats().Size)
+}
@@ -0,0 +1,203 @@
+package reform
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrNoCipher is returned from various methods when struct has encrypted columns, but Querier has no Cipher.
+var ErrNoCipher = errors.New("reform: no cipher for encrypted columns")
+
+// Cipher encrypts and decrypts values of columns with "encrypted" label in "reform:" tag.
+// It is set with Querier.WithCipher.
+type Cipher interface {
+	// Encrypt returns ciphertext for given plaintext.
+	Encrypt(plaintext []byte) ([]byte, error)
+
+	// Decrypt returns plaintext for given ciphertext.
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// AEADCipher is an optional interface for Cipher which binds ciphertext to additional authenticated data,
+// so ciphertext copied to another row or column fails to decrypt.
+// Querier uses table (or view) name, column name and record's primary key as additional data.
+// Values of records inserted without primary key (for example, with auto-increment) are bound
+// to table and column only until record is updated, because primary key is not known before INSERT.
+// Such values are decrypted with that data when decryption with primary key fails.
+type AEADCipher interface {
+	Cipher
+
+	// EncryptWithData returns ciphertext for given plaintext and additional data.
+	EncryptWithData(plaintext, data []byte) ([]byte, error)
+
+	// DecryptWithData returns plaintext for given ciphertext and additional data.
+	DecryptWithData(ciphertext, data []byte) ([]byte, error)
+}
+
+// EncryptedView is an optional interface for View which is used by Querier to encrypt and decrypt column values.
+// It is implemented by generated code for structs with "encrypted" labels.
+type EncryptedView interface {
+	View
+
+	// EncryptedColumns returns a new slice of flags: true for encrypted columns for that view or table in SQL database.
+	EncryptedColumns() []bool
+}
+
+// additionalData returns additional authenticated data for given column of str, see AEADCipher:
+// without primary key, and with primary key (nil if str is not a Record or primary key is not set).
+func additionalData(str Struct, column string) (data, pkData []byte) {
+	data = []byte(str.View().Name() + "\x00" + column)
+	if record, ok := str.(Record); ok && record.HasPK() {
+		pkData = []byte(fmt.Sprintf("%s\x00%v", data, pkArg(record)))
+	}
+	return
+}
+
+// encrypt returns encrypted field value of str. Nil pointers are not encrypted.
+// Supported field types are string, []byte, *string and *[]byte.
+func encrypt(c Cipher, str Struct, column string, v interface{}) (interface{}, error) {
+	var b []byte
+	switch v := v.(type) {
+	case string:
+		b = []byte(v)
+	case []byte:
+		if v == nil {
+			return v, nil
+		}
+		b = v
+	case *string:
+		if v == nil {
+			return v, nil
+		}
+		b = []byte(*v)
+	case *[]byte:
+		if v == nil || *v == nil {
+			return v, nil
+		}
+		b = *v
+	default:
+		return nil, fmt.Errorf("reform: column %s: unsupported type %T for encryption", column, v)
+	}
+
+	var res []byte
+	var err error
+	if ac, ok := c.(AEADCipher); ok {
+		data, pkData := additionalData(str, column)
+		if pkData != nil {
+			data = pkData
+		}
+		res, err = ac.EncryptWithData(b, data)
+	} else {
+		res, err = c.Encrypt(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reform: failed to encrypt column %s: %w", column, err)
+	}
+	return res, nil
+}
+
+// decrypt returns plaintext for ciphertext of given column of str.
+func decrypt(c Cipher, str Struct, column string, ciphertext []byte) ([]byte, error) {
+	ac, ok := c.(AEADCipher)
+	if !ok {
+		return c.Decrypt(ciphertext)
+	}
+
+	data, pkData := additionalData(str, column)
+	if pkData == nil {
+		return ac.DecryptWithData(ciphertext, data)
+	}
+	b, err := ac.DecryptWithData(ciphertext, pkData)
+	if err == nil {
+		return b, nil
+	}
+	if b, e := ac.DecryptWithData(ciphertext, data); e == nil {
+		return b, nil
+	}
+	return nil, err
+}
+
+// encryptedValue is a scanned ciphertext of encrypted field.
+type encryptedValue struct {
+	column     string
+	p          interface{}
+	ciphertext []byte
+}
+
+// decryptRow collects ciphertexts of encrypted fields while row is scanned.
+// They are decrypted after that, because primary key is needed for additional data.
+type decryptRow struct {
+	c      Cipher
+	values []encryptedValue
+}
+
+// decrypt decrypts collected ciphertexts of str's fields. It is no-op for nil row.
+func (r *decryptRow) decrypt(str Struct) error {
+	if r == nil {
+		return nil
+	}
+
+	values := r.values
+	r.values = nil
+	for _, v := range values {
+		b, err := decrypt(r.c, str, v.column, v.ciphertext)
+		if err != nil {
+			return fmt.Errorf("reform: failed to decrypt column %s: %w", v.column, err)
+		}
+
+		switch p := v.p.(type) {
+		case *string:
+			*p = string(b)
+		case *[]byte:
+			*p = b
+		case **string:
+			s := string(b)
+			*p = &s
+		case **[]byte:
+			*p = &b
+		default:
+			return fmt.Errorf("reform: column %s: unsupported type %T for decryption", v.column, v.p)
+		}
+	}
+	return nil
+}
+
+// decryptScanner is a sql.Scanner for encrypted fields. It stores ciphertext in row.
+type decryptScanner struct {
+	row    *decryptRow
+	column string
+	p      interface{}
+}
+
+// Scan implements sql.Scanner.
+func (s decryptScanner) Scan(src interface{}) error {
+	var b []byte
+	switch src := src.(type) {
+	case nil:
+		switch p := s.p.(type) {
+		case **string:
+			*p = nil
+		case **[]byte:
+			*p = nil
+		case *[]byte:
+			*p = nil
+		default:
+			return fmt.Errorf("reform: column %s: can't scan NULL into %T", s.column, s.p)
+		}
+		return nil
+	case []byte:
+		// driver may reuse src
+		b = append([]byte(nil), src...)
+	case string:
+		b = []byte(src)
+	default:
+		return fmt.Errorf("reform: column %s: can't decrypt %T", s.column, src)
+	}
+
+	s.row.values = append(s.row.values, encryptedValue{column: s.column, p: s.p, ciphertext: b})
+	return nil
+}
+
+// check interface
+var _ sql.Scanner = decryptScanner{}
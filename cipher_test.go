@@ -0,0 +1,164 @@
+package reform_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+var errNotEncrypted = errors.New("not encrypted")
+
+// testCipher is a reversible Cipher for tests: it is not secure.
+type testCipher struct{}
+
+func (testCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	res := []byte("enc:")
+	for i := len(plaintext) - 1; i >= 0; i-- {
+		res = append(res, plaintext[i])
+	}
+	return res, nil
+}
+
+func (testCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if !bytes.HasPrefix(ciphertext, []byte("enc:")) {
+		return nil, errNotEncrypted
+	}
+	ciphertext = ciphertext[4:]
+	res := make([]byte, 0, len(ciphertext))
+	for i := len(ciphertext) - 1; i >= 0; i-- {
+		res = append(res, ciphertext[i])
+	}
+	return res, nil
+}
+
+func (s *ReformSuite) TestCipher() {
+	secret := &Secret{Name: "password", Data: "hunter2"}
+	err := s.q.Insert(secret)
+	s.Equal(reform.ErrNoCipher, err)
+
+	q := s.q.WithCipher(testCipher{})
+	err = q.Insert(secret)
+	s.NoError(err)
+
+	var data []byte
+	var note []byte
+	query := "SELECT data, note FROM secrets WHERE id = " + s.q.Placeholder(1)
+	err = s.q.QueryRow(query, secret.ID).Scan(&data, &note)
+	s.NoError(err)
+	s.Equal([]byte("enc:2retnuh"), data)
+	s.Nil(note)
+
+	secret2, err := q.FindByPrimaryKeyFrom(SecretTable, secret.ID)
+	s.NoError(err)
+	s.Equal(secret, secret2)
+
+	secret.Note = &[]byte{'o', 'k'}
+	err = q.Update(secret)
+	s.NoError(err)
+
+	secret2, err = q.FindByPrimaryKeyFrom(SecretTable, secret.ID)
+	s.NoError(err)
+	s.Equal(secret, secret2)
+
+	_, err = s.q.FindByPrimaryKeyFrom(SecretTable, secret.ID)
+	s.Equal(reform.ErrNoCipher, err)
+
+	_, err = s.q.Exec("UPDATE secrets SET data = "+s.q.Placeholder(1)+" WHERE id = "+s.q.Placeholder(2), []byte("plain"), secret.ID)
+	s.NoError(err)
+	_, err = q.FindByPrimaryKeyFrom(SecretTable, secret.ID)
+	s.Error(err)
+	s.True(errors.Is(err, errNotEncrypted))
+}
+
+func (s *ReformSuite) TestAESGCM() {
+	key1, key2 := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 16)
+	c1, err := reform.NewAESGCM(map[uint8][]byte{1: key1}, 1)
+	s.Require().NoError(err)
+	c2, err := reform.NewAESGCM(map[uint8][]byte{1: key1, 2: key2}, 2)
+	s.Require().NoError(err)
+
+	_, err = reform.NewAESGCM(map[uint8][]byte{1: key1}, 2)
+	s.EqualError(err, "reform: no key for current version 2")
+	_, err = reform.NewAESGCM(map[uint8][]byte{1: []byte("short")}, 1)
+	s.Error(err)
+
+	ct1, err := c1.Encrypt([]byte("hunter2"))
+	s.Require().NoError(err)
+	ct1again, err := c1.Encrypt([]byte("hunter2"))
+	s.Require().NoError(err)
+	s.NotEqual(ct1, ct1again) // random nonce
+
+	// rotated key decrypts old values and encrypts new ones
+	pt, err := c2.Decrypt(ct1)
+	s.Require().NoError(err)
+	s.Equal([]byte("hunter2"), pt)
+	ct2, err := c2.Encrypt([]byte("hunter2"))
+	s.Require().NoError(err)
+	version, err := c2.KeyVersion(ct2)
+	s.NoError(err)
+	s.Equal(uint8(2), version)
+	_, err = c1.Decrypt(ct2)
+	s.Equal(reform.ErrUnknownKeyVersion, err)
+
+	ct2[len(ct2)-1] ^= 1
+	_, err = c2.Decrypt(ct2)
+	s.Error(err) // authentication failed
+
+	// real database
+	q := s.q.WithCipher(c1)
+	secret := &Secret{Name: "password", Data: "hunter2"}
+	s.Require().NoError(q.Insert(secret))
+	s.Require().NoError(q.WithCipher(c2).Reload(secret))
+	s.Equal("hunter2", secret.Data)
+	s.Require().NoError(q.WithCipher(c2).Update(secret))
+	_, err = q.FindByPrimaryKeyFrom(SecretTable, secret.ID)
+	s.True(errors.Is(err, reform.ErrUnknownKeyVersion))
+
+	// additional data
+	ct, err := c1.EncryptWithData([]byte("hunter2"), []byte("data"))
+	s.Require().NoError(err)
+	pt, err = c1.DecryptWithData(ct, []byte("data"))
+	s.NoError(err)
+	s.Equal([]byte("hunter2"), pt)
+	_, err = c1.DecryptWithData(ct, []byte("other"))
+	s.Error(err)
+	_, err = c1.Decrypt(ct)
+	s.Error(err)
+
+	// ciphertext copied to another row or column fails to decrypt
+	q = q.WithCipher(c2)
+	secret2 := &Secret{Name: "other", Data: "swordfish"}
+	s.Require().NoError(q.Insert(secret2))
+	s.Require().NoError(q.Update(secret2))
+	s.Require().NoError(q.Reload(secret2))
+	s.Equal("swordfish", secret2.Data)
+
+	var data []byte
+	query := "SELECT data FROM secrets WHERE id = " + s.q.Placeholder(1)
+	s.Require().NoError(s.q.QueryRow(query, secret.ID).Scan(&data))
+	query = "UPDATE secrets SET data = " + s.q.Placeholder(1) + ", note = " + s.q.Placeholder(2) + " WHERE id = " + s.q.Placeholder(3)
+	_, err = s.q.Exec(query, data, data, secret2.ID)
+	s.Require().NoError(err)
+	_, err = q.FindByPrimaryKeyFrom(SecretTable, secret2.ID)
+	s.Error(err)
+	_, err = s.q.Exec(query, data, data, secret.ID)
+	s.Require().NoError(err)
+	_, err = q.FindByPrimaryKeyFrom(SecretTable, secret.ID)
+	s.EqualError(err, "reform: failed to decrypt column note: cipher: message authentication failed")
+}
+
+func (s *ReformSuite) TestBeforeInserterContext() {
+	rl := reform.NewRecordingLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	q := s.q.WithCipher(testCipher{}).WithContext(ctx)
+	q.Logger = rl
+	s.Equal(ctx, q.Context())
+
+	err := q.Insert(&Secret{Name: "password", Data: "hunter2"})
+	s.Equal(context.Canceled, err)
+	s.Empty(rl.Statements())
+}
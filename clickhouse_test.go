@@ -0,0 +1,47 @@
+package reform_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+// TestClickHouse is a separate test: ClickHouse doesn't support transactions used by ReformSuite.
+func TestClickHouse(t *testing.T) {
+	if os.Getenv("REFORM_TEST_DRIVER") != "clickhouse" {
+		t.Skip("ClickHouse-specific test")
+	}
+
+	DB.Logger = reform.NewPrintfLogger(t.Logf)
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	project := &Project{ID: "ch1", Name: "ClickHouse Project 1", Start: start}
+	require.NoError(t, DB.Insert(project))
+
+	err := DB.InsertMulti(
+		&Project{ID: "ch2", Name: "ClickHouse Project 2", Start: start},
+		&Project{ID: "ch3", Name: "ClickHouse Project 3", Start: start},
+	)
+	require.NoError(t, err)
+
+	err = DB.Upsert(project)
+	assert.Equal(t, reform.ErrUpsertNotSupported, err)
+
+	// async_insert is disabled by default, so inserted rows are visible immediately
+	str, err := DB.FindByPrimaryKeyFrom(ProjectTable, "ch2")
+	require.NoError(t, err)
+	assert.Equal(t, "ClickHouse Project 2", str.(*Project).Name)
+	assert.Nil(t, str.(*Project).End)
+
+	structs, err := DB.SelectAllFrom(ProjectTable, "ORDER BY id")
+	require.NoError(t, err)
+	require.Len(t, structs, 3)
+	assert.Equal(t, "ch1", structs[0].(*Project).ID)
+	assert.Equal(t, "ch3", structs[2].(*Project).ID)
+}
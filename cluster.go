@@ -0,0 +1,214 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"sync/atomic"
+	"time"
+)
+
+// replica is a read-only database connection with its health status.
+type replica struct {
+	db   *sql.DB
+	down int32 // 1 if replica failed the last health check or query
+}
+
+func (r *replica) healthy() bool {
+	return atomic.LoadInt32(&r.down) == 0
+}
+
+func (r *replica) setHealthy(healthy bool) {
+	var down int32
+	if !healthy {
+		down = 1
+	}
+	atomic.StoreInt32(&r.down, down)
+}
+
+// router is a dbtx which sends SELECT queries to healthy replicas round-robin,
+// and everything else to the primary.
+type router struct {
+	primary  *sql.DB
+	replicas []*replica
+	dialect  Dialect
+	next     uint32
+}
+
+// lockRE matches row-level locking clauses and table hints of SELECT queries.
+var lockRE = regexp.MustCompile(`(?i)\bFOR\s+(NO\s+KEY\s+)?UPDATE\b|\bFOR\s+(KEY\s+)?SHARE\b|\bLOCK\s+IN\s+SHARE\s+MODE\b|\b(UPDLOCK|XLOCK|HOLDLOCK)\b`)
+
+// isRead returns true if query may be sent to a replica: it is SELECT query without locking clause
+// like "FOR UPDATE" or "FOR SHARE".
+func isRead(query string) bool {
+	return operation(query) == "SELECT" && !lockRE.MatchString(query)
+}
+
+// replica returns the next healthy replica, or nil if there are none.
+func (r *router) replica() *replica {
+	n := uint32(len(r.replicas))
+	start := atomic.AddUint32(&r.next, 1)
+	for i := uint32(0); i < n; i++ {
+		if rep := r.replicas[(start+i)%n]; rep.healthy() {
+			return rep
+		}
+	}
+	return nil
+}
+
+func (r *router) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *router) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *router) QueryRow(query string, args ...interface{}) *sql.Row {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+func (r *router) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+// read calls f with a healthy replica for SELECT query, or with the primary for other queries.
+// If f fails with connection-level error, replica is marked as unhealthy and f is called with the primary.
+func (r *router) read(query string, f func(db *sql.DB) error) {
+	if isRead(query) {
+		if rep := r.replica(); rep != nil {
+			err := f(rep.db)
+			if err == nil || !r.dialect.IsConnectionError(err) {
+				return
+			}
+			rep.setHealthy(false)
+		}
+	}
+	f(r.primary)
+}
+
+// QueryContext sends SELECT query to a replica. If it fails with connection-level error,
+// replica is marked as unhealthy and query is sent to the primary.
+func (r *router) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	var err error
+	r.read(query, func(db *sql.DB) error {
+		rows, err = db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext sends SELECT query to a replica like QueryContext.
+func (r *router) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	r.read(query, func(db *sql.DB) error {
+		row = db.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+func (r *router) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.primary.PrepareContext(ctx, query)
+}
+
+// check pings all replicas, updates their health status, and returns a number of healthy ones.
+func (r *router) check(ctx context.Context) int {
+	var n int
+	for _, rep := range r.replicas {
+		healthy := rep.db.PingContext(ctx) == nil
+		rep.setHealthy(healthy)
+		if healthy {
+			n++
+		}
+	}
+	return n
+}
+
+// primary returns querier's dbtx for commands which should not be sent to replicas.
+func (q *Querier) primary() dbtx {
+	if r, ok := q.dbtx.(*router); ok {
+		return r.primary
+	}
+	return q.dbtx
+}
+
+// WithPrimary returns a copy of querier which sends all queries to the primary database of Cluster,
+// for example, to read just written data without replication lag:
+//
+//	err = cluster.Insert(person)
+//	err = cluster.WithPrimary().FindByPrimaryKeyTo(person, person.ID)
+//
+// For other queriers it returns unchanged copy.
+func (q *Querier) WithPrimary() *Querier {
+	nq := q.clone()
+	nq.dbtx = q.primary()
+	return nq
+}
+
+// Cluster represents a primary SQL database with read-only replicas.
+// SELECT queries outside of transactions are sent to healthy replicas round-robin
+// (or to the primary if there are none), everything else (commands, transactions,
+// locking reads with "FOR UPDATE" or "FOR SHARE") goes to the primary.
+// Use WithPrimary for read-after-write consistency.
+//
+// All replicas are considered healthy initially. Replica is marked as unhealthy when query to it fails
+// with connection-level error (see Dialect.IsConnectionError); CheckReplicas and RunHealthChecks
+// update health status of all replicas.
+type Cluster struct {
+	*DB
+	router *router
+}
+
+// NewCluster creates new Cluster object for given primary and replica SQL database connections.
+func NewCluster(primary *sql.DB, replicas []*sql.DB, dialect Dialect, logger Logger) *Cluster {
+	r := &router{
+		primary:  primary,
+		replicas: make([]*replica, len(replicas)),
+		dialect:  dialect,
+	}
+	for i, db := range replicas {
+		r.replicas[i] = &replica{db: db}
+	}
+
+	q := newQuerier(r, dialect, logger)
+	q.stats = &queryStats{views: make(map[string]QueryStats)}
+	return &Cluster{
+		DB: &DB{
+			Querier: q,
+			db:      primary,
+		},
+		router: r,
+	}
+}
+
+// CheckReplicas pings all replicas with querier's context, updates their health status,
+// and returns a number of healthy ones.
+func (c *Cluster) CheckReplicas() int {
+	return c.router.check(c.ctx)
+}
+
+// RunHealthChecks calls CheckReplicas with given interval until ctx is done.
+// It should be run in a separate goroutine:
+//
+//	go cluster.RunHealthChecks(ctx, 5*time.Second)
+func (c *Cluster) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.router.check(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check interface
+var (
+	_ dbtx     = new(router)
+	_ preparer = new(router)
+)
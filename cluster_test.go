@@ -0,0 +1,67 @@
+package reform_test
+
+import (
+	"database/sql"
+	"os"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+// closedDB returns closed connection to test database.
+func (s *ReformSuite) closedDB() *sql.DB {
+	db, err := sql.Open(os.Getenv("REFORM_TEST_DRIVER"), os.Getenv("REFORM_TEST_SOURCE"))
+	s.Require().NoError(err)
+	s.Require().NoError(db.Close())
+	return db
+}
+
+func (s *ReformSuite) TestClusterRouting() {
+	err := s.q.Rollback()
+	s.Require().NoError(err)
+	s.q = nil
+
+	// closed primary: only queries sent to the replica work
+	c := reform.NewCluster(s.closedDB(), []*sql.DB{sqlDB}, DB.Dialect, DB.Logger)
+	s.Equal(1, c.CheckReplicas())
+
+	person, err := c.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.NoError(err)
+	s.Equal("Denis Mills", person.(*Person).Name)
+
+	_, err = c.WithPrimary().FindByPrimaryKeyFrom(PersonTable, 1)
+	s.Error(err)
+	var id int32
+	s.Error(c.QueryRow("SELECT id FROM people WHERE id = 1 FOR UPDATE").Scan(&id))
+	s.Error(c.Insert(&Person{Name: "Cluster"}))
+	_, err = c.Begin()
+	s.Error(err)
+
+	stats := c.Stats()
+	s.NotZero(stats.Queries)
+	s.NotZero(stats.Errors)
+	s.NotZero(stats.Views["people"].Errors)
+}
+
+func (s *ReformSuite) TestClusterUnhealthyReplicas() {
+	err := s.q.Rollback()
+	s.Require().NoError(err)
+	s.q = nil
+
+	// closed replicas: all queries are sent to the primary
+	c := reform.NewCluster(sqlDB, []*sql.DB{s.closedDB(), s.closedDB()}, DB.Dialect, DB.Logger)
+	s.Equal(0, c.CheckReplicas())
+
+	for i := 0; i < 3; i++ {
+		person, err := c.FindByPrimaryKeyFrom(PersonTable, 1)
+		s.NoError(err)
+		s.Equal("Denis Mills", person.(*Person).Name)
+	}
+
+	// no replicas at all
+	c = reform.NewCluster(sqlDB, nil, DB.Dialect, DB.Logger)
+	s.Equal(0, c.CheckReplicas())
+	exists, err := c.WithPrimary().Exists(PersonTable, "")
+	s.NoError(err)
+	s.True(exists)
+}
@@ -0,0 +1,135 @@
+package reform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CopyFromDialect is an optional interface for Dialect which loads rows into table with database-specific
+// fast path like pgx's CopyFrom or MySQL's "LOAD DATA LOCAL INFILE". It is used by Querier.CopyFrom.
+type CopyFromDialect interface {
+	Dialect
+
+	// CanCopyFrom returns true if CopyFrom can be used with dbtx (*sql.DB, *sql.Tx or other DBTXContext).
+	CanCopyFrom(dbtx DBTXContext) bool
+
+	// CopyFrom loads rows into given table columns using dbtx and returns a number of loaded rows.
+	// Row values are already converted by Querier (see Dialect.BoolValue).
+	CopyFrom(ctx context.Context, dbtx DBTXContext, table string, columns []string, rows [][]interface{}) (int64, error)
+}
+
+// CopySource provides structs for Querier.CopyFrom one by one.
+type CopySource interface {
+	// Next returns the next struct, or nil if there are no more structs.
+	Next() (Struct, error)
+}
+
+// sliceSource is a CopySource for a slice of structs.
+type sliceSource struct {
+	structs []Struct
+}
+
+func (s *sliceSource) Next() (Struct, error) {
+	if len(s.structs) == 0 {
+		return nil, nil
+	}
+	str := s.structs[0]
+	s.structs = s.structs[1:]
+	return str, nil
+}
+
+// CopySlice returns CopySource for given structs.
+func CopySlice(structs []Struct) CopySource {
+	return &sliceSource{structs: structs}
+}
+
+// copyFromBatch is a maximum number of structs read from CopySource and loaded at once.
+const copyFromBatch = 10000
+
+// CopyFrom loads structs of given view from source into SQL database table using the fastest available method
+// and returns a number of loaded rows. Structs are read and loaded in batches, so ingests of any size
+// use bounded memory. If struct implements BeforeInserter or BeforeInserterContext, it calls BeforeInsert() before
+// loading it. If struct implements AfterInserter or AfterInserterContext, it calls AfterInsert() after its batch is loaded.
+//
+// Primary key column is not loaded if the first record of a batch has no primary key, all records should be
+// consistent with it. Unlike Insert, CopyFrom doesn't set primary keys of records.
+//
+// Each batch is loaded like with BulkCopy. Batches are loaded independently:
+// use transaction to load all structs or none of them.
+func (q *Querier) CopyFrom(view View, source CopySource) (int64, error) {
+	var total int64
+	batch := make([]Struct, 0, copyFromBatch)
+	for {
+		batch = batch[:0]
+		for len(batch) < copyFromBatch {
+			str, err := source.Next()
+			if err != nil {
+				return total, err
+			}
+			if str == nil {
+				break
+			}
+			if str.View() != view {
+				return total, fmt.Errorf("reform: all structs should have view %s, got %s", view.Name(), str.View().Name())
+			}
+			batch = append(batch, str)
+		}
+		if len(batch) == 0 {
+			return total, nil
+		}
+
+		n, err := q.copyFromBatch(batch)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if len(batch) < copyFromBatch {
+			return total, nil
+		}
+	}
+}
+
+// copyFromBatch loads a single batch of structs for CopyFrom and BulkCopy.
+func (q *Querier) copyFromBatch(structs []Struct) (int64, error) {
+	view, columns, rows, err := q.bulkRows(structs)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	if d, ok := q.Dialect.(CopyFromDialect); ok && q.schema == "" && d.CanCopyFrom(q.primary()) {
+		n, err = q.copyFrom(d, view, columns, rows)
+	} else if d, ok := q.Dialect.(CopyInDialect); ok && q.schema == "" && d.CanCopyIn(q.driver()) {
+		n, err = q.copyIn(d, view, columns, rows)
+	} else {
+		n, err = q.insertMulti(view, columns, rows)
+	}
+	if err != nil {
+		return n, err
+	}
+	return n, q.afterInsertAll(structs)
+}
+
+// copyFrom loads rows with CopyFromDialect.
+func (q *Querier) copyFrom(d CopyFromDialect, view View, columns []string, rows [][]interface{}) (int64, error) {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = q.QuoteIdentifier(c)
+	}
+	// pseudo-query for logging only
+	query := fmt.Sprintf("COPY %s (%s) FROM reform.CopySource", q.QuoteIdentifier(view.Name()), strings.Join(quoted, ", "))
+
+	// values of sensitive columns are marked for logging only
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = driverArgs(row)
+	}
+
+	start := time.Now()
+	q.logBefore(query, nil)
+	n, err := d.CopyFrom(q.ctx, q.primary(), view.Name(), columns, values)
+	q.logAfter(view.Name(), query, nil, start, nil, err)
+	return n, q.wrapError(err)
+}
@@ -1,7 +1,9 @@
 package reform
 
 import (
+	"context"
 	"database/sql"
+	"math/rand"
 	"time"
 )
 
@@ -13,28 +15,75 @@ type DB struct {
 
 // NewDB creates new DB object for given SQL database connection.
 func NewDB(db *sql.DB, dialect Dialect, logger Logger) *DB {
+	q := newQuerier(db, dialect, logger)
+	q.stats = &queryStats{views: make(map[string]QueryStats)}
 	return &DB{
-		Querier: newQuerier(db, dialect, logger),
+		Querier: q,
 		db:      db,
 	}
 }
 
 // Begin starts a transaction.
 func (db *DB) Begin() (*TX, error) {
+	return db.begin(nil)
+}
+
+// begin starts a transaction with given options (may be nil for defaults).
+func (db *DB) begin(opts *sql.TxOptions) (*TX, error) {
 	start := time.Now()
 	db.logBefore("BEGIN", nil)
-	tx, err := db.db.Begin()
-	db.logAfter("BEGIN", nil, time.Now().Sub(start), err)
+	tx, err := db.db.BeginTx(db.ctx, opts)
+	db.logAfter("", "BEGIN", nil, start, nil, err)
 	if err != nil {
 		return nil, err
 	}
-	return NewTX(tx, db.Dialect, db.Logger), nil
+
+	// keep querier settings like cipher
+	q := db.Querier.clone()
+	q.dbtx = tx
+	q.txDB = db.db
+	if q.recordCache != nil {
+		q.cacheTxKeys = new([]string)
+	}
+	return &TX{
+		Querier: q,
+		tx:      tx,
+	}, nil
+}
+
+// BeginContext starts a transaction with given context.
+// That context is also used for all queries and commands in that transaction.
+func (db *DB) BeginContext(ctx context.Context) (*TX, error) {
+	return (&DB{Querier: db.WithContext(ctx), db: db.db}).Begin()
+}
+
+// BeginTx starts a transaction with given context and options.
+// That context is also used for all queries and commands in that transaction.
+// See sql.TxOptions for details; nil opts means driver's defaults.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*TX, error) {
+	return (&DB{Querier: db.WithContext(ctx), db: db.db}).begin(opts)
 }
 
 // InTransaction wraps function execution in transaction, rolling back it in case of error or panic,
 // committing otherwise.
 func (db *DB) InTransaction(f func(t *TX) error) error {
-	tx, err := db.Begin()
+	return db.inTransaction(nil, f)
+}
+
+// InTransactionOpts is like InTransaction, but starts transaction with given context and options,
+// for example, to request SERIALIZABLE isolation level or read-only transaction:
+//
+//	opts := &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}
+//	err := db.InTransactionOpts(ctx, opts, func(tx *reform.TX) error { ... })
+//
+// Not all drivers support all isolation levels; unsupported ones cause an error.
+func (db *DB) InTransactionOpts(ctx context.Context, opts *sql.TxOptions, f func(t *TX) error) error {
+	return (&DB{Querier: db.WithContext(ctx), db: db.db}).inTransaction(opts, f)
+}
+
+// inTransaction implements InTransaction and InTransactionOpts.
+func (db *DB) inTransaction(opts *sql.TxOptions, f func(t *TX) error) error {
+	tx, err := db.begin(opts)
 	if err != nil {
 		return err
 	}
@@ -56,5 +105,99 @@ func (db *DB) InTransaction(f func(t *TX) error) error {
 	return err
 }
 
+// InTransactionRetry is like InTransaction, but executes the whole transaction again, up to attempts times in total,
+// if it fails with error which is considered retryable by Dialect (see RetryableDialect).
+// Before each retry it waits with randomized exponential backoff; waiting is interrupted
+// if querier's context is done, and the last error is returned.
+// Function f should not have side effects outside of transaction.
+// See InTransactionContext for more options.
+func (db *DB) InTransactionRetry(attempts int, f func(t *TX) error) error {
+	return db.InTransactionContext(db.ctx, &RetryPolicy{Attempts: attempts}, f)
+}
+
+const (
+	retryBackoffMin = 10 * time.Millisecond
+	retryBackoffMax = time.Second
+)
+
+// RetryPolicy configures retries of transactions which fail with errors considered retryable by Dialect,
+// like deadlocks and serialization failures (see RetryableDialect). See DB.InTransactionContext.
+type RetryPolicy struct {
+	// Attempts is a maximum number of attempts including the first one; zero or one disables retries.
+	Attempts int
+
+	// MinBackoff is a delay before the first retry; 10ms is used if it is zero.
+	// Delay doubles with each retry up to MaxBackoff (1s if it is zero), and randomized by up to a half
+	// to avoid retrying conflicting transactions at the same time.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// TxOptions are used to start each transaction; nil means driver's defaults.
+	// For example, serialization failures are expected with sql.LevelSerializable isolation level.
+	TxOptions *sql.TxOptions
+}
+
+// Backoff returns delay before retry after given failed attempt (starting from 1).
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = retryBackoffMin
+	}
+	if max <= 0 {
+		max = retryBackoffMax
+	}
+
+	d := min
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// RetryLogger is an optional interface for Logger which is notified about transaction retries,
+// see DB.InTransactionContext.
+type RetryLogger interface {
+	Logger
+
+	// LogRetry logs that transaction failed with err on given attempt (starting from 1)
+	// and will be retried after delay.
+	LogRetry(ctx context.Context, attempt int, delay time.Duration, err error)
+}
+
+// InTransactionContext is like InTransactionOpts, but executes the whole transaction again according
+// to given retry policy (nil disables retries) if it fails with error which is considered retryable
+// by Dialect (see RetryableDialect). Each retry is logged if Logger implements RetryLogger.
+// Waiting before retry is interrupted if ctx is done, and the last error is returned.
+// Function f should not have side effects outside of transaction.
+//
+//	policy := &reform.RetryPolicy{Attempts: 5, TxOptions: &sql.TxOptions{Isolation: sql.LevelSerializable}}
+//	err := db.InTransactionContext(ctx, policy, func(tx *reform.TX) error { ... })
+func (db *DB) InTransactionContext(ctx context.Context, policy *RetryPolicy, f func(t *TX) error) error {
+	if policy == nil {
+		policy = new(RetryPolicy)
+	}
+	cdb := &DB{Querier: db.WithContext(ctx), db: db.db}
+	rd, _ := db.Dialect.(RetryableDialect)
+	for i := 1; ; i++ {
+		err := cdb.inTransaction(policy.TxOptions, f)
+		if err == nil || rd == nil || i >= policy.Attempts || !rd.IsRetryable(err) {
+			return err
+		}
+
+		delay := policy.Backoff(i)
+		if rl, ok := db.Logger.(RetryLogger); ok {
+			rl.LogRetry(ctx, i, delay, err)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
 // check interface
 var _ DBTX = new(DB)
@@ -0,0 +1,141 @@
+package reform
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrDDLNotSupported is returned by CreateTableSQL and Querier.CreateTable if Dialect doesn't support
+// CREATE TABLE statements generated by reform (see DDLDialect).
+var ErrDDLNotSupported = errors.New("reform: CREATE TABLE is not supported by dialect")
+
+// ColumnType is a database-independent column type used by CreateTableSQL.
+type ColumnType int
+
+// Column types.
+const (
+	StringColumn ColumnType = iota // string (and other types without better mapping)
+	BoolColumn                     // bool
+	IntColumn                      // int8, int16, int32 and unsigned types which fit into them
+	BigIntColumn                   // int, int64, uint32, uint and uint64
+	FloatColumn                    // float32 and float64
+	BytesColumn                    // []byte, and encrypted columns
+	TimeColumn                     // time.Time
+	JSONColumn                     // fields with "json" label
+)
+
+// Column describes table column for CreateTableSQL.
+type Column struct {
+	Name          string     // column name
+	Type          ColumnType // column type
+	Array         bool       // true for array of Type (field with "array" label)
+	Nullable      bool       // true for pointer fields
+	PK            bool       // true for (part of) primary key
+	AutoIncrement bool       // true for single-column integer primary key which is set by database
+}
+
+// DDLDialect is an optional interface for Dialect which uses non-standard column types.
+// It is used by CreateTableSQL and Querier.CreateTable.
+type DDLDialect interface {
+	Dialect
+
+	// ColumnType returns SQL type for given column, including auto increment clause (like "BIGSERIAL"
+	// or "INT AUTO_INCREMENT") if needed. NOT NULL and PRIMARY KEY constraints are added by caller.
+	// It returns empty string if column (or CREATE TABLE statement at all) is not supported:
+	// caller returns ErrDDLNotSupported in that case.
+	ColumnType(c Column) string
+}
+
+// DDLTable is an optional interface for Table with column definitions for CreateTableSQL.
+// It is implemented by code generated with "reform -ddl" for tables.
+type DDLTable interface {
+	Table
+
+	// DDLColumns returns a new slice of column definitions for that table in SQL database.
+	DDLColumns() []Column
+}
+
+// CreateTableSQL returns CREATE TABLE statement for given table in given dialect.
+// Column types are inferred by generator from field types and labels, so statement is intended for tests
+// and small tools which bootstrap schema without migration files; it doesn't include indexes, defaults and
+// foreign keys. Dialects without DDLDialect implementation get standard SQL types.
+// It returns ErrDDLNotSupported if dialect doesn't support some columns or CREATE TABLE statement at all.
+func CreateTableSQL(dialect Dialect, table DDLTable) (string, error) {
+	return createTableSQL(dialect, dialect.QuoteIdentifier(table.Name()), table.DDLColumns())
+}
+
+// CreateTable creates given table with statement returned by CreateTableSQL,
+// qualifying table name with querier's schema (see WithSchema).
+func (q *Querier) CreateTable(table DDLTable) error {
+	query, err := createTableSQL(q.Dialect, q.quoteTable(table.Name()), table.DDLColumns())
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(query)
+	return err
+}
+
+// createTableSQL returns CREATE TABLE statement for given quoted table name and columns.
+func createTableSQL(dialect Dialect, table string, columns []Column) (string, error) {
+	var pk []string
+	for _, c := range columns {
+		if c.PK {
+			pk = append(pk, dialect.QuoteIdentifier(c.Name))
+		}
+	}
+
+	defs := make([]string, 0, len(columns)+1)
+	for _, c := range columns {
+		t := columnType(dialect, c)
+		if t == "" {
+			return "", ErrDDLNotSupported
+		}
+		def := dialect.QuoteIdentifier(c.Name) + " " + t
+		if !c.Nullable {
+			def += " NOT NULL"
+		}
+		if c.PK && len(pk) == 1 {
+			def += " PRIMARY KEY"
+		}
+		defs = append(defs, def)
+	}
+	if len(pk) > 1 {
+		defs = append(defs, "PRIMARY KEY ("+strings.Join(pk, ", ")+")")
+	}
+
+	return "CREATE TABLE " + table + " (\n\t" + strings.Join(defs, ",\n\t") + "\n)", nil
+}
+
+// columnType returns SQL type of column for dialect.
+func columnType(dialect Dialect, c Column) string {
+	if d, ok := dialect.(DDLDialect); ok {
+		return d.ColumnType(c)
+	}
+
+	if c.Array {
+		return "TEXT"
+	}
+	var t string
+	switch c.Type {
+	case BoolColumn:
+		t = "BOOLEAN"
+	case IntColumn:
+		t = "INTEGER"
+	case BigIntColumn:
+		t = "BIGINT"
+	case FloatColumn:
+		t = "DOUBLE PRECISION"
+	case BytesColumn:
+		t = "BLOB"
+	case TimeColumn:
+		t = "TIMESTAMP"
+	case JSONColumn:
+		t = "TEXT"
+	default:
+		t = "VARCHAR(255)"
+	}
+	if c.AutoIncrement {
+		t += " GENERATED BY DEFAULT AS IDENTITY"
+	}
+	return t
+}
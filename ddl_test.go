@@ -0,0 +1,130 @@
+package reform_test
+
+import (
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/clickhouse"
+	"github.com/AlekSi/reform/dialects/duckdb"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/oracle"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/dialects/snowflake"
+	"github.com/AlekSi/reform/dialects/spanner"
+	"github.com/AlekSi/reform/dialects/sqlite3"
+	"github.com/AlekSi/reform/dialects/sqlserver"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+// ddlTable wraps DDLTable to change auto increment flag of its primary key.
+type ddlTable struct {
+	reform.DDLTable
+	autoIncrement bool
+}
+
+func (t ddlTable) DDLColumns() []reform.Column {
+	columns := t.DDLTable.DDLColumns()
+	for i := range columns {
+		if columns[i].PK {
+			columns[i].AutoIncrement = t.autoIncrement
+		}
+	}
+	return columns
+}
+
+func (s *ReformSuite) TestCreateTableSQL() {
+	ddl := func(query string, err error) string {
+		s.Require().NoError(err)
+		return query
+	}
+
+	s.Equal(`CREATE TABLE "secrets" (
+	"id" SERIAL NOT NULL PRIMARY KEY,
+	"name" VARCHAR NOT NULL,
+	"data" BYTEA NOT NULL,
+	"note" BYTEA
+)`, ddl(SecretTable.CreateTableSQL(postgresql.Dialect)))
+
+	s.Equal("CREATE TABLE `secrets` (\n"+
+		"\t`id` INT AUTO_INCREMENT NOT NULL PRIMARY KEY,\n"+
+		"\t`name` VARCHAR(255) NOT NULL,\n"+
+		"\t`data` LONGBLOB NOT NULL,\n"+
+		"\t`note` LONGBLOB\n"+
+		")", ddl(SecretTable.CreateTableSQL(mysql.Dialect)))
+
+	s.Equal(`CREATE TABLE "project_roles" (
+	"project_id" VARCHAR NOT NULL,
+	"person_id" INTEGER NOT NULL,
+	"role" VARCHAR NOT NULL,
+	"version" INTEGER NOT NULL,
+	PRIMARY KEY ("project_id", "person_id")
+)`, ddl(ProjectRoleTable.CreateTableSQL(sqlite3.Dialect)))
+
+	s.Equal(`CREATE TABLE [articles] (
+	[id] INT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+	[tags] NVARCHAR(MAX),
+	[scores] NVARCHAR(MAX)
+)`, ddl(ArticleTable.CreateTableSQL(sqlserver.Dialect)))
+
+	s.Equal(`CREATE TABLE "articles" (
+	"id" SERIAL NOT NULL PRIMARY KEY,
+	"tags" VARCHAR[],
+	"scores" BIGINT[]
+)`, ddl(reform.CreateTableSQL(postgresql.Dialect, ArticleTable)))
+
+	s.Equal(`CREATE TABLE "SECRETS" (
+	"ID" NUMBER(10) GENERATED BY DEFAULT AS IDENTITY NOT NULL PRIMARY KEY,
+	"NAME" VARCHAR2(255) NOT NULL,
+	"DATA" BLOB NOT NULL,
+	"NOTE" BLOB
+)`, ddl(SecretTable.CreateTableSQL(oracle.Dialect)))
+
+	s.Equal(`CREATE TABLE "PROJECT_ROLES" (
+	"PROJECT_ID" VARCHAR NOT NULL,
+	"PERSON_ID" INTEGER NOT NULL,
+	"ROLE" VARCHAR NOT NULL,
+	"VERSION" INTEGER NOT NULL,
+	PRIMARY KEY ("PROJECT_ID", "PERSON_ID")
+)`, ddl(ProjectRoleTable.CreateTableSQL(snowflake.Dialect)))
+
+	s.Equal(`CREATE TABLE "articles" (
+	"id" INTEGER NOT NULL PRIMARY KEY,
+	"tags" VARCHAR,
+	"scores" VARCHAR
+)`, ddl(reform.CreateTableSQL(duckdb.Dialect, ddlTable{ArticleTable, false})))
+
+	// unsupported dialects and columns
+	for _, d := range []reform.Dialect{clickhouse.Dialect, spanner.Dialect, duckdb.Dialect} {
+		_, err := SecretTable.CreateTableSQL(d)
+		s.Equal(reform.ErrDDLNotSupported, err, "%T", d)
+	}
+
+	// dialect without DDLDialect
+	d := (&reform.DialectBuilder{}).Build()
+	s.Equal(`CREATE TABLE "events" (
+	"id" INTEGER GENERATED BY DEFAULT AS IDENTITY NOT NULL PRIMARY KEY,
+	"payload" TEXT,
+	"meta" TEXT
+)`, ddl(EventTable.CreateTableSQL(d)))
+
+	// real database
+	switch s.q.Dialect {
+	case postgresql.Dialect, sqlite3.Dialect:
+	default:
+		s.T().Skip("PostgreSQL- and SQLite3-specific test")
+	}
+	q := s.q.Querier
+	if s.q.Dialect == postgresql.Dialect {
+		_, err := s.q.Exec("CREATE SCHEMA ddl_test")
+		s.Require().NoError(err)
+		q = q.WithSchema("ddl_test")
+	} else {
+		_, err := s.q.Exec("DROP TABLE memos")
+		s.Require().NoError(err)
+	}
+	s.Require().NoError(q.CreateTable(MemoTable))
+	memo := &Memo{Text: "created"}
+	s.Require().NoError(q.Insert(memo))
+	s.NotZero(memo.ID)
+	record, err := q.FindByPrimaryKeyFrom(MemoTable, memo.ID)
+	s.Require().NoError(err)
+	s.Equal("created", record.(*Memo).Text)
+}
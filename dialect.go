@@ -0,0 +1,194 @@
+package reform
+
+import (
+	"database/sql/driver"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	dialectsRW sync.RWMutex
+	dialects   = make(map[string]Dialect)
+)
+
+// RegisterDialect makes dialect available by database/sql driver name for DialectForDriver.
+// Dialect packages register their dialects for known drivers when imported: for example,
+// importing dialects/postgresql registers postgresql.Dialect for "postgres" and "pgx" drivers.
+// Custom dialects (including ones built with DialectBuilder) may be registered the same way.
+// If RegisterDialect is called twice with the same driver name or if dialect is nil, it panics.
+func RegisterDialect(driverName string, dialect Dialect) {
+	if dialect == nil {
+		panic("reform: RegisterDialect dialect is nil")
+	}
+
+	dialectsRW.Lock()
+	defer dialectsRW.Unlock()
+
+	if _, dup := dialects[driverName]; dup {
+		panic("reform: RegisterDialect called twice for driver " + driverName)
+	}
+	dialects[driverName] = dialect
+}
+
+// DialectForDriver returns dialect registered for given database/sql driver name, or nil.
+func DialectForDriver(driverName string) Dialect {
+	dialectsRW.RLock()
+	defer dialectsRW.RUnlock()
+
+	return dialects[driverName]
+}
+
+// DialectDrivers returns a sorted list of driver names with registered dialects.
+func DialectDrivers() []string {
+	dialectsRW.RLock()
+	defer dialectsRW.RUnlock()
+
+	res := make([]string, 0, len(dialects))
+	for name := range dialects {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// DialectBuilder builds Dialect for a database without its own dialect package (like Firebird or DB2)
+// from a few settings, so there is no need to implement all Dialect methods. Zero values of all fields
+// are usable: they mean "?" placeholders, double-quoted identifiers, LastInsertId and OnConflict methods,
+// boolean values as is, standard LIMIT and row locking clauses.
+//
+//	firebird := (&reform.DialectBuilder{
+//		LastInsertIdMethod: reform.Returning,
+//		UpsertMethod:       reform.NoUpsert,
+//		LimitClause:        func(limit int, ordered bool) string { return fmt.Sprintf("ROWS %d", limit) },
+//	}).Build()
+//	reform.RegisterDialect("firebirdsql", firebird)
+//
+// Built dialect implements LimitDialect and RowLockingDialect.
+type DialectBuilder struct {
+	// Placeholder returns representation of placeholder parameter for given index (starting from 1).
+	// Default is "?"; NumberedPlaceholder returns function for "$1"-like ones.
+	Placeholder func(index int) string
+
+	// QuoteIdentifier returns quoted database identifier. Default quotes with double quotes (doubling them inside).
+	QuoteIdentifier func(identifier string) string
+
+	// LastInsertIdMethod is a method of receiving primary key of last inserted row.
+	LastInsertIdMethod LastInsertIdMethod
+
+	// UpsertMethod is a method of inserting or updating row atomically.
+	UpsertMethod UpsertMethod
+
+	// BoolValue returns representation of boolean value for database driver. Default passes it as is.
+	BoolValue func(b bool) interface{}
+
+	// IsConnectionError returns true for connection-level errors, see Dialect.IsConnectionError.
+	// Default checks for driver.ErrBadConn.
+	IsConnectionError func(err error) bool
+
+	// MaxPlaceholders is a maximum number of placeholder parameters in a single query. Default is 999.
+	MaxPlaceholders int
+
+	// LimitClause returns clause placed at the end of SELECT query which limits a number of returned rows,
+	// see LimitDialect. Default is "LIMIT n".
+	LimitClause func(limit int, ordered bool) string
+
+	// RowLockClause returns locking clause for given mode, or empty string if that mode is not supported,
+	// see RowLockingDialect. Default is RowLock.Clause.
+	RowLockClause func(lock RowLock) string
+}
+
+// Build returns Dialect with builder's settings. Builder may be changed or reused after that.
+func (b *DialectBuilder) Build() Dialect {
+	d := &builtDialect{b: *b}
+	if d.b.Placeholder == nil {
+		d.b.Placeholder = func(int) string { return "?" }
+	}
+	if d.b.QuoteIdentifier == nil {
+		d.b.QuoteIdentifier = func(identifier string) string {
+			return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+		}
+	}
+	if d.b.BoolValue == nil {
+		d.b.BoolValue = func(b bool) interface{} { return b }
+	}
+	if d.b.IsConnectionError == nil {
+		d.b.IsConnectionError = func(err error) bool { return errors.Is(err, driver.ErrBadConn) }
+	}
+	if d.b.MaxPlaceholders == 0 {
+		d.b.MaxPlaceholders = 999
+	}
+	if d.b.LimitClause == nil {
+		d.b.LimitClause = func(limit int, ordered bool) string { return "LIMIT " + strconv.Itoa(limit) }
+	}
+	if d.b.RowLockClause == nil {
+		d.b.RowLockClause = RowLock.Clause
+	}
+	return d
+}
+
+// NumberedPlaceholder returns DialectBuilder's Placeholder function for placeholders
+// with given prefix and index, like "$1" or ":1".
+func NumberedPlaceholder(prefix string) func(index int) string {
+	return func(index int) string {
+		return prefix + strconv.Itoa(index)
+	}
+}
+
+// builtDialect implements Dialect with DialectBuilder's settings.
+type builtDialect struct {
+	b DialectBuilder
+}
+
+func (d *builtDialect) Placeholder(index int) string {
+	return d.b.Placeholder(index)
+}
+
+func (d *builtDialect) Placeholders(start, count int) []string {
+	res := make([]string, count)
+	for i := 0; i < count; i++ {
+		res[i] = d.b.Placeholder(start + i)
+	}
+	return res
+}
+
+func (d *builtDialect) QuoteIdentifier(identifier string) string {
+	return d.b.QuoteIdentifier(identifier)
+}
+
+func (d *builtDialect) LastInsertIdMethod() LastInsertIdMethod {
+	return d.b.LastInsertIdMethod
+}
+
+func (d *builtDialect) UpsertMethod() UpsertMethod {
+	return d.b.UpsertMethod
+}
+
+func (d *builtDialect) BoolValue(b bool) interface{} {
+	return d.b.BoolValue(b)
+}
+
+func (d *builtDialect) IsConnectionError(err error) bool {
+	return d.b.IsConnectionError(err)
+}
+
+func (d *builtDialect) MaxPlaceholders() int {
+	return d.b.MaxPlaceholders
+}
+
+func (d *builtDialect) LimitClause(limit int, ordered bool) string {
+	return d.b.LimitClause(limit, ordered)
+}
+
+func (d *builtDialect) RowLockClause(lock RowLock) string {
+	return d.b.RowLockClause(lock)
+}
+
+// check interfaces
+var (
+	_ Dialect           = new(builtDialect)
+	_ LimitDialect      = new(builtDialect)
+	_ RowLockingDialect = new(builtDialect)
+)
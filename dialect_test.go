@@ -0,0 +1,218 @@
+package reform_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/cockroachdb"
+	"github.com/AlekSi/reform/dialects/duckdb"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/oracle"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/dialects/snowflake"
+	"github.com/AlekSi/reform/dialects/spanner"
+	"github.com/AlekSi/reform/dialects/sqlite3"
+	"github.com/AlekSi/reform/dialects/sqlserver"
+	"github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func (s *ReformSuite) TestDialectRegistry() {
+	s.Equal(postgresql.Dialect, reform.DialectForDriver("postgres"))
+	s.Equal(postgresql.Dialect, reform.DialectForDriver("pgx"))
+	s.Equal(duckdb.Dialect, reform.DialectForDriver("duckdb"))
+	s.Equal(snowflake.Dialect, reform.DialectForDriver("snowflake"))
+	s.Equal(`"PEOPLE"`, snowflake.Dialect.QuoteIdentifier("people"))
+	s.Equal(`"MyColumn"`, snowflake.Dialect.QuoteIdentifier("MyColumn"))
+	s.Equal(spanner.Dialect, reform.DialectForDriver("spanner"))
+	s.Equal("people", spanner.Dialect.QuoteIdentifier("people"))
+	s.Equal("`end`", spanner.Dialect.QuoteIdentifier("end"))
+	s.Nil(reform.DialectForDriver("firebirdsql"))
+	s.Contains(reform.DialectDrivers(), "sqlite3")
+
+	s.Panics(func() { reform.RegisterDialect("postgres", postgresql.Dialect) })
+	s.Panics(func() { reform.RegisterDialect("firebirdsql", nil) })
+}
+
+func (s *ReformSuite) TestSavepointDialect() {
+	s.Equal("SAVE TRANSACTION [sp]", sqlserver.Dialect.SavepointQuery("[sp]"))
+	s.Equal("ROLLBACK TRANSACTION [sp]", sqlserver.Dialect.RollbackToSavepointQuery("[sp]"))
+	s.Empty(sqlserver.Dialect.ReleaseSavepointQuery("[sp]"))
+	s.Equal(`ROLLBACK TO SAVEPOINT "sp"`, oracle.Dialect.RollbackToSavepointQuery(`"sp"`))
+	s.Empty(oracle.Dialect.ReleaseSavepointQuery(`"sp"`))
+
+	_, ok := reform.Dialect(postgresql.Dialect).(reform.SavepointDialect)
+	s.False(ok)
+}
+
+func (s *ReformSuite) TestSpannerQueries() {
+	f := reformtest.New(spanner.Dialect)
+	defer f.Close()
+
+	person := &models.Person{Name: "Spanner"}
+	s.Require().NoError(f.DB.Insert(person))
+	s.NotZero(person.ID)
+	s.Require().NoError(f.DB.Insert(&models.Project{ID: "sp", Name: "Spanner"}))
+	s.Equal(reform.ErrUpsertNotSupported, f.DB.Upsert(&models.Person{ID: 5, Name: "Upsert"}))
+	s.Equal(reform.ErrRowLockNotSupported, f.DB.WithRowLock(reform.ForShare).FindByPrimaryKeyTo(new(models.Person), 1))
+	s.Equal(reform.ErrNoRows, f.DB.WithRowLock(reform.ForUpdate).FindByPrimaryKeyTo(new(models.Person), 1))
+
+	statements := f.Statements()
+	s.Require().Len(statements, 3)
+	s.Equal("INSERT INTO people (name, email, created_at, updated_at) VALUES (@p1, @p2, @p3, @p4) THEN RETURN id", statements[0].Query)
+	s.Equal("INSERT INTO projects (name, id, start, `end`) VALUES (@p1, @p2, @p3, @p4)", statements[1].Query)
+	s.Equal("SELECT people.id, people.name, people.email, people.created_at, people.updated_at "+
+		"FROM people WHERE people.id = @p1 LIMIT 1 FOR UPDATE", statements[2].Query)
+	s.Equal([]interface{}{1}, statements[2].Args)
+}
+
+func (s *ReformSuite) TestMySQLRowLock() {
+	f := reformtest.New(mysql.Dialect)
+	defer f.Close()
+
+	s.Equal(reform.ErrNoRows, f.DB.WithRowLock(reform.ForShare).FindByPrimaryKeyTo(new(models.Person), 1))
+	s.Equal(reform.ErrNoRows, f.DB.WithRowLock(reform.ForShare|reform.NoWait).FindByPrimaryKeyTo(new(models.Person), 1))
+	s.Equal(reform.ErrNoRows, f.DB.WithRowLock(reform.ForUpdate|reform.SkipLocked).FindByPrimaryKeyTo(new(models.Person), 1))
+
+	statements := f.Statements()
+	s.Require().Len(statements, 3)
+	s.True(strings.HasSuffix(statements[0].Query, " LIMIT 1 LOCK IN SHARE MODE"), "%s", statements[0].Query)
+	s.True(strings.HasSuffix(statements[1].Query, " LIMIT 1 FOR SHARE NOWAIT"), "%s", statements[1].Query)
+	s.True(strings.HasSuffix(statements[2].Query, " LIMIT 1 FOR UPDATE SKIP LOCKED"), "%s", statements[2].Query)
+}
+
+func (s *ReformSuite) TestCockroachDBErrors() {
+	retry := &pqError{"40001", "restart transaction: TransactionRetryWithProtoRefreshError"}
+	s.True(cockroachdb.Dialect.IsRetryable(retry))
+	s.True(cockroachdb.Dialect.IsRetryable(fmt.Errorf("commit: %w", retry)))
+	s.False(cockroachdb.Dialect.IsRetryable(&pqError{"23505", "duplicate key value violates unique constraint"}))
+	s.False(cockroachdb.Dialect.IsRetryable(errors.New("restart transaction: message without code")))
+
+	s.True(cockroachdb.Dialect.IsTransientError(&pqError{"57P01", "terminating connection due to administrator command"}))
+	s.False(cockroachdb.Dialect.IsTransientError(retry))
+}
+
+func (s *ReformSuite) TestDuckDBQueries() {
+	f := reformtest.New(duckdb.Dialect)
+	defer f.Close()
+
+	person := &models.Person{Name: "DuckDB"}
+	s.Require().NoError(f.DB.Insert(person))
+	s.NotZero(person.ID)
+	s.Require().NoError(f.DB.Upsert(&models.Person{ID: 5, Name: "Upsert"}))
+	s.Equal(reform.ErrRowLockNotSupported, f.DB.WithRowLock(reform.ForUpdate).FindByPrimaryKeyTo(new(models.Person), 1))
+
+	statements := f.Statements()
+	s.Require().Len(statements, 2)
+	s.Equal(`INSERT INTO "people" ("name", "email", "created_at", "updated_at") VALUES (?, ?, ?, ?) RETURNING "id"`, statements[0].Query)
+	s.Equal(`INSERT INTO "people" ("id", "name", "email", "created_at", "updated_at") VALUES (?, ?, ?, ?, ?) `+
+		`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "email" = EXCLUDED."email", `+
+		`"created_at" = EXCLUDED."created_at", "updated_at" = EXCLUDED."updated_at"`, statements[1].Query)
+	s.Equal(int32(5), statements[1].Args[0])
+}
+
+func (s *ReformSuite) TestSnowflakeQueries() {
+	f := reformtest.New(snowflake.Dialect)
+	defer f.Close()
+
+	// primary keys of inserted records are not received, see package documentation
+	person := &models.Person{Name: "Snowflake"}
+	s.Require().NoError(f.DB.Insert(person))
+	s.Zero(person.ID)
+	s.Equal(reform.ErrUpsertNotSupported, f.DB.Upsert(&models.Person{ID: 5, Name: "Upsert"}))
+	s.Equal(reform.ErrRowLockNotSupported, f.DB.WithRowLock(reform.ForUpdate).FindByPrimaryKeyTo(new(models.Person), 1))
+
+	statements := f.Statements()
+	s.Require().Len(statements, 1)
+	s.Equal(`INSERT INTO "PEOPLE" ("NAME", "EMAIL", "CREATED_AT", "UPDATED_AT") VALUES (?, ?, ?, ?)`, statements[0].Query)
+	s.Equal("Snowflake", statements[0].Args[0])
+}
+
+func (s *ReformSuite) TestSQLite3BusyRetry() {
+	errBusy := errors.New("database is locked")
+	f := reformtest.New(sqlite3.Dialect)
+	defer f.Close()
+	q := f.DB.WithTransientRetries(&reform.RetryPolicy{Attempts: 3, MinBackoff: time.Millisecond})
+
+	f.StubError(models.PersonTable, errBusy)
+	s.Equal(errBusy, q.Insert(&models.Person{Name: "Busy"}))
+	s.Len(f.Commands(models.PersonTable), 3)
+
+	f.Reset()
+	f.StubError(models.PersonTable, errors.New("boom"))
+	s.EqualError(q.Insert(&models.Person{Name: "Boom"}), "boom")
+	s.Len(f.Commands(models.PersonTable), 1)
+
+	// without retry policy, only busy errors are retried
+	f.Reset()
+	f.StubError(models.PersonTable, errBusy)
+	s.Equal(errBusy, f.DB.Insert(&models.Person{Name: "Busy"}))
+	s.Len(f.Commands(models.PersonTable), 5)
+
+	f.Reset()
+	f.StubError(models.PersonTable, errors.New("boom"))
+	s.EqualError(f.DB.Insert(&models.Person{Name: "Boom"}), "boom")
+	s.Len(f.Commands(models.PersonTable), 1)
+
+	// retries are disabled
+	f.Reset()
+	f.StubError(models.PersonTable, errBusy)
+	s.Equal(errBusy, f.DB.WithTransientRetries(&reform.RetryPolicy{Attempts: 1}).Insert(&models.Person{Name: "Busy"}))
+	s.Len(f.Commands(models.PersonTable), 1)
+
+	// dialect is comparable
+	s.Equal(sqlite3.Dialect, reform.DialectForDriver("sqlite3"))
+	s.True(sqlite3.Dialect.IsBusy(errBusy))
+}
+
+func (s *ReformSuite) TestDialectBuilder() {
+	d := s.q.Dialect
+	b := &reform.DialectBuilder{
+		Placeholder:        d.Placeholder,
+		QuoteIdentifier:    d.QuoteIdentifier,
+		LastInsertIdMethod: d.LastInsertIdMethod(),
+		UpsertMethod:       d.UpsertMethod(),
+		BoolValue:          d.BoolValue,
+		IsConnectionError:  d.IsConnectionError,
+		MaxPlaceholders:    d.MaxPlaceholders(),
+	}
+	if ld, ok := d.(reform.LimitDialect); ok {
+		b.LimitClause = ld.LimitClause
+	}
+	if rd, ok := d.(reform.RowLockingDialect); ok {
+		b.RowLockClause = rd.RowLockClause
+	}
+	built := b.Build()
+	s.Implements((*reform.LimitDialect)(nil), built)
+	s.Implements((*reform.RowLockingDialect)(nil), built)
+	s.Equal(d.Placeholders(2, 3), built.Placeholders(2, 3))
+
+	// use own transaction with built dialect
+	s.Require().NoError(s.q.Rollback())
+	s.q = nil
+
+	errRollback := errors.New("rollback")
+	err := reform.NewDB(sqlDB, built, DB.Logger).InTransaction(func(tx *reform.TX) error {
+		person := &models.Person{Name: "Builder"}
+		s.Require().NoError(tx.Insert(person))
+		s.NotZero(person.ID)
+
+		tail, args, err := reform.Where(reform.Eq("id", person.ID)).Limit(1).Build(built)
+		s.Require().NoError(err)
+		str, err := tx.SelectOneFrom(models.PersonTable, tail, args...)
+		s.Require().NoError(err)
+		s.Equal("Builder", str.(*models.Person).Name)
+		return errRollback
+	})
+	s.Equal(errRollback, err)
+
+	def := (&reform.DialectBuilder{}).Build()
+	s.Equal(`"a""b"`, def.QuoteIdentifier(`a"b`))
+	s.Equal([]string{"?", "?"}, def.Placeholders(1, 2))
+	s.Equal("LIMIT 5", def.(reform.LimitDialect).LimitClause(5, false))
+	s.Equal(reform.LastInsertId, def.LastInsertIdMethod())
+	s.Equal([]string{"$1", "$2"}, (&reform.DialectBuilder{Placeholder: reform.NumberedPlaceholder("$")}).Build().Placeholders(1, 2))
+}
@@ -0,0 +1,79 @@
+// Package clickhouse implements reform.Dialect for ClickHouse.
+//
+// It is intended for analytics: selecting from views and tables, and inserting rows
+// (use Querier.InsertMulti for batches). ClickHouse has no auto-increment columns and no RETURNING clause,
+// so primary keys of records are never set by reform: application should set them (for example, to UUIDs).
+// Upsert is not supported; UPDATE, DELETE and transactions depend on ClickHouse version and table engine.
+//
+// Inserts are not transactional. If async_insert setting is enabled, inserted rows may become visible
+// for SELECT queries only after buffer is flushed (or immediately with wait_for_async_insert=1);
+// it is configured on the server or in the driver's DSN, not by reform.
+//
+// Use it with ClickHouse database/sql driver like github.com/ClickHouse/clickhouse-go/v2.
+package clickhouse // TODO add canonical import path via gopkg.in
+
+import "github.com/AlekSi/reform"
+
+type clickhouse struct{}
+
+func (clickhouse) Placeholder(index int) string {
+	return "?"
+}
+
+func (clickhouse) Placeholders(start, count int) []string {
+	res := make([]string, count)
+	for i := 0; i < count; i++ {
+		res[i] = "?"
+	}
+	return res
+}
+
+func (clickhouse) QuoteIdentifier(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+func (clickhouse) LastInsertIdMethod() reform.LastInsertIdMethod {
+	return reform.NoLastInsertId
+}
+
+func (clickhouse) UpsertMethod() reform.UpsertMethod {
+	return reform.NoUpsert
+}
+
+func (clickhouse) BoolValue(b bool) interface{} {
+	return b
+}
+
+func (clickhouse) IsConnectionError(err error) bool {
+	return reform.IsConnectionError(err)
+}
+
+// MaxPlaceholders returns a limit which keeps query size reasonable:
+// ClickHouse driver binds parameters on client side, so there is no protocol limit.
+func (clickhouse) MaxPlaceholders() int {
+	return 65535
+}
+
+// RowLockClause returns empty string: ClickHouse doesn't support row-level locking.
+func (clickhouse) RowLockClause(lock reform.RowLock) string {
+	return ""
+}
+
+// ColumnType returns empty string: ClickHouse tables require ENGINE clause, so CREATE TABLE is not supported.
+func (clickhouse) ColumnType(c reform.Column) string {
+	return ""
+}
+
+// Dialect implements reform.Dialect for ClickHouse.
+var Dialect clickhouse
+
+func init() {
+	reform.RegisterDialect("clickhouse", Dialect)
+}
+
+// check interfaces
+var (
+	_ reform.Dialect           = Dialect
+	_ reform.RowLockingDialect = Dialect
+	_ reform.DDLDialect        = Dialect
+)
@@ -0,0 +1,102 @@
+// Package cockroachdb implements reform.Dialect for CockroachDB.
+//
+// CockroachDB uses PostgreSQL wire protocol, so this dialect uses PostgreSQL placeholders,
+// identifier quoting and "RETURNING id" syntax. Use it with PostgreSQL driver like github.com/lib/pq.
+//
+// Unlike PostgreSQL, transactions in CockroachDB are expected to fail with retryable errors (SQLSTATE 40001)
+// under contention. Use DB.InTransactionRetry to handle them: it executes the whole transaction again
+// with exponential backoff, as recommended by CockroachDB documentation.
+//
+// SERIAL columns in CockroachDB are INT8 with unique_rowid() default by default: use int64 fields for them.
+// UUID primary keys with gen_random_uuid() default work with string fields.
+package cockroachdb // TODO add canonical import path via gopkg.in
+
+import (
+	"errors"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+)
+
+type cockroachdb struct{}
+
+func (cockroachdb) Placeholder(index int) string {
+	return postgresql.Dialect.Placeholder(index)
+}
+
+func (cockroachdb) Placeholders(start, count int) []string {
+	return postgresql.Dialect.Placeholders(start, count)
+}
+
+func (cockroachdb) QuoteIdentifier(identifier string) string {
+	return postgresql.Dialect.QuoteIdentifier(identifier)
+}
+
+func (cockroachdb) LastInsertIdMethod() reform.LastInsertIdMethod {
+	return reform.Returning
+}
+
+func (cockroachdb) UpsertMethod() reform.UpsertMethod {
+	return reform.OnConflict
+}
+
+func (cockroachdb) BoolValue(b bool) interface{} {
+	return postgresql.Dialect.BoolValue(b)
+}
+
+func (cockroachdb) IsConnectionError(err error) bool {
+	return postgresql.Dialect.IsConnectionError(err)
+}
+
+func (cockroachdb) MaxPlaceholders() int {
+	return postgresql.Dialect.MaxPlaceholders()
+}
+
+// ConstraintViolation detects unique and foreign key violations like PostgreSQL.
+func (cockroachdb) ConstraintViolation(err error) (error, string) {
+	return postgresql.Dialect.ConstraintViolation(err)
+}
+
+// ExplainQuery returns "EXPLAIN" query like PostgreSQL.
+func (cockroachdb) ExplainQuery(query string) string {
+	return postgresql.Dialect.ExplainQuery(query)
+}
+
+// sqlStateError is implemented by github.com/lib/pq and github.com/jackc/pgx errors.
+type sqlStateError interface {
+	SQLState() string
+}
+
+// IsRetryable returns true for CockroachDB transaction retry errors (SQLSTATE 40001).
+// Error code is taken from driver's error, error messages are not matched.
+func (cockroachdb) IsRetryable(err error) bool {
+	var se sqlStateError
+	return errors.As(err, &se) && se.SQLState() == "40001"
+}
+
+// IsTransientError returns true for connection exceptions and server shutdown errors like PostgreSQL.
+func (cockroachdb) IsTransientError(err error) bool {
+	return postgresql.Dialect.IsTransientError(err)
+}
+
+// TruncateQuery returns "TRUNCATE TABLE" statement with "CASCADE" option.
+// RestartIdentity is ignored: CockroachDB doesn't support it.
+func (cockroachdb) TruncateQuery(table string, options reform.TruncateOption) string {
+	query := "TRUNCATE TABLE " + table
+	if options&reform.Cascade != 0 {
+		query += " CASCADE"
+	}
+	return query
+}
+
+// Dialect implements reform.Dialect for CockroachDB.
+var Dialect cockroachdb
+
+// check interfaces
+var (
+	_ reform.RetryableDialect      = Dialect
+	_ reform.TransientErrorDialect = Dialect
+	_ reform.ConstraintDialect     = Dialect
+	_ reform.ExplainDialect        = Dialect
+	_ reform.TruncateDialect       = Dialect
+)
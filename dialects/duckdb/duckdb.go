@@ -0,0 +1,141 @@
+// Package duckdb implements reform.Dialect for DuckDB.
+//
+// DuckDB SQL is close to PostgreSQL: primary keys of inserted records are received with "RETURNING id" clause,
+// Upsert uses "ON CONFLICT" clause, and "RETURNING" is used by Querier.UpdateReturning and Querier.DeleteReturning,
+// so the same models and code work with embedded DuckDB for local processing and PostgreSQL in production.
+// Parameters use positional "?" placeholders. Row-level locking is not supported: DuckDB uses optimistic
+// concurrency control, and conflicting transactions fail on commit (see IsRetryable).
+//
+// Use it with github.com/marcboeker/go-duckdb driver registered as "duckdb".
+package duckdb // TODO add canonical import path via gopkg.in
+
+import (
+	"strings"
+
+	"github.com/AlekSi/reform"
+)
+
+type duckdb struct{}
+
+func (duckdb) Placeholder(index int) string {
+	return "?"
+}
+
+func (duckdb) Placeholders(start, count int) []string {
+	res := make([]string, count)
+	for i := 0; i < count; i++ {
+		res[i] = "?"
+	}
+	return res
+}
+
+func (duckdb) QuoteIdentifier(identifier string) string {
+	return `"` + identifier + `"`
+}
+
+func (duckdb) LastInsertIdMethod() reform.LastInsertIdMethod {
+	return reform.Returning
+}
+
+func (duckdb) UpsertMethod() reform.UpsertMethod {
+	return reform.OnConflict
+}
+
+func (duckdb) BoolValue(b bool) interface{} {
+	return b
+}
+
+// IsConnectionError returns true for connection errors (see reform.IsConnectionError):
+// embedded database has no network connections, so in practice only for driver.ErrBadConn.
+func (duckdb) IsConnectionError(err error) bool {
+	return reform.IsConnectionError(err)
+}
+
+// MaxPlaceholders returns a limit which keeps query size reasonable:
+// DuckDB has no hard limit on a number of parameters.
+func (duckdb) MaxPlaceholders() int {
+	return 65535
+}
+
+// RowLockClause returns empty string: DuckDB doesn't support row-level locking.
+func (duckdb) RowLockClause(lock reform.RowLock) string {
+	return ""
+}
+
+// ConstraintViolation detects unique and foreign key violations by "Constraint Error" messages like
+// `Constraint Error: Duplicate key "id: 1" violates primary key constraint`.
+// DuckDB doesn't report constraint names, so returned name is always empty.
+func (duckdb) ConstraintViolation(err error) (error, string) {
+	msg := err.Error()
+	if !strings.Contains(msg, "Constraint Error") {
+		return nil, ""
+	}
+
+	switch {
+	case strings.Contains(msg, "Duplicate key"):
+		return reform.ErrUniqueViolation, ""
+	case strings.Contains(msg, "foreign key constraint"):
+		return reform.ErrForeignKeyViolation, ""
+	default:
+		return nil, ""
+	}
+}
+
+// IsRetryable returns true for transaction conflicts like "TransactionContext Error: Conflict on tuple deletion!"
+// or "Catalog write-write conflict".
+func (duckdb) IsRetryable(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "TransactionContext Error") &&
+		(strings.Contains(msg, "Conflict on") || strings.Contains(msg, "write-write conflict"))
+}
+
+// ExplainQuery returns "EXPLAIN" query.
+func (duckdb) ExplainQuery(query string) string {
+	return "EXPLAIN " + query
+}
+
+// ColumnType returns DuckDB type for column: VARCHAR for strings and arrays, TIMESTAMPTZ for times.
+// It returns empty string for auto-incremented primary keys: DuckDB requires a separately created sequence for them.
+func (duckdb) ColumnType(c reform.Column) string {
+	if c.AutoIncrement {
+		return ""
+	}
+	if c.Array {
+		return "VARCHAR"
+	}
+	switch c.Type {
+	case reform.BoolColumn:
+		return "BOOLEAN"
+	case reform.IntColumn:
+		return "INTEGER"
+	case reform.BigIntColumn:
+		return "BIGINT"
+	case reform.FloatColumn:
+		return "DOUBLE"
+	case reform.BytesColumn:
+		return "BLOB"
+	case reform.TimeColumn:
+		return "TIMESTAMPTZ"
+	case reform.JSONColumn:
+		return "JSON"
+	default:
+		return "VARCHAR"
+	}
+}
+
+// Dialect implements reform.Dialect for DuckDB.
+var Dialect duckdb
+
+func init() {
+	reform.RegisterDialect("duckdb", Dialect)
+}
+
+// check interfaces
+var (
+	_ reform.Dialect           = Dialect
+	_ reform.RowLockingDialect = Dialect
+	_ reform.ConstraintDialect = Dialect
+	_ reform.RetryableDialect  = Dialect
+	_ reform.ExplainDialect    = Dialect
+	_ reform.DDLDialect        = Dialect
+)
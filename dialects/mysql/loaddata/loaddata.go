@@ -0,0 +1,130 @@
+// Package loaddata implements reform.Dialect for MySQL with github.com/go-sql-driver/mysql driver.
+// It is the same as mysql.Dialect, but Querier.CopyFrom and BulkCopy load rows with
+// "LOAD DATA LOCAL INFILE" statement, both inside and outside of transactions.
+//
+// It requires local_infile server variable to be enabled. Data is sent without character set conversion,
+// so text columns should use utf8mb4 character set. Time values are sent in UTC, like the driver does by default.
+package loaddata // TODO add canonical import path via gopkg.in
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/AlekSi/reform"
+	mysqldialect "github.com/AlekSi/reform/dialects/mysql"
+)
+
+// mysqlDialect contains optional interfaces of mysql.Dialect used by loaddata.
+type mysqlDialect interface {
+	reform.DDLDialect
+	reform.ConstraintDialect
+	reform.TimeoutDialect
+	reform.ExplainDialect
+	reform.RetryableDialect
+	reform.TransientErrorDialect
+	reform.RowLockingDialect
+}
+
+type loaddata struct {
+	mysqlDialect
+}
+
+// CanCopyFrom returns true.
+func (loaddata) CanCopyFrom(dbtx reform.DBTXContext) bool {
+	return true
+}
+
+// handlers is a counter for unique reader handler names.
+var handlers uint64
+
+// CopyFrom loads rows into given table columns with "LOAD DATA LOCAL INFILE" statement.
+func (d loaddata) CopyFrom(ctx context.Context, dbtx reform.DBTXContext, table string, columns []string, rows [][]interface{}) (int64, error) {
+	var buf bytes.Buffer
+	for _, row := range rows {
+		for i, v := range row {
+			if i > 0 {
+				buf.WriteByte('\t')
+			}
+			if err := writeValue(&buf, v); err != nil {
+				return 0, fmt.Errorf("loaddata: %s: %s", columns[i], err)
+			}
+		}
+		buf.WriteByte('\n')
+	}
+
+	name := "reform-" + strconv.FormatUint(atomic.AddUint64(&handlers, 1), 10)
+	mysql.RegisterReaderHandler(name, func() io.Reader { return &buf })
+	defer mysql.DeregisterReaderHandler(name)
+
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = d.QuoteIdentifier(c)
+	}
+	query := fmt.Sprintf(`LOAD DATA LOCAL INFILE 'Reader::%s' INTO TABLE %s CHARACTER SET binary `+
+		`FIELDS TERMINATED BY '\t' ESCAPED BY '\\' LINES TERMINATED BY '\n' (%s)`,
+		name, d.QuoteIdentifier(table), strings.Join(quoted, ", "))
+	res, err := dbtx.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// escaper escapes special characters for LOAD DATA with default ESCAPED BY '\\'.
+var escaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`, "\x00", `\0`)
+
+// writeValue writes value in LOAD DATA format to buf.
+func writeValue(buf *bytes.Buffer, v interface{}) error {
+	v, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString(`\N`)
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case bool:
+		if v {
+			buf.WriteByte('1')
+		} else {
+			buf.WriteByte('0')
+		}
+	case []byte:
+		escaper.WriteString(buf, string(v))
+	case string:
+		escaper.WriteString(buf, v)
+	case time.Time:
+		buf.WriteString(v.UTC().Format("2006-01-02 15:04:05.999999"))
+	default:
+		return fmt.Errorf("unexpected type %T", v)
+	}
+	return nil
+}
+
+// Dialect implements reform.Dialect for MySQL with LOAD DATA support.
+var Dialect = loaddata{mysqldialect.Dialect}
+
+// check interfaces
+var (
+	_ reform.DDLDialect            = Dialect
+	_ reform.ConstraintDialect     = Dialect
+	_ reform.CopyFromDialect       = Dialect
+	_ reform.TimeoutDialect        = Dialect
+	_ reform.ExplainDialect        = Dialect
+	_ reform.RetryableDialect      = Dialect
+	_ reform.TransientErrorDialect = Dialect
+	_ reform.RowLockingDialect     = Dialect
+)
@@ -2,6 +2,11 @@
 package mysql // TODO add canonical import path via gopkg.in
 
 import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/AlekSi/reform"
 )
 
@@ -27,8 +32,159 @@ func (mysql) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.LastInsertId
 }
 
+func (mysql) UpsertMethod() reform.UpsertMethod {
+	return reform.OnDuplicateKey
+}
+
+func (mysql) BoolValue(b bool) interface{} {
+	if b {
+		return int64(1)
+	}
+	return int64(0)
+}
+
+func (mysql) IsConnectionError(err error) bool {
+	return reform.IsConnectionError(err)
+}
+
+func (mysql) MaxPlaceholders() int {
+	return 65535
+}
+
+var (
+	// errorNumber extracts error number from github.com/go-sql-driver/mysql ("Error 1062 (23000): ...")
+	// and github.com/ziutek/mymysql ("Received #1062 error from MySQL server: ...") error messages.
+	errorNumber = regexp.MustCompile(`(?:^Error |#)(\d+)\b`)
+
+	// keyName and constraintName extract unique key and foreign key names from error messages.
+	keyName        = regexp.MustCompile("for key '([^']+)'")
+	constraintName = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+)
+
+// ConstraintViolation detects unique (error 1062) and foreign key (errors 1451 and 1452) violations.
+func (mysql) ConstraintViolation(err error) (error, string) {
+	msg := err.Error()
+	m := errorNumber.FindStringSubmatch(msg)
+	if m == nil {
+		return nil, ""
+	}
+
+	var kind error
+	re := constraintName
+	switch m[1] {
+	case "1062":
+		kind = reform.ErrUniqueViolation
+		re = keyName
+	case "1451", "1452":
+		kind = reform.ErrForeignKeyViolation
+	default:
+		return nil, ""
+	}
+
+	var constraint string
+	if m = re.FindStringSubmatch(msg); m != nil {
+		constraint = m[1]
+	}
+	return kind, constraint
+}
+
+// IsRetryable returns true for deadlocks (error 1213) and lock wait timeouts (error 1205).
+func (mysql) IsRetryable(err error) bool {
+	m := errorNumber.FindStringSubmatch(err.Error())
+	return m != nil && (m[1] == "1213" || m[1] == "1205")
+}
+
+// IsTransientError returns true for server shutdown (error 1053) and lost connection errors
+// (client errors 2006 and 2013, and "invalid connection" of github.com/go-sql-driver/mysql).
+func (mysql) IsTransientError(err error) bool {
+	msg := err.Error()
+	if msg == "invalid connection" {
+		return true
+	}
+	m := errorNumber.FindStringSubmatch(msg)
+	return m != nil && (m[1] == "1053" || m[1] == "2006" || m[1] == "2013")
+}
+
+// TimeoutQuery adds MAX_EXECUTION_TIME optimizer hint to SELECT query.
+// Other statements can't be limited.
+func (mysql) TimeoutQuery(query string, d time.Duration) string {
+	const sel = "SELECT "
+	if len(query) < len(sel) || !strings.EqualFold(query[:len(sel)], sel) {
+		return query
+	}
+	ms := d.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	return query[:len(sel)] + "/*+ MAX_EXECUTION_TIME(" + strconv.FormatInt(ms, 10) + ") */ " + query[len(sel):]
+}
+
+// StatementTimeout returns empty string: max_execution_time can be set only for the whole session,
+// and it would remain set after transaction's connection is returned to the pool.
+func (mysql) StatementTimeout(d time.Duration) string {
+	return ""
+}
+
+// ExplainQuery returns "EXPLAIN" query.
+func (mysql) ExplainQuery(query string) string {
+	return "EXPLAIN " + query
+}
+
+// ColumnType returns MySQL type for column. Strings are VARCHAR(255), so they can be used in keys and indexes;
+// arrays are stored as TEXT.
+func (mysql) ColumnType(c reform.Column) string {
+	if c.Array {
+		return "TEXT"
+	}
+	var t string
+	switch c.Type {
+	case reform.BoolColumn:
+		t = "BOOL"
+	case reform.IntColumn:
+		t = "INT"
+	case reform.BigIntColumn:
+		t = "BIGINT"
+	case reform.FloatColumn:
+		t = "DOUBLE"
+	case reform.BytesColumn:
+		t = "LONGBLOB"
+	case reform.TimeColumn:
+		t = "DATETIME(6)"
+	case reform.JSONColumn:
+		t = "JSON"
+	default:
+		t = "VARCHAR(255)"
+	}
+	if c.AutoIncrement {
+		t += " AUTO_INCREMENT"
+	}
+	return t
+}
+
+// RowLockClause returns "LOCK IN SHARE MODE" for plain ForShare mode (supported by MySQL 5.7 and MariaDB too)
+// and standard clause for other modes. FOR SHARE with SKIP LOCKED or NOWAIT requires MySQL 8.0.
+func (mysql) RowLockClause(lock reform.RowLock) string {
+	if lock == reform.ForShare {
+		return "LOCK IN SHARE MODE"
+	}
+	return lock.Clause()
+}
+
 // Dialect implements reform.Dialect for MySQL.
 var Dialect mysql
 
-// check interface
-var _ reform.Dialect = Dialect
+func init() {
+	reform.RegisterDialect("mysql", Dialect)
+	reform.RegisterDialect("mymysql", Dialect)
+}
+
+// check interfaces
+var (
+	_ reform.ConstraintDialect     = Dialect
+	_ reform.TimeoutDialect        = Dialect
+	_ reform.ExplainDialect        = Dialect
+	_ reform.RetryableDialect      = Dialect
+	_ reform.DDLDialect            = Dialect
+	_ reform.TransientErrorDialect = Dialect
+	_ reform.RowLockingDialect     = Dialect
+)
@@ -0,0 +1,179 @@
+// Package oracle implements reform.Dialect for Oracle Database 12c+.
+//
+// Oracle folds unquoted identifiers to upper case, so this dialect upper-cases identifiers before quoting them:
+// lower-case names in reform tags and comments match tables and columns created without quotes.
+// Primary keys of inserted records are received with "RETURNING id INTO :n" clause and sql.Out parameter.
+// Oracle doesn't support LIMIT clause, so "FETCH FIRST n ROWS ONLY" is used instead;
+// note that it can't be combined with row-level locking clauses in the same query.
+// Multi-row INSERT requires Oracle 23ai. Upsert and "FOR SHARE" locking are not supported.
+// Also note that Oracle treats empty strings as NULL.
+//
+// Use it with github.com/sijms/go-ora/v2 driver registered as "oracle".
+package oracle // TODO add canonical import path via gopkg.in
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AlekSi/reform"
+)
+
+type oracle struct{}
+
+func (oracle) Placeholder(index int) string {
+	return ":" + strconv.Itoa(index)
+}
+
+func (oracle) Placeholders(start, count int) []string {
+	res := make([]string, count)
+	for i := 0; i < count; i++ {
+		res[i] = ":" + strconv.Itoa(start+i)
+	}
+	return res
+}
+
+// QuoteIdentifier returns upper-cased quoted identifier: people -> "PEOPLE".
+func (oracle) QuoteIdentifier(identifier string) string {
+	return `"` + strings.ToUpper(identifier) + `"`
+}
+
+func (oracle) LastInsertIdMethod() reform.LastInsertIdMethod {
+	return reform.ReturningInto
+}
+
+func (oracle) UpsertMethod() reform.UpsertMethod {
+	return reform.NoUpsert
+}
+
+// BoolValue returns 1 or 0: Oracle before 23ai has no boolean SQL type.
+func (oracle) BoolValue(b bool) interface{} {
+	if b {
+		return int64(1)
+	}
+	return int64(0)
+}
+
+func (oracle) IsConnectionError(err error) bool {
+	return reform.IsConnectionError(err)
+}
+
+// LimitClause returns "FETCH FIRST n ROWS ONLY" clause.
+func (oracle) LimitClause(limit int, ordered bool) string {
+	return "FETCH FIRST " + strconv.Itoa(limit) + " ROWS ONLY"
+}
+
+// RowLockClause returns standard clause for ForUpdate modes and empty string for ForShare ones.
+func (oracle) RowLockClause(lock reform.RowLock) string {
+	if lock&reform.ForShare != 0 {
+		return ""
+	}
+	return lock.Clause()
+}
+
+// MaxPlaceholders returns 999: Oracle limits IN lists to 1000 expressions.
+func (oracle) MaxPlaceholders() int {
+	return 999
+}
+
+var (
+	// errorCode extracts error code from error message like "ORA-00001: unique constraint (REFORM.PK) violated".
+	errorCode = regexp.MustCompile(`ORA-(\d{5})`)
+
+	// constraintName extracts constraint name from error message.
+	constraintName = regexp.MustCompile(`constraint \(([^)]+)\)`)
+)
+
+// ConstraintViolation detects unique (ORA-00001) and foreign key (ORA-02291 and ORA-02292) violations.
+func (oracle) ConstraintViolation(err error) (error, string) {
+	msg := err.Error()
+	m := errorCode.FindStringSubmatch(msg)
+	if m == nil {
+		return nil, ""
+	}
+
+	var kind error
+	switch m[1] {
+	case "00001":
+		kind = reform.ErrUniqueViolation
+	case "02291", "02292":
+		kind = reform.ErrForeignKeyViolation
+	default:
+		return nil, ""
+	}
+
+	var constraint string
+	if m = constraintName.FindStringSubmatch(msg); m != nil {
+		constraint = m[1]
+	}
+	return kind, constraint
+}
+
+// IsRetryable returns true for deadlocks (ORA-00060) and serialization failures (ORA-08177).
+func (oracle) IsRetryable(err error) bool {
+	m := errorCode.FindStringSubmatch(err.Error())
+	return m != nil && (m[1] == "00060" || m[1] == "08177")
+}
+
+// SavepointQuery returns standard "SAVEPOINT name" query.
+func (oracle) SavepointQuery(name string) string {
+	return "SAVEPOINT " + name
+}
+
+// RollbackToSavepointQuery returns standard "ROLLBACK TO SAVEPOINT name" query.
+func (oracle) RollbackToSavepointQuery(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// ReleaseSavepointQuery returns empty string: Oracle doesn't release savepoints.
+func (oracle) ReleaseSavepointQuery(name string) string {
+	return ""
+}
+
+// ColumnType returns Oracle type for column: NUMBER for integers and booleans (as 0 and 1),
+// VARCHAR2(255) for strings, CLOB for arrays and JSON; auto-incremented primary keys are identity columns.
+func (oracle) ColumnType(c reform.Column) string {
+	if c.Array {
+		return "CLOB"
+	}
+	var t string
+	switch c.Type {
+	case reform.BoolColumn:
+		t = "NUMBER(1)"
+	case reform.IntColumn:
+		t = "NUMBER(10)"
+	case reform.BigIntColumn:
+		t = "NUMBER(19)"
+	case reform.FloatColumn:
+		t = "BINARY_DOUBLE"
+	case reform.BytesColumn:
+		t = "BLOB"
+	case reform.TimeColumn:
+		t = "TIMESTAMP"
+	case reform.JSONColumn:
+		t = "CLOB"
+	default:
+		t = "VARCHAR2(255)"
+	}
+	if c.AutoIncrement {
+		t += " GENERATED BY DEFAULT AS IDENTITY"
+	}
+	return t
+}
+
+// Dialect implements reform.Dialect for Oracle Database.
+var Dialect oracle
+
+func init() {
+	reform.RegisterDialect("oracle", Dialect)
+}
+
+// check interfaces
+var (
+	_ reform.ConstraintDialect = Dialect
+	_ reform.LimitDialect      = Dialect
+	_ reform.RowLockingDialect = Dialect
+	_ reform.RetryableDialect  = Dialect
+	_ reform.SavepointDialect  = Dialect
+	_ reform.DDLDialect        = Dialect
+)
@@ -0,0 +1,32 @@
+package postgresql
+
+import (
+	"github.com/AlekSi/reform"
+)
+
+// RefreshMaterializedView replaces contents of materialized view with "REFRESH MATERIALIZED VIEW" statement.
+// View is usually generated for a struct marked as view in magic comment:
+//
+//	//reform:monthly_sales view
+//	type MonthlySales struct {
+//		Month string `reform:"month"`
+//		Total int64  `reform:"total"`
+//	}
+//
+//	err = postgresql.RefreshMaterializedView(DB.Querier, MonthlySalesView)
+//
+// Reads of the view are blocked while it is refreshed, see RefreshMaterializedViewConcurrently.
+func RefreshMaterializedView(q *reform.Querier, view reform.View) error {
+	return refreshMaterializedView(q, view, "")
+}
+
+// RefreshMaterializedViewConcurrently refreshes materialized view without blocking concurrent reads of it
+// with "REFRESH MATERIALIZED VIEW CONCURRENTLY" statement. It requires unique index on the view.
+func RefreshMaterializedViewConcurrently(q *reform.Querier, view reform.View) error {
+	return refreshMaterializedView(q, view, "CONCURRENTLY ")
+}
+
+func refreshMaterializedView(q *reform.Querier, view reform.View, concurrently string) error {
+	_, err := q.Exec("REFRESH MATERIALIZED VIEW " + concurrently + q.QualifiedView(view))
+	return err
+}
@@ -0,0 +1,75 @@
+// Package pgxcopy implements reform.Dialect for PostgreSQL with github.com/jackc/pgx driver
+// (registered by github.com/jackc/pgx/stdlib as "pgx"). It is the same as postgresql.Dialect,
+// but Querier.CopyFrom and BulkCopy load rows with pgx's CopyFrom (COPY protocol).
+//
+// database/sql doesn't expose pgx's transaction, so CopyFrom is used only outside of transactions;
+// multi-row INSERT statements are used inside them. pgx's CopyFrom doesn't accept context,
+// so it can't be canceled once started.
+package pgxcopy // TODO add canonical import path via gopkg.in
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx"
+	"github.com/jackc/pgx/stdlib"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+)
+
+// postgresqlDialect contains optional interfaces of postgresql.Dialect used by pgxcopy.
+// reform.CopyInDialect is not included: it uses github.com/lib/pq.
+type postgresqlDialect interface {
+	reform.DDLDialect
+	reform.ConstraintDialect
+	reform.TimeoutDialect
+	reform.ExplainDialect
+	reform.RetryableDialect
+	reform.TransientErrorDialect
+	reform.TruncateDialect
+}
+
+type pgxcopy struct {
+	postgresqlDialect
+}
+
+// CanCopyFrom returns true for *sql.DB.
+func (pgxcopy) CanCopyFrom(dbtx reform.DBTXContext) bool {
+	_, ok := dbtx.(*sql.DB)
+	return ok
+}
+
+// CopyFrom loads rows into given table columns with pgx's CopyFrom.
+func (pgxcopy) CopyFrom(ctx context.Context, dbtx reform.DBTXContext, table string, columns []string, rows [][]interface{}) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	db := dbtx.(*sql.DB)
+	conn, err := stdlib.AcquireConn(db)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := conn.CopyFrom(pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+	if e := stdlib.ReleaseConn(db, conn); err == nil {
+		err = e
+	}
+	return int64(n), err
+}
+
+// Dialect implements reform.Dialect for PostgreSQL with pgx driver.
+var Dialect = pgxcopy{postgresql.Dialect}
+
+// check interfaces
+var (
+	_ reform.DDLDialect            = Dialect
+	_ reform.ConstraintDialect     = Dialect
+	_ reform.CopyFromDialect       = Dialect
+	_ reform.TimeoutDialect        = Dialect
+	_ reform.ExplainDialect        = Dialect
+	_ reform.RetryableDialect      = Dialect
+	_ reform.TransientErrorDialect = Dialect
+	_ reform.TruncateDialect       = Dialect
+)
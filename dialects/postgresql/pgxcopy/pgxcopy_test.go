@@ -0,0 +1,46 @@
+package pgxcopy_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/jackc/pgx/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql/pgxcopy"
+	"github.com/AlekSi/reform/internal/test/models"
+)
+
+func TestCopyFrom(t *testing.T) {
+	if os.Getenv("REFORM_TEST_DRIVER") != "pgx" {
+		t.Skip("REFORM_TEST_DRIVER is not pgx")
+	}
+
+	sqlDB, err := sql.Open("pgx", os.Getenv("REFORM_TEST_SOURCE"))
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db := reform.NewDB(sqlDB, pgxcopy.Dialect, reform.NewPrintfLogger(t.Logf))
+
+	r := reform.NewQueryRecorder(1)
+	db.Querier = db.WithQueryRecorder(r)
+	_, err = db.DeleteFrom(models.PersonTable, "WHERE name = $1", "pgx Copied Person")
+	require.NoError(t, err)
+	defer db.DeleteFrom(models.PersonTable, "WHERE name = $1", "pgx Copied Person")
+
+	structs := make([]reform.Struct, 25)
+	for i := range structs {
+		structs[i] = &models.Person{Name: "pgx Copied Person"}
+	}
+	n, err := db.CopyFrom(models.PersonTable, reform.CopySlice(structs))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(structs)), n)
+	require.Len(t, r.Statements(), 1)
+	assert.Regexp(t, `^COPY "people" \(.+\) FROM reform.CopySource$`, r.Statements()[0].Query)
+
+	count, err := db.Count(models.PersonTable, "WHERE name = $1", "pgx Copied Person")
+	require.NoError(t, err)
+	assert.Equal(t, uint(len(structs)), count)
+}
@@ -2,7 +2,13 @@
 package postgresql // TODO add canonical import path via gopkg.in
 
 import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/AlekSi/reform"
 )
@@ -29,8 +35,182 @@ func (postgresql) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.Returning
 }
 
+func (postgresql) UpsertMethod() reform.UpsertMethod {
+	return reform.OnConflict
+}
+
+func (postgresql) BoolValue(b bool) interface{} {
+	return b
+}
+
+func (postgresql) IsConnectionError(err error) bool {
+	return reform.IsConnectionError(err)
+}
+
+// sqlStateError is implemented by github.com/lib/pq and github.com/jackc/pgx errors.
+type sqlStateError interface {
+	SQLState() string
+}
+
+// constraintName extracts constraint name from error message.
+var constraintName = regexp.MustCompile(`constraint "([^"]+)"`)
+
+// ConstraintViolation detects unique (SQLSTATE 23505) and foreign key (SQLSTATE 23503) violations.
+func (postgresql) ConstraintViolation(err error) (error, string) {
+	var se sqlStateError
+	if !errors.As(err, &se) {
+		return nil, ""
+	}
+
+	var kind error
+	switch se.SQLState() {
+	case "23505":
+		kind = reform.ErrUniqueViolation
+	case "23503":
+		kind = reform.ErrForeignKeyViolation
+	default:
+		return nil, ""
+	}
+
+	var constraint string
+	if m := constraintName.FindStringSubmatch(err.Error()); m != nil {
+		constraint = m[1]
+	}
+	return kind, constraint
+}
+
+// IsRetryable returns true for serialization failures (SQLSTATE 40001) and deadlocks (SQLSTATE 40P01).
+func (postgresql) IsRetryable(err error) bool {
+	var se sqlStateError
+	if !errors.As(err, &se) {
+		return false
+	}
+	switch se.SQLState() {
+	case "40001", "40P01":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTransientError returns true for connection exceptions (SQLSTATE class 08)
+// and server shutdowns (SQLSTATE 57P01, 57P02 and 57P03) which happen during failover.
+func (postgresql) IsTransientError(err error) bool {
+	var se sqlStateError
+	if !errors.As(err, &se) {
+		return false
+	}
+	code := se.SQLState()
+	return strings.HasPrefix(code, "08") || code == "57P01" || code == "57P02" || code == "57P03"
+}
+
+// TruncateQuery returns "TRUNCATE TABLE" statement with "RESTART IDENTITY" and "CASCADE" options.
+func (postgresql) TruncateQuery(table string, options reform.TruncateOption) string {
+	query := "TRUNCATE TABLE " + table
+	if options&reform.RestartIdentity != 0 {
+		query += " RESTART IDENTITY"
+	}
+	if options&reform.Cascade != 0 {
+		query += " CASCADE"
+	}
+	return query
+}
+
+// CanCopyIn returns true for github.com/lib/pq driver.
+func (postgresql) CanCopyIn(d driver.Driver) bool {
+	if d == nil {
+		return false
+	}
+	t := reflect.TypeOf(d)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath() == "github.com/lib/pq"
+}
+
+// CopyIn returns "COPY FROM STDIN" query for bulk loading of rows into given table columns.
+// It requires github.com/lib/pq driver.
+func (d postgresql) CopyIn(table string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = d.QuoteIdentifier(c)
+	}
+	return "COPY " + d.QuoteIdentifier(table) + " (" + strings.Join(quoted, ", ") + ") FROM STDIN"
+}
+
+func (postgresql) MaxPlaceholders() int {
+	return 65535
+}
+
+// TimeoutQuery returns query as is: PostgreSQL has no per-query execution time limit.
+func (postgresql) TimeoutQuery(query string, d time.Duration) string {
+	return query
+}
+
+// StatementTimeout returns "SET LOCAL statement_timeout" statement.
+func (postgresql) StatementTimeout(d time.Duration) string {
+	ms := d.Milliseconds()
+	if ms < 1 {
+		ms = 1 // zero disables timeout
+	}
+	return "SET LOCAL statement_timeout = " + strconv.FormatInt(ms, 10)
+}
+
+// ExplainQuery returns "EXPLAIN" query.
+func (postgresql) ExplainQuery(query string) string {
+	return "EXPLAIN " + query
+}
+
+// ColumnType returns PostgreSQL type for column: SERIAL and BIGSERIAL for auto-incremented primary keys,
+// TIMESTAMP WITH TIME ZONE for time, JSONB for JSON and arrays for array columns.
+func (postgresql) ColumnType(c reform.Column) string {
+	var t string
+	switch c.Type {
+	case reform.BoolColumn:
+		t = "BOOLEAN"
+	case reform.IntColumn:
+		if c.AutoIncrement {
+			return "SERIAL"
+		}
+		t = "INTEGER"
+	case reform.BigIntColumn:
+		if c.AutoIncrement {
+			return "BIGSERIAL"
+		}
+		t = "BIGINT"
+	case reform.FloatColumn:
+		t = "DOUBLE PRECISION"
+	case reform.BytesColumn:
+		t = "BYTEA"
+	case reform.TimeColumn:
+		t = "TIMESTAMP WITH TIME ZONE"
+	case reform.JSONColumn:
+		t = "JSONB"
+	default:
+		t = "VARCHAR"
+	}
+	if c.Array {
+		t += "[]"
+	}
+	return t
+}
+
 // Dialect implements reform.Dialect for PostgreSQL.
 var Dialect postgresql
 
-// check interface
-var _ reform.Dialect = Dialect
+func init() {
+	reform.RegisterDialect("postgres", Dialect)
+	reform.RegisterDialect("pgx", Dialect)
+}
+
+// check interfaces
+var (
+	_ reform.CopyInDialect         = Dialect
+	_ reform.DDLDialect            = Dialect
+	_ reform.ConstraintDialect     = Dialect
+	_ reform.TimeoutDialect        = Dialect
+	_ reform.ExplainDialect        = Dialect
+	_ reform.RetryableDialect      = Dialect
+	_ reform.TransientErrorDialect = Dialect
+	_ reform.TruncateDialect       = Dialect
+)
@@ -0,0 +1,135 @@
+// Package snowflake implements reform.Dialect for Snowflake.
+//
+// It is intended for reusing generated models with warehouse tables: selecting, inserting (use Querier.InsertMulti
+// or Querier.BulkCopy for batches), updating and deleting rows. Snowflake folds unquoted identifiers to upper case,
+// so this dialect upper-cases identifiers without upper-case letters before quoting them: lower-case names
+// in reform tags and comments match tables and columns created without quotes, while mixed-case names
+// like "MyColumn" are quoted as is and match ones created with quotes.
+//
+// Snowflake has no RETURNING clause, and RESULT_SCAN(LAST_QUERY_ID()) for INSERT statement returns
+// only a number of inserted rows, not values of identity columns, so primary keys of inserted records
+// can't be received. They are never set by reform: application should set them, for example, with "generated"
+// label (see Querier.WithIDGenerator) or with NextVal in BeforeInserterTx hook.
+//
+// Unique and foreign key constraints are not enforced by Snowflake, so ConstraintDialect is not implemented.
+// Upsert (MERGE) and row-level locking are not supported.
+//
+// Use it with github.com/snowflakedb/gosnowflake driver registered as "snowflake".
+package snowflake // TODO add canonical import path via gopkg.in
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/AlekSi/reform"
+)
+
+type snowflake struct{}
+
+func (snowflake) Placeholder(index int) string {
+	return "?"
+}
+
+func (snowflake) Placeholders(start, count int) []string {
+	res := make([]string, count)
+	for i := 0; i < count; i++ {
+		res[i] = "?"
+	}
+	return res
+}
+
+// QuoteIdentifier returns quoted identifier, upper-cased if it has no upper-case letters:
+// people -> "PEOPLE", MyColumn -> "MyColumn".
+func (snowflake) QuoteIdentifier(identifier string) string {
+	if strings.IndexFunc(identifier, unicode.IsUpper) < 0 {
+		identifier = strings.ToUpper(identifier)
+	}
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// LastInsertIdMethod returns NoLastInsertId: RESULT_SCAN(LAST_QUERY_ID()) can't be used to emulate RETURNING clause,
+// see package documentation.
+func (snowflake) LastInsertIdMethod() reform.LastInsertIdMethod {
+	return reform.NoLastInsertId
+}
+
+func (snowflake) UpsertMethod() reform.UpsertMethod {
+	return reform.NoUpsert
+}
+
+func (snowflake) BoolValue(b bool) interface{} {
+	return b
+}
+
+func (snowflake) IsConnectionError(err error) bool {
+	return reform.IsConnectionError(err)
+}
+
+// MaxPlaceholders returns 16384: Snowflake limits a number of expressions in a list.
+func (snowflake) MaxPlaceholders() int {
+	return 16384
+}
+
+// RowLockClause returns empty string: Snowflake doesn't support row-level locking.
+func (snowflake) RowLockClause(lock reform.RowLock) string {
+	return ""
+}
+
+// NextVal returns the next value of given sequence. It may be used to set primary keys of records before insert:
+//
+//	func (o *Order) BeforeInsertTx(q *reform.Querier) error {
+//		if o.ID == 0 {
+//			id, err := snowflake.NextVal(q, "orders_seq")
+//			o.ID = id
+//			return err
+//		}
+//		return nil
+//	}
+func NextVal(q *reform.Querier, sequence string) (int64, error) {
+	var id int64
+	err := q.QueryRow("SELECT " + q.QuoteIdentifier(sequence) + ".NEXTVAL").Scan(&id)
+	return id, err
+}
+
+// ColumnType returns Snowflake type for column: VARCHAR for strings, arrays and JSON,
+// TIMESTAMP_TZ for times; auto-incremented primary keys use AUTOINCREMENT (but see package documentation).
+func (snowflake) ColumnType(c reform.Column) string {
+	if c.Array {
+		return "VARCHAR"
+	}
+	var t string
+	switch c.Type {
+	case reform.BoolColumn:
+		t = "BOOLEAN"
+	case reform.IntColumn:
+		t = "INTEGER"
+	case reform.BigIntColumn:
+		t = "BIGINT"
+	case reform.FloatColumn:
+		t = "FLOAT"
+	case reform.BytesColumn:
+		t = "BINARY"
+	case reform.TimeColumn:
+		t = "TIMESTAMP_TZ"
+	default:
+		t = "VARCHAR"
+	}
+	if c.AutoIncrement {
+		t += " AUTOINCREMENT"
+	}
+	return t
+}
+
+// Dialect implements reform.Dialect for Snowflake.
+var Dialect snowflake
+
+func init() {
+	reform.RegisterDialect("snowflake", Dialect)
+}
+
+// check interfaces
+var (
+	_ reform.Dialect           = Dialect
+	_ reform.RowLockingDialect = Dialect
+	_ reform.DDLDialect        = Dialect
+)
@@ -0,0 +1,133 @@
+// Package spanner implements reform.Dialect for Google Cloud Spanner databases with GoogleSQL dialect
+// (use postgresql.Dialect for PostgreSQL-dialect databases).
+//
+// Query parameters are named @p1, @p2, etc. Identifiers are not quoted unless they are reserved keywords
+// (like `end`), so queries look like the ones written by hand. Insert uses plain DML for records
+// with primary key set by application, which is the usual case for Spanner (UUIDs, see "generated" label
+// and Querier.WithIDGenerator); for records without primary key it uses "THEN RETURN id" clause to receive
+// primary key generated by column default or sequence. Querier.UpdateReturning and Querier.DeleteReturning
+// use "THEN RETURN" clause too. Upsert ("INSERT OR UPDATE") and "FOR SHARE" locking are not supported.
+//
+// Rows of interleaved tables are stored with their parent rows: insert parent row before child rows
+// (Querier.InsertMulti and Querier.SaveAll don't reorder rows of different tables), and note that deleting
+// parent row deletes child rows only with "ON DELETE CASCADE", otherwise it fails. Reform doesn't know about
+// interleaving, so relations and preloading work as usual, by foreign key-like columns.
+//
+// DML statements in read-write transactions are limited by mutation count (80 000 per commit,
+// each inserted or updated column counts), so split large batches into several transactions.
+//
+// Use it with github.com/googleapis/go-sql-spanner driver registered as "spanner".
+package spanner // TODO add canonical import path via gopkg.in
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AlekSi/reform"
+)
+
+type spanner struct{}
+
+func (spanner) Placeholder(index int) string {
+	return "@p" + strconv.Itoa(index)
+}
+
+func (spanner) Placeholders(start, count int) []string {
+	res := make([]string, count)
+	for i := 0; i < count; i++ {
+		res[i] = "@p" + strconv.Itoa(start+i)
+	}
+	return res
+}
+
+// identifier matches identifiers which can be used without quoting.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reserved contains GoogleSQL reserved keywords in upper case.
+var reserved = func() map[string]struct{} {
+	res := make(map[string]struct{})
+	for _, k := range strings.Fields(`ALL AND ANY ARRAY AS ASC ASSERT_ROWS_MODIFIED AT BETWEEN BY CASE CAST COLLATE
+		CONTAINS CREATE CROSS CUBE CURRENT DEFAULT DEFINE DESC DISTINCT ELSE END ENUM ESCAPE EXCEPT EXCLUDE EXISTS
+		EXTRACT FALSE FETCH FOLLOWING FOR FROM FULL GROUP GROUPING GROUPS HASH HAVING IF IGNORE IN INNER INTERSECT
+		INTERVAL INTO IS JOIN LATERAL LEFT LIKE LIMIT LOOKUP MERGE NATURAL NEW NO NOT NULL NULLS OF ON OR ORDER
+		OUTER OVER PARTITION PRECEDING PROTO RANGE RECURSIVE RESPECT RIGHT ROLLUP ROWS SELECT SET SOME STRUCT
+		TABLESAMPLE THEN TO TREAT TRUE UNBOUNDED UNION UNNEST USING WHEN WHERE WINDOW WITH WITHIN`) {
+		res[k] = struct{}{}
+	}
+	return res
+}()
+
+// QuoteIdentifier returns identifier as is, or quoted with backticks if it is a reserved keyword
+// or contains other characters than letters, digits and underscores: people -> people, end -> `end`.
+func (spanner) QuoteIdentifier(ident string) string {
+	if _, ok := reserved[strings.ToUpper(ident)]; !ok && identifier.MatchString(ident) {
+		return ident
+	}
+	return "`" + strings.ReplaceAll(ident, "`", "\\`") + "`"
+}
+
+func (spanner) LastInsertIdMethod() reform.LastInsertIdMethod {
+	return reform.ThenReturn
+}
+
+func (spanner) UpsertMethod() reform.UpsertMethod {
+	return reform.NoUpsert
+}
+
+func (spanner) BoolValue(b bool) interface{} {
+	return b
+}
+
+func (spanner) IsConnectionError(err error) bool {
+	return reform.IsConnectionError(err)
+}
+
+// MaxPlaceholders returns 950: Spanner limits a number of parameters per query.
+func (spanner) MaxPlaceholders() int {
+	return 950
+}
+
+// RowLockClause returns "FOR UPDATE" clause for ForUpdate mode and empty string for others:
+// Spanner supports neither "FOR SHARE", nor "SKIP LOCKED" and "NOWAIT".
+func (spanner) RowLockClause(lock reform.RowLock) string {
+	if lock != reform.ForUpdate {
+		return ""
+	}
+	return lock.Clause()
+}
+
+// IsRetryable returns true for aborted transactions (gRPC code ABORTED): Spanner aborts read-write transactions
+// on lock conflicts and expects client to retry them. Spanner client errors look like
+// `spanner: code = "Aborted", desc = "..."`.
+func (spanner) IsRetryable(err error) bool {
+	return strings.Contains(err.Error(), `code = "Aborted"`)
+}
+
+// TruncateQuery returns "DELETE FROM ... WHERE true" statement: Spanner has no TRUNCATE,
+// and requires WHERE clause for DELETE. Options are ignored.
+func (spanner) TruncateQuery(table string, options reform.TruncateOption) string {
+	return "DELETE FROM " + table + " WHERE true"
+}
+
+// ColumnType returns empty string: Spanner requires PRIMARY KEY clause after column definitions,
+// so CREATE TABLE is not supported.
+func (spanner) ColumnType(c reform.Column) string {
+	return ""
+}
+
+// Dialect implements reform.Dialect for Google Cloud Spanner.
+var Dialect spanner
+
+func init() {
+	reform.RegisterDialect("spanner", Dialect)
+}
+
+// check interfaces
+var (
+	_ reform.Dialect           = Dialect
+	_ reform.RowLockingDialect = Dialect
+	_ reform.RetryableDialect  = Dialect
+	_ reform.TruncateDialect   = Dialect
+	_ reform.DDLDialect        = Dialect
+)
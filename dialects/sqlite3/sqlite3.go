@@ -1,7 +1,15 @@
 // Package sqlite3 implements reform.Dialect for SQLite3.
+//
+// Upsert uses "ON CONFLICT" clause (SQLite 3.24+) and "RETURNING" clause for records without primary key
+// (SQLite 3.35+). SQLite allows only one writer at a time: concurrent writers fail with "database is locked"
+// (SQLITE_BUSY) error after driver's busy timeout. Querier retries statements failed with that error by default
+// (see IsBusy). Use EnableWAL to let readers work concurrently with a writer.
 package sqlite3 // TODO add canonical import path via gopkg.in
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/AlekSi/reform"
 )
 
@@ -27,8 +35,125 @@ func (sqlite3) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.LastInsertId
 }
 
+func (sqlite3) UpsertMethod() reform.UpsertMethod {
+	return reform.OnConflict
+}
+
+func (sqlite3) BoolValue(b bool) interface{} {
+	if b {
+		return int64(1)
+	}
+	return int64(0)
+}
+
+func (sqlite3) IsConnectionError(err error) bool {
+	return reform.IsConnectionError(err)
+}
+
+// MaxPlaceholders returns the limit of SQLite before 3.32.0, which is lower than for newer versions.
+func (sqlite3) MaxPlaceholders() int {
+	return 999
+}
+
+// RowLockClause returns empty string: SQLite doesn't support row-level locking,
+// writing transactions lock the whole database instead.
+func (sqlite3) RowLockClause(lock reform.RowLock) string {
+	return ""
+}
+
+// ConstraintViolation detects unique (including primary key) and foreign key violations by error message.
+// SQLite doesn't report constraint names: for unique violations constraint is a list of columns
+// like "people.email", for foreign key violations it is empty.
+func (sqlite3) ConstraintViolation(err error) (error, string) {
+	msg := err.Error()
+	const unique = "UNIQUE constraint failed: "
+	if i := strings.Index(msg, unique); i >= 0 {
+		return reform.ErrUniqueViolation, msg[i+len(unique):]
+	}
+	if strings.Contains(msg, "FOREIGN KEY constraint failed") {
+		return reform.ErrForeignKeyViolation, ""
+	}
+	return nil, ""
+}
+
+// IsRetryable returns true for "database is locked" (SQLITE_BUSY) errors.
+func (sqlite3) IsRetryable(err error) bool {
+	return strings.Contains(err.Error(), "database is locked")
+}
+
+// IsBusy returns true for "database is locked" (SQLITE_BUSY) errors, so statements failed with them are retried
+// by Querier outside of transactions: up to 5 attempts by default, or with retry policy set by WithTransientRetries:
+//
+//	q := DB.WithTransientRetries(&reform.RetryPolicy{Attempts: 10, MaxBackoff: 5 * time.Second})
+//
+// Transactions are not retried statement by statement: use DB.InTransactionContext with RetryPolicy for them.
+func (d sqlite3) IsBusy(err error) bool {
+	return d.IsRetryable(err)
+}
+
+// EnableWAL switches database to write-ahead log journal mode, which allows readers to work concurrently
+// with a writer. Journal mode is persistent, so it is enough to do it once per database file.
+// It returns error if mode can't be changed, for example, for in-memory database.
+func EnableWAL(q *reform.Querier) error {
+	var mode string
+	if err := q.QueryRow("PRAGMA journal_mode = WAL").Scan(&mode); err != nil {
+		return err
+	}
+	if !strings.EqualFold(mode, "wal") {
+		return fmt.Errorf("sqlite3: failed to enable WAL, journal mode is %s", mode)
+	}
+	return nil
+}
+
+// ExplainQuery returns "EXPLAIN QUERY PLAN" query.
+func (sqlite3) ExplainQuery(query string) string {
+	return "EXPLAIN QUERY PLAN " + query
+}
+
+// ColumnType returns SQLite3 type for column. Auto-incremented primary key is INTEGER:
+// INTEGER PRIMARY KEY column is an alias for ROWID and is set by SQLite3.
+func (sqlite3) ColumnType(c reform.Column) string {
+	if c.Array {
+		return "TEXT"
+	}
+	switch c.Type {
+	case reform.BoolColumn:
+		return "BOOLEAN"
+	case reform.IntColumn, reform.BigIntColumn:
+		return "INTEGER"
+	case reform.FloatColumn:
+		return "REAL"
+	case reform.BytesColumn:
+		return "BLOB"
+	case reform.TimeColumn:
+		return "DATETIME"
+	case reform.JSONColumn:
+		return "TEXT"
+	default:
+		return "VARCHAR"
+	}
+}
+
+// TruncateQuery returns "DELETE FROM" statement: SQLite3 has no TRUNCATE, but optimizes DELETE without WHERE.
+// Options are ignored.
+func (sqlite3) TruncateQuery(table string, options reform.TruncateOption) string {
+	return "DELETE FROM " + table
+}
+
 // Dialect implements reform.Dialect for SQLite3.
 var Dialect sqlite3
 
-// check interface
-var _ reform.Dialect = Dialect
+func init() {
+	reform.RegisterDialect("sqlite3", Dialect)
+}
+
+// check interfaces
+var (
+	_ reform.ConstraintDialect = Dialect
+	_ reform.RowLockingDialect = Dialect
+	_ reform.ExplainDialect    = Dialect
+	_ reform.RetryableDialect  = Dialect
+	_ reform.BusyErrorDialect  = Dialect
+	_ reform.DDLDialect        = Dialect
+	_ reform.TruncateDialect   = Dialect
+)
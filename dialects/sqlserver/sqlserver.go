@@ -0,0 +1,180 @@
+// Package sqlserver implements reform.Dialect for Microsoft SQL Server.
+//
+// Primary keys of inserted records are received with "OUTPUT INSERTED.id" clause.
+// SQL Server doesn't support LIMIT clause: "SELECT TOP n" can't be expressed in a query tail,
+// so Tail.Limit and other helpers use "OFFSET 0 ROWS FETCH NEXT n ROWS ONLY" clause (SQL Server 2012+),
+// with "ORDER BY (SELECT NULL)" added for queries without ORDER BY.
+// Upsert is not supported; row-level locking clauses are not supported (use table hints in queries instead).
+//
+// Use it with github.com/denisenkom/go-mssqldb driver registered as "sqlserver".
+package sqlserver // TODO add canonical import path via gopkg.in
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AlekSi/reform"
+)
+
+type sqlserver struct{}
+
+func (sqlserver) Placeholder(index int) string {
+	return "@p" + strconv.Itoa(index)
+}
+
+func (sqlserver) Placeholders(start, count int) []string {
+	res := make([]string, count)
+	for i := 0; i < count; i++ {
+		res[i] = "@p" + strconv.Itoa(start+i)
+	}
+	return res
+}
+
+func (sqlserver) QuoteIdentifier(identifier string) string {
+	return "[" + identifier + "]"
+}
+
+func (sqlserver) LastInsertIdMethod() reform.LastInsertIdMethod {
+	return reform.OutputInserted
+}
+
+func (sqlserver) UpsertMethod() reform.UpsertMethod {
+	return reform.NoUpsert
+}
+
+func (sqlserver) BoolValue(b bool) interface{} {
+	return b
+}
+
+func (sqlserver) IsConnectionError(err error) bool {
+	return reform.IsConnectionError(err)
+}
+
+// LimitClause returns "OFFSET 0 ROWS FETCH NEXT n ROWS ONLY" clause,
+// prepended with "ORDER BY (SELECT NULL)" if query is not ordered.
+func (sqlserver) LimitClause(limit int, ordered bool) string {
+	res := "OFFSET 0 ROWS FETCH NEXT " + strconv.Itoa(limit) + " ROWS ONLY"
+	if !ordered {
+		res = "ORDER BY (SELECT NULL) " + res
+	}
+	return res
+}
+
+// RowLockClause returns empty string: SQL Server uses table hints like WITH (UPDLOCK) instead.
+func (sqlserver) RowLockClause(lock reform.RowLock) string {
+	return ""
+}
+
+// MaxPlaceholders returns 2000: SQL Server's limit is 2100 parameters per request.
+// Note that multi-row INSERT is also limited to 1000 rows.
+func (sqlserver) MaxPlaceholders() int {
+	return 2000
+}
+
+// ColumnType returns SQL Server type for column: IDENTITY is used for auto-incremented primary keys,
+// NVARCHAR(255) for strings (so they can be used in keys and indexes), NVARCHAR(MAX) for JSON and arrays.
+func (sqlserver) ColumnType(c reform.Column) string {
+	if c.Array {
+		return "NVARCHAR(MAX)"
+	}
+	var t string
+	switch c.Type {
+	case reform.BoolColumn:
+		t = "BIT"
+	case reform.IntColumn:
+		t = "INT"
+	case reform.BigIntColumn:
+		t = "BIGINT"
+	case reform.FloatColumn:
+		t = "FLOAT"
+	case reform.BytesColumn:
+		t = "VARBINARY(MAX)"
+	case reform.TimeColumn:
+		t = "DATETIME2"
+	case reform.JSONColumn:
+		t = "NVARCHAR(MAX)"
+	default:
+		t = "NVARCHAR(255)"
+	}
+	if c.AutoIncrement {
+		t += " IDENTITY(1,1)"
+	}
+	return t
+}
+
+// sqlError is implemented by github.com/denisenkom/go-mssqldb errors.
+type sqlError interface {
+	SQLErrorNumber() int32
+}
+
+// constraintName extracts constraint or index name from error message.
+var constraintName = regexp.MustCompile(`(?:constraint|index) ['"]([^'"]+)['"]`)
+
+// ConstraintViolation detects unique (errors 2627 and 2601) and foreign key (error 547) violations.
+func (sqlserver) ConstraintViolation(err error) (error, string) {
+	var se sqlError
+	if !errors.As(err, &se) {
+		return nil, ""
+	}
+
+	msg := err.Error()
+	var kind error
+	switch se.SQLErrorNumber() {
+	case 2627, 2601:
+		kind = reform.ErrUniqueViolation
+	case 547:
+		// also used for CHECK constraints
+		if !strings.Contains(msg, "FOREIGN KEY") && !strings.Contains(msg, "REFERENCE") {
+			return nil, ""
+		}
+		kind = reform.ErrForeignKeyViolation
+	default:
+		return nil, ""
+	}
+
+	var constraint string
+	if m := constraintName.FindStringSubmatch(msg); m != nil {
+		constraint = m[1]
+	}
+	return kind, constraint
+}
+
+// IsRetryable returns true if transaction was chosen as a deadlock victim (error 1205).
+func (sqlserver) IsRetryable(err error) bool {
+	var se sqlError
+	return errors.As(err, &se) && se.SQLErrorNumber() == 1205
+}
+
+// SavepointQuery returns "SAVE TRANSACTION name" query.
+func (sqlserver) SavepointQuery(name string) string {
+	return "SAVE TRANSACTION " + name
+}
+
+// RollbackToSavepointQuery returns "ROLLBACK TRANSACTION name" query.
+func (sqlserver) RollbackToSavepointQuery(name string) string {
+	return "ROLLBACK TRANSACTION " + name
+}
+
+// ReleaseSavepointQuery returns empty string: SQL Server doesn't release savepoints.
+func (sqlserver) ReleaseSavepointQuery(name string) string {
+	return ""
+}
+
+// Dialect implements reform.Dialect for Microsoft SQL Server.
+var Dialect sqlserver
+
+func init() {
+	reform.RegisterDialect("sqlserver", Dialect)
+}
+
+// check interfaces
+var (
+	_ reform.ConstraintDialect = Dialect
+	_ reform.LimitDialect      = Dialect
+	_ reform.RowLockingDialect = Dialect
+	_ reform.RetryableDialect  = Dialect
+	_ reform.SavepointDialect  = Dialect
+	_ reform.DDLDialect        = Dialect
+)
@@ -0,0 +1,161 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Statement is an SQL statement with arguments captured by DryRun.
+type Statement struct {
+	Query string
+	Args  []interface{}
+}
+
+// DryRun captures SQL statements of querier returned by Querier.WithDryRun instead of executing them.
+// It is safe for concurrent use.
+type DryRun struct {
+	m          sync.Mutex
+	statements []Statement
+	db         *sql.DB
+}
+
+// NewDryRun creates new DryRun. Close should be called when it is no longer needed.
+func NewDryRun() *DryRun {
+	return &DryRun{
+		db: sql.OpenDB(dryRunConnector{}),
+	}
+}
+
+// Close closes underlying fake *sql.DB. Queriers with this DryRun can't be used after that.
+func (d *DryRun) Close() error {
+	return d.db.Close()
+}
+
+// Statements returns captured statements in execution order.
+func (d *DryRun) Statements() []Statement {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	res := make([]Statement, len(d.statements))
+	copy(res, d.statements)
+	return res
+}
+
+// Reset removes captured statements.
+func (d *DryRun) Reset() {
+	d.m.Lock()
+	d.statements = nil
+	d.m.Unlock()
+}
+
+func (d *DryRun) capture(query string, args []interface{}) {
+	d.m.Lock()
+	d.statements = append(d.statements, Statement{Query: query, Args: args})
+	d.m.Unlock()
+}
+
+// WithDryRun returns a copy of querier which doesn't execute queries and commands, but captures them
+// (after all hooks, encryption and other conversions) to given DryRun, for example, to preview destructive command:
+//
+//	d := reform.NewDryRun()
+//	defer d.Close()
+//	tail, args, err := reform.Where(reform.Lt("created_at", t)).Build(db.Dialect)
+//	...
+//	_, err = db.WithDryRun(d).DeleteFrom(PersonTable, tail, args...)
+//	log.Print(d.Statements())
+//
+// Commands behave as if each statement affected exactly one row, without last insert ID.
+// Queries return no rows, so finders return ErrNoRows, and commands which receive values from database
+// (like Insert with Returning LastInsertIdMethod) return sql.ErrNoRows after capturing statement.
+// Transactions are not captured: use WithDryRun on TX querier to capture statements inside transaction.
+func (q *Querier) WithDryRun(d *DryRun) *Querier {
+	nq := q.clone()
+	nq.dbtx = &dryRunDBTX{d: d}
+	return nq
+}
+
+// dryRunDBTX is a dbtx which captures statements and executes them on fake database.
+type dryRunDBTX struct {
+	d *DryRun
+}
+
+func (t *dryRunDBTX) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.ExecContext(context.Background(), query, args...)
+}
+
+func (t *dryRunDBTX) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.QueryContext(context.Background(), query, args...)
+}
+
+func (t *dryRunDBTX) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.QueryRowContext(context.Background(), query, args...)
+}
+
+func (t *dryRunDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	t.d.capture(query, args)
+	return t.d.db.ExecContext(ctx, query, args...)
+}
+
+func (t *dryRunDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	t.d.capture(query, args)
+	return t.d.db.QueryContext(ctx, query, args...)
+}
+
+func (t *dryRunDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	t.d.capture(query, args)
+	return t.d.db.QueryRowContext(ctx, query, args...)
+}
+
+// errDryRun is returned by fake database for unexpected calls.
+var errDryRun = errors.New("reform: not supported in dry run")
+
+// dryRunConnector and other types below implement fake database driver
+// which accepts any statement and arguments and returns no rows.
+type dryRunConnector struct{}
+
+func (dryRunConnector) Connect(context.Context) (driver.Conn, error) { return dryRunConn{}, nil }
+func (dryRunConnector) Driver() driver.Driver                        { return dryRunDriver{} }
+
+type dryRunDriver struct{}
+
+func (dryRunDriver) Open(string) (driver.Conn, error) { return dryRunConn{}, nil }
+
+type dryRunConn struct{}
+
+func (dryRunConn) Prepare(string) (driver.Stmt, error) { return nil, errDryRun }
+func (dryRunConn) Close() error                        { return nil }
+func (dryRunConn) Begin() (driver.Tx, error)           { return nil, errDryRun }
+
+func (dryRunConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (dryRunConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return dryRunResult{}, nil
+}
+
+func (dryRunConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return dryRunRows{}, nil
+}
+
+type dryRunResult struct{}
+
+func (dryRunResult) LastInsertId() (int64, error) { return 0, nil }
+func (dryRunResult) RowsAffected() (int64, error) { return 1, nil }
+
+type dryRunRows struct{}
+
+func (dryRunRows) Columns() []string              { return nil }
+func (dryRunRows) Close() error                   { return nil }
+func (dryRunRows) Next(dest []driver.Value) error { return io.EOF }
+
+// check interfaces
+var (
+	_ dbtx                     = new(dryRunDBTX)
+	_ driver.Connector         = dryRunConnector{}
+	_ driver.ExecerContext     = dryRunConn{}
+	_ driver.QueryerContext    = dryRunConn{}
+	_ driver.NamedValueChecker = dryRunConn{}
+)
@@ -0,0 +1,51 @@
+package reform_test
+
+import (
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestDryRun() {
+	d := reform.NewDryRun()
+	q := s.q.WithDryRun(d)
+
+	tail, args, err := reform.Where(reform.Gt("id", 100)).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	ra, err := q.DeleteFrom(PersonTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(1), ra)
+
+	person := &Person{ID: 1, Name: "Dry Run"}
+	s.NoError(q.UpdateColumns(person, "name"))
+
+	_, err = q.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.Equal(reform.ErrNoRows, err)
+
+	expected := []reform.Statement{{
+		Query: "DELETE FROM " + s.q.QuoteIdentifier("people") + " " + tail,
+		Args:  []interface{}{100},
+	}, {
+		Query: "UPDATE " + s.q.QuoteIdentifier("people") + " SET " + s.q.QuoteIdentifier("name") + " = " + s.q.Placeholder(1) +
+			" WHERE " + s.q.QuoteIdentifier("id") + " = " + s.q.Placeholder(2),
+		Args: []interface{}{"Dry Run", int32(1)},
+	}}
+	statements := d.Statements()
+	s.Require().Len(statements, 3)
+	s.Equal(expected, statements[:2])
+	s.Contains(statements[2].Query, "SELECT ")
+
+	// nothing is changed
+	count, err := s.q.Count(PersonTable, "")
+	s.NoError(err)
+	s.Equal(uint(5), count)
+	person2, err := s.q.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.NoError(err)
+	s.Equal("Denis Mills", person2.(*Person).Name)
+
+	d.Reset()
+	s.Empty(d.Statements())
+
+	s.NoError(d.Close())
+	_, err = q.DeleteFrom(PersonTable, tail, args...)
+	s.Error(err)
+}
@@ -0,0 +1,85 @@
+package reform
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UnexpectedColumnsError is returned from Querier's update commands and column selects
+// when record's table (or view) has no given columns.
+type UnexpectedColumnsError struct {
+	Columns []string // sorted
+}
+
+// Error implements error interface.
+func (e *UnexpectedColumnsError) Error() string {
+	return fmt.Sprintf("reform: unexpected columns: %v", e.Columns)
+}
+
+// ConstraintError is returned from Querier's queries and commands instead of database driver's error
+// for constraint violations detected by Dialect (see ConstraintDialect).
+// Both errors.Is(err, ErrUniqueViolation) (or ErrForeignKeyViolation) and errors.As for driver error type work:
+//
+//	err = db.Insert(person)
+//	if errors.Is(err, reform.ErrUniqueViolation) { ... }
+type ConstraintError struct {
+	Kind       error  // ErrUniqueViolation or ErrForeignKeyViolation
+	Constraint string // constraint or index name, may be empty if not known
+	Err        error  // original driver error
+}
+
+// Error implements error interface. It returns driver's error message.
+func (e *ConstraintError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns original driver error.
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is error's Kind.
+func (e *ConstraintError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// wrapError returns *ConstraintError for driver error err if Dialect detects constraint violation,
+// and err as is otherwise.
+func (q *Querier) wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	cd, ok := q.Dialect.(ConstraintDialect)
+	if !ok {
+		return err
+	}
+	if _, ok = err.(*ConstraintError); ok {
+		return err
+	}
+	kind, constraint := cd.ConstraintViolation(err)
+	if kind == nil {
+		return err
+	}
+	return &ConstraintError{
+		Kind:       kind,
+		Constraint: constraint,
+		Err:        err,
+	}
+}
+
+// IsUniqueViolation returns true and constraint name (if known) if err is a unique constraint
+// (or primary key) violation, so it can be translated into validation error:
+//
+//	if name, ok := q.IsUniqueViolation(err); ok && name == "people_email_key" {
+//		return errors.New("email is already used")
+//	}
+//
+// It handles *ConstraintError returned by Querier, and also driver errors returned directly
+// (for example, by database/sql used directly) detected by Dialect (see ConstraintDialect).
+func (q *Querier) IsUniqueViolation(err error) (constraintName string, ok bool) {
+	var ce *ConstraintError
+	if !errors.As(q.wrapError(err), &ce) || ce.Kind != ErrUniqueViolation {
+		return "", false
+	}
+	return ce.Constraint, true
+}
@@ -0,0 +1,94 @@
+package reform_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/dialects/sqlite3"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestConstraintError() {
+	if _, ok := s.q.Dialect.(reform.ConstraintDialect); !ok {
+		s.T().Skip("dialect doesn't detect constraint violations")
+	}
+
+	err := s.q.Insert(&Project{ID: "baron", Name: "Duplicate", Start: time.Now()})
+	s.True(errors.Is(err, reform.ErrUniqueViolation), "%+v", err)
+	s.False(errors.Is(err, reform.ErrForeignKeyViolation))
+	var ce *reform.ConstraintError
+	s.Require().True(errors.As(err, &ce))
+	s.Equal(reform.ErrUniqueViolation, ce.Kind)
+	s.Equal(ce.Err, errors.Unwrap(err))
+	s.Equal(ce.Err.Error(), err.Error())
+	name, ok := s.q.IsUniqueViolation(err)
+	s.True(ok)
+	s.Equal(ce.Constraint, name)
+
+	s.RestartTransaction()
+
+	err = s.q.Insert(&PersonProject{PersonID: 1000000, ProjectID: "baron"})
+	s.True(errors.Is(err, reform.ErrForeignKeyViolation), "%+v", err)
+	s.False(errors.Is(err, reform.ErrUniqueViolation))
+	_, ok = s.q.IsUniqueViolation(err)
+	s.False(ok)
+}
+
+// pqError imitates github.com/lib/pq error.
+type pqError struct {
+	code string
+	msg  string
+}
+
+func (e *pqError) Error() string    { return "pq: " + e.msg }
+func (e *pqError) SQLState() string { return e.code }
+
+func (s *ReformSuite) TestIsUniqueViolation() {
+	for d, errs := range map[reform.Dialect]map[error]string{
+		postgresql.Dialect: {
+			&pqError{"23505", `duplicate key value violates unique constraint "people_email_key"`}:             "people_email_key",
+			fmt.Errorf("insert: %w", &pqError{"23505", `duplicate key value violates unique constraint "pk"`}): "pk",
+		},
+		mysql.Dialect: {
+			errors.New("Error 1062 (23000): Duplicate entry 'a@example.com' for key 'people.email'"):      "people.email",
+			errors.New(`Received #1062 error from MySQL server: "Duplicate entry '1' for key 'PRIMARY'"`): "PRIMARY",
+		},
+		sqlite3.Dialect: {
+			errors.New("UNIQUE constraint failed: people.email"):                                                 "people.email",
+			&reform.ConstraintError{Kind: reform.ErrUniqueViolation, Constraint: "c", Err: errors.New("driver")}: "c",
+		},
+	} {
+		q := reform.NewDB(nil, d, nil).Querier
+		for err, expected := range errs {
+			name, ok := q.IsUniqueViolation(err)
+			s.True(ok, "%s", err)
+			s.Equal(expected, name, "%s", err)
+		}
+	}
+
+	for d, errs := range map[reform.Dialect][]error{
+		postgresql.Dialect: {
+			nil,
+			reform.ErrNoRows,
+			&pqError{"23503", `insert or update on table "a" violates foreign key constraint "a_b_fkey"`},
+			errors.New("UNIQUE constraint failed: people.email"),
+		},
+		mysql.Dialect: {
+			errors.New("Error 1452 (23000): Cannot add or update a child row"),
+		},
+		sqlite3.Dialect: {
+			errors.New("FOREIGN KEY constraint failed"),
+			&reform.ConstraintError{Kind: reform.ErrForeignKeyViolation, Constraint: "c", Err: errors.New("driver")},
+		},
+	} {
+		q := reform.NewDB(nil, d, nil).Querier
+		for _, err := range errs {
+			_, ok := q.IsUniqueViolation(err)
+			s.False(ok, "%v", err)
+		}
+	}
+}
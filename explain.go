@@ -0,0 +1,131 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrExplainNotSupported is returned by Querier.Explain if dialect doesn't implement ExplainDialect.
+var ErrExplainNotSupported = errors.New("reform: EXPLAIN is not supported by dialect")
+
+// ExplainDialect is an optional interface for Dialect which can show query execution plan.
+// See Querier.Explain and Querier.WithSlowQueryExplain.
+type ExplainDialect interface {
+	Dialect
+
+	// ExplainQuery returns query which returns execution plan of given query without executing it,
+	// like "EXPLAIN SELECT ...".
+	ExplainQuery(query string) string
+}
+
+// Explain returns execution plan of given query with given args as text, one result row per line,
+// with column values separated by tabs. The query itself is not executed.
+// Method returns ErrExplainNotSupported if dialect doesn't implement ExplainDialect.
+func (q *Querier) Explain(query string, args ...interface{}) (string, error) {
+	ed, ok := q.Dialect.(ExplainDialect)
+	if !ok {
+		return "", ErrExplainNotSupported
+	}
+
+	rows, cancel, err := q.queryRows("", ed.ExplainQuery(query), args...)
+	if err != nil {
+		return "", err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	values := make([]sql.NullString, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var lines []string
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			return "", err
+		}
+		line := make([]string, len(values))
+		for i, v := range values {
+			line[i] = v.String
+		}
+		lines = append(lines, strings.Join(line, "\t"))
+	}
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// SlowQueryLogger is an optional interface for Logger which receives queries and commands slower than
+// threshold set by Querier.WithSlowQueryThreshold.
+type SlowQueryLogger interface {
+	Logger
+
+	// LogSlowQuery logs slow query after it is logged as usual. Context is querier's context.
+	// Plan is query execution plan if Querier.WithSlowQueryExplain is used, or empty string.
+	LogSlowQuery(ctx context.Context, event *QueryEvent, plan string)
+}
+
+// WithSlowQueryThreshold returns a copy of querier which passes queries and commands executed for d or longer
+// (zero disables it) to Logger's LogSlowQuery method if it implements SlowQueryLogger (PrintfLogger does that).
+func (q *Querier) WithSlowQueryThreshold(d time.Duration) *Querier {
+	nq := q.clone()
+	nq.slowQueryThreshold = d
+	return nq
+}
+
+// WithSlowQueryExplain returns a copy of querier which also passes execution plans of slow SELECT, INSERT, UPDATE
+// and DELETE statements to SlowQueryLogger, see WithSlowQueryThreshold. Plan is obtained with Explain after
+// the statement is executed, so it may differ from the actual one.
+//
+// Inside transaction, connection is busy until rows of a query are read, so plans are obtained
+// only for commands there (and for failed queries).
+func (q *Querier) WithSlowQueryExplain() *Querier {
+	nq := q.clone()
+	nq.slowQueryExplain = true
+	return nq
+}
+
+// logSlow passes slow query to SlowQueryLogger, if any. Rows of successful query are still open if rowsOpen is true.
+// Query args are used for EXPLAIN, because values of sensitive columns are redacted in event.
+func (q *Querier) logSlow(event *QueryEvent, args []interface{}, rowsOpen bool) {
+	sl, ok := q.Logger.(SlowQueryLogger)
+	if !ok {
+		return
+	}
+
+	var plan string
+	if q.slowQueryExplain && q.canExplain(event.Operation, rowsOpen) {
+		nq := q.clone()
+		nq.slowQueryThreshold = 0
+		var err error
+		if plan, err = nq.Explain(event.Query, args...); err != nil {
+			plan = "EXPLAIN failed: " + err.Error()
+		}
+	}
+	sl.LogSlowQuery(q.ctx, event, plan)
+}
+
+// canExplain returns true if statement with given operation can be explained right after execution.
+func (q *Querier) canExplain(operation string, rowsOpen bool) bool {
+	if _, ok := q.Dialect.(ExplainDialect); !ok {
+		return false
+	}
+	if _, ok := q.dbtx.(*sql.Tx); ok && rowsOpen {
+		return false
+	}
+	switch operation {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "WITH":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,59 @@
+package reform_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+// slowQueryLogger records slow queries and their plans.
+type slowQueryLogger struct {
+	*reform.RecordingLogger
+	events []*reform.QueryEvent
+	plans  []string
+}
+
+func (l *slowQueryLogger) LogSlowQuery(ctx context.Context, event *reform.QueryEvent, plan string) {
+	l.events = append(l.events, event)
+	l.plans = append(l.plans, plan)
+}
+
+func (s *ReformSuite) TestExplain() {
+	query := "SELECT * FROM " + s.q.QuoteIdentifier("people") + " WHERE " + s.q.QuoteIdentifier("id") + " = " + s.q.Placeholder(1)
+	plan, err := s.q.Explain(query, 1)
+	if _, ok := s.q.Dialect.(reform.ExplainDialect); !ok {
+		s.Equal(reform.ErrExplainNotSupported, err)
+		return
+	}
+	s.NoError(err)
+	s.NotEmpty(plan)
+}
+
+func (s *ReformSuite) TestSlowQuery() {
+	l := &slowQueryLogger{RecordingLogger: reform.NewRecordingLogger()}
+	s.q.Logger = l
+	q := s.q.WithSlowQueryThreshold(time.Nanosecond).WithSlowQueryExplain()
+
+	_, err := q.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.NoError(err)
+	s.NoError(q.UpdateColumns(&Person{ID: 1, Name: "Slow"}, "name"))
+
+	s.Require().Len(l.events, 2)
+	s.Equal("SELECT", l.events[0].Operation)
+	s.Empty(l.plans[0], "rows are not read yet")
+	s.Equal("UPDATE", l.events[1].Operation)
+	if _, ok := s.q.Dialect.(reform.ExplainDialect); ok {
+		s.NotEmpty(l.plans[1])
+
+		// EXPLAIN itself is logged, but not passed to LogSlowQuery
+		s.Len(l.RecordingLogger.Statements(), 3)
+	} else {
+		s.Empty(l.plans[1])
+	}
+
+	_, err = s.q.WithSlowQueryThreshold(time.Hour).FindByPrimaryKeyFrom(PersonTable, 1)
+	s.NoError(err)
+	s.Len(l.events, 2)
+}
@@ -0,0 +1,36 @@
+package reform
+
+// Expr represents SQL expression used as a new column value by UpdateExpr.
+// Use Raw, Inc and Dec to create it.
+type Expr struct {
+	raw    string
+	column string
+	op     string
+	arg    interface{}
+}
+
+// Raw returns expression which is inserted into query as is, like "views + 1" or "GREATEST(score, 0)".
+// Identifiers in it are not quoted, so pass only trusted strings and use Inc, Dec or arguments of other methods
+// for user-provided values.
+func Raw(sql string) Expr {
+	return Expr{raw: sql}
+}
+
+// Inc returns expression "column + delta" with delta passed as query argument.
+func Inc(column string, delta interface{}) Expr {
+	return Expr{column: column, op: "+", arg: delta}
+}
+
+// Dec returns expression "column - delta" with delta passed as query argument.
+func Dec(column string, delta interface{}) Expr {
+	return Expr{column: column, op: "-", arg: delta}
+}
+
+// build renders expression for given dialect, appending its argument (if any) to args.
+func (e Expr) build(dialect Dialect, args *[]interface{}) string {
+	if e.column == "" {
+		return e.raw
+	}
+	*args = append(*args, e.arg)
+	return dialect.QuoteIdentifier(e.column) + " " + e.op + " " + dialect.Placeholder(len(*args))
+}
@@ -0,0 +1,304 @@
+// Package fixtures loads test data from YAML and JSON files into SQL database tables via reform.
+//
+// Fixture file maps table (or view) name to a list of rows, each row maps column name to value:
+//
+//	people:
+//	  - id: 101
+//	    name: Denis Mills
+//	    created_at: 2009-11-10T23:00:00Z
+//	person_project:
+//	  - person_id: 101
+//	    project_id: baron
+//
+// Rows are converted to generated structs and inserted with Querier.Insert, so before insert hooks,
+// automatic timestamps and "json", "array" and "encrypted" labels work as usual.
+// Tables are loaded in an order which satisfies foreign keys: see Loader.References.
+//
+// Typical usage in tests is Setup: it starts a transaction, loads fixtures and rolls it back after the test:
+//
+//	loader := &fixtures.Loader{
+//		Views:      []reform.View{PersonTable, ProjectTable, PersonProjectView},
+//		References: map[string][]string{"person_project": {"people", "projects"}},
+//	}
+//	tx := fixtures.Setup(t, DB, loader, "testdata/people.yml")
+package fixtures // TODO add canonical import path via gopkg.in
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AlekSi/reform"
+)
+
+// Fixtures contains rows by table (or view) name; row contains column values by column name.
+type Fixtures map[string][]map[string]interface{}
+
+// Parse parses fixtures in given format: "json" or "yaml" ("yml").
+func Parse(b []byte, format string) (Fixtures, error) {
+	var res Fixtures
+	var err error
+	switch strings.ToLower(format) {
+	case "json":
+		err = json.Unmarshal(b, &res)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(b, &res)
+	default:
+		return nil, fmt.Errorf("fixtures: unknown format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fixtures: %s", err)
+	}
+	return res, nil
+}
+
+// LoadFiles reads and parses given files (format is determined by extension) and merges them.
+// Rows of the same table from several files are appended in files order.
+func LoadFiles(paths ...string) (Fixtures, error) {
+	res := make(Fixtures)
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := Parse(b, strings.TrimPrefix(filepath.Ext(path), "."))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		for table, rows := range f {
+			res[table] = append(res[table], rows...)
+		}
+	}
+	return res, nil
+}
+
+// Loader loads fixtures into known views and tables.
+type Loader struct {
+	// Views are generated views and tables fixtures can be loaded into.
+	Views []reform.View
+
+	// References maps table (or view) name to names of tables it references with foreign keys.
+	// Load uses them to insert referenced tables first and to delete them last.
+	// Self-references are allowed; rows of such tables are inserted in fixtures order.
+	References map[string][]string
+
+	// Truncate makes Load to delete all rows from all Views (not only ones with fixtures) before loading.
+	// Soft delete is not used for that.
+	Truncate bool
+}
+
+// Load loads fixtures with given transaction. Rows of each table are inserted in fixtures order.
+//
+// Tables are sorted topologically by References, so referenced tables are loaded before referencing ones;
+// otherwise, Views order is kept. Truncation is done in reverse order.
+// It returns error for unknown tables in fixtures and References, and for reference cycles.
+func (l *Loader) Load(tx *reform.TX, f Fixtures) error {
+	views := make(map[string]reform.View, len(l.Views))
+	for _, v := range l.Views {
+		views[v.Name()] = v
+	}
+	for name := range f {
+		if _, ok := views[name]; !ok {
+			return fmt.Errorf("fixtures: unknown table %s", name)
+		}
+	}
+
+	sorted, err := l.sort(views)
+	if err != nil {
+		return err
+	}
+
+	if l.Truncate {
+		for i := len(sorted) - 1; i >= 0; i-- {
+			if _, err = tx.Unscoped().DeleteFrom(sorted[i], "", reform.AllRows); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, v := range sorted {
+		for i, row := range f[v.Name()] {
+			str, err := newStruct(v, row)
+			if err != nil {
+				return fmt.Errorf("fixtures: %s[%d]: %s", v.Name(), i, err)
+			}
+			if err = tx.Insert(str); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sort returns Views sorted topologically by References: referenced views go first,
+// otherwise Views order is kept.
+func (l *Loader) sort(views map[string]reform.View) ([]reform.View, error) {
+	for name, refs := range l.References {
+		for _, n := range append([]string{name}, refs...) {
+			if _, ok := views[n]; !ok {
+				return nil, fmt.Errorf("fixtures: unknown table %s", n)
+			}
+		}
+	}
+
+	res := make([]reform.View, 0, len(l.Views))
+	done := make(map[string]bool, len(l.Views)) // false while visiting, true when added
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if added, ok := done[name]; ok {
+			if !added {
+				return fmt.Errorf("fixtures: foreign key cycle: %s -> %s", strings.Join(path, " -> "), name)
+			}
+			return nil
+		}
+		done[name] = false
+		for _, ref := range l.References[name] {
+			if ref == name {
+				continue
+			}
+			if err := visit(ref, append(path, name)); err != nil {
+				return err
+			}
+		}
+		done[name] = true
+		res = append(res, views[name])
+		return nil
+	}
+
+	for _, v := range l.Views {
+		if err := visit(v.Name(), nil); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Setup starts a transaction, loads fixtures from given files with given loader and returns that transaction.
+// Transaction is rolled back when test and all its subtests complete. Errors fail the test immediately.
+func Setup(tb testing.TB, db *reform.DB, l *Loader, paths ...string) *reform.TX {
+	tb.Helper()
+
+	f, err := LoadFiles(paths...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() {
+		if err := tx.Rollback(); err != nil {
+			tb.Error(err)
+		}
+	})
+	if err = l.Load(tx, f); err != nil {
+		tb.Fatal(err)
+	}
+	return tx
+}
+
+// newStruct returns a new struct for view with column values from row.
+func newStruct(view reform.View, row map[string]interface{}) (reform.Struct, error) {
+	var str reform.Struct
+	if t, ok := view.(reform.Table); ok {
+		str = t.NewRecord()
+	} else {
+		str = view.NewStruct()
+	}
+
+	indexes := make(map[string]int, len(view.Columns()))
+	for i, c := range view.Columns() {
+		indexes[c] = i
+	}
+	pointers := str.Pointers()
+	for column, value := range row {
+		i, ok := indexes[column]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s", column)
+		}
+		if err := assign(pointers[i], value); err != nil {
+			return nil, fmt.Errorf("%s: %s", column, err)
+		}
+	}
+	return str, nil
+}
+
+// timeLayouts are layouts of time values in fixtures in addition to time.RFC3339Nano.
+var timeLayouts = []string{"2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+// assign sets value decoded from JSON or YAML to field by pointer.
+func assign(pointer interface{}, value interface{}) error {
+	switch p := pointer.(type) {
+	case *reform.JSON:
+		pointer = p.V
+	case *reform.Array:
+		pointer = p.V
+	}
+
+	dest := reflect.ValueOf(pointer).Elem()
+	if value == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	// allocate pointer fields
+	elem := dest
+	if dest.Kind() == reflect.Ptr {
+		p := reflect.New(dest.Type().Elem())
+		dest.Set(p)
+		elem = p.Elem()
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		if elem.Type() == reflect.TypeOf(v) {
+			elem.Set(reflect.ValueOf(v))
+			return nil
+		}
+	case string:
+		if elem.Type() == reflect.TypeOf(time.Time{}) {
+			t, err := parseTime(v)
+			if err != nil {
+				return err
+			}
+			elem.Set(reflect.ValueOf(t))
+			return nil
+		}
+		if elem.Type() == reflect.TypeOf([]byte(nil)) {
+			elem.SetBytes([]byte(v))
+			return nil
+		}
+	}
+
+	if s, ok := elem.Addr().Interface().(sql.Scanner); ok {
+		return s.Scan(value)
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, elem.Addr().Interface())
+}
+
+// parseTime parses time value in one of supported layouts; time without zone is UTC.
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err == nil {
+		return t, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, e := time.Parse(layout, s); e == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
@@ -0,0 +1,111 @@
+package fixtures
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/internal/test/models"
+)
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(`{"people": [{"id": 1, "name": "Denis"}]}`), "json")
+	require.NoError(t, err)
+	assert.Equal(t, Fixtures{"people": {{"id": 1.0, "name": "Denis"}}}, f)
+
+	_, err = Parse(nil, "xml")
+	assert.Equal(t, errors.New(`fixtures: unknown format "xml"`), err)
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir, err := os.MkdirTemp("", "reform-fixtures")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, content := range map[string]string{
+		"a.json": `{"people": [{"id": 1}], "projects": [{"id": "baron"}]}`,
+		"b.json": `{"people": [{"id": 2}]}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	f, err := LoadFiles(filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json"))
+	require.NoError(t, err)
+	assert.Equal(t, Fixtures{
+		"people":   {{"id": 1.0}, {"id": 2.0}},
+		"projects": {{"id": "baron"}},
+	}, f)
+}
+
+func TestSort(t *testing.T) {
+	l := &Loader{
+		Views: []reform.View{models.PersonProjectView, models.ProjectRoleTable, models.PersonTable, models.ProjectTable},
+		References: map[string][]string{
+			"person_project": {"people", "projects"},
+			"project_roles":  {"projects", "people"},
+			"people":         {"people"},
+		},
+	}
+	views := make(map[string]reform.View)
+	for _, v := range l.Views {
+		views[v.Name()] = v
+	}
+
+	sorted, err := l.sort(views)
+	require.NoError(t, err)
+	var names []string
+	for _, v := range sorted {
+		names = append(names, v.Name())
+	}
+	assert.Equal(t, []string{"people", "projects", "person_project", "project_roles"}, names)
+
+	l.References["people"] = []string{"project_roles"}
+	_, err = l.sort(views)
+	assert.EqualError(t, err, "fixtures: foreign key cycle: person_project -> people -> project_roles -> people")
+
+	l.References["people"] = []string{"unknown"}
+	_, err = l.sort(views)
+	assert.EqualError(t, err, "fixtures: unknown table unknown")
+}
+
+func TestNewStruct(t *testing.T) {
+	str, err := newStruct(models.PersonTable, map[string]interface{}{
+		"id":         101.0,
+		"name":       "Denis Mills",
+		"email":      "denis@example.com",
+		"created_at": "2009-11-10 23:00:00",
+		"updated_at": time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &models.Person{
+		ID:        101,
+		Name:      "Denis Mills",
+		Email:     pointer.ToString("denis@example.com"),
+		CreatedAt: time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC),
+		UpdatedAt: pointer.ToTime(time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC)),
+	}, str)
+
+	str, err = newStruct(models.EventTable, map[string]interface{}{
+		"payload": map[string]interface{}{"type": "click"},
+		"meta":    map[string]interface{}{"source": "web", "tags": []interface{}{"a"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &models.Event{
+		Payload: map[string]interface{}{"type": "click"},
+		Meta:    &models.EventMeta{Source: "web", Tags: []string{"a"}},
+	}, str)
+
+	str, err = newStruct(models.ArticleTable, map[string]interface{}{"tags": []interface{}{"go"}, "scores": nil})
+	require.NoError(t, err)
+	assert.Equal(t, &models.Article{Tags: []string{"go"}}, str)
+
+	_, err = newStruct(models.PersonTable, map[string]interface{}{"age": 42})
+	assert.Equal(t, errors.New("unknown column age"), err)
+}
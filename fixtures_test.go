@@ -0,0 +1,38 @@
+package reform_test
+
+import (
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/fixtures"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestFixtures() {
+	f, err := fixtures.Parse([]byte(`{
+		"person_project": [{"person_id": 101, "project_id": "fixture"}],
+		"people": [{"id": 101, "name": "Fixture", "created_at": "2009-11-10T23:00:00Z"}],
+		"projects": [{"id": "fixture", "name": "Fixture", "start": "2014-06-01"}]
+	}`), "json")
+	s.Require().NoError(err)
+
+	// referencing view is listed first
+	loader := &fixtures.Loader{
+		Views:      []reform.View{PersonProjectView, PersonTable, ProjectTable},
+		References: map[string][]string{"person_project": {"people", "projects"}},
+		Truncate:   true,
+	}
+	s.Require().NoError(loader.Load(s.q, f))
+
+	n, err := s.q.Count(PersonTable, "")
+	s.NoError(err)
+	s.Equal(uint(1), n)
+
+	person, err := s.q.FindByPrimaryKeyFrom(PersonTable, 101)
+	s.Require().NoError(err)
+	s.Equal("Fixture", person.(*Person).Name)
+
+	structs, err := s.q.SelectAllFrom(PersonProjectView, "")
+	s.NoError(err)
+	s.Equal([]reform.Struct{&PersonProject{PersonID: 101, ProjectID: "fixture"}}, structs)
+
+	s.EqualError(loader.Load(s.q, fixtures.Fixtures{"unknown": nil}), "fixtures: unknown table unknown")
+}
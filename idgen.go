@@ -0,0 +1,100 @@
+package reform
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// IDGenerator generates a new primary key value for GeneratedPKTable.
+type IDGenerator func() (string, error)
+
+// UUIDv4 is an IDGenerator returning random UUID version 4 in canonical form:
+// 8-4-4-4-12 lowercase hexadecimal digits. It is a default IDGenerator.
+func UUIDv4() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return formatUUID(b, 4), nil
+}
+
+// UUIDv7 is an IDGenerator returning time-ordered UUID version 7 in canonical form:
+// 48 bits of Unix time in milliseconds followed by random bits.
+// Such keys are better for B-tree indexes than random ones.
+func UUIDv7() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+	putMillis(b[:6], time.Now())
+	return formatUUID(b, 7), nil
+}
+
+// crockford is Crockford's Base32 alphabet used by ULID.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID is an IDGenerator returning time-ordered ULID: 26 characters of Crockford's Base32 encoding
+// of 48 bits of Unix time in milliseconds followed by 80 random bits.
+func ULID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+	putMillis(b[:6], time.Now())
+
+	// encode 128 bits as 26 5-bit groups, the first group has only 3 bits
+	res := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		var v byte
+		for bit := 0; bit < 5; bit++ {
+			n := (25-i)*5 + bit // bit number from the least significant one
+			if n < 128 && b[15-n/8]&(1<<uint(n%8)) != 0 {
+				v |= 1 << uint(bit)
+			}
+		}
+		res[i] = crockford[v]
+	}
+	return string(res), nil
+}
+
+// putMillis puts 48 bits of Unix time t in milliseconds to b in big-endian order.
+func putMillis(b []byte, t time.Time) {
+	ms := uint64(t.UnixNano() / int64(time.Millisecond))
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// formatUUID sets given version and RFC 4122 variant bits and returns UUID in canonical form.
+func formatUUID(b [16]byte, version byte) string {
+	b[6] = b[6]&0x0f | version<<4
+	b[8] = b[8]&0x3f | 0x80
+
+	s := hex.EncodeToString(b[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// generatePK sets primary key of str generated by querier's IDGenerator
+// if str is a Record without primary key and its table implements GeneratedPKTable.
+func (q *Querier) generatePK(str Struct) error {
+	record, ok := str.(Record)
+	if !ok || record.HasPK() {
+		return nil
+	}
+	if _, ok = record.Table().(GeneratedPKTable); !ok {
+		return nil
+	}
+
+	g := q.idGenerator
+	if g == nil {
+		g = UUIDv4
+	}
+	id, err := g()
+	if err != nil {
+		return err
+	}
+	record.SetPK(id)
+	return nil
+}
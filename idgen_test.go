@@ -0,0 +1,56 @@
+package reform_test
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestIDGenerators() {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([47])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	id1, err := reform.UUIDv4()
+	s.NoError(err)
+	s.Equal([]string{id1, "4"}, uuid.FindStringSubmatch(id1))
+
+	id2, err := reform.UUIDv7()
+	s.NoError(err)
+	s.Equal([]string{id2, "7"}, uuid.FindStringSubmatch(id2))
+	id3, err := reform.UUIDv7()
+	s.NoError(err)
+	s.True(id2[:13] <= id3[:13], "%s > %s", id2, id3) // time-ordered up to milliseconds
+
+	id4, err := reform.ULID()
+	s.NoError(err)
+	s.Regexp(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`, id4)
+	id5, err := reform.ULID()
+	s.NoError(err)
+	s.True(id4[:10] <= id5[:10], "%s > %s", id4, id5)
+}
+
+func (s *ReformSuite) TestInsertGeneratedPK() {
+	project := &Project{Name: "Generated", Start: queenStart}
+	s.NoError(s.q.Insert(project))
+	s.Len(project.ID, 36)
+
+	project2, err := s.q.FindByPrimaryKeyFrom(ProjectTable, project.ID)
+	s.NoError(err)
+	s.Equal("Generated", project2.(*Project).Name)
+
+	// explicit primary key is not replaced
+	project = &Project{ID: "explicit", Name: "Explicit", Start: queenStart}
+	s.NoError(s.q.Insert(project))
+	s.Equal("explicit", project.ID)
+
+	q := s.q.WithIDGenerator(reform.ULID)
+	multi1, multi2 := &Project{Name: "Multi 1", Start: queenStart}, &Project{Name: "Multi 2", Start: queenStart}
+	s.NoError(q.InsertMulti(multi1, multi2))
+	s.Len(multi1.ID, 26)
+	s.Len(multi2.ID, 26)
+	s.NotEqual(multi1.ID, multi2.ID)
+
+	q = s.q.WithIDGenerator(func() (string, error) { return "", errors.New("no ids") })
+	s.EqualError(q.Insert(&Project{Name: "Failed", Start: queenStart}), "no ids")
+}
@@ -0,0 +1,238 @@
+package reform
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUnknownSubtype is returned when discriminator column contains value without registered subtype.
+var ErrUnknownSubtype = errors.New("reform: unknown subtype")
+
+// inheritance describes subtypes of base table registered with RegisterSubtypes.
+type inheritance struct {
+	base     Table
+	column   string
+	subtypes map[string]Table
+}
+
+// subtype describes table registered as a subtype with RegisterSubtypes.
+type subtype struct {
+	inheritance *inheritance
+	value       string
+}
+
+var (
+	inheritanceRW sync.RWMutex
+	inheritances  = make(map[Table]*inheritance)
+	subtypes      = make(map[Table]subtype)
+)
+
+// RegisterSubtypes registers single-table inheritance: rows of base table are stored in the same SQL table
+// as rows of subtype tables, and string discriminator column contains a key of subtypes map.
+// Base and subtype tables are generated for structs with the same "reform:" table name,
+// primary key and discriminator columns; subtypes have additional columns:
+//
+//	//reform:vehicles
+//	type Vehicle struct { ID int32 `reform:"id,pk"`; Kind string `reform:"kind"`; Name string `reform:"name"` }
+//
+//	//reform:vehicles
+//	type Car struct { ID int32 `reform:"id,pk"`; Kind string `reform:"kind"`; Name string `reform:"name"`; Doors int32 `reform:"doors"` }
+//
+//	func init() {
+//		reform.RegisterSubtypes(VehicleTable, "kind", map[string]reform.Table{"car": CarTable, "truck": TruckTable})
+//	}
+//
+// After that, FindByPrimaryKeyFrom for base table returns a record of subtype table for row's discriminator value
+// (or ErrUnknownSubtype); FindByPrimaryKeyTo, FindByPrimaryKeyFrom, Update and Delete for subtype table
+// affect only rows with its discriminator value. Inserted and updated subtype records get their discriminator value
+// if it is empty, and return an error if it is different; base records should have registered discriminator value.
+// RecordCache is not used for all those tables.
+//
+// RegisterSubtypes should be called during initialization; it panics on invalid arguments
+// or if it is called twice for the same table.
+func RegisterSubtypes(base Table, column string, subtypeTables map[string]Table) {
+	in := &inheritance{
+		base:     base,
+		column:   column,
+		subtypes: make(map[string]Table, len(subtypeTables)),
+	}
+	for _, t := range append([]Table{base}, tableValues(subtypeTables)...) {
+		if t.Name() != base.Name() {
+			panic(fmt.Sprintf("reform: RegisterSubtypes: %s has table %s, expected %s", tableType(t), t.Name(), base.Name()))
+		}
+		i := columnIndex(t, column)
+		if i < 0 {
+			panic(fmt.Sprintf("reform: RegisterSubtypes: %s has no column %s", tableType(t), column))
+		}
+		switch t.NewStruct().Values()[i].(type) {
+		case string, *string:
+		default:
+			panic(fmt.Sprintf("reform: RegisterSubtypes: %s column %s should have string or *string type", tableType(t), column))
+		}
+	}
+
+	inheritanceRW.Lock()
+	defer inheritanceRW.Unlock()
+
+	if _, dup := inheritances[base]; dup {
+		panic(fmt.Sprintf("reform: RegisterSubtypes called twice for %s", tableType(base)))
+	}
+	for _, t := range subtypeTables {
+		if _, dup := subtypes[t]; dup && t != base {
+			panic(fmt.Sprintf("reform: RegisterSubtypes called twice for %s", tableType(t)))
+		}
+	}
+
+	inheritances[base] = in
+	for value, t := range subtypeTables {
+		in.subtypes[value] = t
+		if t != base {
+			subtypes[t] = subtype{inheritance: in, value: value}
+		}
+	}
+}
+
+// tableValues returns values of map in unspecified order.
+func tableValues(m map[string]Table) []Table {
+	res := make([]Table, 0, len(m))
+	for _, t := range m {
+		res = append(res, t)
+	}
+	return res
+}
+
+// tableType returns struct type name of table for error messages, like "models.Car".
+func tableType(table Table) string {
+	return reflect.TypeOf(table.NewStruct()).Elem().String()
+}
+
+// columnIndex returns index of column in view, or -1.
+func columnIndex(view View, column string) int {
+	for i, c := range view.Columns() {
+		if c == column {
+			return i
+		}
+	}
+	return -1
+}
+
+// lookupInheritance returns inheritance registered for base table, or nil.
+func lookupInheritance(table Table) *inheritance {
+	inheritanceRW.RLock()
+	defer inheritanceRW.RUnlock()
+
+	return inheritances[table]
+}
+
+// lookupSubtype returns subtype registered for table.
+func lookupSubtype(table Table) (subtype, bool) {
+	inheritanceRW.RLock()
+	defer inheritanceRW.RUnlock()
+
+	st, ok := subtypes[table]
+	return st, ok
+}
+
+// hasInheritance returns true if table is registered as base or subtype table.
+func hasInheritance(table Table) bool {
+	inheritanceRW.RLock()
+	defer inheritanceRW.RUnlock()
+
+	_, base := inheritances[table]
+	_, sub := subtypes[table]
+	return base || sub
+}
+
+// discriminator returns discriminator column value of str.
+func discriminator(str Struct, column string) string {
+	switch v := str.Values()[columnIndex(str.View(), column)].(type) {
+	case string:
+		return v
+	case *string:
+		if v != nil {
+			return *v
+		}
+	}
+	return ""
+}
+
+// checkDiscriminator sets discriminator value of inserted or updated subtype record if it is empty,
+// and checks that it is valid.
+func checkDiscriminator(str Struct) error {
+	record, ok := str.(Record)
+	if !ok {
+		return nil
+	}
+	table := record.Table()
+
+	if st, ok := lookupSubtype(table); ok {
+		column := st.inheritance.column
+		switch value := discriminator(record, column); value {
+		case st.value:
+			return nil
+		case "":
+			p := record.Pointers()[columnIndex(table, column)]
+			v := reflect.ValueOf(p).Elem()
+			if v.Kind() == reflect.Ptr {
+				v.Set(reflect.New(v.Type().Elem()))
+				v = v.Elem()
+			}
+			v.SetString(st.value)
+			return nil
+		default:
+			return fmt.Errorf("reform: %s should have %s %q, got %q", tableType(table), column, st.value, value)
+		}
+	}
+
+	if in := lookupInheritance(table); in != nil {
+		value := discriminator(record, in.column)
+		if _, ok := in.subtypes[value]; !ok {
+			return fmt.Errorf("%w %q for %s", ErrUnknownSubtype, value, tableType(table))
+		}
+	}
+	return nil
+}
+
+// subtypeCondition appends condition matching rows with subtype's discriminator value to WHERE clause condition
+// and that value to args. It does nothing if table is not registered as a subtype.
+func (q *Querier) subtypeCondition(table Table, where string, args []interface{}) (string, []interface{}) {
+	st, ok := lookupSubtype(table)
+	if !ok {
+		return where, args
+	}
+	return where + " AND " + q.QuoteIdentifier(st.inheritance.column) + " = " + q.Placeholder(len(args)+1), append(args, st.value)
+}
+
+// findSubtypeByPK queries base table with primary key and returns a new record of subtype table
+// for found row's discriminator value.
+// Subtype's row is queried with that value, so if it was changed concurrently between queries
+// (which is not possible for querier with row-level locking in transaction), both queries are repeated.
+func (q *Querier) findSubtypeByPK(in *inheritance, pk interface{}) (Record, error) {
+	for {
+		base := in.base.NewRecord()
+		if err := q.findByPK(base, in.base, pk); err != nil {
+			return nil, err
+		}
+
+		value := discriminator(base, in.column)
+		table, ok := in.subtypes[value]
+		if !ok {
+			return nil, fmt.Errorf("%w %q for %s", ErrUnknownSubtype, value, tableType(in.base))
+		}
+		if table == in.base {
+			return base, nil
+		}
+
+		record := table.NewRecord()
+		err := q.findByPK(record, table, pk)
+		if err == ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+}
@@ -0,0 +1,62 @@
+package reform_test
+
+import (
+	"errors"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+// subtypes are registered here, not in models package: generated tables are absent when parse tests are run
+func init() {
+	reform.RegisterSubtypes(VehicleTable, "kind", map[string]reform.Table{"car": CarTable, "truck": TruckTable})
+}
+
+func (s *ReformSuite) TestSubtypes() {
+	car := &Car{Name: "Beetle", Doors: 2}
+	s.Require().NoError(s.q.Insert(car))
+	s.Equal("car", car.Kind)
+	truck := &Truck{Kind: "truck", Name: "Actros", Payload: 18000}
+	s.Require().NoError(s.q.Insert(truck))
+
+	err := s.q.Insert(&Truck{Kind: "car", Name: "Actros"})
+	s.EqualError(err, `reform: models.Truck should have kind "truck", got "car"`)
+	err = s.q.Insert(&Vehicle{Kind: "bike", Name: "Bianchi"})
+	s.True(errors.Is(err, reform.ErrUnknownSubtype), "%+v", err)
+
+	record, err := s.q.FindByPrimaryKeyFrom(VehicleTable, car.ID)
+	s.Require().NoError(err)
+	s.Equal(car, record)
+	record, err = s.q.FindByPrimaryKeyFrom(VehicleTable, truck.ID)
+	s.Require().NoError(err)
+	s.Equal(truck, record)
+
+	s.Equal(reform.ErrNoRows, s.q.FindByPrimaryKeyTo(new(Car), truck.ID))
+	_, err = s.q.FindByPrimaryKeyFrom(TruckTable, car.ID)
+	s.Equal(reform.ErrNoRows, err)
+	_, err = s.q.FindByPrimaryKeyFrom(VehicleTable, -1)
+	s.Equal(reform.ErrNoRows, err)
+
+	// update and delete by primary key affect only rows of subtype
+	car.Kind = "truck"
+	err = s.q.Update(car)
+	s.EqualError(err, `reform: models.Car should have kind "car", got "truck"`)
+	err = s.q.UpdateColumns(car, "name")
+	s.EqualError(err, `reform: models.Car should have kind "car", got "truck"`)
+	car.Kind = ""
+	car.Name = "Golf"
+	s.NoError(s.q.Update(car))
+	s.Equal("car", car.Kind)
+	s.Equal(reform.ErrNoRows, s.q.Update(&Truck{ID: car.ID, Name: "Golf", Payload: 1}))
+	s.Equal(reform.ErrNoRows, s.q.Delete(&Truck{ID: car.ID}))
+	record, err = s.q.FindByPrimaryKeyFrom(VehicleTable, car.ID)
+	s.Require().NoError(err)
+	s.Equal(car, record)
+
+	s.NoError(s.q.Delete(truck))
+	_, err = s.q.FindByPrimaryKeyFrom(VehicleTable, truck.ID)
+	s.Equal(reform.ErrNoRows, err)
+
+	s.Panics(func() { reform.RegisterSubtypes(VehicleTable, "kind", nil) })
+	s.Panics(func() { reform.RegisterSubtypes(PersonTable, "name", map[string]reform.Table{"car": CarTable}) })
+}
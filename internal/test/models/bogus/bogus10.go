@@ -4,6 +4,5 @@ package bogus
 
 // Bogus10 is used for testing. reform:bogus
 type Bogus10 struct {
-	Bogus1 string `reform:"bogus1,pk"`
-	Bogus2 string `reform:"bogus2,pk"` // field with "reform:" tag with duplicate pk label should generate error
+	Bogus string `reform:"bogus,pk,encrypted"` // field with "reform:" tag with both pk and encrypted labels should generate error
 }
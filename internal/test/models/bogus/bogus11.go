@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus11 is used for testing. reform:bogus
+type Bogus11 struct {
+	ID    int32  `reform:"id,pk"`
+	Bogus string `reform:"bogus,lock"` // non-integer field with "reform:" tag and lock label should generate error
+}
@@ -0,0 +1,13 @@
+package bogus
+
+import (
+	"time"
+)
+
+//go:generate reform
+
+// Bogus12 is used for testing. reform:bogus
+type Bogus12 struct {
+	ID    int32     `reform:"id,pk"`
+	Bogus time.Time `reform:"bogus,softdelete"` // non-pointer field with "reform:" tag and softdelete label should generate error
+}
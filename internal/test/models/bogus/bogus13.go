@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus13 is used for testing. reform:bogus
+type Bogus13 struct {
+	ID    int32  `reform:"id,pk"`
+	Bogus string `reform:"bogus,index,encrypted"` // field with "reform:" tag with both index and encrypted labels should generate error
+}
@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus14 is used for testing. reform:bogus
+type Bogus14 struct {
+	ID    int32    `reform:"id,pk"`
+	Bogus *Bogus13 `reform-rel:"has_many,bogus_id"` // non-slice field with has_many relation should generate error
+}
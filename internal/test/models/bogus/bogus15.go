@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus15 is used for testing. reform:bogus
+type Bogus15 struct {
+	ID    int32  `reform:"id,pk"`
+	Bogus string `reform:"bogus,autoupdate"` // non-time field with autoupdate label should generate error
+}
@@ -0,0 +1,8 @@
+package bogus
+
+//go:generate reform
+
+// Bogus16 is used for testing. reform:bogus
+type Bogus16 struct {
+	Bogus int32 `reform:"bogus,pk,generated"` // non-string field with generated label should generate error
+}
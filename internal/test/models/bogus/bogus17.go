@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus17 is used for testing. reform:bogus
+type Bogus17 struct {
+	ID    int32             `reform:"id,pk"`
+	Bogus map[string]string `reform:"bogus,index,json"` // field with "reform:" tag with both index and json labels should generate error
+}
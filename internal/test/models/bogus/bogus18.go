@@ -0,0 +1,9 @@
+package bogus
+
+//go:generate reform
+
+// Bogus18 is used for testing. reform:bogus
+type Bogus18 struct {
+	ID    int32  `reform:"id,pk"`
+	Bogus []byte `reform:"bogus,array"` // field with "reform:" tag with array label of non-array type should generate error
+}
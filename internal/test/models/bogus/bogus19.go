@@ -0,0 +1,14 @@
+package bogus
+
+//go:generate reform
+
+// BogusEmbedded is embedded into other structs used for testing.
+type BogusEmbedded struct {
+	Bogus string `reform:"bogus"`
+}
+
+// Bogus19 is used for testing. reform:bogus
+type Bogus19 struct {
+	ID             int32 `reform:"id,pk"`
+	*BogusEmbedded       // embedded pointer to struct with "reform:" tags should generate error
+}
@@ -0,0 +1,10 @@
+package bogus
+
+//go:generate reform
+
+// Bogus20 is used for testing. reform:bogus
+type Bogus20 struct {
+	ID            int32  `reform:"id,pk"`
+	Bogus         string `reform:"bogus2"`
+	BogusEmbedded        // embedded struct (declared in other file) with field with the same name should generate error
+}
@@ -0,0 +1,10 @@
+package bogus
+
+//go:generate reform
+
+// Bogus21 is used for testing.
+//
+//reform:bogus view
+type Bogus21 struct {
+	Bogus string `reform:"bogus,pk"`
+}
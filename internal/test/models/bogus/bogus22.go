@@ -0,0 +1,13 @@
+package bogus
+
+import (
+	"github.com/AlekSi/reform/internal/test/models/contact"
+)
+
+//go:generate reform
+
+// Bogus22 is used for testing. reform:bogus
+type Bogus22 struct {
+	ID               int32 `reform:"id,pk"`
+	*contact.Contact       // embedded pointer to struct from other package with "reform:" tags should generate error
+}
@@ -0,0 +1,8 @@
+// Package contact contains a struct embedded into models from another package.
+package contact
+
+// Contact is embedded into models with contact columns.
+// It is declared in a separate package to check that reform finds it.
+type Contact struct {
+	Email *string `reform:"email"`
+}
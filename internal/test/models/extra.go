@@ -0,0 +1,147 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/internal/test/models/contact"
+)
+
+//go:generate reform -equal -ddl -json
+
+// Secret represents row in table secrets with encrypted columns.
+//
+//reform:secrets
+type Secret struct {
+	ID   int32   `reform:"id,pk"`
+	Name string  `reform:"name"`
+	Data string  `reform:"data,encrypted,sensitive"`
+	Note *[]byte `reform:"note,encrypted"`
+}
+
+// ProjectRole represents row in table project_roles with composite primary key.
+//
+//reform:project_roles
+type ProjectRole struct {
+	ProjectID string `reform:"project_id,pk"`
+	PersonID  int32  `reform:"person_id,pk"`
+	Role      string `reform:"role"`
+	Version   int32  `reform:"version,lock"`
+
+	Project *Project `reform-rel:"belongs_to,project_id"`
+	Hooks   []string // called hooks
+}
+
+// AfterInsert records hook call.
+func (pr *ProjectRole) AfterInsert(ctx context.Context) error {
+	pr.Hooks = append(pr.Hooks, "AfterInsert")
+	return ctx.Err()
+}
+
+// AfterUpdate records hook call.
+func (pr *ProjectRole) AfterUpdate() error {
+	pr.Hooks = append(pr.Hooks, "AfterUpdate")
+	return nil
+}
+
+// AfterDelete records hook call.
+func (pr *ProjectRole) AfterDelete() error {
+	pr.Hooks = append(pr.Hooks, "AfterDelete")
+	return nil
+}
+
+// Memo represents row in table memos with soft delete and automatically set timestamps
+// from embedded struct.
+//
+//reform:memos
+type Memo struct {
+	ID        int32      `reform:"id,pk"`
+	Text      string     `reform:"text"`
+	DeletedAt *time.Time `reform:"deleted_at,softdelete"`
+	Timestamps
+}
+
+// PersonContact represents row in table people as a view with columns from embedded struct
+// declared in another package.
+//
+//reform:people view
+type PersonContact struct {
+	Name string `reform:"name"`
+	contact.Contact
+}
+
+// EventMeta is stored as JSON in events table.
+type EventMeta struct {
+	Source string   `json:"source"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// Event represents row in table events with JSON columns.
+//
+//reform:events
+type Event struct {
+	ID      int32                  `reform:"id,pk"`
+	Payload map[string]interface{} `reform:"payload,json"`
+	Meta    *EventMeta             `reform:"meta,json"`
+}
+
+// Article represents row in table articles with PostgreSQL array columns.
+//
+//reform:articles
+type Article struct {
+	ID     int32    `reform:"id,pk"`
+	Tags   []string `reform:"tags,array"`
+	Scores []int64  `reform:"scores,array"`
+}
+
+// PersonProjectCount represents row in PostgreSQL materialized view person_project_counts.
+//
+//reform:person_project_counts view
+type PersonProjectCount struct {
+	PersonID int32 `reform:"person_id"`
+	Projects int64 `reform:"projects"`
+}
+
+// Vehicle represents row in table vehicles with single-table inheritance:
+// kind column determines concrete subtype, Car or Truck.
+//
+//reform:vehicles
+type Vehicle struct {
+	ID   int32  `reform:"id,pk"`
+	Kind string `reform:"kind"`
+	Name string `reform:"name"`
+}
+
+// Car represents row in table vehicles with kind "car".
+//
+//reform:vehicles
+type Car struct {
+	ID    int32  `reform:"id,pk"`
+	Kind  string `reform:"kind"`
+	Name  string `reform:"name"`
+	Doors int32  `reform:"doors"`
+}
+
+// Truck represents row in table vehicles with kind "truck".
+//
+//reform:vehicles
+type Truck struct {
+	ID      int32  `reform:"id,pk"`
+	Kind    string `reform:"kind"`
+	Name    string `reform:"name"`
+	Payload int64  `reform:"payload"`
+}
+
+// BeforeInsert returns context's error, if any.
+func (s *Secret) BeforeInsert(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// check interfaces
+var (
+	_ reform.BeforeInserterContext = new(Secret)
+	_ reform.AfterInserterContext  = new(ProjectRole)
+	_ reform.AfterUpdater          = new(ProjectRole)
+	_ reform.AfterDeleter          = new(ProjectRole)
+)
@@ -0,0 +1,1874 @@
+package models
+
+// generated with github.com/AlekSi/reform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/parse"
+)
+
+type secretTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see SecretColumns.
+	C struct {
+		ID   string
+		Name string
+		Data string
+		Note string
+	}
+}
+
+// Name returns a view or table name in SQL database (secrets).
+func (v *secretTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *secretTable) Columns() []string {
+	return []string{"id", "name", "data", "note"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *secretTable) NewStruct() reform.Struct {
+	return new(Secret)
+}
+
+// EncryptedColumns returns a new slice of flags: true for encrypted columns for that view or table in SQL database.
+func (v *secretTable) EncryptedColumns() []bool {
+	return []bool{false, false, true, true}
+}
+
+// SensitiveColumns returns a new slice of flags: true for sensitive columns for that view or table in SQL database.
+func (v *secretTable) SensitiveColumns() []bool {
+	return []bool{false, false, true, false}
+}
+
+// NewRecord makes a new record for that table.
+func (v *secretTable) NewRecord() reform.Record {
+	return new(Secret)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *secretTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *secretTable) DDLColumns() []reform.Column {
+	return []reform.Column{
+		{Name: "id", Type: reform.IntColumn, PK: true, AutoIncrement: true},
+		{Name: "name", Type: reform.StringColumn},
+		{Name: "data", Type: reform.BytesColumn},
+		{Name: "note", Type: reform.BytesColumn, Nullable: true},
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *secretTable) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+// SecretTable represents secrets view or table in SQL database.
+var SecretTable = &secretTable{
+	s: parse.StructInfo{Type: "Secret", SQLName: "secrets", Fields: []parse.FieldInfo{{Name: "ID", Type: "int32", Column: "id"}, {Name: "Name", Type: "string", Column: "name"}, {Name: "Data", Type: "string", Column: "data", Encrypted: true, Sensitive: true}, {Name: "Note", Type: "*[]byte", Column: "note", Encrypted: true}}, PKFieldIndex: 0},
+	z: new(Secret).Values(),
+	C: SecretColumns,
+}
+
+// SecretColumns contains column names of secrets view or table in SQL database.
+// Use them (or SecretTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var SecretColumns = struct {
+	ID   string
+	Name string
+	Data string
+	Note string
+}{
+	ID:   "id",
+	Name: "name",
+	Data: "data",
+	Note: "note",
+}
+
+// String returns a string representation of this struct or record.
+func (s Secret) String() string {
+	res := make([]string, 4)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Name: " + reform.Inspect(s.Name, true)
+	res[2] = "Data: " + reform.Redacted
+	res[3] = "Note: " + reform.Inspect(s.Note, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s Secret) GoString() string {
+	return "Secret{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *Secret) Equal(other *Secret) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys, omitting sensitive fields.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s, 2)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record, ignoring sensitive fields.
+func (s *Secret) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b, 2)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Secret) Clone() *Secret {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.Note != nil {
+		v := *s.Note
+		if v != nil {
+			v = make([]byte, len(v))
+			copy(v, *s.Note)
+		}
+		c.Note = &v
+	}
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *Secret) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		s.Name,
+		s.Data,
+		s.Note,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *Secret) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Name,
+		&s.Data,
+		&s.Note,
+	}
+}
+
+// View returns View object for that struct.
+func (s *Secret) View() reform.View {
+	return SecretTable
+}
+
+// Table returns Table object for that record.
+func (s *Secret) Table() reform.Table {
+	return SecretTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Secret) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Secret) PKPointer() interface{} {
+	return &s.ID
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Secret) HasPK() bool {
+	return s.ID != SecretTable.z[SecretTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key.
+func (s *Secret) SetPK(pk interface{}) {
+	if i64, ok := pk.(int64); ok {
+		s.ID = int32(i64)
+	} else {
+		s.ID = pk.(int32)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.View          = SecretTable
+	_ reform.Struct        = new(Secret)
+	_ reform.Table         = SecretTable
+	_ reform.Record        = new(Secret)
+	_ reform.EncryptedView = SecretTable
+	_ reform.SensitiveView = SecretTable
+	_ reform.DDLTable      = SecretTable
+	_ fmt.Stringer         = new(Secret)
+	_ fmt.GoStringer       = new(Secret)
+)
+
+type projectRoleTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see ProjectRoleColumns.
+	C struct {
+		ProjectID string
+		PersonID  string
+		Role      string
+		Version   string
+	}
+}
+
+// Name returns a view or table name in SQL database (project_roles).
+func (v *projectRoleTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *projectRoleTable) Columns() []string {
+	return []string{"project_id", "person_id", "role", "version"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *projectRoleTable) NewStruct() reform.Struct {
+	return new(ProjectRole)
+}
+
+// LockColumnIndex returns an index of optimistic locking version column for that table in SQL database.
+func (v *projectRoleTable) LockColumnIndex() uint {
+	return 3
+}
+
+// NewRecord makes a new record for that table.
+func (v *projectRoleTable) NewRecord() reform.Record {
+	return new(ProjectRole)
+}
+
+// PKColumnIndex returns an index of the first primary key column for that table in SQL database.
+func (v *projectRoleTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// PKColumnIndexes returns indexes of primary key columns for that table in SQL database.
+func (v *projectRoleTable) PKColumnIndexes() []uint {
+	return []uint{0, 1}
+}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *projectRoleTable) DDLColumns() []reform.Column {
+	return []reform.Column{
+		{Name: "project_id", Type: reform.StringColumn, PK: true},
+		{Name: "person_id", Type: reform.IntColumn, PK: true},
+		{Name: "role", Type: reform.StringColumn},
+		{Name: "version", Type: reform.IntColumn},
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *projectRoleTable) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+// PreloadProject loads belongs_to relation Project for all given records
+// with a single query (or a few for a large number of records) instead of a query per record.
+func (v *projectRoleTable) PreloadProject(q *reform.Querier, records []*ProjectRole) error {
+	keys := make([]interface{}, len(records))
+	for i, r := range records {
+		keys[i] = r.ProjectID
+	}
+	related := new(Project).Table()
+	groups, err := q.FindAllGrouped(related, related.Columns()[related.PKColumnIndex()], keys...)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		r.Project = nil
+		if structs := groups.Get(r.ProjectID); len(structs) > 0 {
+			r.Project = structs[0].(*Project)
+		}
+	}
+	return nil
+}
+
+// ProjectRoleTable represents project_roles view or table in SQL database.
+var ProjectRoleTable = &projectRoleTable{
+	s: parse.StructInfo{Type: "ProjectRole", SQLName: "project_roles", Fields: []parse.FieldInfo{{Name: "ProjectID", Type: "string", Column: "project_id"}, {Name: "PersonID", Type: "int32", Column: "person_id"}, {Name: "Role", Type: "string", Column: "role"}, {Name: "Version", Type: "int32", Column: "version", Lock: true}}, PKFieldIndex: 0, PKFieldIndexes: []int{0, 1}, Relations: []parse.RelationInfo{{Name: "Project", Type: "Project", Kind: "belongs_to", Column: "project_id"}}},
+	z: new(ProjectRole).Values(),
+	C: ProjectRoleColumns,
+}
+
+// ProjectRoleColumns contains column names of project_roles view or table in SQL database.
+// Use them (or ProjectRoleTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var ProjectRoleColumns = struct {
+	ProjectID string
+	PersonID  string
+	Role      string
+	Version   string
+}{
+	ProjectID: "project_id",
+	PersonID:  "person_id",
+	Role:      "role",
+	Version:   "version",
+}
+
+// String returns a string representation of this struct or record.
+func (s ProjectRole) String() string {
+	res := make([]string, 4)
+	res[0] = "ProjectID: " + reform.Inspect(s.ProjectID, true)
+	res[1] = "PersonID: " + reform.Inspect(s.PersonID, true)
+	res[2] = "Role: " + reform.Inspect(s.Role, true)
+	res[3] = "Version: " + reform.Inspect(s.Version, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s ProjectRole) GoString() string {
+	return "ProjectRole{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *ProjectRole) Equal(other *ProjectRole) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys.
+func (s ProjectRole) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record.
+func (s *ProjectRole) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *ProjectRole) Clone() *ProjectRole {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *ProjectRole) Values() []interface{} {
+	return []interface{}{
+		s.ProjectID,
+		s.PersonID,
+		s.Role,
+		s.Version,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *ProjectRole) Pointers() []interface{} {
+	return []interface{}{
+		&s.ProjectID,
+		&s.PersonID,
+		&s.Role,
+		&s.Version,
+	}
+}
+
+// LoadProject loads belongs_to relation Project for this record.
+func (s *ProjectRole) LoadProject(q *reform.Querier) error {
+	return ProjectRoleTable.PreloadProject(q, []*ProjectRole{s})
+}
+
+// View returns View object for that struct.
+func (s *ProjectRole) View() reform.View {
+	return ProjectRoleTable
+}
+
+// Table returns Table object for that record.
+func (s *ProjectRole) Table() reform.Table {
+	return ProjectRoleTable
+}
+
+// PKValue returns a value of the first field of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *ProjectRole) PKValue() interface{} {
+	return s.ProjectID
+}
+
+// PKPointer returns a pointer to the first primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *ProjectRole) PKPointer() interface{} {
+	return &s.ProjectID
+}
+
+// PKValues returns values of primary key for that record.
+// Returned interface{} values are never untyped nils.
+func (s *ProjectRole) PKValues() []interface{} {
+	return []interface{}{
+		s.ProjectID,
+		s.PersonID,
+	}
+}
+
+// PKPointers returns pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *ProjectRole) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ProjectID,
+		&s.PersonID,
+	}
+}
+
+// HasPK returns true if record has all primary key fields set to non-zero values, false otherwise.
+func (s *ProjectRole) HasPK() bool {
+	if s.ProjectID == ProjectRoleTable.z[0] {
+		return false
+	}
+	if s.PersonID == ProjectRoleTable.z[1] {
+		return false
+	}
+	return true
+}
+
+// SetPK sets record primary key (the first field of it).
+func (s *ProjectRole) SetPK(pk interface{}) {
+	s.ProjectID = pk.(string)
+}
+
+// check interfaces
+var (
+	_ reform.View              = ProjectRoleTable
+	_ reform.Struct            = new(ProjectRole)
+	_ reform.Table             = ProjectRoleTable
+	_ reform.Record            = new(ProjectRole)
+	_ reform.CompositePKTable  = ProjectRoleTable
+	_ reform.CompositePKRecord = new(ProjectRole)
+	_ reform.LockingTable      = ProjectRoleTable
+	_ reform.DDLTable          = ProjectRoleTable
+	_ fmt.Stringer             = new(ProjectRole)
+	_ fmt.GoStringer           = new(ProjectRole)
+)
+
+type memoTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see MemoColumns.
+	C struct {
+		ID        string
+		Text      string
+		DeletedAt string
+		CreatedAt string
+		UpdatedAt string
+	}
+}
+
+// Name returns a view or table name in SQL database (memos).
+func (v *memoTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *memoTable) Columns() []string {
+	return []string{"id", "text", "deleted_at", "created_at", "updated_at"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *memoTable) NewStruct() reform.Struct {
+	return new(Memo)
+}
+
+// AutoTimestamps returns automatically set timestamp columns for that view or table in SQL database.
+func (v *memoTable) AutoTimestamps() []reform.AutoTimestamp {
+	return []reform.AutoTimestamp{
+		{Index: 3, OnUpdate: false, Precision: reform.SecondPrecision},
+		{Index: 4, OnUpdate: true, Precision: reform.SecondPrecision},
+	}
+}
+
+// SoftDeleteColumnIndex returns an index of soft delete timestamp column for that table in SQL database.
+func (v *memoTable) SoftDeleteColumnIndex() uint {
+	return 2
+}
+
+// NewRecord makes a new record for that table.
+func (v *memoTable) NewRecord() reform.Record {
+	return new(Memo)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *memoTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *memoTable) DDLColumns() []reform.Column {
+	return []reform.Column{
+		{Name: "id", Type: reform.IntColumn, PK: true, AutoIncrement: true},
+		{Name: "text", Type: reform.StringColumn},
+		{Name: "deleted_at", Type: reform.TimeColumn, Nullable: true},
+		{Name: "created_at", Type: reform.TimeColumn},
+		{Name: "updated_at", Type: reform.TimeColumn, Nullable: true},
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *memoTable) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+// MemoTable represents memos view or table in SQL database.
+var MemoTable = &memoTable{
+	s: parse.StructInfo{Type: "Memo", SQLName: "memos", Fields: []parse.FieldInfo{{Name: "ID", Type: "int32", Column: "id"}, {Name: "Text", Type: "string", Column: "text"}, {Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SoftDelete: true}, {Name: "CreatedAt", Type: "time.Time", Column: "created_at", AutoCreate: true}, {Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at", AutoUpdate: true}}, PKFieldIndex: 0},
+	z: new(Memo).Values(),
+	C: MemoColumns,
+}
+
+// MemoColumns contains column names of memos view or table in SQL database.
+// Use them (or MemoTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var MemoColumns = struct {
+	ID        string
+	Text      string
+	DeletedAt string
+	CreatedAt string
+	UpdatedAt string
+}{
+	ID:        "id",
+	Text:      "text",
+	DeletedAt: "deleted_at",
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
+}
+
+// String returns a string representation of this struct or record.
+func (s Memo) String() string {
+	res := make([]string, 5)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Text: " + reform.Inspect(s.Text, true)
+	res[2] = "DeletedAt: " + reform.Inspect(s.DeletedAt, true)
+	res[3] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[4] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s Memo) GoString() string {
+	return "Memo{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *Memo) Equal(other *Memo) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys.
+func (s Memo) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record.
+func (s *Memo) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Memo) Clone() *Memo {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.DeletedAt != nil {
+		v := *s.DeletedAt
+		c.DeletedAt = &v
+	}
+	if s.UpdatedAt != nil {
+		v := *s.UpdatedAt
+		c.UpdatedAt = &v
+	}
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *Memo) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		s.Text,
+		s.DeletedAt,
+		s.CreatedAt,
+		s.UpdatedAt,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *Memo) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Text,
+		&s.DeletedAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	}
+}
+
+// View returns View object for that struct.
+func (s *Memo) View() reform.View {
+	return MemoTable
+}
+
+// Table returns Table object for that record.
+func (s *Memo) Table() reform.Table {
+	return MemoTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Memo) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Memo) PKPointer() interface{} {
+	return &s.ID
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Memo) HasPK() bool {
+	return s.ID != MemoTable.z[MemoTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key.
+func (s *Memo) SetPK(pk interface{}) {
+	if i64, ok := pk.(int64); ok {
+		s.ID = int32(i64)
+	} else {
+		s.ID = pk.(int32)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.View               = MemoTable
+	_ reform.Struct             = new(Memo)
+	_ reform.Table              = MemoTable
+	_ reform.Record             = new(Memo)
+	_ reform.SoftDeleteTable    = MemoTable
+	_ reform.AutoTimestampsView = MemoTable
+	_ reform.DDLTable           = MemoTable
+	_ fmt.Stringer              = new(Memo)
+	_ fmt.GoStringer            = new(Memo)
+)
+
+type personContactView struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see PersonContactColumns.
+	C struct {
+		Name  string
+		Email string
+	}
+}
+
+// Name returns a view or table name in SQL database (people).
+func (v *personContactView) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *personContactView) Columns() []string {
+	return []string{"name", "email"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *personContactView) NewStruct() reform.Struct {
+	return new(PersonContact)
+}
+
+// PersonContactView represents people view or table in SQL database.
+var PersonContactView = &personContactView{
+	s: parse.StructInfo{Type: "PersonContact", SQLName: "people", Fields: []parse.FieldInfo{{Name: "Name", Type: "string", Column: "name"}, {Name: "Email", Type: "*string", Column: "email"}}, PKFieldIndex: -1, View: true},
+	z: new(PersonContact).Values(),
+	C: PersonContactColumns,
+}
+
+// PersonContactColumns contains column names of people view or table in SQL database.
+// Use them (or PersonContactView.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var PersonContactColumns = struct {
+	Name  string
+	Email string
+}{
+	Name:  "name",
+	Email: "email",
+}
+
+// String returns a string representation of this struct or record.
+func (s PersonContact) String() string {
+	res := make([]string, 2)
+	res[0] = "Name: " + reform.Inspect(s.Name, true)
+	res[1] = "Email: " + reform.Inspect(s.Email, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s PersonContact) GoString() string {
+	return "PersonContact{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *PersonContact) Equal(other *PersonContact) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys.
+func (s PersonContact) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record.
+func (s *PersonContact) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *PersonContact) Clone() *PersonContact {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.Email != nil {
+		v := *s.Email
+		c.Email = &v
+	}
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *PersonContact) Values() []interface{} {
+	return []interface{}{
+		s.Name,
+		s.Email,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *PersonContact) Pointers() []interface{} {
+	return []interface{}{
+		&s.Name,
+		&s.Email,
+	}
+}
+
+// View returns View object for that struct.
+func (s *PersonContact) View() reform.View {
+	return PersonContactView
+}
+
+// check interfaces
+var (
+	_ reform.View    = PersonContactView
+	_ reform.Struct  = new(PersonContact)
+	_ fmt.Stringer   = new(PersonContact)
+	_ fmt.GoStringer = new(PersonContact)
+)
+
+type eventTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see EventColumns.
+	C struct {
+		ID      string
+		Payload string
+		Meta    string
+	}
+}
+
+// Name returns a view or table name in SQL database (events).
+func (v *eventTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *eventTable) Columns() []string {
+	return []string{"id", "payload", "meta"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *eventTable) NewStruct() reform.Struct {
+	return new(Event)
+}
+
+// NewRecord makes a new record for that table.
+func (v *eventTable) NewRecord() reform.Record {
+	return new(Event)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *eventTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *eventTable) DDLColumns() []reform.Column {
+	return []reform.Column{
+		{Name: "id", Type: reform.IntColumn, PK: true, AutoIncrement: true},
+		{Name: "payload", Type: reform.JSONColumn, Nullable: true},
+		{Name: "meta", Type: reform.JSONColumn, Nullable: true},
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *eventTable) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+// EventTable represents events view or table in SQL database.
+var EventTable = &eventTable{
+	s: parse.StructInfo{Type: "Event", SQLName: "events", Fields: []parse.FieldInfo{{Name: "ID", Type: "int32", Column: "id"}, {Name: "Payload", Type: "map[string]interface {}", Column: "payload", JSON: true}, {Name: "Meta", Type: "*EventMeta", Column: "meta", JSON: true}}, PKFieldIndex: 0},
+	z: new(Event).Values(),
+	C: EventColumns,
+}
+
+// EventColumns contains column names of events view or table in SQL database.
+// Use them (or EventTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var EventColumns = struct {
+	ID      string
+	Payload string
+	Meta    string
+}{
+	ID:      "id",
+	Payload: "payload",
+	Meta:    "meta",
+}
+
+// String returns a string representation of this struct or record.
+func (s Event) String() string {
+	res := make([]string, 3)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Payload: " + reform.Inspect(s.Payload, true)
+	res[2] = "Meta: " + reform.Inspect(s.Meta, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s Event) GoString() string {
+	return "Event{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *Event) Equal(other *Event) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys.
+func (s Event) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record.
+func (s *Event) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Event) Clone() *Event {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Payload, _ = reform.DeepCopy(s.Payload).(map[string]interface{})
+	c.Meta, _ = reform.DeepCopy(s.Meta).(*EventMeta)
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils. Values of fields with "json" label are wrapped with reform.JSON.
+func (s *Event) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		reform.JSON{V: s.Payload},
+		reform.JSON{V: s.Meta},
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils. Pointers to fields with "json" label are wrapped with reform.JSON.
+func (s *Event) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&reform.JSON{V: &s.Payload},
+		&reform.JSON{V: &s.Meta},
+	}
+}
+
+// View returns View object for that struct.
+func (s *Event) View() reform.View {
+	return EventTable
+}
+
+// Table returns Table object for that record.
+func (s *Event) Table() reform.Table {
+	return EventTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Event) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Event) PKPointer() interface{} {
+	return &s.ID
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Event) HasPK() bool {
+	return s.ID != EventTable.z[EventTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key.
+func (s *Event) SetPK(pk interface{}) {
+	if i64, ok := pk.(int64); ok {
+		s.ID = int32(i64)
+	} else {
+		s.ID = pk.(int32)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.View     = EventTable
+	_ reform.Struct   = new(Event)
+	_ reform.Table    = EventTable
+	_ reform.Record   = new(Event)
+	_ reform.DDLTable = EventTable
+	_ fmt.Stringer    = new(Event)
+	_ fmt.GoStringer  = new(Event)
+)
+
+type articleTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see ArticleColumns.
+	C struct {
+		ID     string
+		Tags   string
+		Scores string
+	}
+}
+
+// Name returns a view or table name in SQL database (articles).
+func (v *articleTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *articleTable) Columns() []string {
+	return []string{"id", "tags", "scores"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *articleTable) NewStruct() reform.Struct {
+	return new(Article)
+}
+
+// NewRecord makes a new record for that table.
+func (v *articleTable) NewRecord() reform.Record {
+	return new(Article)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *articleTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *articleTable) DDLColumns() []reform.Column {
+	return []reform.Column{
+		{Name: "id", Type: reform.IntColumn, PK: true, AutoIncrement: true},
+		{Name: "tags", Type: reform.StringColumn, Array: true, Nullable: true},
+		{Name: "scores", Type: reform.BigIntColumn, Array: true, Nullable: true},
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *articleTable) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+// ArticleTable represents articles view or table in SQL database.
+var ArticleTable = &articleTable{
+	s: parse.StructInfo{Type: "Article", SQLName: "articles", Fields: []parse.FieldInfo{{Name: "ID", Type: "int32", Column: "id"}, {Name: "Tags", Type: "[]string", Column: "tags", Array: true}, {Name: "Scores", Type: "[]int64", Column: "scores", Array: true}}, PKFieldIndex: 0},
+	z: new(Article).Values(),
+	C: ArticleColumns,
+}
+
+// ArticleColumns contains column names of articles view or table in SQL database.
+// Use them (or ArticleTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var ArticleColumns = struct {
+	ID     string
+	Tags   string
+	Scores string
+}{
+	ID:     "id",
+	Tags:   "tags",
+	Scores: "scores",
+}
+
+// String returns a string representation of this struct or record.
+func (s Article) String() string {
+	res := make([]string, 3)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Tags: " + reform.Inspect(s.Tags, true)
+	res[2] = "Scores: " + reform.Inspect(s.Scores, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s Article) GoString() string {
+	return "Article{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *Article) Equal(other *Article) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys.
+func (s Article) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record.
+func (s *Article) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Article) Clone() *Article {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.Tags != nil {
+		c.Tags = make([]string, len(s.Tags))
+		copy(c.Tags, s.Tags)
+	}
+	if s.Scores != nil {
+		c.Scores = make([]int64, len(s.Scores))
+		copy(c.Scores, s.Scores)
+	}
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils. Values of fields with "array" label are wrapped with reform.Array.
+func (s *Article) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		reform.Array{V: s.Tags},
+		reform.Array{V: s.Scores},
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils. Pointers to fields with "array" label are wrapped with reform.Array.
+func (s *Article) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&reform.Array{V: &s.Tags},
+		&reform.Array{V: &s.Scores},
+	}
+}
+
+// View returns View object for that struct.
+func (s *Article) View() reform.View {
+	return ArticleTable
+}
+
+// Table returns Table object for that record.
+func (s *Article) Table() reform.Table {
+	return ArticleTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Article) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Article) PKPointer() interface{} {
+	return &s.ID
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Article) HasPK() bool {
+	return s.ID != ArticleTable.z[ArticleTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key.
+func (s *Article) SetPK(pk interface{}) {
+	if i64, ok := pk.(int64); ok {
+		s.ID = int32(i64)
+	} else {
+		s.ID = pk.(int32)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.View     = ArticleTable
+	_ reform.Struct   = new(Article)
+	_ reform.Table    = ArticleTable
+	_ reform.Record   = new(Article)
+	_ reform.DDLTable = ArticleTable
+	_ fmt.Stringer    = new(Article)
+	_ fmt.GoStringer  = new(Article)
+)
+
+type personProjectCountView struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see PersonProjectCountColumns.
+	C struct {
+		PersonID string
+		Projects string
+	}
+}
+
+// Name returns a view or table name in SQL database (person_project_counts).
+func (v *personProjectCountView) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *personProjectCountView) Columns() []string {
+	return []string{"person_id", "projects"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *personProjectCountView) NewStruct() reform.Struct {
+	return new(PersonProjectCount)
+}
+
+// PersonProjectCountView represents person_project_counts view or table in SQL database.
+var PersonProjectCountView = &personProjectCountView{
+	s: parse.StructInfo{Type: "PersonProjectCount", SQLName: "person_project_counts", Fields: []parse.FieldInfo{{Name: "PersonID", Type: "int32", Column: "person_id"}, {Name: "Projects", Type: "int64", Column: "projects"}}, PKFieldIndex: -1, View: true},
+	z: new(PersonProjectCount).Values(),
+	C: PersonProjectCountColumns,
+}
+
+// PersonProjectCountColumns contains column names of person_project_counts view or table in SQL database.
+// Use them (or PersonProjectCountView.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var PersonProjectCountColumns = struct {
+	PersonID string
+	Projects string
+}{
+	PersonID: "person_id",
+	Projects: "projects",
+}
+
+// String returns a string representation of this struct or record.
+func (s PersonProjectCount) String() string {
+	res := make([]string, 2)
+	res[0] = "PersonID: " + reform.Inspect(s.PersonID, true)
+	res[1] = "Projects: " + reform.Inspect(s.Projects, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s PersonProjectCount) GoString() string {
+	return "PersonProjectCount{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *PersonProjectCount) Equal(other *PersonProjectCount) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys.
+func (s PersonProjectCount) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record.
+func (s *PersonProjectCount) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *PersonProjectCount) Clone() *PersonProjectCount {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *PersonProjectCount) Values() []interface{} {
+	return []interface{}{
+		s.PersonID,
+		s.Projects,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *PersonProjectCount) Pointers() []interface{} {
+	return []interface{}{
+		&s.PersonID,
+		&s.Projects,
+	}
+}
+
+// View returns View object for that struct.
+func (s *PersonProjectCount) View() reform.View {
+	return PersonProjectCountView
+}
+
+// check interfaces
+var (
+	_ reform.View    = PersonProjectCountView
+	_ reform.Struct  = new(PersonProjectCount)
+	_ fmt.Stringer   = new(PersonProjectCount)
+	_ fmt.GoStringer = new(PersonProjectCount)
+)
+
+type vehicleTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see VehicleColumns.
+	C struct {
+		ID   string
+		Kind string
+		Name string
+	}
+}
+
+// Name returns a view or table name in SQL database (vehicles).
+func (v *vehicleTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *vehicleTable) Columns() []string {
+	return []string{"id", "kind", "name"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *vehicleTable) NewStruct() reform.Struct {
+	return new(Vehicle)
+}
+
+// NewRecord makes a new record for that table.
+func (v *vehicleTable) NewRecord() reform.Record {
+	return new(Vehicle)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *vehicleTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *vehicleTable) DDLColumns() []reform.Column {
+	return []reform.Column{
+		{Name: "id", Type: reform.IntColumn, PK: true, AutoIncrement: true},
+		{Name: "kind", Type: reform.StringColumn},
+		{Name: "name", Type: reform.StringColumn},
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *vehicleTable) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+// VehicleTable represents vehicles view or table in SQL database.
+var VehicleTable = &vehicleTable{
+	s: parse.StructInfo{Type: "Vehicle", SQLName: "vehicles", Fields: []parse.FieldInfo{{Name: "ID", Type: "int32", Column: "id"}, {Name: "Kind", Type: "string", Column: "kind"}, {Name: "Name", Type: "string", Column: "name"}}, PKFieldIndex: 0},
+	z: new(Vehicle).Values(),
+	C: VehicleColumns,
+}
+
+// VehicleColumns contains column names of vehicles view or table in SQL database.
+// Use them (or VehicleTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var VehicleColumns = struct {
+	ID   string
+	Kind string
+	Name string
+}{
+	ID:   "id",
+	Kind: "kind",
+	Name: "name",
+}
+
+// String returns a string representation of this struct or record.
+func (s Vehicle) String() string {
+	res := make([]string, 3)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Kind: " + reform.Inspect(s.Kind, true)
+	res[2] = "Name: " + reform.Inspect(s.Name, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s Vehicle) GoString() string {
+	return "Vehicle{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *Vehicle) Equal(other *Vehicle) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys.
+func (s Vehicle) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record.
+func (s *Vehicle) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Vehicle) Clone() *Vehicle {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *Vehicle) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		s.Kind,
+		s.Name,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *Vehicle) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Kind,
+		&s.Name,
+	}
+}
+
+// View returns View object for that struct.
+func (s *Vehicle) View() reform.View {
+	return VehicleTable
+}
+
+// Table returns Table object for that record.
+func (s *Vehicle) Table() reform.Table {
+	return VehicleTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Vehicle) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Vehicle) PKPointer() interface{} {
+	return &s.ID
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Vehicle) HasPK() bool {
+	return s.ID != VehicleTable.z[VehicleTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key.
+func (s *Vehicle) SetPK(pk interface{}) {
+	if i64, ok := pk.(int64); ok {
+		s.ID = int32(i64)
+	} else {
+		s.ID = pk.(int32)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.View     = VehicleTable
+	_ reform.Struct   = new(Vehicle)
+	_ reform.Table    = VehicleTable
+	_ reform.Record   = new(Vehicle)
+	_ reform.DDLTable = VehicleTable
+	_ fmt.Stringer    = new(Vehicle)
+	_ fmt.GoStringer  = new(Vehicle)
+)
+
+type carTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see CarColumns.
+	C struct {
+		ID    string
+		Kind  string
+		Name  string
+		Doors string
+	}
+}
+
+// Name returns a view or table name in SQL database (vehicles).
+func (v *carTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *carTable) Columns() []string {
+	return []string{"id", "kind", "name", "doors"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *carTable) NewStruct() reform.Struct {
+	return new(Car)
+}
+
+// NewRecord makes a new record for that table.
+func (v *carTable) NewRecord() reform.Record {
+	return new(Car)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *carTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *carTable) DDLColumns() []reform.Column {
+	return []reform.Column{
+		{Name: "id", Type: reform.IntColumn, PK: true, AutoIncrement: true},
+		{Name: "kind", Type: reform.StringColumn},
+		{Name: "name", Type: reform.StringColumn},
+		{Name: "doors", Type: reform.IntColumn},
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *carTable) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+// CarTable represents vehicles view or table in SQL database.
+var CarTable = &carTable{
+	s: parse.StructInfo{Type: "Car", SQLName: "vehicles", Fields: []parse.FieldInfo{{Name: "ID", Type: "int32", Column: "id"}, {Name: "Kind", Type: "string", Column: "kind"}, {Name: "Name", Type: "string", Column: "name"}, {Name: "Doors", Type: "int32", Column: "doors"}}, PKFieldIndex: 0},
+	z: new(Car).Values(),
+	C: CarColumns,
+}
+
+// CarColumns contains column names of vehicles view or table in SQL database.
+// Use them (or CarTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var CarColumns = struct {
+	ID    string
+	Kind  string
+	Name  string
+	Doors string
+}{
+	ID:    "id",
+	Kind:  "kind",
+	Name:  "name",
+	Doors: "doors",
+}
+
+// String returns a string representation of this struct or record.
+func (s Car) String() string {
+	res := make([]string, 4)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Kind: " + reform.Inspect(s.Kind, true)
+	res[2] = "Name: " + reform.Inspect(s.Name, true)
+	res[3] = "Doors: " + reform.Inspect(s.Doors, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s Car) GoString() string {
+	return "Car{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *Car) Equal(other *Car) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys.
+func (s Car) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record.
+func (s *Car) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Car) Clone() *Car {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *Car) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		s.Kind,
+		s.Name,
+		s.Doors,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *Car) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Kind,
+		&s.Name,
+		&s.Doors,
+	}
+}
+
+// View returns View object for that struct.
+func (s *Car) View() reform.View {
+	return CarTable
+}
+
+// Table returns Table object for that record.
+func (s *Car) Table() reform.Table {
+	return CarTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Car) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Car) PKPointer() interface{} {
+	return &s.ID
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Car) HasPK() bool {
+	return s.ID != CarTable.z[CarTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key.
+func (s *Car) SetPK(pk interface{}) {
+	if i64, ok := pk.(int64); ok {
+		s.ID = int32(i64)
+	} else {
+		s.ID = pk.(int32)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.View     = CarTable
+	_ reform.Struct   = new(Car)
+	_ reform.Table    = CarTable
+	_ reform.Record   = new(Car)
+	_ reform.DDLTable = CarTable
+	_ fmt.Stringer    = new(Car)
+	_ fmt.GoStringer  = new(Car)
+)
+
+type truckTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see TruckColumns.
+	C struct {
+		ID      string
+		Kind    string
+		Name    string
+		Payload string
+	}
+}
+
+// Name returns a view or table name in SQL database (vehicles).
+func (v *truckTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *truckTable) Columns() []string {
+	return []string{"id", "kind", "name", "payload"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *truckTable) NewStruct() reform.Struct {
+	return new(Truck)
+}
+
+// NewRecord makes a new record for that table.
+func (v *truckTable) NewRecord() reform.Record {
+	return new(Truck)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *truckTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *truckTable) DDLColumns() []reform.Column {
+	return []reform.Column{
+		{Name: "id", Type: reform.IntColumn, PK: true, AutoIncrement: true},
+		{Name: "kind", Type: reform.StringColumn},
+		{Name: "name", Type: reform.StringColumn},
+		{Name: "payload", Type: reform.BigIntColumn},
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *truckTable) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+// TruckTable represents vehicles view or table in SQL database.
+var TruckTable = &truckTable{
+	s: parse.StructInfo{Type: "Truck", SQLName: "vehicles", Fields: []parse.FieldInfo{{Name: "ID", Type: "int32", Column: "id"}, {Name: "Kind", Type: "string", Column: "kind"}, {Name: "Name", Type: "string", Column: "name"}, {Name: "Payload", Type: "int64", Column: "payload"}}, PKFieldIndex: 0},
+	z: new(Truck).Values(),
+	C: TruckColumns,
+}
+
+// TruckColumns contains column names of vehicles view or table in SQL database.
+// Use them (or TruckTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var TruckColumns = struct {
+	ID      string
+	Kind    string
+	Name    string
+	Payload string
+}{
+	ID:      "id",
+	Kind:    "kind",
+	Name:    "name",
+	Payload: "payload",
+}
+
+// String returns a string representation of this struct or record.
+func (s Truck) String() string {
+	res := make([]string, 4)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Kind: " + reform.Inspect(s.Kind, true)
+	res[2] = "Name: " + reform.Inspect(s.Name, true)
+	res[3] = "Payload: " + reform.Inspect(s.Payload, true)
+	return strings.Join(res, ", ")
+}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s Truck) GoString() string {
+	return "Truck{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *Truck) Equal(other *Truck) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys.
+func (s Truck) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s)
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record.
+func (s *Truck) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b)
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Truck) Clone() *Truck {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *Truck) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		s.Kind,
+		s.Name,
+		s.Payload,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *Truck) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Kind,
+		&s.Name,
+		&s.Payload,
+	}
+}
+
+// View returns View object for that struct.
+func (s *Truck) View() reform.View {
+	return TruckTable
+}
+
+// Table returns Table object for that record.
+func (s *Truck) Table() reform.Table {
+	return TruckTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Truck) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Truck) PKPointer() interface{} {
+	return &s.ID
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Truck) HasPK() bool {
+	return s.ID != TruckTable.z[TruckTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key.
+func (s *Truck) SetPK(pk interface{}) {
+	if i64, ok := pk.(int64); ok {
+		s.ID = int32(i64)
+	} else {
+		s.ID = pk.(int32)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.View     = TruckTable
+	_ reform.Struct   = new(Truck)
+	_ reform.Table    = TruckTable
+	_ reform.Record   = new(Truck)
+	_ reform.DDLTable = TruckTable
+	_ fmt.Stringer    = new(Truck)
+	_ fmt.GoStringer  = new(Truck)
+)
+
+func init() {
+	parse.AssertUpToDate(&SecretTable.s, new(Secret))
+	parse.AssertUpToDate(&ProjectRoleTable.s, new(ProjectRole))
+	parse.AssertUpToDate(&MemoTable.s, new(Memo))
+	parse.AssertUpToDate(&PersonContactView.s, new(PersonContact))
+	parse.AssertUpToDate(&EventTable.s, new(Event))
+	parse.AssertUpToDate(&ArticleTable.s, new(Article))
+	parse.AssertUpToDate(&PersonProjectCountView.s, new(PersonProjectCount))
+	parse.AssertUpToDate(&VehicleTable.s, new(Vehicle))
+	parse.AssertUpToDate(&CarTable.s, new(Car))
+	parse.AssertUpToDate(&TruckTable.s, new(Truck))
+}
@@ -13,10 +13,12 @@ type (
 	//reform:people
 	Person struct {
 		ID        int32      `reform:"id,pk"`
-		Name      string     `reform:"name"`
-		Email     *string    `reform:"email"`
+		Name      string     `reform:"name,index"`
+		Email     *string    `reform:"email,index"`
 		CreatedAt time.Time  `reform:"created_at"`
 		UpdatedAt *time.Time `reform:"updated_at"`
+
+		Roles []*ProjectRole `reform-rel:"has_many,person_id"`
 	}
 )
 
@@ -66,7 +68,7 @@ func (p *Person) AfterFind() error {
 // (reform:projects).
 type Project struct {
 	Name  string     `reform:"name"`
-	ID    string     `reform:"id,pk"`
+	ID    string     `reform:"id,pk,generated"`
 	Start time.Time  `reform:"start"`
 	End   *time.Time `reform:"end"`
 }
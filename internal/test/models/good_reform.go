@@ -13,6 +13,15 @@ import (
 type personTable struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see PersonColumns.
+	C struct {
+		ID        string
+		Name      string
+		Email     string
+		CreatedAt string
+		UpdatedAt string
+	}
 }
 
 // Name returns a view or table name in SQL database (people).
@@ -40,10 +49,114 @@ func (v *personTable) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// FindByName queries people with name column and arg and returns the first found Person.
+// Nil arg matches NULL values. If there are no rows in result, it returns nil, reform.ErrNoRows.
+func (v *personTable) FindByName(q *reform.Querier, arg string) (*Person, error) {
+	tail, args, err := reform.Where(reform.Eq("name", arg)).Limit(1).Build(q.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	str, err := q.SelectOneFrom(v, tail, args...)
+	if err != nil {
+		return nil, err
+	}
+	return str.(*Person), nil
+}
+
+// FindAllByName queries people with name column and arg and returns a slice of found Persons.
+// Nil arg matches NULL values. If error is encountered during iteration, partial result and error will be returned.
+// Error is never reform.ErrNoRows.
+func (v *personTable) FindAllByName(q *reform.Querier, arg string) ([]*Person, error) {
+	tail, args, err := reform.Where(reform.Eq("name", arg)).Build(q.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	structs, err := q.SelectAllFrom(v, tail, args...)
+	if structs == nil {
+		return nil, err
+	}
+	res := make([]*Person, len(structs))
+	for i, str := range structs {
+		res[i] = str.(*Person)
+	}
+	return res, err
+}
+
+// FindByEmail queries people with email column and arg and returns the first found Person.
+// Nil arg matches NULL values. If there are no rows in result, it returns nil, reform.ErrNoRows.
+func (v *personTable) FindByEmail(q *reform.Querier, arg *string) (*Person, error) {
+	tail, args, err := reform.Where(reform.Eq("email", arg)).Limit(1).Build(q.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	str, err := q.SelectOneFrom(v, tail, args...)
+	if err != nil {
+		return nil, err
+	}
+	return str.(*Person), nil
+}
+
+// FindAllByEmail queries people with email column and arg and returns a slice of found Persons.
+// Nil arg matches NULL values. If error is encountered during iteration, partial result and error will be returned.
+// Error is never reform.ErrNoRows.
+func (v *personTable) FindAllByEmail(q *reform.Querier, arg *string) ([]*Person, error) {
+	tail, args, err := reform.Where(reform.Eq("email", arg)).Build(q.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	structs, err := q.SelectAllFrom(v, tail, args...)
+	if structs == nil {
+		return nil, err
+	}
+	res := make([]*Person, len(structs))
+	for i, str := range structs {
+		res[i] = str.(*Person)
+	}
+	return res, err
+}
+
+// PreloadRoles loads has_many relation Roles for all given records
+// with a single query (or a few for a large number of records) instead of a query per record.
+func (v *personTable) PreloadRoles(q *reform.Querier, records []*Person) error {
+	keys := make([]interface{}, len(records))
+	for i, r := range records {
+		keys[i] = r.ID
+	}
+	groups, err := q.FindAllGrouped(new(ProjectRole).View(), "person_id", keys...)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		r.Roles = nil
+		for _, str := range groups.Get(r.ID) {
+			r.Roles = append(r.Roles, str.(*ProjectRole))
+		}
+	}
+	return nil
+}
+
 // PersonTable represents people view or table in SQL database.
 var PersonTable = &personTable{
-	s: parse.StructInfo{Type: "Person", SQLName: "people", Fields: []parse.FieldInfo{{Name: "ID", Type: "int32", Column: "id"}, {Name: "Name", Type: "string", Column: "name"}, {Name: "Email", Type: "*string", Column: "email"}, {Name: "CreatedAt", Type: "time.Time", Column: "created_at"}, {Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at"}}, PKFieldIndex: 0},
+	s: parse.StructInfo{Type: "Person", SQLName: "people", Fields: []parse.FieldInfo{{Name: "ID", Type: "int32", Column: "id"}, {Name: "Name", Type: "string", Column: "name", Index: true}, {Name: "Email", Type: "*string", Column: "email", Index: true}, {Name: "CreatedAt", Type: "time.Time", Column: "created_at"}, {Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at"}}, PKFieldIndex: 0, Relations: []parse.RelationInfo{{Name: "Roles", Type: "ProjectRole", Kind: "has_many", Column: "person_id"}}},
 	z: new(Person).Values(),
+	C: PersonColumns,
+}
+
+// PersonColumns contains column names of people view or table in SQL database.
+// Use them (or PersonTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var PersonColumns = struct {
+	ID        string
+	Name      string
+	Email     string
+	CreatedAt string
+	UpdatedAt string
+}{
+	ID:        "id",
+	Name:      "name",
+	Email:     "email",
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
 }
 
 // String returns a string representation of this struct or record.
@@ -57,6 +170,25 @@ func (s Person) String() string {
 	return strings.Join(res, ", ")
 }
 
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Person) Clone() *Person {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.Email != nil {
+		v := *s.Email
+		c.Email = &v
+	}
+	if s.UpdatedAt != nil {
+		v := *s.UpdatedAt
+		c.UpdatedAt = &v
+	}
+	return &c
+}
+
 // Values returns a slice of struct or record field values.
 // Returned interface{} values are never untyped nils.
 func (s *Person) Values() []interface{} {
@@ -81,6 +213,11 @@ func (s *Person) Pointers() []interface{} {
 	}
 }
 
+// LoadRoles loads has_many relation Roles for this record.
+func (s *Person) LoadRoles(q *reform.Querier) error {
+	return PersonTable.PreloadRoles(q, []*Person{s})
+}
+
 // View returns View object for that struct.
 func (s *Person) View() reform.View {
 	return PersonTable
@@ -129,6 +266,14 @@ var (
 type projectTable struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see ProjectColumns.
+	C struct {
+		Name  string
+		ID    string
+		Start string
+		End   string
+	}
 }
 
 // Name returns a view or table name in SQL database (projects).
@@ -146,6 +291,9 @@ func (v *projectTable) NewStruct() reform.Struct {
 	return new(Project)
 }
 
+// GeneratedPK marks that table as having client-generated primary key.
+func (v *projectTable) GeneratedPK() {}
+
 // NewRecord makes a new record for that table.
 func (v *projectTable) NewRecord() reform.Record {
 	return new(Project)
@@ -158,8 +306,24 @@ func (v *projectTable) PKColumnIndex() uint {
 
 // ProjectTable represents projects view or table in SQL database.
 var ProjectTable = &projectTable{
-	s: parse.StructInfo{Type: "Project", SQLName: "projects", Fields: []parse.FieldInfo{{Name: "Name", Type: "string", Column: "name"}, {Name: "ID", Type: "string", Column: "id"}, {Name: "Start", Type: "time.Time", Column: "start"}, {Name: "End", Type: "*time.Time", Column: "end"}}, PKFieldIndex: 1},
+	s: parse.StructInfo{Type: "Project", SQLName: "projects", Fields: []parse.FieldInfo{{Name: "Name", Type: "string", Column: "name"}, {Name: "ID", Type: "string", Column: "id", Generated: true}, {Name: "Start", Type: "time.Time", Column: "start"}, {Name: "End", Type: "*time.Time", Column: "end"}}, PKFieldIndex: 1},
 	z: new(Project).Values(),
+	C: ProjectColumns,
+}
+
+// ProjectColumns contains column names of projects view or table in SQL database.
+// Use them (or ProjectTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var ProjectColumns = struct {
+	Name  string
+	ID    string
+	Start string
+	End   string
+}{
+	Name:  "name",
+	ID:    "id",
+	Start: "start",
+	End:   "end",
 }
 
 // String returns a string representation of this struct or record.
@@ -172,6 +336,21 @@ func (s Project) String() string {
 	return strings.Join(res, ", ")
 }
 
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Project) Clone() *Project {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.End != nil {
+		v := *s.End
+		c.End = &v
+	}
+	return &c
+}
+
 // Values returns a slice of struct or record field values.
 // Returned interface{} values are never untyped nils.
 func (s *Project) Values() []interface{} {
@@ -223,25 +402,28 @@ func (s *Project) HasPK() bool {
 
 // SetPK sets record primary key.
 func (s *Project) SetPK(pk interface{}) {
-	if i64, ok := pk.(int64); ok {
-		s.ID = string(i64)
-	} else {
-		s.ID = pk.(string)
-	}
+	s.ID = pk.(string)
 }
 
 // check interfaces
 var (
-	_ reform.View   = ProjectTable
-	_ reform.Struct = new(Project)
-	_ reform.Table  = ProjectTable
-	_ reform.Record = new(Project)
-	_ fmt.Stringer  = new(Project)
+	_ reform.View             = ProjectTable
+	_ reform.Struct           = new(Project)
+	_ reform.Table            = ProjectTable
+	_ reform.Record           = new(Project)
+	_ reform.GeneratedPKTable = ProjectTable
+	_ fmt.Stringer            = new(Project)
 )
 
 type personProjectView struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see PersonProjectColumns.
+	C struct {
+		PersonID  string
+		ProjectID string
+	}
 }
 
 // Name returns a view or table name in SQL database (person_project).
@@ -263,6 +445,18 @@ func (v *personProjectView) NewStruct() reform.Struct {
 var PersonProjectView = &personProjectView{
 	s: parse.StructInfo{Type: "PersonProject", SQLName: "person_project", Fields: []parse.FieldInfo{{Name: "PersonID", Type: "int32", Column: "person_id"}, {Name: "ProjectID", Type: "string", Column: "project_id"}}, PKFieldIndex: -1},
 	z: new(PersonProject).Values(),
+	C: PersonProjectColumns,
+}
+
+// PersonProjectColumns contains column names of person_project view or table in SQL database.
+// Use them (or PersonProjectView.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var PersonProjectColumns = struct {
+	PersonID  string
+	ProjectID string
+}{
+	PersonID:  "person_id",
+	ProjectID: "project_id",
 }
 
 // String returns a string representation of this struct or record.
@@ -273,6 +467,17 @@ func (s PersonProject) String() string {
 	return strings.Join(res, ", ")
 }
 
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *PersonProject) Clone() *PersonProject {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
 // Values returns a slice of struct or record field values.
 // Returned interface{} values are never untyped nils.
 func (s *PersonProject) Values() []interface{} {
@@ -0,0 +1,12 @@
+package models
+
+import (
+	"time"
+)
+
+// Timestamps is embedded into models with automatically set timestamps.
+// It is declared in a separate file to check that reform finds it.
+type Timestamps struct {
+	CreatedAt time.Time  `reform:"created_at,autocreate"`
+	UpdatedAt *time.Time `reform:"updated_at,autoupdate"`
+}
@@ -0,0 +1,6 @@
+CREATE TABLE projects (
+  name String,
+  id String,
+  start Date,
+  `end` Nullable(Date)
+) ENGINE = MergeTree ORDER BY id;
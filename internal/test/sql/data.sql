@@ -19,3 +19,9 @@ INSERT INTO person_project (project_id, person_id) VALUES ('queen', 102);
 INSERT INTO person_project (project_id, person_id) VALUES ('queen', 103);
 
 INSERT INTO person_project (project_id, person_id) VALUES ('traveler', 103);
+
+INSERT INTO project_roles (project_id, person_id, role) VALUES ('baron', 102, 'lead');
+INSERT INTO project_roles (project_id, person_id, role) VALUES ('baron', 103, 'developer');
+
+INSERT INTO memos (text, deleted_at, created_at) VALUES ('active', NULL, '2016-01-01 00:00:00');  -- id 1
+INSERT INTO memos (text, deleted_at, created_at) VALUES ('deleted', '2016-01-01 00:00:00', '2015-01-01 00:00:00');  -- id 2
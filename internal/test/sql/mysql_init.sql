@@ -32,3 +32,58 @@ CREATE TABLE person_project (
   FOREIGN KEY (person_id) REFERENCES people (id) ON DELETE CASCADE,
   FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
 );
+
+CREATE TABLE secrets (
+  id int NOT NULL AUTO_INCREMENT,
+  name varchar(255) NOT NULL,
+  data blob NOT NULL,
+  note blob,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE project_roles (
+  project_id varchar(255) NOT NULL,
+  person_id int NOT NULL,
+  role varchar(255) NOT NULL,
+  version int NOT NULL DEFAULT 0,
+  PRIMARY KEY (project_id, person_id),
+  FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+  FOREIGN KEY (person_id) REFERENCES people (id) ON DELETE CASCADE
+);
+
+CREATE TABLE memos (
+  id int NOT NULL AUTO_INCREMENT,
+  text varchar(255) NOT NULL,
+  deleted_at datetime,
+  created_at datetime NOT NULL,
+  updated_at datetime,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE vehicles (
+  id int NOT NULL AUTO_INCREMENT,
+  kind varchar(255) NOT NULL,
+  name varchar(255) NOT NULL,
+  doors int,
+  payload bigint,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE events (
+  id int NOT NULL AUTO_INCREMENT,
+  payload json,
+  meta json,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE audit_log (
+  id int NOT NULL AUTO_INCREMENT,
+  table_name varchar(255) NOT NULL,
+  action varchar(255) NOT NULL,
+  record_pk varchar(255) NOT NULL,
+  actor varchar(255) NOT NULL,
+  created_at datetime NOT NULL,
+  old_values json,
+  new_values json,
+  PRIMARY KEY (id)
+);
@@ -0,0 +1,29 @@
+-- data.sql with typed date and time literals
+
+INSERT INTO people (name, email, created_at) VALUES ('Denis Mills', NULL, TIMESTAMP '2009-11-10 23:00:00');  -- id 1
+INSERT INTO people (name, email, created_at) VALUES ('Garrick Muller', 'muller_garrick@example.com', TIMESTAMP '2009-12-12 12:34:56');  -- id 2
+
+INSERT INTO people (id, name, email, created_at) VALUES (101, 'Noble Schumm', NULL, TIMESTAMP '2013-01-01 00:00:00');
+INSERT INTO people (id, name, email, created_at) VALUES (102, 'Elfrieda Abbott', 'elfrieda_abbott@example.org', TIMESTAMP '2014-01-01 00:00:00');
+INSERT INTO people (id, name, email, created_at) VALUES (103, 'Elfrieda Abbott', NULL, TIMESTAMP '2014-01-01 00:00:00');
+
+INSERT INTO projects (id, name, "START", "END") VALUES ('baron', 'Vicious Baron', DATE '2014-06-01', DATE '2016-02-21');
+INSERT INTO projects (id, name, "START", "END") VALUES ('queen', 'Thirsty Queen', DATE '2016-01-15', NULL);
+INSERT INTO projects (id, name, "START", "END") VALUES ('traveler', 'Kosher Traveler', DATE '2016-02-01', NULL);
+INSERT INTO projects (id, name, "START", "END") VALUES ('lightfoot', 'Sweet Lightfoot', DATE '2016-01-01', NULL);
+INSERT INTO projects (id, name, "START", "END") VALUES ('walker', 'Eager Walker', DATE '2015-01-01', NULL);
+
+INSERT INTO person_project (project_id, person_id) VALUES ('baron', 101);
+INSERT INTO person_project (project_id, person_id) VALUES ('baron', 102);
+INSERT INTO person_project (project_id, person_id) VALUES ('baron', 103);
+
+INSERT INTO person_project (project_id, person_id) VALUES ('queen', 102);
+INSERT INTO person_project (project_id, person_id) VALUES ('queen', 103);
+
+INSERT INTO person_project (project_id, person_id) VALUES ('traveler', 103);
+
+INSERT INTO project_roles (project_id, person_id, role) VALUES ('baron', 102, 'lead');
+INSERT INTO project_roles (project_id, person_id, role) VALUES ('baron', 103, 'developer');
+
+INSERT INTO memos (text, deleted_at, created_at) VALUES ('active', NULL, TIMESTAMP '2016-01-01 00:00:00');  -- id 1
+INSERT INTO memos (text, deleted_at, created_at) VALUES ('deleted', TIMESTAMP '2016-01-01 00:00:00', TIMESTAMP '2015-01-01 00:00:00');  -- id 2
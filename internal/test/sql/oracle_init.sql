@@ -0,0 +1,68 @@
+CREATE TABLE people (
+  id NUMBER(10) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  name VARCHAR2(255) NOT NULL,
+  email VARCHAR2(255),
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP
+);
+
+CREATE TABLE projects (
+  name VARCHAR2(255) NOT NULL,
+  id VARCHAR2(255) PRIMARY KEY,
+  "START" DATE NOT NULL,
+  "END" DATE
+);
+
+CREATE TABLE person_project (
+  person_id NUMBER(10) NOT NULL REFERENCES people ON DELETE CASCADE,
+  project_id VARCHAR2(255) NOT NULL REFERENCES projects ON DELETE CASCADE,
+  UNIQUE (person_id, project_id)
+);
+
+CREATE TABLE secrets (
+  id NUMBER(10) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  name VARCHAR2(255) NOT NULL,
+  data BLOB NOT NULL,
+  note BLOB
+);
+
+CREATE TABLE project_roles (
+  project_id VARCHAR2(255) NOT NULL REFERENCES projects ON DELETE CASCADE,
+  person_id NUMBER(10) NOT NULL REFERENCES people ON DELETE CASCADE,
+  role VARCHAR2(255) NOT NULL,
+  version NUMBER(10) DEFAULT 0 NOT NULL,
+  PRIMARY KEY (project_id, person_id)
+);
+
+CREATE TABLE memos (
+  id NUMBER(10) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  text VARCHAR2(255) NOT NULL,
+  deleted_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP
+);
+
+CREATE TABLE vehicles (
+  id NUMBER(10) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  kind VARCHAR2(255) NOT NULL,
+  name VARCHAR2(255) NOT NULL,
+  doors NUMBER(10),
+  payload NUMBER(19)
+);
+
+CREATE TABLE events (
+  id NUMBER(10) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  payload VARCHAR2(4000),
+  meta VARCHAR2(4000)
+);
+
+CREATE TABLE audit_log (
+  id NUMBER(10) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  table_name VARCHAR2(255) NOT NULL,
+  action VARCHAR2(255) NOT NULL,
+  record_pk VARCHAR2(255),
+  actor VARCHAR2(255),
+  created_at TIMESTAMP NOT NULL,
+  old_values VARCHAR2(4000),
+  new_values VARCHAR2(4000)
+);
@@ -0,0 +1 @@
+ALTER TABLE people MODIFY id GENERATED BY DEFAULT AS IDENTITY (START WITH 1000);
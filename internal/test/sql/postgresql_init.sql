@@ -20,3 +20,61 @@ CREATE TABLE person_project (
   project_id varchar NOT NULL REFERENCES projects ON DELETE CASCADE,
   UNIQUE (person_id, project_id)
 );
+
+CREATE TABLE secrets (
+  id serial PRIMARY KEY,
+  name varchar NOT NULL,
+  data bytea NOT NULL,
+  note bytea
+);
+
+CREATE TABLE project_roles (
+  project_id varchar NOT NULL REFERENCES projects ON DELETE CASCADE,
+  person_id int NOT NULL REFERENCES people ON DELETE CASCADE,
+  role varchar NOT NULL,
+  version int NOT NULL DEFAULT 0,
+  PRIMARY KEY (project_id, person_id)
+);
+
+CREATE TABLE memos (
+  id serial PRIMARY KEY,
+  text varchar NOT NULL,
+  deleted_at timestamp with time zone,
+  created_at timestamp with time zone NOT NULL,
+  updated_at timestamp with time zone
+);
+
+CREATE TABLE vehicles (
+  id serial PRIMARY KEY,
+  kind varchar NOT NULL,
+  name varchar NOT NULL,
+  doors integer,
+  payload bigint
+);
+
+CREATE TABLE events (
+  id serial PRIMARY KEY,
+  payload jsonb,
+  meta jsonb
+);
+
+CREATE TABLE articles (
+  id serial PRIMARY KEY,
+  tags text[],
+  scores bigint[]
+);
+
+CREATE TABLE audit_log (
+  id serial PRIMARY KEY,
+  table_name varchar NOT NULL,
+  action varchar NOT NULL,
+  record_pk varchar NOT NULL,
+  actor varchar NOT NULL,
+  created_at timestamp with time zone NOT NULL,
+  old_values jsonb,
+  new_values jsonb
+);
+
+CREATE MATERIALIZED VIEW person_project_counts AS
+  SELECT person_id, COUNT(*) AS projects FROM person_project GROUP BY person_id
+  WITH NO DATA;
@@ -18,3 +18,51 @@ CREATE TABLE person_project (
   project_id varchar NOT NULL REFERENCES projects ON DELETE CASCADE,
   UNIQUE (person_id, project_id)
 );
+
+CREATE TABLE secrets (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  name varchar NOT NULL,
+  data blob NOT NULL,
+  note blob
+);
+
+CREATE TABLE project_roles (
+  project_id varchar NOT NULL REFERENCES projects ON DELETE CASCADE,
+  person_id integer NOT NULL REFERENCES people ON DELETE CASCADE,
+  role varchar NOT NULL,
+  version int NOT NULL DEFAULT 0,
+  PRIMARY KEY (project_id, person_id)
+);
+
+CREATE TABLE memos (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  text varchar NOT NULL,
+  deleted_at datetime,
+  created_at datetime NOT NULL,
+  updated_at datetime
+);
+
+CREATE TABLE vehicles (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  kind varchar NOT NULL,
+  name varchar NOT NULL,
+  doors integer,
+  payload bigint
+);
+
+CREATE TABLE events (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  payload text,
+  meta text
+);
+
+CREATE TABLE audit_log (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  table_name varchar NOT NULL,
+  action varchar NOT NULL,
+  record_pk varchar NOT NULL,
+  actor varchar NOT NULL,
+  created_at datetime NOT NULL,
+  old_values text,
+  new_values text
+);
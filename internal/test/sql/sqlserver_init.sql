@@ -0,0 +1,68 @@
+CREATE TABLE people (
+  id int IDENTITY(1,1) PRIMARY KEY,
+  name nvarchar(255) NOT NULL,
+  email nvarchar(255),
+  created_at datetime2 NOT NULL,
+  updated_at datetime2
+);
+
+CREATE TABLE projects (
+  name nvarchar(255) NOT NULL,
+  id nvarchar(255) PRIMARY KEY,
+  start date NOT NULL,
+  [end] date
+);
+
+CREATE TABLE person_project (
+  person_id int NOT NULL REFERENCES people ON DELETE CASCADE,
+  project_id nvarchar(255) NOT NULL REFERENCES projects ON DELETE CASCADE,
+  UNIQUE (person_id, project_id)
+);
+
+CREATE TABLE secrets (
+  id int IDENTITY(1,1) PRIMARY KEY,
+  name nvarchar(255) NOT NULL,
+  data varbinary(max) NOT NULL,
+  note varbinary(max)
+);
+
+CREATE TABLE project_roles (
+  project_id nvarchar(255) NOT NULL REFERENCES projects ON DELETE CASCADE,
+  person_id int NOT NULL REFERENCES people ON DELETE CASCADE,
+  role nvarchar(255) NOT NULL,
+  version int NOT NULL DEFAULT 0,
+  PRIMARY KEY (project_id, person_id)
+);
+
+CREATE TABLE memos (
+  id int IDENTITY(1,1) PRIMARY KEY,
+  text nvarchar(255) NOT NULL,
+  deleted_at datetime2,
+  created_at datetime2 NOT NULL,
+  updated_at datetime2
+);
+
+CREATE TABLE vehicles (
+  id int IDENTITY(1,1) PRIMARY KEY,
+  kind nvarchar(255) NOT NULL,
+  name nvarchar(255) NOT NULL,
+  doors int,
+  payload bigint
+);
+
+CREATE TABLE events (
+  id int IDENTITY(1,1) PRIMARY KEY,
+  payload nvarchar(max),
+  meta nvarchar(max)
+);
+
+CREATE TABLE audit_log (
+  id int IDENTITY(1,1) PRIMARY KEY,
+  table_name nvarchar(255) NOT NULL,
+  action nvarchar(255) NOT NULL,
+  record_pk nvarchar(255) NOT NULL,
+  actor nvarchar(255) NOT NULL,
+  created_at datetime2 NOT NULL,
+  old_values nvarchar(max),
+  new_values nvarchar(max)
+);
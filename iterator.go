@@ -0,0 +1,99 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Iterator streams rows of SELECT query one by one, so result sets of any size can be processed
+// with constant memory. Use Querier.Iterate to create it:
+//
+//	iter, err := q.Iterate(PersonTable, "WHERE id > "+q.Placeholder(1), 100)
+//	if err != nil {
+//		return err
+//	}
+//	defer iter.Close()
+//
+//	var person Person
+//	for iter.Next() {
+//		if err = iter.Scan(&person); err != nil {
+//			return err
+//		}
+//		...
+//	}
+//	return iter.Err()
+//
+// Iterator is not safe for concurrent use.
+type Iterator struct {
+	q      *Querier
+	rows   *sql.Rows
+	cancel context.CancelFunc
+	err    error
+}
+
+// Iterate queries view with tail and args and returns Iterator for result rows.
+// Caller must call Iterator.Close() (typically with defer): rows are closed automatically only when Next
+// returns false or Scan fails, so returning or breaking from the loop early without Close leaks
+// database connection. If querier's context is canceled, underlying rows are closed automatically,
+// and Next returns false with Err returning context's error.
+//
+// In case of error iterator will be nil. Error is never ErrNoRows.
+func (q *Querier) Iterate(view View, tail string, args ...interface{}) (*Iterator, error) {
+	rows, cancel, err := q.selectRows(view, tail, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &Iterator{q: q, rows: rows, cancel: cancel}, nil
+}
+
+// Next prepares the next row for Scan. It returns false if there are no more rows or error happened;
+// use Err to distinguish those cases. Rows are closed automatically when Next returns false.
+func (iter *Iterator) Next() bool {
+	if iter.err != nil {
+		return false
+	}
+	if err := iter.q.ctx.Err(); err != nil {
+		iter.err = err
+		iter.Close()
+		return false
+	}
+	if !iter.rows.Next() {
+		iter.err = iter.rows.Err()
+		if iter.err == nil {
+			iter.err = iter.q.ctx.Err()
+		}
+		iter.cancel()
+		return false
+	}
+	return true
+}
+
+// Scan scans current row to str. It should be called only after Next returned true.
+// If str implements AfterFinder or AfterFinderContext, it also calls AfterFind().
+// In case of error iterator is closed, Next returns false, and Err returns the same error.
+func (iter *Iterator) Scan(str Struct) error {
+	pointers, found, err := iter.q.scanTarget(str, nil)
+	if err == nil {
+		if err = iter.rows.Scan(pointers...); err == nil {
+			err = found()
+		}
+	}
+	if err != nil {
+		iter.err = err
+		iter.Close()
+	}
+	return err
+}
+
+// Err returns error encountered during iteration, if any. It never returns ErrNoRows.
+func (iter *Iterator) Err() error {
+	return iter.err
+}
+
+// Close closes iterator. It must be called when iterator is no longer needed;
+// it is safe to call it several times, or after Next returned false or Scan failed.
+func (iter *Iterator) Close() error {
+	err := iter.rows.Close()
+	iter.cancel()
+	return err
+}
@@ -0,0 +1,238 @@
+package reform
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSON wraps a value of any type for JSON/JSONB (or text) column: it is marshaled to JSON text when
+// stored and unmarshaled when scanned. Generated Values and Pointers methods use it for struct fields
+// with "json" label in "reform:" tag, so there is no need to write Valuer and Scanner for each type:
+//
+//	type Event struct {
+//		ID      int32                  `reform:"id,pk"`
+//		Payload map[string]interface{} `reform:"payload,json"`
+//		Meta    *EventMeta             `reform:"meta,json"`
+//	}
+//
+// Nil pointers, maps, slices and interfaces are stored as NULL, and NULL is scanned as zero value.
+type JSON struct {
+	V interface{} // value for Value, pointer to value for Scan
+}
+
+// Value implements database/sql/driver.Valuer interface.
+func (j JSON) Value() (driver.Value, error) {
+	if isNilValue(j.V) {
+		return nil, nil
+	}
+	b, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements database/sql.Scanner interface.
+func (j *JSON) Scan(src interface{}) error {
+	v := reflect.ValueOf(j.V)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("reform: JSON.Scan: expected non-nil pointer, got %T", j.V)
+	}
+
+	var b []byte
+	switch src := src.(type) {
+	case nil:
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+		return nil
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	default:
+		return fmt.Errorf("reform: JSON.Scan: unexpected source type %T", src)
+	}
+
+	// unmarshal into a new value to not merge with the old one
+	nv := reflect.New(v.Elem().Type())
+	if err := json.Unmarshal(b, nv.Interface()); err != nil {
+		return err
+	}
+	v.Elem().Set(nv.Elem())
+	return nil
+}
+
+// String returns JSON text representation of wrapped value for logging.
+func (j JSON) String() string {
+	v, err := j.Value()
+	if err != nil {
+		return fmt.Sprintf("<%s>", err)
+	}
+	if v == nil {
+		return "<nil>"
+	}
+	return v.(string)
+}
+
+// isNilValue returns true if v is untyped nil or nil pointer, map, slice or interface.
+func isNilValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// JSONContains returns condition "column @> arg" for PostgreSQL JSONB column: it matches rows where column value
+// contains JSON representation of v, for example, JSONContains("payload", map[string]interface{}{"type": "click"}).
+func JSONContains(column string, v interface{}) Condition {
+	return Condition{column: column, op: "@>", arg: JSON{V: v}}
+}
+
+// JSONContainedBy returns condition "column <@ arg" for PostgreSQL JSONB column: it matches rows where column value
+// is contained within JSON representation of v.
+func JSONContainedBy(column string, v interface{}) Condition {
+	return Condition{column: column, op: "<@", arg: JSON{V: v}}
+}
+
+// WhereJSONContains returns a new tail with a single JSONContains condition. It is a shortcut for
+//
+//	reform.Where(reform.JSONContains(column, v))
+func WhereJSONContains(column string, v interface{}) *Tail {
+	return Where(JSONContains(column, v))
+}
+
+// MarshalStructJSON encodes struct as JSON object with column names as keys in column order,
+// omitting columns with given indexes. It is used by MarshalJSON methods generated with "reform -json"
+// (sensitive fields are omitted), so API layers may use models without a parallel set of DTOs.
+// Values wrapped with JSON and Array are encoded as is; sql.Null* and other driver.Valuer values
+// which are not json.Marshaler are encoded as their driver values (so NULL becomes null).
+func MarshalStructJSON(str Struct, omit ...int) ([]byte, error) {
+	skip := make(map[int]bool, len(omit))
+	for _, i := range omit {
+		skip[i] = true
+	}
+
+	values := str.Values()
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	var n int
+	for i, c := range str.View().Columns() {
+		if skip[i] {
+			continue
+		}
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		n++
+
+		v := values[i]
+		switch w := v.(type) {
+		case JSON:
+			v = w.V
+		case Array:
+			v = w.V
+		case json.Marshaler:
+		case driver.Valuer:
+			var err error
+			if v, err = w.Value(); err != nil {
+				return nil, err
+			}
+		}
+
+		key, _ := json.Marshal(c)
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("reform: MarshalStructJSON: %s: %s", c, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalStructJSON decodes JSON object with column names as keys into struct, ignoring columns
+// with given indexes. It is used by UnmarshalJSON methods generated with "reform -json".
+// Fields for absent keys are not changed, unknown keys are ignored.
+// Fields of sql.Scanner types which are not json.Unmarshaler (like sql.NullString) are set with Scan.
+func UnmarshalStructJSON(str Struct, b []byte, omit ...int) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	skip := make(map[int]bool, len(omit))
+	for _, i := range omit {
+		skip[i] = true
+	}
+
+	pointers := str.Pointers()
+	for i, c := range str.View().Columns() {
+		raw, ok := m[c]
+		if !ok || skip[i] {
+			continue
+		}
+
+		var err error
+		switch p := pointers[i].(type) {
+		case *JSON:
+			err = json.Unmarshal(raw, p.V)
+		case *Array:
+			err = json.Unmarshal(raw, p.V)
+		case json.Unmarshaler:
+			err = p.UnmarshalJSON(raw)
+		case *sql.NullTime:
+			*p = sql.NullTime{}
+			if string(raw) != "null" {
+				p.Valid = true
+				err = json.Unmarshal(raw, &p.Time)
+			}
+		case sql.Scanner:
+			var v interface{}
+			if v, err = scanValue(raw); err == nil {
+				err = p.Scan(v)
+			}
+		default:
+			err = json.Unmarshal(raw, p)
+		}
+		if err != nil {
+			return fmt.Errorf("reform: UnmarshalStructJSON: %s: %s", c, err)
+		}
+	}
+	return nil
+}
+
+// scanValue decodes JSON value to a value similar to returned by database drivers:
+// nil, bool, string, int64 or float64 (not float64 for all numbers, like encoding/json does).
+func scanValue(raw json.RawMessage) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		return n.Float64()
+	}
+	return v, nil
+}
+
+// check interfaces
+var (
+	_ driver.Valuer = JSON{}
+	_ sql.Scanner   = new(JSON)
+	_ fmt.Stringer  = JSON{}
+)
@@ -0,0 +1,107 @@
+package reform_test
+
+import (
+	"encoding/json"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestJSON() {
+	event := &Event{
+		Payload: map[string]interface{}{"type": "click", "ok": true},
+		Meta:    &EventMeta{Source: "web", Tags: []string{"a", "b"}},
+	}
+	s.Require().NoError(s.q.Insert(event))
+	empty := new(Event)
+	s.Require().NoError(s.q.Insert(empty))
+
+	record, err := s.q.FindByPrimaryKeyFrom(EventTable, event.ID)
+	s.Require().NoError(err)
+	s.Equal(event, record)
+	s.True(event.Equal(record.(*Event)))
+
+	record, err = s.q.FindByPrimaryKeyFrom(EventTable, empty.ID)
+	s.Require().NoError(err)
+	s.Nil(record.(*Event).Payload)
+	s.Nil(record.(*Event).Meta)
+
+	// scanned value replaces the old one instead of merging with it
+	event.Payload = map[string]interface{}{"type": "view"}
+	event.Meta = nil
+	s.Require().NoError(s.q.Update(event))
+	record = &Event{Payload: map[string]interface{}{"stale": true}}
+	s.Require().NoError(s.q.FindByPrimaryKeyTo(record, event.ID))
+	s.Equal(map[string]interface{}{"type": "view"}, record.(*Event).Payload)
+	s.Nil(record.(*Event).Meta)
+
+	// nil map is zero and not updated
+	s.Require().NoError(s.q.UpdateNonZero(&Event{ID: event.ID, Meta: &EventMeta{Source: "api"}}))
+	s.Require().NoError(s.q.Reload(event))
+	s.Equal(map[string]interface{}{"type": "view"}, event.Payload)
+	s.Equal(&EventMeta{Source: "api"}, event.Meta)
+}
+
+func (s *ReformSuite) TestJSONContains() {
+	if s.q.Dialect != postgresql.Dialect {
+		s.T().Skip("PostgreSQL-specific test")
+	}
+
+	for _, source := range []string{"web", "api", "web"} {
+		s.Require().NoError(s.q.Insert(&Event{
+			Payload: map[string]interface{}{"source": source, "n": 1},
+			Meta:    &EventMeta{Source: source},
+		}))
+	}
+
+	tail, args, err := reform.WhereJSONContains("payload", map[string]interface{}{"source": "web"}).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	n, err := s.q.Count(EventTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(2), n)
+
+	tail, args, err = reform.Where(reform.JSONContains("meta", EventMeta{Source: "api"})).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	n, err = s.q.Count(EventTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(1), n)
+
+	tail, args, err = reform.Where(reform.JSONContainedBy("payload", map[string]interface{}{"source": "api", "n": 1, "x": 2})).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	n, err = s.q.Count(EventTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(1), n)
+}
+
+func (s *ReformSuite) TestStructJSON() {
+	secret := &Secret{ID: 1, Name: "password", Data: "hunter2", Note: &[]byte{'o', 'k'}}
+	b, err := json.Marshal(secret)
+	s.Require().NoError(err)
+	s.Equal(`{"id":1,"name":"password","note":"b2s="}`, string(b)) // sensitive field is omitted
+
+	secret2 := &Secret{Data: "kept"}
+	s.Require().NoError(json.Unmarshal([]byte(`{"id": 2, "name": "token", "data": "ignored", "unknown": 1}`), secret2))
+	s.Equal(&Secret{ID: 2, Name: "token", Data: "kept"}, secret2)
+
+	event := &Event{ID: 3, Payload: map[string]interface{}{"type": "click"}, Meta: &EventMeta{Source: "web"}}
+	b, err = json.Marshal(event)
+	s.Require().NoError(err)
+	s.Equal(`{"id":3,"payload":{"type":"click"},"meta":{"source":"web"}}`, string(b))
+	event2 := new(Event)
+	s.Require().NoError(json.Unmarshal(b, event2))
+	s.Equal(event, event2)
+
+	article := &Article{ID: 4, Tags: []string{"a", "b"}}
+	b, err = json.Marshal(article)
+	s.Require().NoError(err)
+	s.Equal(`{"id":4,"tags":["a","b"],"scores":null}`, string(b))
+
+	// models without generated methods
+	b, err = reform.MarshalStructJSON(&Person{ID: 5, Name: "Alice", CreatedAt: personCreated})
+	s.Require().NoError(err)
+	s.Equal(`{"id":5,"name":"Alice","email":null,"created_at":"2014-01-01T00:00:00Z","updated_at":null}`, string(b))
+	var person Person
+	s.Require().NoError(reform.UnmarshalStructJSON(&person, b))
+	s.Equal(Person{ID: 5, Name: "Alice", CreatedAt: personCreated}, person)
+}
@@ -0,0 +1,58 @@
+package reform
+
+import (
+	"reflect"
+)
+
+// versionLock represents optimistic locking version of a record during update.
+type versionLock struct {
+	column  string
+	pointer reflect.Value // pointer to record's version field
+	current interface{}   // current version
+	next    interface{}   // incremented version
+}
+
+// set sets record's version to incremented one.
+func (l *versionLock) set() {
+	l.pointer.Elem().Set(reflect.ValueOf(l.next))
+}
+
+// lock prepares optimistic locking for updating record if its table implements LockingTable.
+// It returns given columns and values with version column set to incremented version
+// (any value for that column given by caller is dropped), and nil versionLock for other tables.
+func (q *Querier) lock(record Record, columns []string, values []interface{}) ([]string, []interface{}, *versionLock) {
+	table, ok := record.Table().(LockingTable)
+	if !ok {
+		return columns, values, nil
+	}
+
+	index := table.LockColumnIndex()
+	l := &versionLock{
+		column:  table.Columns()[index],
+		pointer: reflect.ValueOf(record.Pointers()[index]),
+	}
+	v := l.pointer.Elem()
+	next := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		next.SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		next.SetUint(v.Uint() + 1)
+	default:
+		panic("reform: unhandled version type " + v.Type().String() + ". Please report this bug.")
+	}
+	l.current = v.Interface()
+	l.next = next.Interface()
+
+	resColumns := make([]string, 0, len(columns)+1)
+	resValues := make([]interface{}, 0, len(values)+1)
+	for i, c := range columns {
+		if c != l.column {
+			resColumns = append(resColumns, c)
+			resValues = append(resValues, values[i])
+		}
+	}
+	resColumns = append(resColumns, l.column)
+	resValues = append(resValues, l.next)
+	return resColumns, resValues, l
+}
@@ -1,11 +1,16 @@
 package reform
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 )
 
+// Redacted is used instead of values of sensitive columns in string representations.
+const Redacted = "<redacted>"
+
 // Inspect returns suitable for logging representation of a query argument.
 func Inspect(arg interface{}, addType bool) string {
 	// do not merge cases, we want "arg == nil" to work
@@ -47,6 +52,62 @@ type Logger interface {
 	After(query string, args []interface{}, d time.Duration, err error)
 }
 
+// TaggedLogger is an optional interface for Logger which receives tag set by Querier.Tagged.
+// If Logger implements it, its methods are called instead of Before and After for tagged queriers.
+type TaggedLogger interface {
+	Logger
+
+	// BeforeTagged logs tagged query before execution.
+	BeforeTagged(tag, query string, args []interface{})
+
+	// AfterTagged logs tagged query after execution.
+	AfterTagged(tag, query string, args []interface{}, d time.Duration, err error)
+}
+
+// QueryEvent represents a single executed query or command for StructuredLogger.
+type QueryEvent struct {
+	Tag          string        // querier's tag set by Querier.Tagged, or empty string
+	View         string        // view or table name for reform's queries and commands, empty string for others
+	Operation    string        // the first keyword of query, like SELECT, INSERT, UPDATE, DELETE or COMMIT
+	Query        string        // SQL query, including tag comment if enabled
+	Args         []interface{} // query arguments; they should not be modified
+	Start        time.Time     // query start time
+	Duration     time.Duration // query duration
+	Err          error         // query error
+	RowsAffected int64         // number of rows affected by Exec, -1 for other queries or if unknown
+	Caller       string        // "file:line" of the first caller outside of reform package, or empty string
+}
+
+// StructuredLogger is an optional interface for Logger which receives structured information about executed queries,
+// so it can be written to JSON logs or adapted to structured logging libraries.
+// If Logger implements it, LogQuery is called instead of After and TaggedLogger's AfterTagged.
+// Before (or BeforeTagged) is still called before query execution.
+type StructuredLogger interface {
+	Logger
+
+	// LogQuery logs query after execution. Context is querier's context.
+	LogQuery(ctx context.Context, event *QueryEvent)
+}
+
+// LogEvent passes query event to given Logger after query execution: to LogQuery for StructuredLogger,
+// to AfterTagged for TaggedLogger and tagged event, to After otherwise. Nil Logger is ignored.
+// It is intended to be used by StructuredLogger implementations which wrap other loggers.
+func LogEvent(ctx context.Context, l Logger, event *QueryEvent) {
+	switch l := l.(type) {
+	case nil:
+	case StructuredLogger:
+		l.LogQuery(ctx, event)
+	case TaggedLogger:
+		if event.Tag != "" {
+			l.AfterTagged(event.Tag, event.Query, event.Args, event.Duration, event.Err)
+			return
+		}
+		l.After(event.Query, event.Args, event.Duration, event.Err)
+	default:
+		l.After(event.Query, event.Args, event.Duration, event.Err)
+	}
+}
+
 // Printf is a (fmt.Printf|log.Printf|testing.T.Logf)-like function.
 type Printf func(format string, a ...interface{})
 
@@ -101,5 +162,91 @@ func (pl *PrintfLogger) After(query string, args []interface{}, d time.Duration,
 	pl.printf("<<< %s", msg)
 }
 
-// check interface
-var _ Logger = new(PrintfLogger)
+// LogSlowQuery logs slow query with its duration and execution plan, if any.
+func (pl *PrintfLogger) LogSlowQuery(ctx context.Context, event *QueryEvent, plan string) {
+	msg := fmt.Sprintf("slow query %s: %s", event.Duration, event.Query)
+	if plan != "" {
+		msg += "\n" + plan
+	}
+	pl.printf("!!! %s", msg)
+}
+
+// LogRetry logs transaction retry.
+func (pl *PrintfLogger) LogRetry(ctx context.Context, attempt int, delay time.Duration, err error) {
+	pl.printf("!!! transaction attempt %d failed, retrying after %s: %s", attempt, delay, err)
+}
+
+// RecordedStatement represents a single query recorded by RecordingLogger.
+type RecordedStatement struct {
+	Tag      string
+	Query    string
+	Args     []interface{}
+	Duration time.Duration
+	Err      error
+}
+
+// RecordingLogger is a query logger which records executed queries in memory.
+// It is intended to be used in tests. It is safe for concurrent use.
+type RecordingLogger struct {
+	rw         sync.RWMutex
+	statements []RecordedStatement
+}
+
+// NewRecordingLogger creates a new recording query logger.
+func NewRecordingLogger() *RecordingLogger {
+	return new(RecordingLogger)
+}
+
+// Before does nothing: queries are recorded after execution.
+func (rl *RecordingLogger) Before(query string, args []interface{}) {}
+
+// After records query after execution.
+func (rl *RecordingLogger) After(query string, args []interface{}, d time.Duration, err error) {
+	rl.AfterTagged("", query, args, d, err)
+}
+
+// BeforeTagged does nothing: queries are recorded after execution.
+func (rl *RecordingLogger) BeforeTagged(tag, query string, args []interface{}) {}
+
+// AfterTagged records tagged query after execution.
+func (rl *RecordingLogger) AfterTagged(tag, query string, args []interface{}, d time.Duration, err error) {
+	var a []interface{}
+	if args != nil {
+		a = make([]interface{}, len(args))
+		copy(a, args)
+	}
+
+	rl.rw.Lock()
+	rl.statements = append(rl.statements, RecordedStatement{
+		Tag:      tag,
+		Query:    query,
+		Args:     a,
+		Duration: d,
+		Err:      err,
+	})
+	rl.rw.Unlock()
+}
+
+// Statements returns a copy of recorded statements in order of execution.
+func (rl *RecordingLogger) Statements() []RecordedStatement {
+	rl.rw.RLock()
+	defer rl.rw.RUnlock()
+
+	res := make([]RecordedStatement, len(rl.statements))
+	copy(res, rl.statements)
+	return res
+}
+
+// Reset removes all recorded statements.
+func (rl *RecordingLogger) Reset() {
+	rl.rw.Lock()
+	rl.statements = nil
+	rl.rw.Unlock()
+}
+
+// check interfaces
+var (
+	_ SlowQueryLogger = new(PrintfLogger)
+	_ RetryLogger     = new(PrintfLogger)
+	_ TaggedLogger    = new(RecordingLogger)
+)
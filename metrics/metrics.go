@@ -0,0 +1,137 @@
+// Package metrics implements Prometheus metrics for reform.
+//
+// Logger counts queries and observes their durations, labelled by view (or table) name and operation
+// (SELECT, INSERT, UPDATE, DELETE, BEGIN, COMMIT, ROLLBACK, etc.); errors are counted separately.
+// It is a prometheus.Collector:
+//
+//	l := metrics.NewLogger("myapp", db.Logger)
+//	prometheus.MustRegister(l)
+//	db.Logger = l
+//
+// Queries executed by Querier.Exec, Query and QueryRow directly have empty view label.
+//
+// StatementCacheCollector exports reform.StatementCache metrics.
+package metrics // TODO add canonical import path via gopkg.in
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/AlekSi/reform"
+)
+
+// Logger is a reform.StructuredLogger which records Prometheus metrics
+// and passes queries to the next Logger, if any.
+type Logger struct {
+	next      reform.Logger
+	queries   *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+	rows      *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+}
+
+// NewLogger creates a new Logger with metrics in given namespace (may be empty).
+// Next logger may be nil.
+func NewLogger(namespace string, next reform.Logger) *Logger {
+	labels := []string{"view", "operation"}
+	return &Logger{
+		next: next,
+		queries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "reform",
+			Name:      "queries_total",
+			Help:      "Total number of executed queries and commands.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "reform",
+			Name:      "errors_total",
+			Help:      "Total number of failed queries and commands.",
+		}, labels),
+		rows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "reform",
+			Name:      "rows_affected_total",
+			Help:      "Total number of rows affected by commands.",
+		}, labels),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "reform",
+			Name:      "query_duration_seconds",
+			Help:      "Durations of queries and commands.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (l *Logger) Describe(ch chan<- *prometheus.Desc) {
+	l.queries.Describe(ch)
+	l.errors.Describe(ch)
+	l.rows.Describe(ch)
+	l.durations.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (l *Logger) Collect(ch chan<- prometheus.Metric) {
+	l.queries.Collect(ch)
+	l.errors.Collect(ch)
+	l.rows.Collect(ch)
+	l.durations.Collect(ch)
+}
+
+// Before passes query to the next Logger.
+func (l *Logger) Before(query string, args []interface{}) {
+	if l.next != nil {
+		l.next.Before(query, args)
+	}
+}
+
+// After passes query to the next Logger. It is not called by reform: LogQuery is called instead.
+func (l *Logger) After(query string, args []interface{}, d time.Duration, err error) {
+	if l.next != nil {
+		l.next.After(query, args, d, err)
+	}
+}
+
+// BeforeTagged passes tagged query to the next Logger.
+func (l *Logger) BeforeTagged(tag, query string, args []interface{}) {
+	if tl, ok := l.next.(reform.TaggedLogger); ok {
+		tl.BeforeTagged(tag, query, args)
+		return
+	}
+	l.Before(query, args)
+}
+
+// AfterTagged passes tagged query to the next Logger. It is not called by reform: LogQuery is called instead.
+func (l *Logger) AfterTagged(tag, query string, args []interface{}, d time.Duration, err error) {
+	if tl, ok := l.next.(reform.TaggedLogger); ok {
+		tl.AfterTagged(tag, query, args, d, err)
+		return
+	}
+	l.After(query, args, d, err)
+}
+
+// LogQuery records metrics for query and passes it to the next Logger.
+func (l *Logger) LogQuery(ctx context.Context, event *reform.QueryEvent) {
+	labels := []string{event.View, event.Operation}
+	l.queries.WithLabelValues(labels...).Inc()
+	l.durations.WithLabelValues(labels...).Observe(event.Duration.Seconds())
+	if event.Err != nil {
+		l.errors.WithLabelValues(labels...).Inc()
+	}
+	if event.RowsAffected > 0 {
+		l.rows.WithLabelValues(labels...).Add(float64(event.RowsAffected))
+	}
+
+	reform.LogEvent(ctx, l.next, event)
+}
+
+// check interfaces
+var (
+	_ reform.StructuredLogger = new(Logger)
+	_ reform.TaggedLogger     = new(Logger)
+	_ prometheus.Collector    = new(Logger)
+)
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func TestLogger(t *testing.T) {
+	f := reformtest.New(postgresql.Dialect)
+	var logged []string
+	f.DB.Logger = reform.NewPrintfLogger(func(format string, a ...interface{}) {
+		logged = append(logged, format)
+	})
+	l := NewLogger("test", f.DB.Logger)
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(l))
+	q := f.DB.WithLogger(l)
+
+	require.NoError(t, q.Update(&models.Person{ID: 1, Name: "Alice", CreatedAt: time.Now()}))
+	require.NoError(t, q.Update(&models.Person{ID: 2, Name: "Bob", CreatedAt: time.Now()}))
+
+	errBoom := errors.New("boom")
+	f.StubError(models.PersonTable, errBoom)
+	_, err := q.FindByPrimaryKeyFrom(models.PersonTable, int32(1))
+	assert.True(t, errors.Is(err, errBoom), "%+v", err)
+
+	expected := `
+# HELP test_reform_errors_total Total number of failed queries and commands.
+# TYPE test_reform_errors_total counter
+test_reform_errors_total{operation="SELECT",view="people"} 1
+# HELP test_reform_queries_total Total number of executed queries and commands.
+# TYPE test_reform_queries_total counter
+test_reform_queries_total{operation="SELECT",view="people"} 1
+test_reform_queries_total{operation="UPDATE",view="people"} 2
+# HELP test_reform_rows_affected_total Total number of rows affected by commands.
+# TYPE test_reform_rows_affected_total counter
+test_reform_rows_affected_total{operation="UPDATE",view="people"} 2
+`
+	err = testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"test_reform_errors_total", "test_reform_queries_total", "test_reform_rows_affected_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, testutil.CollectAndCount(l, "test_reform_query_duration_seconds"))
+
+	// queries are passed to the next logger: Before and After for each
+	assert.Len(t, logged, 6)
+}
+
+func TestStatementCacheCollector(t *testing.T) {
+	c := NewStatementCacheCollector("test", reform.NewStatementCache(nil, 10))
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(c))
+
+	expected := `
+# HELP test_reform_stmt_cache_evictions_total Total number of statements evicted from cache.
+# TYPE test_reform_stmt_cache_evictions_total counter
+test_reform_stmt_cache_evictions_total 0
+# HELP test_reform_stmt_cache_hits_total Total number of queries executed with cached prepared statement.
+# TYPE test_reform_stmt_cache_hits_total counter
+test_reform_stmt_cache_hits_total 0
+# HELP test_reform_stmt_cache_misses_total Total number of statements prepared for cache.
+# TYPE test_reform_stmt_cache_misses_total counter
+test_reform_stmt_cache_misses_total 0
+# HELP test_reform_stmt_cache_size Current number of prepared statements in cache.
+# TYPE test_reform_stmt_cache_size gauge
+test_reform_stmt_cache_size 0
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/AlekSi/reform"
+)
+
+// StatementCacheCollector is a prometheus.Collector for reform.StatementCache metrics:
+//
+//	prometheus.MustRegister(metrics.NewStatementCacheCollector("myapp", cache))
+type StatementCacheCollector struct {
+	cache     *reform.StatementCache
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	evictions *prometheus.Desc
+	size      *prometheus.Desc
+}
+
+// NewStatementCacheCollector creates a new StatementCacheCollector with metrics in given namespace (may be empty).
+func NewStatementCacheCollector(namespace string, cache *reform.StatementCache) *StatementCacheCollector {
+	name := func(n string) string {
+		return prometheus.BuildFQName(namespace, "reform", n)
+	}
+	return &StatementCacheCollector{
+		cache:     cache,
+		hits:      prometheus.NewDesc(name("stmt_cache_hits_total"), "Total number of queries executed with cached prepared statement.", nil, nil),
+		misses:    prometheus.NewDesc(name("stmt_cache_misses_total"), "Total number of statements prepared for cache.", nil, nil),
+		evictions: prometheus.NewDesc(name("stmt_cache_evictions_total"), "Total number of statements evicted from cache.", nil, nil),
+		size:      prometheus.NewDesc(name("stmt_cache_size"), "Current number of prepared statements in cache.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *StatementCacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.size
+}
+
+// Collect implements prometheus.Collector.
+func (c *StatementCacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.cache.Stats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions))
+	ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(stats.Size))
+}
+
+// check interface
+var _ prometheus.Collector = new(StatementCacheCollector)
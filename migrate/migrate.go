@@ -0,0 +1,256 @@
+// Package migrate implements versioned schema migrations for reform.
+//
+// Migration has a version, a name and up and down functions executed in a transaction.
+// They may be written in Go or loaded from SQL files with LoadDir:
+//
+//	0001_create_people.up.sql
+//	0001_create_people.down.sql
+//	0002_add_email.up.sql
+//
+// Applied migrations are tracked in schema_migrations table which is created if needed:
+//
+//	migrations, err := migrate.LoadDir("migrations")
+//	m, err := migrate.New(db, migrations)
+//	applied, err := m.Up()
+//
+// Note that some databases (like MySQL) implicitly commit transaction on schema changes,
+// so failed migration may be applied partially there.
+package migrate // TODO add canonical import path via gopkg.in
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/AlekSi/reform"
+)
+
+// Func is a migration function. It is executed in a transaction.
+type Func func(tx *reform.TX) error
+
+// Migration represents a single versioned schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      Func
+	Down    Func // nil for irreversible migration
+}
+
+// Status represents migration with its state.
+type Status struct {
+	Migration
+	AppliedAt *time.Time // nil if migration is not applied
+}
+
+// SQL returns migration function which executes given SQL query.
+// Query may contain several statements if it is supported by database driver.
+func SQL(query string) Func {
+	return func(tx *reform.TX) error {
+		_, err := tx.Exec(query)
+		return err
+	}
+}
+
+// migrationFile matches SQL migration file name: version, name and direction.
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// LoadDir returns migrations for SQL files in given directory sorted by version.
+// Files should be named "<version>_<name>.up.sql" and "<version>_<name>.down.sql"; down file is optional.
+// Other files are ignored.
+func LoadDir(dir string) ([]Migration, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, f := range files {
+		sm := migrationFile.FindStringSubmatch(f.Name())
+		if f.IsDir() || sm == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(sm[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: %s", f.Name(), err)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: sm[2]}
+			byVersion[version] = m
+		}
+		if m.Name != sm[2] {
+			return nil, fmt.Errorf("migrate: %s: version %d is already used by %s", f.Name(), version, m.Name)
+		}
+		if sm[3] == "up" {
+			m.Up = SQL(string(b))
+		} else {
+			m.Down = SQL(string(b))
+		}
+	}
+
+	res := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == nil {
+			return nil, fmt.Errorf("migrate: no up file for version %d (%s)", m.Version, m.Name)
+		}
+		res = append(res, *m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+	return res, nil
+}
+
+// Migrator applies and rolls back migrations.
+type Migrator struct {
+	db         *reform.DB
+	migrations []Migration
+}
+
+// New creates new Migrator for given database and migrations.
+// Migrations should have unique positive versions and up functions.
+func New(db *reform.DB, migrations []Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migrate: %s has invalid version %d", m.Name, m.Version)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migrate: %d_%s has no up function", m.Version, m.Name)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migrate: %s and %s have the same version %d", sorted[i-1].Name, m.Name, m.Version)
+		}
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+	}, nil
+}
+
+// createTable creates schema_migrations table if it does not exist.
+func (m *Migrator) createTable() error {
+	q := m.db.Querier
+	columns := SchemaMigrationTable.Columns()
+	_, err := q.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s BIGINT NOT NULL PRIMARY KEY, %s VARCHAR(255) NOT NULL, %s TIMESTAMP NOT NULL)",
+		q.QuoteIdentifier(SchemaMigrationTable.Name()),
+		q.QuoteIdentifier(columns[0]),
+		q.QuoteIdentifier(columns[1]),
+		q.QuoteIdentifier(columns[2]),
+	))
+	return err
+}
+
+// applied returns applied migrations by version.
+func (m *Migrator) applied() (map[int64]*SchemaMigration, error) {
+	if err := m.createTable(); err != nil {
+		return nil, err
+	}
+
+	structs, err := m.db.SelectAllFrom(SchemaMigrationTable, "")
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]*SchemaMigration, len(structs))
+	for _, str := range structs {
+		sm := str.(*SchemaMigration)
+		res[sm.Version] = sm
+	}
+	return res, nil
+}
+
+// Status returns all known migrations with their state sorted by version.
+// Applied migrations which are not known to Migrator are not returned.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		res[i].Migration = migration
+		if sm := applied[migration.Version]; sm != nil {
+			t := sm.AppliedAt
+			res[i].AppliedAt = &t
+		}
+	}
+	return res, nil
+}
+
+// Up applies all not yet applied migrations in version order, each in its own transaction,
+// and returns applied ones. It stops on the first error.
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Migration
+	for _, migration := range m.migrations {
+		if applied[migration.Version] != nil {
+			continue
+		}
+
+		err = m.db.InTransaction(func(tx *reform.TX) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Insert(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC().Truncate(time.Second),
+			})
+		})
+		if err != nil {
+			return res, fmt.Errorf("migrate: %d_%s: %s", migration.Version, migration.Name, err)
+		}
+		res = append(res, migration)
+	}
+	return res, nil
+}
+
+// Down rolls back the last applied migration in a transaction and returns it.
+// It returns nil migration if there are no applied migrations.
+func (m *Migrator) Down() (*Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var last *Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if applied[m.migrations[i].Version] != nil {
+			last = &m.migrations[i]
+			break
+		}
+	}
+	if last == nil {
+		return nil, nil
+	}
+	if last.Down == nil {
+		return nil, fmt.Errorf("migrate: %d_%s is irreversible", last.Version, last.Name)
+	}
+
+	err = m.db.InTransaction(func(tx *reform.TX) error {
+		if err := last.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(applied[last.Version])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %d_%s: %s", last.Version, last.Name, err)
+	}
+	return last, nil
+}
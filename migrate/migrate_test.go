@@ -0,0 +1,207 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/sqlite3"
+)
+
+// setupDB returns DB for empty in-memory SQLite3 database.
+func setupDB(t *testing.T) *reform.DB {
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	sqlDB.SetMaxOpenConns(1) // each connection has its own in-memory database
+	return reform.NewDB(sqlDB, sqlite3.Dialect, nil)
+}
+
+// columns returns column names of given table.
+func columns(t *testing.T, db *reform.DB, table string) []string {
+	rows, err := db.Query("PRAGMA table_info(" + db.QuoteIdentifier(table) + ")")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var cid, pk int
+		var name, typ string
+		var notNull bool
+		var dflt sql.NullString
+		require.NoError(t, rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk))
+		res = append(res, name)
+	}
+	require.NoError(t, rows.Err())
+	return res
+}
+
+func TestLoadDir(t *testing.T) {
+	dir, err := os.MkdirTemp("", "reform-migrate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, content := range map[string]string{
+		"0002_add_email.up.sql":       "ALTER TABLE people ADD email VARCHAR(255)",
+		"0001_create_people.up.sql":   "CREATE TABLE people (id INTEGER PRIMARY KEY)",
+		"0001_create_people.down.sql": "DROP TABLE people",
+		"README.md":                   "ignored",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	migrations, err := LoadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create_people", migrations[0].Name)
+	assert.NotNil(t, migrations[0].Up)
+	assert.NotNil(t, migrations[0].Down)
+	assert.Equal(t, int64(2), migrations[1].Version)
+	assert.Equal(t, "add_email", migrations[1].Name)
+	assert.NotNil(t, migrations[1].Up)
+	assert.Nil(t, migrations[1].Down)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0003_drop_email.down.sql"), nil, 0644))
+	_, err = LoadDir(dir)
+	assert.Equal(t, errors.New("migrate: no up file for version 3 (drop_email)"), err)
+}
+
+func TestNew(t *testing.T) {
+	up := SQL("SELECT 1")
+
+	m, err := New(nil, []Migration{{Version: 2, Name: "b", Up: up}, {Version: 1, Name: "a", Up: up}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), m.migrations[0].Version)
+	assert.Equal(t, int64(2), m.migrations[1].Version)
+
+	_, err = New(nil, []Migration{{Version: 1, Name: "a", Up: up}, {Version: 1, Name: "b", Up: up}})
+	assert.Equal(t, errors.New("migrate: a and b have the same version 1"), err)
+
+	_, err = New(nil, []Migration{{Version: 0, Name: "a", Up: up}})
+	assert.Equal(t, errors.New("migrate: a has invalid version 0"), err)
+
+	_, err = New(nil, []Migration{{Version: 1, Name: "a"}})
+	assert.Equal(t, errors.New("migrate: 1_a has no up function"), err)
+}
+
+func TestUpDown(t *testing.T) {
+	db := setupDB(t)
+	migrations := []Migration{{
+		Version: 1,
+		Name:    "create_people",
+		Up:      SQL("CREATE TABLE people (id INTEGER PRIMARY KEY)"),
+		Down:    SQL("DROP TABLE people"),
+	}, {
+		Version: 2,
+		Name:    "add_email",
+		Up:      SQL("ALTER TABLE people ADD email VARCHAR(255)"),
+		Down:    SQL("ALTER TABLE people DROP COLUMN email"),
+	}}
+	m, err := New(db, migrations[:1])
+	require.NoError(t, err)
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Nil(t, statuses[0].AppliedAt)
+
+	applied, err := m.Up()
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, int64(1), applied[0].Version)
+	assert.Equal(t, []string{"id"}, columns(t, db, "people"))
+
+	// only new migrations are applied
+	m, err = New(db, migrations)
+	require.NoError(t, err)
+	applied, err = m.Up()
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, int64(2), applied[0].Version)
+	assert.Equal(t, []string{"id", "email"}, columns(t, db, "people"))
+
+	applied, err = m.Up()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err = m.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	for _, s := range statuses {
+		require.NotNil(t, s.AppliedAt, "%d", s.Version)
+	}
+	versions, err := db.SelectAllFrom(SchemaMigrationTable, "ORDER BY version")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "create_people", versions[0].(*SchemaMigration).Name)
+	assert.Equal(t, "add_email", versions[1].(*SchemaMigration).Name)
+
+	// the last applied migration is rolled back
+	migration, err := m.Down()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), migration.Version)
+	assert.Equal(t, []string{"id"}, columns(t, db, "people"))
+	statuses, err = m.Status()
+	require.NoError(t, err)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+
+	migration, err = m.Down()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), migration.Version)
+	assert.Empty(t, columns(t, db, "people"))
+
+	migration, err = m.Down()
+	require.NoError(t, err)
+	assert.Nil(t, migration)
+}
+
+func TestUpError(t *testing.T) {
+	db := setupDB(t)
+	errBoom := errors.New("boom")
+	m, err := New(db, []Migration{{
+		Version: 1,
+		Name:    "create_people",
+		Up:      SQL("CREATE TABLE people (id INTEGER PRIMARY KEY)"),
+	}, {
+		Version: 2,
+		Name:    "broken",
+		Up: func(tx *reform.TX) error {
+			if _, err := tx.Exec("CREATE TABLE projects (id INTEGER PRIMARY KEY)"); err != nil {
+				return err
+			}
+			return errBoom
+		},
+	}, {
+		Version: 3,
+		Name:    "never",
+		Up:      SQL("CREATE TABLE never (id INTEGER PRIMARY KEY)"),
+	}})
+	require.NoError(t, err)
+
+	// migrations are applied up to the failed one, which is rolled back
+	applied, err := m.Up()
+	assert.EqualError(t, err, "migrate: 2_broken: boom")
+	require.Len(t, applied, 1)
+	assert.Equal(t, int64(1), applied[0].Version)
+	assert.Empty(t, columns(t, db, "projects"))
+	assert.Empty(t, columns(t, db, "never"))
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.Nil(t, statuses[2].AppliedAt)
+
+	// the first migration has no down function
+	_, err = m.Down()
+	assert.EqualError(t, err, "migrate: 1_create_people is irreversible")
+}
@@ -0,0 +1,16 @@
+package migrate
+
+import (
+	"time"
+)
+
+//go:generate reform
+
+// SchemaMigration represents an applied migration in schema_migrations table.
+//
+//reform:schema_migrations
+type SchemaMigration struct {
+	Version   int64     `reform:"version,pk"`
+	Name      string    `reform:"name"`
+	AppliedAt time.Time `reform:"applied_at"`
+}
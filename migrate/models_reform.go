@@ -0,0 +1,157 @@
+package migrate
+
+// generated with github.com/AlekSi/reform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/parse"
+)
+
+type schemaMigrationTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see SchemaMigrationColumns.
+	C struct {
+		Version   string
+		Name      string
+		AppliedAt string
+	}
+}
+
+// Name returns a view or table name in SQL database (schema_migrations).
+func (v *schemaMigrationTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *schemaMigrationTable) Columns() []string {
+	return []string{"version", "name", "applied_at"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *schemaMigrationTable) NewStruct() reform.Struct {
+	return new(SchemaMigration)
+}
+
+// NewRecord makes a new record for that table.
+func (v *schemaMigrationTable) NewRecord() reform.Record {
+	return new(SchemaMigration)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *schemaMigrationTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// SchemaMigrationTable represents schema_migrations view or table in SQL database.
+var SchemaMigrationTable = &schemaMigrationTable{
+	s: parse.StructInfo{Type: "SchemaMigration", SQLName: "schema_migrations", Fields: []parse.FieldInfo{{Name: "Version", Type: "int64", Column: "version"}, {Name: "Name", Type: "string", Column: "name"}, {Name: "AppliedAt", Type: "time.Time", Column: "applied_at"}}, PKFieldIndex: 0},
+	z: new(SchemaMigration).Values(),
+	C: SchemaMigrationColumns,
+}
+
+// SchemaMigrationColumns contains column names of schema_migrations view or table in SQL database.
+// Use them (or SchemaMigrationTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var SchemaMigrationColumns = struct {
+	Version   string
+	Name      string
+	AppliedAt string
+}{
+	Version:   "version",
+	Name:      "name",
+	AppliedAt: "applied_at",
+}
+
+// String returns a string representation of this struct or record.
+func (s SchemaMigration) String() string {
+	res := make([]string, 3)
+	res[0] = "Version: " + reform.Inspect(s.Version, true)
+	res[1] = "Name: " + reform.Inspect(s.Name, true)
+	res[2] = "AppliedAt: " + reform.Inspect(s.AppliedAt, true)
+	return strings.Join(res, ", ")
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *SchemaMigration) Clone() *SchemaMigration {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *SchemaMigration) Values() []interface{} {
+	return []interface{}{
+		s.Version,
+		s.Name,
+		s.AppliedAt,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *SchemaMigration) Pointers() []interface{} {
+	return []interface{}{
+		&s.Version,
+		&s.Name,
+		&s.AppliedAt,
+	}
+}
+
+// View returns View object for that struct.
+func (s *SchemaMigration) View() reform.View {
+	return SchemaMigrationTable
+}
+
+// Table returns Table object for that record.
+func (s *SchemaMigration) Table() reform.Table {
+	return SchemaMigrationTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *SchemaMigration) PKValue() interface{} {
+	return s.Version
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *SchemaMigration) PKPointer() interface{} {
+	return &s.Version
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *SchemaMigration) HasPK() bool {
+	return s.Version != SchemaMigrationTable.z[SchemaMigrationTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key.
+func (s *SchemaMigration) SetPK(pk interface{}) {
+	if i64, ok := pk.(int64); ok {
+		s.Version = int64(i64)
+	} else {
+		s.Version = pk.(int64)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.View   = SchemaMigrationTable
+	_ reform.Struct = new(SchemaMigration)
+	_ reform.Table  = SchemaMigrationTable
+	_ reform.Record = new(SchemaMigration)
+	_ fmt.Stringer  = new(SchemaMigration)
+)
+
+func init() {
+	parse.AssertUpToDate(&SchemaMigrationTable.s, new(SchemaMigration))
+}
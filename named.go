@@ -0,0 +1,189 @@
+package reform
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// BindNamed replaces ":name" parameters in query with dialect's placeholders and returns them
+// with args in placeholder order, so long hand-written queries don't depend on positional argument order:
+//
+//	query, args, err := q.BindNamed("UPDATE people SET name = :name WHERE id = :id", map[string]interface{}{
+//		"id":   1,
+//		"name": "Alice",
+//	})
+//
+// Arg is either map[string]interface{} or Struct; for Struct, column names are used as parameter names,
+// and values are the same as for Insert and Update.
+// Parameter names start with a letter or underscore and contain letters, digits and underscores.
+// String literals (with backslash escapes for dialects quoting identifiers with backticks, like MySQL,
+// and in PostgreSQL's E'...' strings), quoted identifiers, comments, PostgreSQL casts ("::text"),
+// colons after names and numbers (like in array slices "arr[1:2]"), and positional parameters (like Oracle's ":1")
+// are left as is. Each occurrence of the same name gets its own placeholder.
+// Error is returned if arg has no value for parameter.
+func (q *Querier) BindNamed(query string, arg interface{}) (string, []interface{}, error) {
+	query, args, err := q.bindNamed(query, arg)
+	return query, driverArgs(args), err
+}
+
+// bindNamed is BindNamed which keeps values of sensitive Struct columns marked for redaction in logs.
+func (q *Querier) bindNamed(query string, arg interface{}) (string, []interface{}, error) {
+	var lookup func(name string) (interface{}, bool)
+	switch arg := arg.(type) {
+	case map[string]interface{}:
+		lookup = func(name string) (interface{}, bool) {
+			v, ok := arg[name]
+			return v, ok
+		}
+	case Struct:
+		values, err := q.values(arg)
+		if err != nil {
+			return "", nil, err
+		}
+		columns := arg.View().Columns()
+		lookup = func(name string) (interface{}, bool) {
+			for i, c := range columns {
+				if c == name {
+					return values[i], true
+				}
+			}
+			return nil, false
+		}
+	default:
+		return "", nil, fmt.Errorf("reform: BindNamed: unexpected arg type %T, expected map[string]interface{} or Struct", arg)
+	}
+
+	backslashEscapes := q.backslashEscapes()
+
+	var res strings.Builder
+	var args []interface{}
+	for i := 0; i < len(query); i++ {
+		if end := skippedEnd(query, i, backslashEscapes); end > i {
+			// copy literal, quoted identifier or comment as is
+			res.WriteString(query[i:end])
+			i = end - 1
+			continue
+		}
+
+		c := query[i]
+		switch {
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			// PostgreSQL cast
+			res.WriteString("::")
+			i++
+
+		case c == ':' && i+1 < len(query) && isNameStartByte(query[i+1]) && (i == 0 || !isNameByte(query[i-1])):
+			j := i + 1
+			for j < len(query) && isNameByte(query[j]) {
+				j++
+			}
+			name := query[i+1 : j]
+			v, ok := lookup(name)
+			if !ok {
+				return "", nil, fmt.Errorf("reform: BindNamed: no value for parameter %q", name)
+			}
+			args = append(args, v)
+			res.WriteString(q.Placeholder(len(args)))
+			i = j - 1
+
+		default:
+			res.WriteByte(c)
+		}
+	}
+
+	return res.String(), args, nil
+}
+
+// backslashEscapes returns true if backslash escapes characters in string literals and quoted identifiers
+// of querier's dialect: it is true for dialects quoting identifiers with backticks, like MySQL.
+func (q *Querier) backslashEscapes() bool {
+	return strings.HasPrefix(q.QuoteIdentifier("select"), "`")
+}
+
+// skippedEnd returns index after the end of string literal, quoted identifier or comment which starts at query[i],
+// or i if there is none; placeholders and parameters inside them are ignored.
+// Backslash escapes are handled if backslashEscapes is true, and in PostgreSQL's E'...' strings.
+func skippedEnd(query string, i int, backslashEscapes bool) int {
+	switch c := query[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		escapes := (c != '`' && backslashEscapes) || (c == '\'' && isEscapeString(query, i))
+		return literalEnd(query, i, escapes)
+
+	case strings.HasPrefix(query[i:], "--"):
+		end := strings.IndexByte(query[i:], '\n')
+		if end < 0 {
+			return len(query)
+		}
+		return i + end
+
+	case strings.HasPrefix(query[i:], "/*"):
+		end := strings.Index(query[i+2:], "*/")
+		if end < 0 {
+			return len(query)
+		}
+		return i + 2 + end + 2
+
+	default:
+		return i
+	}
+}
+
+// literalEnd returns index after the end of string literal or quoted identifier which starts at query[start],
+// or length of query if it is not terminated. Doubled quotes are part of literal;
+// if escapes is true, backslash escapes the next character.
+func literalEnd(query string, start int, escapes bool) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// isEscapeString returns true if string literal starting at query[start] is PostgreSQL's escape string
+// constant like E'\n'.
+func isEscapeString(query string, start int) bool {
+	if start == 0 || (query[start-1] != 'E' && query[start-1] != 'e') {
+		return false
+	}
+	return start == 1 || !isNameByte(query[start-2])
+}
+
+// isNameStartByte returns true if c may be used as the first byte of named parameter.
+func isNameStartByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isNameByte returns true if c may be used in named parameter.
+func isNameByte(c byte) bool {
+	return isNameStartByte(c) || (c >= '0' && c <= '9')
+}
+
+// ExecNamed is like Exec, but uses ":name" parameters bound from arg, see BindNamed.
+func (q *Querier) ExecNamed(query string, arg interface{}) (sql.Result, error) {
+	query, args, err := q.bindNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return q.Exec(query, args...)
+}
+
+// QueryNamed is like Query, but uses ":name" parameters bound from arg, see BindNamed.
+func (q *Querier) QueryNamed(query string, arg interface{}) (*sql.Rows, error) {
+	query, args, err := q.bindNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return q.Query(query, args...)
+}
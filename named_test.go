@@ -0,0 +1,76 @@
+package reform_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	. "github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func (s *ReformSuite) TestBindNamed() {
+	pg := reformtest.New(postgresql.Dialect)
+	defer pg.Close()
+
+	query, args, err := pg.DB.BindNamed(
+		`SELECT id::text, ':skipped', "a:b" FROM people WHERE name = :name OR email = :email OR name = :name`,
+		map[string]interface{}{"name": "Alice", "email": "alice@example.com"},
+	)
+	s.Require().NoError(err)
+	s.Equal(`SELECT id::text, ':skipped', "a:b" FROM people WHERE name = $1 OR email = $2 OR name = $3`, query)
+	s.Equal([]interface{}{"Alice", "alice@example.com", "Alice"}, args)
+
+	query, args, err = pg.DB.BindNamed(
+		"SELECT arr[1:2], arr[lo:hi], E'it\\'s :skipped', 'C:\\' -- it's :skipped\n"+
+			"FROM t /* it's :skipped */ WHERE a = :a AND b = 'it''s :skipped' AND c = :1",
+		map[string]interface{}{"a": 1},
+	)
+	s.Require().NoError(err)
+	s.Equal("SELECT arr[1:2], arr[lo:hi], E'it\\'s :skipped', 'C:\\' -- it's :skipped\n"+
+		"FROM t /* it's :skipped */ WHERE a = $1 AND b = 'it''s :skipped' AND c = :1", query)
+	s.Equal([]interface{}{1}, args)
+
+	_, _, err = pg.DB.BindNamed("SELECT :foo", map[string]interface{}{})
+	s.Equal(errors.New(`reform: BindNamed: no value for parameter "foo"`), err)
+	_, _, err = pg.DB.BindNamed("SELECT :foo", 42)
+	s.EqualError(err, "reform: BindNamed: unexpected arg type int, expected map[string]interface{} or Struct")
+
+	my := reformtest.New(mysql.Dialect)
+	defer my.Close()
+	_, err = my.DB.ExecNamed("UPDATE people SET name = :name WHERE id = :id", &Person{ID: 7, Name: "Bob"})
+	s.Require().NoError(err)
+	statements := my.Statements()
+	s.Require().Len(statements, 1)
+	s.Equal("UPDATE people SET name = ? WHERE id = ?", statements[0].Query)
+	s.Equal([]interface{}{"Bob", int32(7)}, statements[0].Args)
+
+	query, args, err = my.DB.BindNamed(`SELECT 'it\'s :skipped', "a\":skipped" FROM people WHERE name = :name`, &Person{Name: "Bob"})
+	s.Require().NoError(err)
+	s.Equal(`SELECT 'it\'s :skipped', "a\":skipped" FROM people WHERE name = ?`, query)
+	s.Equal([]interface{}{"Bob"}, args)
+
+	// struct values are the same as for Insert: tenant column is filled and checked
+	scoped := my.DB.WithTenantScope(&reform.TenantScope{
+		Column: "project_id",
+		Value:  func(context.Context) interface{} { return "baron" },
+	})
+	pp := &PersonProject{PersonID: 101}
+	_, args, err = scoped.BindNamed("SELECT :person_id, :project_id", pp)
+	s.Require().NoError(err)
+	s.Equal([]interface{}{int32(101), "baron"}, args)
+	s.Equal("baron", pp.ProjectID)
+	_, _, err = scoped.BindNamed("SELECT :person_id", &PersonProject{PersonID: 101, ProjectID: "queen"})
+	s.Equal(reform.ErrTenantMismatch, err)
+
+	// real database
+	rows, err := s.q.QueryNamed("SELECT name FROM people WHERE id = :id", map[string]interface{}{"id": 102})
+	s.Require().NoError(err)
+	defer rows.Close()
+	s.Require().True(rows.Next())
+	var name string
+	s.Require().NoError(rows.Scan(&name))
+	s.Equal("Elfrieda Abbott", name)
+}
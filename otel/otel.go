@@ -0,0 +1,146 @@
+// Package otel implements OpenTelemetry tracing for reform.
+//
+// Logger emits a client span for every query, command and transaction boundary (BEGIN, COMMIT, ROLLBACK)
+// with db.system, db.statement, db.operation, db.sql.table and db.rows_affected attributes, and error status for failed ones:
+//
+//	db.Logger = otel.NewLogger(tracer, db.Dialect, db.Logger)
+//
+// or, for a single querier:
+//
+//	q := otel.WithTracer(tx.Querier, tracer)
+//
+// Spans are created after query execution with its start time and duration, so they are children
+// of span in querier's context (see reform.Querier.WithContext), but not parents of driver's spans.
+package otel // TODO add canonical import path via gopkg.in
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AlekSi/reform"
+)
+
+// Logger is a reform.StructuredLogger which emits OpenTelemetry spans
+// and passes queries to the next Logger, if any.
+type Logger struct {
+	tracer trace.Tracer
+	system string
+	next   reform.Logger
+}
+
+// NewLogger creates a new Logger for given tracer. Dialect is used for db.system attribute.
+// Next logger may be nil.
+func NewLogger(tracer trace.Tracer, dialect reform.Dialect, next reform.Logger) *Logger {
+	return &Logger{
+		tracer: tracer,
+		system: dbSystem(dialect),
+		next:   next,
+	}
+}
+
+// WithTracer returns a copy of querier which emits OpenTelemetry spans with given tracer,
+// keeping querier's Logger.
+func WithTracer(q *reform.Querier, tracer trace.Tracer) *reform.Querier {
+	return q.WithLogger(NewLogger(tracer, q.Dialect, q.Logger))
+}
+
+// dbSystem returns db.system attribute value for given dialect.
+func dbSystem(dialect reform.Dialect) string {
+	if dialect == nil {
+		return "other_sql"
+	}
+	t := reflect.TypeOf(dialect)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch name := path.Base(t.PkgPath()); name {
+	case "sqlite3":
+		return "sqlite"
+	case "mysql", "postgresql", "mssql", "cockroachdb", "clickhouse":
+		return name
+	default:
+		return "other_sql"
+	}
+}
+
+// Before passes query to the next Logger.
+func (l *Logger) Before(query string, args []interface{}) {
+	if l.next != nil {
+		l.next.Before(query, args)
+	}
+}
+
+// After passes query to the next Logger. It is not called by reform: LogQuery is called instead.
+func (l *Logger) After(query string, args []interface{}, d time.Duration, err error) {
+	if l.next != nil {
+		l.next.After(query, args, d, err)
+	}
+}
+
+// BeforeTagged passes tagged query to the next Logger.
+func (l *Logger) BeforeTagged(tag, query string, args []interface{}) {
+	if tl, ok := l.next.(reform.TaggedLogger); ok {
+		tl.BeforeTagged(tag, query, args)
+		return
+	}
+	l.Before(query, args)
+}
+
+// AfterTagged passes tagged query to the next Logger. It is not called by reform: LogQuery is called instead.
+func (l *Logger) AfterTagged(tag, query string, args []interface{}, d time.Duration, err error) {
+	if tl, ok := l.next.(reform.TaggedLogger); ok {
+		tl.AfterTagged(tag, query, args, d, err)
+		return
+	}
+	l.After(query, args, d, err)
+}
+
+// LogQuery emits span for query and passes it to the next Logger.
+func (l *Logger) LogQuery(ctx context.Context, event *reform.QueryEvent) {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", l.system),
+		attribute.String("db.statement", event.Query),
+		attribute.String("db.operation", event.Operation),
+	}
+	if event.View != "" {
+		attrs = append(attrs, attribute.String("db.sql.table", event.View))
+	}
+	if event.RowsAffected >= 0 {
+		attrs = append(attrs, attribute.Int64("db.rows_affected", event.RowsAffected))
+	}
+	if event.Tag != "" {
+		attrs = append(attrs, attribute.String("reform.tag", event.Tag))
+	}
+	if event.Caller != "" {
+		attrs = append(attrs, attribute.String("code.filepath", event.Caller))
+	}
+
+	name := event.Operation
+	if event.View != "" {
+		name += " " + event.View
+	}
+	_, span := l.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(event.Start),
+		trace.WithAttributes(attrs...),
+	)
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+	span.End(trace.WithTimestamp(event.Start.Add(event.Duration)))
+
+	reform.LogEvent(ctx, l.next, event)
+}
+
+// check interfaces
+var (
+	_ reform.StructuredLogger = new(Logger)
+	_ reform.TaggedLogger     = new(Logger)
+)
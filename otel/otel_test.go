@@ -0,0 +1,91 @@
+package otel
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/dialects/sqlite3"
+	"github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+// attributes returns span attributes by key.
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	res := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		res[kv.Key] = kv.Value
+	}
+	return res
+}
+
+func TestLogger(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)).Tracer("test")
+
+	f := reformtest.New(postgresql.Dialect)
+	var logged []string
+	f.DB.Logger = reform.NewPrintfLogger(func(format string, a ...interface{}) {
+		logged = append(logged, format)
+	})
+	q := WithTracer(f.DB.Querier, tracer)
+
+	require.NoError(t, q.Update(&models.Person{ID: 1, Name: "Alice", CreatedAt: time.Now()}))
+
+	errBoom := errors.New("boom")
+	f.StubError(models.PersonTable, errBoom)
+	_, err := q.Tagged("find").FindByPrimaryKeyFrom(models.PersonTable, int32(1))
+	assert.True(t, errors.Is(err, errBoom), "%+v", err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+
+	span := spans[0]
+	assert.Equal(t, "UPDATE people", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, codes.Unset, span.Status().Code)
+	assert.False(t, span.EndTime().Before(span.StartTime()))
+	attrs := attributes(span)
+	assert.Equal(t, "postgresql", attrs["db.system"].AsString())
+	assert.Equal(t, "UPDATE", attrs["db.operation"].AsString())
+	assert.Equal(t, "people", attrs["db.sql.table"].AsString())
+	assert.Equal(t, int64(1), attrs["db.rows_affected"].AsInt64())
+	assert.Contains(t, attrs["db.statement"].AsString(), `UPDATE "people" SET`)
+	assert.NotContains(t, attrs, attribute.Key("reform.tag"))
+
+	span = spans[1]
+	assert.Equal(t, "SELECT people", span.Name())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Equal(t, "boom", span.Status().Description)
+	require.Len(t, span.Events(), 1)
+	assert.Equal(t, "exception", span.Events()[0].Name)
+	attrs = attributes(span)
+	assert.Equal(t, "SELECT", attrs["db.operation"].AsString())
+	assert.Equal(t, "find", attrs["reform.tag"].AsString())
+	assert.NotContains(t, attrs, attribute.Key("db.rows_affected"))
+
+	// queries are passed to the next logger: Before and After for each
+	assert.Len(t, logged, 4)
+}
+
+func TestDBSystem(t *testing.T) {
+	for dialect, expected := range map[reform.Dialect]string{
+		postgresql.Dialect: "postgresql",
+		mysql.Dialect:      "mysql",
+		sqlite3.Dialect:    "sqlite",
+	} {
+		assert.Equal(t, expected, dbSystem(dialect))
+	}
+	assert.Equal(t, "other_sql", dbSystem(nil))
+}
@@ -0,0 +1,21 @@
+package outbox
+
+import (
+	"time"
+)
+
+//go:generate reform -ddl
+
+// Message represents a message in outbox_messages table.
+//
+//reform:outbox_messages
+type Message struct {
+	ID        int64      `reform:"id,pk"`
+	Topic     string     `reform:"topic"`
+	Key       string     `reform:"key"`
+	Payload   []byte     `reform:"payload"`
+	CreatedAt time.Time  `reform:"created_at"`
+	Attempts  int32      `reform:"attempts"`
+	LastError *string    `reform:"last_error"`
+	SentAt    *time.Time `reform:"sent_at"`
+}
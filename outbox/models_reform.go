@@ -0,0 +1,219 @@
+package outbox
+
+// generated with github.com/AlekSi/reform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/parse"
+)
+
+type messageTable struct {
+	s parse.StructInfo
+	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see MessageColumns.
+	C struct {
+		ID        string
+		Topic     string
+		Key       string
+		Payload   string
+		CreatedAt string
+		Attempts  string
+		LastError string
+		SentAt    string
+	}
+}
+
+// Name returns a view or table name in SQL database (outbox_messages).
+func (v *messageTable) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *messageTable) Columns() []string {
+	return []string{"id", "topic", "key", "payload", "created_at", "attempts", "last_error", "sent_at"}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *messageTable) NewStruct() reform.Struct {
+	return new(Message)
+}
+
+// NewRecord makes a new record for that table.
+func (v *messageTable) NewRecord() reform.Record {
+	return new(Message)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *messageTable) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *messageTable) DDLColumns() []reform.Column {
+	return []reform.Column{
+		{Name: "id", Type: reform.BigIntColumn, PK: true, AutoIncrement: true},
+		{Name: "topic", Type: reform.StringColumn},
+		{Name: "key", Type: reform.StringColumn},
+		{Name: "payload", Type: reform.BytesColumn},
+		{Name: "created_at", Type: reform.TimeColumn},
+		{Name: "attempts", Type: reform.IntColumn},
+		{Name: "last_error", Type: reform.StringColumn, Nullable: true},
+		{Name: "sent_at", Type: reform.TimeColumn, Nullable: true},
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *messageTable) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+// MessageTable represents outbox_messages view or table in SQL database.
+var MessageTable = &messageTable{
+	s: parse.StructInfo{Type: "Message", SQLName: "outbox_messages", Fields: []parse.FieldInfo{{Name: "ID", Type: "int64", Column: "id"}, {Name: "Topic", Type: "string", Column: "topic"}, {Name: "Key", Type: "string", Column: "key"}, {Name: "Payload", Type: "[]byte", Column: "payload"}, {Name: "CreatedAt", Type: "time.Time", Column: "created_at"}, {Name: "Attempts", Type: "int32", Column: "attempts"}, {Name: "LastError", Type: "*string", Column: "last_error"}, {Name: "SentAt", Type: "*time.Time", Column: "sent_at"}}, PKFieldIndex: 0},
+	z: new(Message).Values(),
+	C: MessageColumns,
+}
+
+// MessageColumns contains column names of outbox_messages view or table in SQL database.
+// Use them (or MessageTable.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var MessageColumns = struct {
+	ID        string
+	Topic     string
+	Key       string
+	Payload   string
+	CreatedAt string
+	Attempts  string
+	LastError string
+	SentAt    string
+}{
+	ID:        "id",
+	Topic:     "topic",
+	Key:       "key",
+	Payload:   "payload",
+	CreatedAt: "created_at",
+	Attempts:  "attempts",
+	LastError: "last_error",
+	SentAt:    "sent_at",
+}
+
+// String returns a string representation of this struct or record.
+func (s Message) String() string {
+	res := make([]string, 8)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Topic: " + reform.Inspect(s.Topic, true)
+	res[2] = "Key: " + reform.Inspect(s.Key, true)
+	res[3] = "Payload: " + reform.Inspect(s.Payload, true)
+	res[4] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[5] = "Attempts: " + reform.Inspect(s.Attempts, true)
+	res[6] = "LastError: " + reform.Inspect(s.LastError, true)
+	res[7] = "SentAt: " + reform.Inspect(s.SentAt, true)
+	return strings.Join(res, ", ")
+}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *Message) Clone() *Message {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.Payload != nil {
+		c.Payload = make([]byte, len(s.Payload))
+		copy(c.Payload, s.Payload)
+	}
+	if s.LastError != nil {
+		v := *s.LastError
+		c.LastError = &v
+	}
+	if s.SentAt != nil {
+		v := *s.SentAt
+		c.SentAt = &v
+	}
+	return &c
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *Message) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		s.Topic,
+		s.Key,
+		s.Payload,
+		s.CreatedAt,
+		s.Attempts,
+		s.LastError,
+		s.SentAt,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *Message) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Topic,
+		&s.Key,
+		&s.Payload,
+		&s.CreatedAt,
+		&s.Attempts,
+		&s.LastError,
+		&s.SentAt,
+	}
+}
+
+// View returns View object for that struct.
+func (s *Message) View() reform.View {
+	return MessageTable
+}
+
+// Table returns Table object for that record.
+func (s *Message) Table() reform.Table {
+	return MessageTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Message) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Message) PKPointer() interface{} {
+	return &s.ID
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Message) HasPK() bool {
+	return s.ID != MessageTable.z[MessageTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key.
+func (s *Message) SetPK(pk interface{}) {
+	if i64, ok := pk.(int64); ok {
+		s.ID = int64(i64)
+	} else {
+		s.ID = pk.(int64)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.View     = MessageTable
+	_ reform.Struct   = new(Message)
+	_ reform.Table    = MessageTable
+	_ reform.Record   = new(Message)
+	_ reform.DDLTable = MessageTable
+	_ fmt.Stringer    = new(Message)
+)
+
+func init() {
+	parse.AssertUpToDate(&MessageTable.s, new(Message))
+}
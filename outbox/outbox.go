@@ -0,0 +1,224 @@
+// Package outbox implements transactional outbox pattern for reform.
+//
+// Messages are stored in outbox_messages table in the same transaction as business data,
+// so they are published if and only if that transaction is committed:
+//
+//	err := db.InTransaction(func(tx *reform.TX) error {
+//		if err := tx.Insert(order); err != nil {
+//			return err
+//		}
+//		_, err := d.Enqueue(tx, "orders.created", order)
+//		return err
+//	})
+//
+// Dispatcher polls the table and passes unsent messages to Publisher.
+// Publishers for message brokers are small adapters, for example, for Kafka:
+//
+//	p := outbox.PublisherFunc(func(ctx context.Context, msg *outbox.Message) error {
+//		return writer.WriteMessages(ctx, kafka.Message{Topic: msg.Topic, Key: []byte(msg.Key), Value: msg.Payload})
+//	})
+//	d := outbox.New(db, p)
+//	go d.Run(ctx)
+//
+// Delivery is at-least-once: message may be published again if process crashes or database fails
+// after publishing but before marking message as sent, so consumers should be idempotent
+// (ID may be used as deduplication key). Messages are published in ID order, but failed messages
+// are retried with later batches.
+//
+// Table may be created with MessageTable.CreateTableSQL or Querier.CreateTable.
+package outbox // TODO add canonical import path via gopkg.in
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/AlekSi/reform"
+)
+
+// Publisher publishes messages to message broker.
+type Publisher interface {
+	// Publish publishes a single message. It should return nil only if message was accepted by broker.
+	Publish(ctx context.Context, msg *Message) error
+}
+
+// PublisherFunc is an adapter to use ordinary function as Publisher.
+type PublisherFunc func(ctx context.Context, msg *Message) error
+
+// Publish calls f(ctx, msg).
+func (f PublisherFunc) Publish(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// Enqueue stores a message with given topic and payload in transaction.
+// Payload of []byte and string types is stored as is, other values are encoded to JSON.
+func Enqueue(tx *reform.TX, topic string, payload interface{}) (*Message, error) {
+	msg := &Message{Topic: topic}
+	switch p := payload.(type) {
+	case []byte:
+		msg.Payload = p
+	case string:
+		msg.Payload = []byte(p)
+	default:
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		msg.Payload = b
+	}
+
+	if err := EnqueueMessage(tx, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// EnqueueMessage stores given message in transaction. It may be used to set Key.
+// CreatedAt is set to the current time if it is zero.
+func EnqueueMessage(tx *reform.TX, msg *Message) error {
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+	return tx.Insert(msg)
+}
+
+// Dispatcher passes stored messages to Publisher.
+type Dispatcher struct {
+	db        *reform.DB
+	publisher Publisher
+	notify    chan struct{}
+
+	// BatchSize is a maximal number of messages handled in a single transaction. Default is 100.
+	BatchSize int
+
+	// Interval is a delay between polls when there are no more messages. Default is 1 second.
+	Interval time.Duration
+
+	// MaxAttempts is a maximal number of publishing attempts for a message, 0 means no limit.
+	// Messages which reached it are left in the table with LastError for manual handling.
+	MaxAttempts int32
+
+	// OnError is called by Run for errors returned by DispatchOnce; Run continues after that.
+	// If nil, Run stops and returns error.
+	OnError func(err error)
+}
+
+// New creates new Dispatcher for given database and publisher.
+func New(db *reform.DB, publisher Publisher) *Dispatcher {
+	return &Dispatcher{
+		db:        db,
+		publisher: publisher,
+		notify:    make(chan struct{}, 1),
+		BatchSize: 100,
+		Interval:  time.Second,
+	}
+}
+
+// Notify wakes up Run without waiting for Interval. It never blocks.
+func (d *Dispatcher) Notify() {
+	select {
+	case d.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Enqueue is like package-level Enqueue, but also calls Notify after transaction is committed,
+// so message is published without waiting for Interval.
+func (d *Dispatcher) Enqueue(tx *reform.TX, topic string, payload interface{}) (*Message, error) {
+	msg, err := Enqueue(tx, topic, payload)
+	if err != nil {
+		return nil, err
+	}
+	tx.OnCommit(d.Notify)
+	return msg, nil
+}
+
+// DispatchOnce publishes a single batch of unsent messages and returns a number of handled messages.
+// Batch is selected with "FOR UPDATE SKIP LOCKED" (where supported), so several dispatchers may run concurrently.
+// Published messages are marked as sent; for failed ones Attempts is incremented and LastError is set.
+// Publishing errors are not returned.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	var n int
+	err := d.db.InTransactionOpts(ctx, nil, func(tx *reform.TX) error {
+		conditions := []reform.Condition{reform.Eq("sent_at", nil)}
+		if d.MaxAttempts > 0 {
+			conditions = append(conditions, reform.Lt("attempts", d.MaxAttempts))
+		}
+		tail, args, err := reform.Where(conditions...).OrderBy("id").Limit(d.BatchSize).Build(tx.Dialect)
+		if err != nil {
+			return err
+		}
+		structs, err := tx.WithRowLock(reform.ForUpdate|reform.SkipLocked).SelectAllFrom(MessageTable, tail, args...)
+		if err != nil {
+			return err
+		}
+
+		for _, str := range structs {
+			msg := str.(*Message)
+			if err = d.publisher.Publish(ctx, msg); err == nil {
+				now := time.Now().UTC().Truncate(time.Microsecond)
+				msg.SentAt = &now
+				msg.LastError = nil
+			} else {
+				e := err.Error()
+				msg.LastError = &e
+			}
+			msg.Attempts++
+			if err = tx.UpdateColumns(msg, "attempts", "last_error", "sent_at"); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Run calls DispatchOnce until ctx is done. It waits for Interval or Notify call
+// when the last batch was not full. It returns ctx.Err() or error (see OnError).
+func (d *Dispatcher) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		n, err := d.DispatchOnce(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if d.OnError == nil {
+				return err
+			}
+			d.OnError(err)
+		}
+		if err == nil && d.BatchSize > 0 && n >= d.BatchSize {
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(d.Interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-d.notify:
+		case <-timer.C:
+		}
+	}
+}
+
+// Cleanup deletes messages sent before given time and returns a number of deleted messages.
+func (d *Dispatcher) Cleanup(before time.Time) (uint, error) {
+	tail, args, err := reform.Where(reform.Lt("sent_at", before)).Build(d.db.Dialect)
+	if err != nil {
+		return 0, err
+	}
+	return d.db.DeleteFrom(MessageTable, tail, args...)
+}
@@ -0,0 +1,89 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func TestEnqueue(t *testing.T) {
+	f := reformtest.New(postgresql.Dialect)
+	d := New(f.DB, nil)
+
+	var msg *Message
+	require.NoError(t, f.DB.InTransaction(func(tx *reform.TX) error {
+		var err error
+		msg, err = d.Enqueue(tx, "people.created", map[string]string{"name": "Alice"})
+		return err
+	}))
+	assert.Equal(t, "people.created", msg.Topic)
+	assert.Equal(t, `{"name":"Alice"}`, string(msg.Payload))
+	assert.False(t, msg.CreatedAt.IsZero())
+
+	select {
+	case <-d.notify:
+	default:
+		t.Error("Notify is not called")
+	}
+
+	commands := f.Commands(MessageTable)
+	require.Len(t, commands, 1)
+	assert.Equal(t, "INSERT", commands[0].Operation)
+	assert.Equal(t, "people.created", commands[0].Args[0])
+
+	_, err := Enqueue(nil, "bad", func() {})
+	assert.Error(t, err)
+}
+
+func TestDispatchOnce(t *testing.T) {
+	f := reformtest.New(postgresql.Dialect)
+	require.NoError(t, f.Stub(MessageTable,
+		&Message{ID: 1, Topic: "a", Payload: []byte("1")},
+		&Message{ID: 2, Topic: "b", Payload: []byte("2")},
+	))
+
+	errBoom := errors.New("boom")
+	var published []string
+	d := New(f.DB, PublisherFunc(func(ctx context.Context, msg *Message) error {
+		published = append(published, msg.Topic)
+		if msg.Topic == "b" {
+			return errBoom
+		}
+		return nil
+	}))
+	d.MaxAttempts = 3
+
+	n, err := d.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"a", "b"}, published)
+
+	statements := f.Statements()
+	require.Len(t, statements, 5)
+	assert.Equal(t, `SELECT "outbox_messages"."id", "outbox_messages"."topic", "outbox_messages"."key", `+
+		`"outbox_messages"."payload", "outbox_messages"."created_at", "outbox_messages"."attempts", `+
+		`"outbox_messages"."last_error", "outbox_messages"."sent_at" FROM "outbox_messages" `+
+		`WHERE "sent_at" IS NULL AND "attempts" < $1 ORDER BY "id" LIMIT 100 FOR UPDATE SKIP LOCKED`, statements[1].Query)
+	assert.Equal(t, []interface{}{int32(3)}, statements[1].Args)
+
+	commands := f.Commands(MessageTable)
+	require.Len(t, commands, 2)
+	assert.Equal(t, "UPDATE", commands[0].Operation)
+	assert.Equal(t, int32(1), commands[0].Args[0])
+	assert.Nil(t, commands[0].Args[1])
+	assert.NotNil(t, commands[0].Args[2])
+	assert.Equal(t, "UPDATE", commands[1].Operation)
+	assert.Equal(t, "boom", *commands[1].Args[1].(*string))
+	assert.Nil(t, commands[1].Args[2])
+
+	f.StubError(MessageTable, errBoom)
+	_, err = d.DispatchOnce(context.Background())
+	assert.Equal(t, errBoom, err)
+}
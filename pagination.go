@@ -0,0 +1,199 @@
+package reform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Cursor describes a page for keyset (cursor) pagination. See Querier.SelectPage.
+type Cursor struct {
+	// After is an opaque token returned by previous SelectPage call, empty string for the first page.
+	// It is valid only for the same view and OrderBy.
+	After string
+
+	// OrderBy contains columns defining order of rows, each with optional " ASC" or " DESC" suffix.
+	// Columns should be NOT NULL, and together they should be unique: for table primary key column
+	// is appended automatically if it is missing.
+	OrderBy []string
+
+	// Where contains optional conditions joined by AND, like in Tail.
+	Where []Condition
+
+	// Limit is a maximum number of rows in page, it should be positive.
+	Limit int
+}
+
+// SelectPage queries view for a page of rows described by cursor and returns a slice of new Structs
+// and a token for the next page (to be used as Cursor.After), or empty string if there are no more rows.
+// If view's Struct implements AfterFinder or AfterFinderContext, it also calls AfterFind().
+//
+// Unlike LIMIT with OFFSET, it uses WHERE condition on values of OrderBy columns of the last row,
+// so query performance doesn't degrade for later pages if there is an index for them.
+func (q *Querier) SelectPage(view View, cursor Cursor) ([]Struct, string, error) {
+	if cursor.Limit <= 0 {
+		return nil, "", errors.New("reform: cursor limit should be positive")
+	}
+
+	columns, desc, indexes, err := pageColumns(view, cursor.OrderBy)
+	if err != nil {
+		return nil, "", err
+	}
+
+	where, args, err := Where(cursor.Where...).Build(q.Dialect)
+	if err != nil {
+		return nil, "", err
+	}
+	var conditions []string
+	if where != "" {
+		conditions = append(conditions, strings.TrimPrefix(where, "WHERE "))
+	}
+
+	if cursor.After != "" {
+		values, err := decodeCursor(view, indexes, cursor.After)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// c1 > v1 OR (c1 = v1 AND c2 > v2) OR ...; expanded form works for any dialect and mixed directions
+		or := make([]string, len(columns))
+		for i := range columns {
+			and := make([]string, i+1)
+			for j := 0; j <= i; j++ {
+				op := "="
+				if j == i {
+					op = ">"
+					if desc[j] {
+						op = "<"
+					}
+				}
+				args = append(args, values[j])
+				and[j] = q.QuoteIdentifier(columns[j]) + " " + op + " " + q.Placeholder(len(args))
+			}
+			or[i] = "(" + strings.Join(and, " AND ") + ")"
+		}
+		conditions = append(conditions, "("+strings.Join(or, " OR ")+")")
+	}
+
+	order := make([]string, len(columns))
+	for i, c := range columns {
+		order[i] = q.QuoteIdentifier(c)
+		if desc[i] {
+			order[i] += " DESC"
+		}
+	}
+
+	var tail string
+	if len(conditions) > 0 {
+		tail = "WHERE " + strings.Join(conditions, " AND ") + " "
+	}
+	tail += "ORDER BY " + strings.Join(order, ", ") + " " + limitClause(q.Dialect, cursor.Limit+1, true)
+
+	structs, err := q.SelectAllFrom(view, tail, args...)
+	if err != nil || len(structs) <= cursor.Limit {
+		return structs, "", err
+	}
+
+	structs = structs[:cursor.Limit]
+	next, err := encodeCursor(structs[len(structs)-1], indexes)
+	if err != nil {
+		return nil, "", err
+	}
+	return structs, next, nil
+}
+
+// pageColumns returns column names, directions and column indexes for given OrderBy items,
+// with primary key column appended for table if it is missing.
+func pageColumns(view View, orderBy []string) (columns []string, desc []bool, indexes []int, err error) {
+	if len(orderBy) == 0 {
+		if _, ok := view.(Table); !ok {
+			return nil, nil, nil, errors.New("reform: cursor should have OrderBy for view")
+		}
+	}
+
+	all := view.Columns()
+	index := func(column string) int {
+		for i, c := range all {
+			if c == column {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, item := range orderBy {
+		column, dir := splitOrder(item)
+		i := index(column)
+		if i < 0 {
+			return nil, nil, nil, fmt.Errorf("reform: %s has no column %s", view.Name(), column)
+		}
+		columns = append(columns, column)
+		desc = append(desc, dir == " DESC")
+		indexes = append(indexes, i)
+	}
+
+	if table, ok := view.(Table); ok {
+		for _, pk := range pkColumnIndexes(table) {
+			if index := int(pk); !containsInt(indexes, index) {
+				columns = append(columns, all[index])
+				desc = append(desc, false)
+				indexes = append(indexes, index)
+			}
+		}
+	}
+	return
+}
+
+// containsInt returns true if s contains i.
+func containsInt(s []int, i int) bool {
+	for _, e := range s {
+		if e == i {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeCursor returns token with values of str's columns with given indexes.
+func encodeCursor(str Struct, indexes []int) (string, error) {
+	values := str.Values()
+	res := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		res[i] = values[index]
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor returns values of columns with given indexes from token.
+// Values are decoded to fields of view's new Struct, so they have the same types.
+func decodeCursor(view View, indexes []int, token string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var raw []json.RawMessage
+	if err = json.Unmarshal(b, &raw); err != nil || len(raw) != len(indexes) {
+		return nil, ErrInvalidCursor
+	}
+
+	str := view.NewStruct()
+	pointers := str.Pointers()
+	for i, index := range indexes {
+		if err = json.Unmarshal(raw[i], pointers[index]); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	values := str.Values()
+	res := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		res[i] = values[index]
+	}
+	return res, nil
+}
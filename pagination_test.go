@@ -0,0 +1,65 @@
+package reform_test
+
+import (
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestSelectPage() {
+	cursor := reform.Cursor{Limit: 2}
+	var ids []int32
+	var pages int
+	for {
+		structs, next, err := s.q.SelectPage(PersonTable, cursor)
+		s.Require().NoError(err)
+		pages++
+		for _, str := range structs {
+			ids = append(ids, str.(*Person).ID)
+		}
+		if next == "" {
+			break
+		}
+		cursor.After = next
+	}
+	s.Equal(3, pages)
+	s.Equal([]int32{1, 2, 101, 102, 103}, ids)
+
+	// mixed directions and conditions, primary key is appended
+	cursor = reform.Cursor{
+		OrderBy: []string{"name DESC", "created_at"},
+		Where:   []reform.Condition{reform.Ne("name", "Denis Mills")},
+		Limit:   2,
+	}
+	structs, next, err := s.q.SelectPage(PersonTable, cursor)
+	s.NoError(err)
+	s.Require().Len(structs, 2)
+	s.Equal("Noble Schumm", structs[0].(*Person).Name)
+	s.Equal("Garrick Muller", structs[1].(*Person).Name)
+	s.NotEmpty(next)
+
+	cursor.After = next
+	structs, next, err = s.q.SelectPage(PersonTable, cursor)
+	s.NoError(err)
+	s.Require().Len(structs, 2)
+	s.Equal(int32(102), structs[0].(*Person).ID)
+	s.Equal(int32(103), structs[1].(*Person).ID)
+	s.Empty(next)
+
+	// composite primary key
+	structs, next, err = s.q.SelectPage(ProjectRoleTable, reform.Cursor{Limit: 1})
+	s.NoError(err)
+	s.Require().Len(structs, 1)
+	s.Equal(int32(102), structs[0].(*ProjectRole).PersonID)
+	structs, next, err = s.q.SelectPage(ProjectRoleTable, reform.Cursor{After: next, Limit: 1})
+	s.NoError(err)
+	s.Require().Len(structs, 1)
+	s.Equal(int32(103), structs[0].(*ProjectRole).PersonID)
+	s.Empty(next)
+
+	_, _, err = s.q.SelectPage(PersonTable, reform.Cursor{After: "invalid", Limit: 1})
+	s.Equal(reform.ErrInvalidCursor, err)
+	_, _, err = s.q.SelectPage(PersonTable, reform.Cursor{OrderBy: []string{"bogus"}, Limit: 1})
+	s.EqualError(err, "reform: people has no column bogus")
+	_, _, err = s.q.SelectPage(PersonTable, reform.Cursor{})
+	s.EqualError(err, "reform: cursor limit should be positive")
+}
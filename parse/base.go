@@ -9,17 +9,117 @@ import (
 
 // FieldInfo represents information about struct field.
 type FieldInfo struct {
-	Name   string // field name as defined in source file, e.g. Name
-	Type   string // field type as defined in source file, e.g. string
-	Column string // SQL database column name from "reform:" struct field tag, e.g. name
+	Name       string // field name as defined in source file, e.g. Name
+	Type       string // field type as defined in source file, e.g. string
+	Column     string // SQL database column name from "reform:" struct field tag, e.g. name
+	Encrypted  bool   // true if field has "encrypted" label in "reform:" tag
+	Sensitive  bool   // true if field has "sensitive" label in "reform:" tag
+	Lock       bool   // true if field has "lock" label in "reform:" tag (optimistic locking version)
+	SoftDelete bool   // true if field has "softdelete" label in "reform:" tag (soft delete timestamp)
+	Index      bool   // true if field has "index" label in "reform:" tag (FindBy helpers are generated)
+	AutoCreate bool   // true if field has "autocreate" label in "reform:" tag (set by Insert)
+	AutoUpdate bool   // true if field has "autoupdate" label in "reform:" tag (set by Insert and Update)
+	Precision  string // "ms" or "us" label in "reform:" tag for autocreate/autoupdate field, empty for seconds
+	Generated  bool   // true if field has "generated" label in "reform:" tag (client-generated primary key)
+	JSON       bool   // true if field has "json" label in "reform:" tag (value is stored as JSON, see reform.JSON)
+	Array      bool   // true if field has "array" label in "reform:" tag (PostgreSQL array, see reform.Array)
+}
+
+// GoString returns a Go-syntax representation of FieldInfo without zero-value labels.
+// It is used by reform generator to keep generated files readable.
+func (f FieldInfo) GoString() string {
+	res := fmt.Sprintf("parse.FieldInfo{Name: %q, Type: %q, Column: %q", f.Name, f.Type, f.Column)
+	if f.Encrypted {
+		res += ", Encrypted: true"
+	}
+	if f.Sensitive {
+		res += ", Sensitive: true"
+	}
+	if f.Lock {
+		res += ", Lock: true"
+	}
+	if f.SoftDelete {
+		res += ", SoftDelete: true"
+	}
+	if f.Index {
+		res += ", Index: true"
+	}
+	if f.AutoCreate {
+		res += ", AutoCreate: true"
+	}
+	if f.AutoUpdate {
+		res += ", AutoUpdate: true"
+	}
+	if f.Precision != "" {
+		res += fmt.Sprintf(", Precision: %q", f.Precision)
+	}
+	if f.Generated {
+		res += ", Generated: true"
+	}
+	if f.JSON {
+		res += ", JSON: true"
+	}
+	if f.Array {
+		res += ", Array: true"
+	}
+	return res + "}"
+}
+
+// Relation kinds for "reform-rel:" struct field tag.
+const (
+	HasOne    = "has_one"    // field *T is loaded from T's table by foreign key column referencing this primary key
+	HasMany   = "has_many"   // field []*T is loaded from T's table by foreign key column referencing this primary key
+	BelongsTo = "belongs_to" // field *T is loaded from T's table by primary key referenced by this foreign key column
+)
+
+// RelationInfo represents information about struct field with "reform-rel:" tag.
+type RelationInfo struct {
+	Name   string // field name as defined in source file, e.g. Posts
+	Type   string // related struct type, e.g. Post
+	Kind   string // relation kind: HasOne, HasMany or BelongsTo
+	Column string // foreign key column: in related table for HasOne and HasMany, in this table for BelongsTo
+}
+
+// GoString returns a Go-syntax representation of RelationInfo.
+// It is used by reform generator to keep generated files readable.
+func (r RelationInfo) GoString() string {
+	return fmt.Sprintf("parse.RelationInfo{Name: %q, Type: %q, Kind: %q, Column: %q}", r.Name, r.Type, r.Kind, r.Column)
 }
 
 // StructInfo represents information about struct.
 type StructInfo struct {
-	Type         string      // struct type as defined in source file, e.g. User
-	SQLName      string      // SQL database view or table name from magic "reform:" comment, e.g. users
-	Fields       []FieldInfo // fields info
-	PKFieldIndex int         // index of primary key field in Fields, -1 if none
+	Type           string         // struct type as defined in source file, e.g. User
+	SQLName        string         // SQL database view or table name from magic "reform:" comment, e.g. users
+	Fields         []FieldInfo    // fields info
+	PKFieldIndex   int            // index of (first) primary key field in Fields, -1 if none
+	PKFieldIndexes []int          // indexes of primary key fields in Fields for composite primary key, nil otherwise
+	Relations      []RelationInfo // relations info from "reform-rel:" tags, nil if none
+	View           bool           // true if struct is marked as view in magic comment, e.g. "reform:monthly_sales view"
+}
+
+// GoString returns a Go-syntax representation of StructInfo without nil PKFieldIndexes, Relations and false View.
+// It is used by reform generator to keep generated files readable.
+func (s StructInfo) GoString() string {
+	fields := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		fields[i] = f.GoString()
+	}
+	res := fmt.Sprintf("parse.StructInfo{Type: %q, SQLName: %q, Fields: []parse.FieldInfo{%s}, PKFieldIndex: %d",
+		s.Type, s.SQLName, strings.Join(fields, ", "), s.PKFieldIndex)
+	if s.PKFieldIndexes != nil {
+		res += fmt.Sprintf(", PKFieldIndexes: %#v", s.PKFieldIndexes)
+	}
+	if s.Relations != nil {
+		relations := make([]string, len(s.Relations))
+		for i, r := range s.Relations {
+			relations[i] = r.GoString()
+		}
+		res += fmt.Sprintf(", Relations: []parse.RelationInfo{%s}", strings.Join(relations, ", "))
+	}
+	if s.View {
+		res += ", View: true"
+	}
+	return res + "}"
 }
 
 // Columns returns a new slice of column names.
@@ -31,12 +131,136 @@ func (s *StructInfo) Columns() []string {
 	return res
 }
 
+// EncryptedColumns returns a new slice of flags: true for columns with "encrypted" label.
+func (s *StructInfo) EncryptedColumns() []bool {
+	res := make([]bool, len(s.Fields))
+	for i, f := range s.Fields {
+		res[i] = f.Encrypted
+	}
+	return res
+}
+
+// SensitiveColumns returns a new slice of flags: true for columns with "sensitive" label.
+func (s *StructInfo) SensitiveColumns() []bool {
+	res := make([]bool, len(s.Fields))
+	for i, f := range s.Fields {
+		res[i] = f.Sensitive
+	}
+	return res
+}
+
+// HasSensitiveColumns returns true if at least one column has "sensitive" label.
+func (s *StructInfo) HasSensitiveColumns() bool {
+	for _, f := range s.Fields {
+		if f.Sensitive {
+			return true
+		}
+	}
+	return false
+}
+
+// HasEncryptedColumns returns true if at least one column has "encrypted" label.
+func (s *StructInfo) HasEncryptedColumns() bool {
+	for _, f := range s.Fields {
+		if f.Encrypted {
+			return true
+		}
+	}
+	return false
+}
+
+// HasJSONFields returns true if some field has "json" label.
+func (s *StructInfo) HasJSONFields() bool {
+	for _, f := range s.Fields {
+		if f.JSON {
+			return true
+		}
+	}
+	return false
+}
+
+// HasArrayFields returns true if some field has "array" label.
+func (s *StructInfo) HasArrayFields() bool {
+	for _, f := range s.Fields {
+		if f.Array {
+			return true
+		}
+	}
+	return false
+}
+
+// LockFieldIndex returns an index of field with "lock" label in Fields, -1 if none.
+func (s *StructInfo) LockFieldIndex() int {
+	for i, f := range s.Fields {
+		if f.Lock {
+			return i
+		}
+	}
+	return -1
+}
+
+// HasLockField returns true if some field has "lock" label.
+func (s *StructInfo) HasLockField() bool {
+	return s.LockFieldIndex() >= 0
+}
+
+// SoftDeleteFieldIndex returns an index of field with "softdelete" label in Fields, -1 if none.
+func (s *StructInfo) SoftDeleteFieldIndex() int {
+	for i, f := range s.Fields {
+		if f.SoftDelete {
+			return i
+		}
+	}
+	return -1
+}
+
+// HasSoftDeleteField returns true if some field has "softdelete" label.
+func (s *StructInfo) HasSoftDeleteField() bool {
+	return s.SoftDeleteFieldIndex() >= 0
+}
+
+// HasGeneratedPK returns true if primary key field has "generated" label.
+func (s *StructInfo) HasGeneratedPK() bool {
+	return s.PKFieldIndex >= 0 && s.Fields[s.PKFieldIndex].Generated
+}
+
+// HasAutoTimestampFields returns true if some field has "autocreate" or "autoupdate" label.
+func (s *StructInfo) HasAutoTimestampFields() bool {
+	for _, f := range s.Fields {
+		if f.AutoCreate || f.AutoUpdate {
+			return true
+		}
+	}
+	return false
+}
+
+// IndexFields returns fields with "index" label.
+func (s *StructInfo) IndexFields() []FieldInfo {
+	var res []FieldInfo
+	for _, f := range s.Fields {
+		if f.Index {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// ColumnField returns a field for given column, panics if there is no such field.
+func (s *StructInfo) ColumnField(column string) FieldInfo {
+	for _, f := range s.Fields {
+		if f.Column == column {
+			return f
+		}
+	}
+	panic("reform: no field for column " + column)
+}
+
 // IsTable returns true if this object represent information for table, false for view.
 func (s *StructInfo) IsTable() bool {
 	return s.PKFieldIndex >= 0
 }
 
-// PKField returns a primary key field, panics for views.
+// PKField returns a primary key field (the first one for composite primary key), panics for views.
 func (s *StructInfo) PKField() FieldInfo {
 	if !s.IsTable() {
 		panic("reform: not a table")
@@ -44,6 +268,35 @@ func (s *StructInfo) PKField() FieldInfo {
 	return s.Fields[s.PKFieldIndex]
 }
 
+// IsCompositePK returns true if this object represent information for table with composite primary key.
+func (s *StructInfo) IsCompositePK() bool {
+	return len(s.PKFieldIndexes) > 1
+}
+
+// PKFields returns primary key fields, panics for views.
+func (s *StructInfo) PKFields() []FieldInfo {
+	if !s.IsCompositePK() {
+		return []FieldInfo{s.PKField()}
+	}
+	res := make([]FieldInfo, len(s.PKFieldIndexes))
+	for i, pk := range s.PKFieldIndexes {
+		res[i] = s.Fields[pk]
+	}
+	return res
+}
+
+// addPKField records field with given index as (a part of) primary key.
+func (s *StructInfo) addPKField(i int) {
+	if s.PKFieldIndex < 0 {
+		s.PKFieldIndex = i
+		return
+	}
+	if s.PKFieldIndexes == nil {
+		s.PKFieldIndexes = []int{s.PKFieldIndex}
+	}
+	s.PKFieldIndexes = append(s.PKFieldIndexes, i)
+}
+
 // AssertUpToDate checks that given StructInfo matches given object.
 // It is used during program initialization to check that generated files are up-to-date.
 func AssertUpToDate(si *StructInfo, obj interface{}) {
@@ -56,31 +309,126 @@ func AssertUpToDate(si *StructInfo, obj interface{}) {
 	if err != nil {
 		panic(msg + err.Error())
 	}
+	si2.View = si.View // magic comment is not available at runtime
 	if !reflect.DeepEqual(si, si2) {
 		panic(msg)
 	}
 }
 
+// fieldTag represents parsed "reform:" struct field tag.
+type fieldTag struct {
+	column     string
+	pk         bool
+	encrypted  bool
+	sensitive  bool
+	lock       bool
+	softDelete bool
+	index      bool
+	autoCreate bool
+	autoUpdate bool
+	precision  string
+	generated  bool
+	json       bool
+	array      bool
+}
+
 // parseStructFieldTag is used by both file and runtime parsers
-func parseStructFieldTag(tag string) (sqlName string, isPK bool) {
+func parseStructFieldTag(tag string) (res fieldTag) {
 	parts := strings.Split(tag, ",")
-	if len(parts) == 0 || len(parts) > 2 {
+	if len(parts) == 0 {
 		return
 	}
 
-	if len(parts) == 2 {
-		switch parts[1] {
+	for _, label := range parts[1:] {
+		switch label {
 		case "pk":
-			isPK = true
+			res.pk = true
+		case "encrypted":
+			res.encrypted = true
+		case "sensitive":
+			res.sensitive = true
+		case "lock":
+			res.lock = true
+		case "softdelete":
+			res.softDelete = true
+		case "index":
+			res.index = true
+		case "autocreate":
+			res.autoCreate = true
+		case "autoupdate":
+			res.autoUpdate = true
+		case "ms", "us":
+			res.precision = label
+		case "generated":
+			res.generated = true
+		case "json":
+			res.json = true
+		case "array":
+			res.array = true
 		default:
-			return
+			return fieldTag{}
 		}
 	}
 
-	sqlName = parts[0]
+	res.column = parts[0]
 	return
 }
 
+// parseRelation parses "reform-rel:" struct field tag for field with given name and type.
+// It is used by both file and runtime parsers; type should not contain package name for types from the same package.
+func parseRelation(structType, name, typ, tag string) (*RelationInfo, error) {
+	parts := strings.Split(tag, ",")
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf(`reform: %s has field %s with invalid "reform-rel:" tag value, it is not allowed`, structType, name)
+	}
+
+	res := &RelationInfo{Name: name, Kind: parts[0], Column: parts[1]}
+	var prefix string
+	switch res.Kind {
+	case HasOne, BelongsTo:
+		prefix = "*"
+	case HasMany:
+		prefix = "[]*"
+	default:
+		return nil, fmt.Errorf(`reform: %s has field %s with invalid "reform-rel:" tag value, it is not allowed`, structType, name)
+	}
+
+	res.Type = strings.TrimPrefix(typ, prefix)
+	if !strings.HasPrefix(typ, prefix) || strings.ContainsAny(res.Type, "*[]") {
+		return nil, fmt.Errorf(`reform: %s has field %s with %q relation of type other than %sT, it is not allowed`, structType, name, res.Kind, prefix)
+	}
+	if strings.Contains(res.Type, ".") {
+		return nil, fmt.Errorf(`reform: %s has field %s with relation to type from other package, it is not supported`, structType, name)
+	}
+	return res, nil
+}
+
+// lockTypes contains allowed types of fields with "lock" label.
+var lockTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+// arrayTypes contains allowed types of fields with "array" label.
+var arrayTypes = map[string]bool{
+	"[]string": true, "[]bool": true, "[]float32": true, "[]float64": true,
+	"[]int": true, "[]int8": true, "[]int16": true, "[]int32": true, "[]int64": true,
+	"[]uint": true, "[]uint16": true, "[]uint32": true, "[]uint64": true,
+}
+
+// isPKField returns true if field with given index is (a part of) primary key.
+func isPKField(res *StructInfo, i int) bool {
+	if res.PKFieldIndexes == nil {
+		return res.PKFieldIndex == i
+	}
+	for _, pk := range res.PKFieldIndexes {
+		if pk == i {
+			return true
+		}
+	}
+	return false
+}
+
 // checkFields is used by both file and runtime parsers
 func checkFields(res *StructInfo) error {
 	if len(res.Fields) == 0 {
@@ -88,11 +436,106 @@ func checkFields(res *StructInfo) error {
 	}
 
 	dupes := make(map[string]string)
-	for _, f := range res.Fields {
+	names := make(map[string]bool)
+	var lock, softDelete string
+	for i, f := range res.Fields {
 		if f2, ok := dupes[f.Column]; ok {
 			return fmt.Errorf(`reform: %s has field %s with "reform:" tag with duplicate column name %s (used by %s), it is not allowed`, res.Type, f.Name, f.Column, f2)
 		}
 		dupes[f.Column] = f.Name
+
+		// possible with embedded structs
+		if names[f.Name] {
+			return fmt.Errorf(`reform: %s has duplicate field %s with "reform:" tag (from embedded struct), it is not allowed`, res.Type, f.Name)
+		}
+		names[f.Name] = true
+
+		if f.Index {
+			if f.Encrypted {
+				return fmt.Errorf(`reform: %s has field %s with both "index" and "encrypted" labels in "reform:" tag, it is not allowed`, res.Type, f.Name)
+			}
+			if strings.Contains(f.Type, ".") {
+				return fmt.Errorf(`reform: %s has field %s of type from other package with "index" label in "reform:" tag, it is not supported`, res.Type, f.Name)
+			}
+		}
+
+		if f.JSON && (isPKField(res, i) || f.Index || f.Encrypted || f.Lock || f.SoftDelete || f.AutoCreate || f.AutoUpdate || f.Array) {
+			return fmt.Errorf(`reform: %s has field %s with "json" label and other label except "sensitive" in "reform:" tag, it is not allowed`, res.Type, f.Name)
+		}
+
+		if f.Array {
+			if isPKField(res, i) || f.Index || f.Encrypted || f.Lock || f.SoftDelete || f.AutoCreate || f.AutoUpdate {
+				return fmt.Errorf(`reform: %s has field %s with "array" label and other label except "sensitive" in "reform:" tag, it is not allowed`, res.Type, f.Name)
+			}
+			if !arrayTypes[f.Type] {
+				return fmt.Errorf(`reform: %s has field %s with "array" label in "reform:" tag of type other than slice of strings, integers, floats or booleans, it is not allowed`, res.Type, f.Name)
+			}
+		}
+
+		if f.Generated {
+			if !isPKField(res, i) || res.IsCompositePK() {
+				return fmt.Errorf(`reform: %s has field %s with "generated" label, but not single-column "pk" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
+			}
+			if f.Type != "string" {
+				return fmt.Errorf(`reform: %s has field %s with "generated" label in "reform:" tag of type other than string, it is not allowed`, res.Type, f.Name)
+			}
+		}
+
+		if f.AutoCreate || f.AutoUpdate {
+			if f.AutoCreate && f.AutoUpdate {
+				return fmt.Errorf(`reform: %s has field %s with both "autocreate" and "autoupdate" labels in "reform:" tag, it is not allowed`, res.Type, f.Name)
+			}
+			if f.Type != "time.Time" && f.Type != "*time.Time" {
+				return fmt.Errorf(`reform: %s has field %s with "autocreate" or "autoupdate" label in "reform:" tag of type other than time.Time or *time.Time, it is not allowed`, res.Type, f.Name)
+			}
+			if f.Encrypted || f.SoftDelete || isPKField(res, i) {
+				return fmt.Errorf(`reform: %s has field %s with "autocreate" or "autoupdate" label and "pk", "encrypted" or "softdelete" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
+			}
+		} else if f.Precision != "" {
+			return fmt.Errorf(`reform: %s has field %s with %q label, but without "autocreate" or "autoupdate" label in "reform:" tag, it is not allowed`, res.Type, f.Name, f.Precision)
+		}
+
+		if f.SoftDelete {
+			if softDelete != "" {
+				return fmt.Errorf(`reform: %s has field %s with duplicate "softdelete" label in "reform:" tag (first used by %s), it is not allowed`, res.Type, f.Name, softDelete)
+			}
+			softDelete = f.Name
+			if f.Type != "*time.Time" {
+				return fmt.Errorf(`reform: %s has field %s with "softdelete" label in "reform:" tag of type other than *time.Time, it is not allowed`, res.Type, f.Name)
+			}
+			if res.PKFieldIndex < 0 {
+				return fmt.Errorf(`reform: %s has field %s with "softdelete" label in "reform:" tag, but no primary key, it is not allowed`, res.Type, f.Name)
+			}
+		}
+
+		if !f.Lock {
+			continue
+		}
+		if lock != "" {
+			return fmt.Errorf(`reform: %s has field %s with duplicate "lock" label in "reform:" tag (first used by %s), it is not allowed`, res.Type, f.Name, lock)
+		}
+		lock = f.Name
+		if res.PKFieldIndex < 0 {
+			return fmt.Errorf(`reform: %s has field %s with "lock" label in "reform:" tag, but no primary key, it is not allowed`, res.Type, f.Name)
+		}
+		if !lockTypes[f.Type] {
+			return fmt.Errorf(`reform: %s has non-integer field %s with "lock" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
+		}
+		if f.Encrypted || isPKField(res, i) {
+			return fmt.Errorf(`reform: %s has field %s with "lock" label and "pk" or "encrypted" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
+		}
+	}
+
+	for _, r := range res.Relations {
+		if r.Kind == BelongsTo {
+			if _, ok := dupes[r.Column]; !ok {
+				return fmt.Errorf(`reform: %s has field %s with "belongs_to" relation by unknown column %s, it is not allowed`, res.Type, r.Name, r.Column)
+			}
+			continue
+		}
+		if res.PKFieldIndex < 0 || res.IsCompositePK() {
+			return fmt.Errorf(`reform: %s has field %s with %q relation, but no single-column primary key, it is not allowed`, res.Type, r.Name, r.Kind)
+		}
 	}
 
 	return nil
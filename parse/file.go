@@ -3,52 +3,222 @@ package parse
 import (
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
-var magicReformComment = regexp.MustCompile(`reform:(\w+)`)
+// magicReformComment matches magic comment with SQL name and optional "view" marker,
+// e.g. "reform:people" or "reform:monthly_sales view".
+var magicReformComment = regexp.MustCompile(`reform:(\w+)(?:[ \t]+(view)\b)?`)
 
 func goType(x ast.Expr) string {
+	return qualifiedGoType(x, "")
+}
+
+// qualifiedGoType returns type like goType, qualifying types declared in package with given name
+// (if it is not empty), like "pkg.ID".
+func qualifiedGoType(x ast.Expr, pack string) string {
 	switch t := x.(type) {
 	case *ast.Ident:
+		if pack != "" && types.Universe.Lookup(t.Name) == nil {
+			return pack + "." + t.Name
+		}
 		return t.String()
 	case *ast.SelectorExpr:
 		return goType(t.X) + "." + t.Sel.String()
 	case *ast.StarExpr:
-		return "*" + goType(t.X)
-	default:
-		panic(fmt.Errorf("reform: goType: unhandled %#v. Please report this bug.", x))
+		return "*" + qualifiedGoType(t.X, pack)
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "[]" + qualifiedGoType(t.Elt, pack)
+		}
+		if l, ok := t.Len.(*ast.BasicLit); ok {
+			return "[" + l.Value + "]" + qualifiedGoType(t.Elt, pack)
+		}
+	case *ast.MapType:
+		return "map[" + qualifiedGoType(t.Key, pack) + "]" + qualifiedGoType(t.Value, pack)
+	case *ast.InterfaceType:
+		// the same representation as reflect's
+		if len(t.Methods.List) == 0 {
+			return "interface {}"
+		}
+	}
+
+	panic(fmt.Errorf("reform: goType: unhandled %#v. Please report this bug.", x))
+}
+
+// packageStructs finds struct types declared in the package of parsed file or in the package of embedded struct.
+type packageStructs struct {
+	dir       string
+	pack      string
+	qualifier string // package name for types of other package, empty for the package of parsed file
+	structs   map[string]*ast.StructType
+	files     map[string]*ast.File       // files with struct types declarations
+	all       bool                       // true if all package files were parsed
+	parsing   map[string]bool            // embedded struct types being parsed, to ignore recursive embedding
+	imported  map[string]*packageStructs // other packages by directory, shared by all packageStructs
+}
+
+// newPackageStructs returns packageStructs with struct types declared in given parsed file.
+func newPackageStructs(path string, fileNode *ast.File) *packageStructs {
+	ps := &packageStructs{
+		dir:      filepath.Dir(path),
+		pack:     fileNode.Name.Name,
+		structs:  make(map[string]*ast.StructType),
+		files:    make(map[string]*ast.File),
+		parsing:  make(map[string]bool),
+		imported: make(map[string]*packageStructs),
+	}
+	ps.add(fileNode)
+	return ps
+}
+
+func (ps *packageStructs) add(fileNode *ast.File) {
+	for _, decl := range fileNode.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				if str, ok := ts.Type.(*ast.StructType); ok && ps.structs[ts.Name.Name] == nil {
+					ps.structs[ts.Name.Name] = str
+					ps.files[ts.Name.Name] = fileNode
+				}
+			}
+		}
+	}
+}
+
+// get returns struct type with given name and file with its declaration, parsing other package files on the first miss.
+func (ps *packageStructs) get(name string) (*ast.StructType, *ast.File) {
+	if str := ps.structs[name]; str != nil || ps.all {
+		return str, ps.files[name]
+	}
+
+	ps.all = true
+	filter := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	packs, _ := parser.ParseDir(token.NewFileSet(), ps.dir, filter, 0) // ignore errors in unrelated files
+	if pack := packs[ps.pack]; pack != nil {
+		for _, fileNode := range pack.Files {
+			ps.add(fileNode)
+		}
+	}
+	return ps.structs[name], ps.files[name]
+}
+
+// importPackage returns packageStructs for package imported by given file with given name.
+func (ps *packageStructs) importPackage(fileNode *ast.File, name string) (*packageStructs, error) {
+	for _, imp := range fileNode.Imports {
+		if imp.Name != nil && imp.Name.Name != name {
+			continue
+		}
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		bp, err := build.Import(path, ps.dir, 0)
+		if err != nil {
+			return nil, err
+		}
+		if imp.Name == nil && bp.Name != name {
+			continue
+		}
+
+		if other := ps.imported[bp.Dir]; other != nil {
+			return other, nil
+		}
+		other := &packageStructs{
+			dir:       bp.Dir,
+			pack:      bp.Name,
+			qualifier: bp.Name,
+			structs:   make(map[string]*ast.StructType),
+			files:     make(map[string]*ast.File),
+			parsing:   make(map[string]bool),
+			imported:  ps.imported,
+		}
+		ps.imported[bp.Dir] = other
+		return other, nil
 	}
+	return nil, fmt.Errorf("package %s is not imported", name)
 }
 
-func parseStructTypeSpec(ts *ast.TypeSpec, str *ast.StructType) (*StructInfo, error) {
+func parseStructTypeSpec(ts *ast.TypeSpec, str *ast.StructType, file *ast.File, ps *packageStructs) (*StructInfo, error) {
 	res := &StructInfo{
 		Type:         ts.Name.Name,
 		PKFieldIndex: -1,
 	}
 
-	var n int
+	if err := parseStructFields(res, str, file, ps); err != nil {
+		return nil, err
+	}
+
+	if len(res.Fields) == 0 {
+		return nil, fmt.Errorf(`reform: %s has no fields with "reform:" tag, it is not allowed`, res.Type)
+	}
+
+	err := checkFields(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// parseStructFields adds fields of str declared in file to res, flattening embedded structs.
+func parseStructFields(res *StructInfo, str *ast.StructType, file *ast.File, ps *packageStructs) error {
 	for _, f := range str.Fields.List {
+		var tag string
+		if f.Tag != nil && len(f.Tag.Value) >= 3 {
+			tag = f.Tag.Value[1 : len(f.Tag.Value)-1] // strip quotes
+		}
+		st := reflect.StructTag(tag)
+
+		// flatten embedded struct without tags
+		if len(f.Names) == 0 && st.Get("reform") == "" && st.Get("reform-rel") == "" {
+			if err := parseEmbeddedStruct(res, f.Type, file, ps); err != nil {
+				return err
+			}
+			continue
+		}
+
 		// consider only fields with "reform:" tag
-		if f.Tag == nil {
+		if tag == "" {
 			continue
 		}
-		tag := f.Tag.Value
-		if len(tag) < 3 {
+		tag = st.Get("reform")
+		if relTag := st.Get("reform-rel"); relTag != "" {
+			if tag != "" || len(f.Names) != 1 || !f.Names[0].IsExported() {
+				name := qualifiedGoType(f.Type, ps.qualifier)
+				if len(f.Names) > 0 {
+					name = f.Names[0].Name
+				}
+				return fmt.Errorf(`reform: %s has invalid field %s with "reform-rel:" tag, it is not allowed`, res.Type, name)
+			}
+			rel, err := parseRelation(res.Type, f.Names[0].Name, qualifiedGoType(f.Type, ps.qualifier), relTag)
+			if err != nil {
+				return err
+			}
+			res.Relations = append(res.Relations, *rel)
 			continue
 		}
-		tag = reflect.StructTag(tag[1 : len(tag)-1]).Get("reform") // strip quotes
 		if len(tag) == 0 {
 			continue
 		}
 
 		// check for anonymous fields
 		if len(f.Names) == 0 {
-			return nil, fmt.Errorf(`reform: %s has anonymous field %s with "reform:" tag, it is not allowed`, res.Type, f.Type)
+			return fmt.Errorf(`reform: %s has anonymous field %s with "reform:" tag, it is not allowed`, res.Type, f.Type)
 		}
 		if len(f.Names) != 1 {
 			panic(fmt.Errorf("reform: %d names: %#v. Please report this bug.", len(f.Names), f.Names))
@@ -57,47 +227,92 @@ func parseStructTypeSpec(ts *ast.TypeSpec, str *ast.StructType) (*StructInfo, er
 		// check for exported name
 		name := f.Names[0]
 		if !name.IsExported() {
-			return nil, fmt.Errorf(`reform: %s has non-exported field %s with "reform:" tag, it is not allowed`, res.Type, name.Name)
+			return fmt.Errorf(`reform: %s has non-exported field %s with "reform:" tag, it is not allowed`, res.Type, name.Name)
 		}
 
 		// parse tag and type
-		column, isPK := parseStructFieldTag(tag)
+		ft := parseStructFieldTag(tag)
+		column, isPK := ft.column, ft.pk
 		if column == "" {
-			return nil, fmt.Errorf(`reform: %s has field %s with invalid "reform:" tag value, it is not allowed`, res.Type, name.Name)
+			return fmt.Errorf(`reform: %s has field %s with invalid "reform:" tag value, it is not allowed`, res.Type, name.Name)
 		}
-		typ := goType(f.Type)
+		typ := qualifiedGoType(f.Type, ps.qualifier)
 		if isPK && strings.HasPrefix(typ, "*") {
-			return nil, fmt.Errorf(`reform: %s has pointer field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, name.Name)
+			return fmt.Errorf(`reform: %s has pointer field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, name.Name)
 		}
-		if isPK && res.PKFieldIndex >= 0 {
-			return nil, fmt.Errorf(`reform: %s has field %s with with duplicate "pk" label in "reform:" tag (first used by %s), it is not allowed`, res.Type, name.Name, res.Fields[res.PKFieldIndex].Name)
+		if isPK && ft.encrypted {
+			return fmt.Errorf(`reform: %s has field %s with both "pk" and "encrypted" labels in "reform:" tag, it is not allowed`, res.Type, name.Name)
 		}
 		// if isPKOrOmitEmpty && strings.HasPrefix(typ, "*") {
-		// 	return nil, fmt.Errorf(`reform: %s has pointer field %s with with "omitempty" label in "reform:" tag, it is not allowed`, res.Type, name.Name)
+		// 	return fmt.Errorf(`reform: %s has pointer field %s with with "omitempty" label in "reform:" tag, it is not allowed`, res.Type, name.Name)
 		// }
 
 		res.Fields = append(res.Fields, FieldInfo{
-			Name:   name.Name,
-			Type:   typ,
-			Column: column,
+			Name:       name.Name,
+			Type:       typ,
+			Column:     column,
+			Encrypted:  ft.encrypted,
+			Sensitive:  ft.sensitive,
+			Lock:       ft.lock,
+			SoftDelete: ft.softDelete,
+			Index:      ft.index,
+			AutoCreate: ft.autoCreate,
+			AutoUpdate: ft.autoUpdate,
+			Precision:  ft.precision,
+			Generated:  ft.generated,
+			JSON:       ft.json,
+			Array:      ft.array,
 			// PKOrOmitEmpty: isPKOrOmitEmpty,
 		})
 		if isPK {
-			res.PKFieldIndex = n
+			res.addPKField(len(res.Fields) - 1)
 		}
-		n++
 	}
 
-	if len(res.Fields) == 0 {
-		return nil, fmt.Errorf(`reform: %s has no fields with "reform:" tag, it is not allowed`, res.Type)
+	return nil
+}
+
+// parseEmbeddedStruct adds fields of embedded struct with given type, used in given file, to res.
+// Types from other packages are found with go/build. Non-struct types are ignored.
+func parseEmbeddedStruct(res *StructInfo, typ ast.Expr, file *ast.File, ps *packageStructs) error {
+	star, isPtr := typ.(*ast.StarExpr)
+	if isPtr {
+		typ = star.X
 	}
 
-	err := checkFields(res)
-	if err != nil {
-		return nil, err
+	var name string
+	switch t := typ.(type) {
+	case *ast.Ident:
+		name = t.Name
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		other, err := ps.importPackage(file, x.Name)
+		if err != nil {
+			return fmt.Errorf(`reform: %s has embedded field %s: %s`, res.Type, goType(typ), err)
+		}
+		ps, name = other, t.Sel.Name
+	default:
+		return nil
 	}
 
-	return res, nil
+	str, strFile := ps.get(name)
+	if str == nil || ps.parsing[name] {
+		return nil
+	}
+	ps.parsing[name] = true
+	defer delete(ps.parsing, name)
+
+	if isPtr {
+		tmp := &StructInfo{Type: res.Type, PKFieldIndex: -1}
+		if err := parseStructFields(tmp, str, strFile, ps); err != nil || len(tmp.Fields) > 0 {
+			return fmt.Errorf(`reform: %s has embedded pointer field *%s with "reform:" tags, it is not allowed`, res.Type, goType(typ))
+		}
+		return nil
+	}
+	return parseStructFields(res, str, strFile, ps)
 }
 
 // File parses given file and returns found structs information.
@@ -110,6 +325,7 @@ func File(path string) ([]StructInfo, error) {
 	}
 
 	// consider only top-level struct type declarations with magic comment
+	ps := newPackageStructs(path, fileNode)
 	var res []StructInfo
 	for _, decl := range fileNode.Decls {
 		// ast.Print(fset, decl)
@@ -137,7 +353,12 @@ func File(path string) ([]StructInfo, error) {
 			}
 
 			// ast.Print(fset, doc)
-			sm := magicReformComment.FindStringSubmatch(doc.Text())
+			// use raw comments: doc.Text() drops directive-like lines such as "//reform:people"
+			var text string
+			for _, c := range doc.List {
+				text += c.Text + "\n"
+			}
+			sm := magicReformComment.FindStringSubmatch(text)
 			if len(sm) < 2 {
 				continue
 			}
@@ -152,11 +373,17 @@ func File(path string) ([]StructInfo, error) {
 			}
 
 			// ast.Print(fset, ts)
-			s, err := parseStructTypeSpec(ts, str)
+			s, err := parseStructTypeSpec(ts, str, fileNode, ps)
 			if err != nil {
 				return nil, err
 			}
 			s.SQLName = table
+			if sm[2] != "" {
+				if s.PKFieldIndex >= 0 {
+					return nil, fmt.Errorf(`reform: %s is marked as view in magic "reform:" comment, but has field with "pk" label, it is not allowed`, s.Type)
+				}
+				s.View = true
+			}
 			res = append(res, *s)
 		}
 	}
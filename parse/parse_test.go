@@ -18,12 +18,15 @@ var (
 		SQLName: "people",
 		Fields: []FieldInfo{
 			{Name: "ID", Type: "int32", Column: "id"},
-			{Name: "Name", Type: "string", Column: "name"},
-			{Name: "Email", Type: "*string", Column: "email"},
+			{Name: "Name", Type: "string", Column: "name", Index: true},
+			{Name: "Email", Type: "*string", Column: "email", Index: true},
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
 			{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at"},
 		},
 		PKFieldIndex: 0,
+		Relations: []RelationInfo{
+			{Name: "Roles", Type: "ProjectRole", Kind: HasMany, Column: "person_id"},
+		},
 	}
 
 	project = StructInfo{
@@ -31,13 +34,133 @@ var (
 		SQLName: "projects",
 		Fields: []FieldInfo{
 			{Name: "Name", Type: "string", Column: "name"},
-			{Name: "ID", Type: "string", Column: "id"},
+			{Name: "ID", Type: "string", Column: "id", Generated: true},
 			{Name: "Start", Type: "time.Time", Column: "start"},
 			{Name: "End", Type: "*time.Time", Column: "end"},
 		},
 		PKFieldIndex: 1,
 	}
 
+	secret = StructInfo{
+		Type:    "Secret",
+		SQLName: "secrets",
+		Fields: []FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "Data", Type: "string", Column: "data", Encrypted: true, Sensitive: true},
+			{Name: "Note", Type: "*[]byte", Column: "note", Encrypted: true},
+		},
+		PKFieldIndex: 0,
+	}
+
+	projectRole = StructInfo{
+		Type:    "ProjectRole",
+		SQLName: "project_roles",
+		Fields: []FieldInfo{
+			{Name: "ProjectID", Type: "string", Column: "project_id"},
+			{Name: "PersonID", Type: "int32", Column: "person_id"},
+			{Name: "Role", Type: "string", Column: "role"},
+			{Name: "Version", Type: "int32", Column: "version", Lock: true},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0, 1},
+		Relations: []RelationInfo{
+			{Name: "Project", Type: "Project", Kind: BelongsTo, Column: "project_id"},
+		},
+	}
+
+	memo = StructInfo{
+		Type:    "Memo",
+		SQLName: "memos",
+		Fields: []FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Text", Type: "string", Column: "text"},
+			{Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SoftDelete: true},
+			{Name: "CreatedAt", Type: "time.Time", Column: "created_at", AutoCreate: true},
+			{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at", AutoUpdate: true},
+		},
+		PKFieldIndex: 0,
+	}
+
+	event = StructInfo{
+		Type:    "Event",
+		SQLName: "events",
+		Fields: []FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Payload", Type: "map[string]interface {}", Column: "payload", JSON: true},
+			{Name: "Meta", Type: "*EventMeta", Column: "meta", JSON: true},
+		},
+		PKFieldIndex: 0,
+	}
+
+	article = StructInfo{
+		Type:    "Article",
+		SQLName: "articles",
+		Fields: []FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Tags", Type: "[]string", Column: "tags", Array: true},
+			{Name: "Scores", Type: "[]int64", Column: "scores", Array: true},
+		},
+		PKFieldIndex: 0,
+	}
+
+	personProjectCount = StructInfo{
+		Type:    "PersonProjectCount",
+		SQLName: "person_project_counts",
+		Fields: []FieldInfo{
+			{Name: "PersonID", Type: "int32", Column: "person_id"},
+			{Name: "Projects", Type: "int64", Column: "projects"},
+		},
+		PKFieldIndex: -1,
+		View:         true,
+	}
+
+	personContact = StructInfo{
+		Type:    "PersonContact",
+		SQLName: "people",
+		Fields: []FieldInfo{
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "Email", Type: "*string", Column: "email"},
+		},
+		PKFieldIndex: -1,
+		View:         true,
+	}
+
+	vehicle = StructInfo{
+		Type:    "Vehicle",
+		SQLName: "vehicles",
+		Fields: []FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Kind", Type: "string", Column: "kind"},
+			{Name: "Name", Type: "string", Column: "name"},
+		},
+		PKFieldIndex: 0,
+	}
+
+	car = StructInfo{
+		Type:    "Car",
+		SQLName: "vehicles",
+		Fields: []FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Kind", Type: "string", Column: "kind"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "Doors", Type: "int32", Column: "doors"},
+		},
+		PKFieldIndex: 0,
+	}
+
+	truck = StructInfo{
+		Type:    "Truck",
+		SQLName: "vehicles",
+		Fields: []FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Kind", Type: "string", Column: "kind"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "Payload", Type: "int64", Column: "payload"},
+		},
+		PKFieldIndex: 0,
+	}
+
 	personProject = StructInfo{
 		Type:    "PersonProject",
 		SQLName: "person_project",
@@ -58,6 +181,23 @@ func TestFileGood(t *testing.T) {
 	assert.Equal(t, personProject, s[2])
 }
 
+func TestFileExtra(t *testing.T) {
+	s, err := File("../internal/test/models/extra.go")
+	assert.NoError(t, err)
+	require.Len(t, s, 10)
+	assert.Equal(t, secret, s[0])
+	assert.Equal(t, projectRole, s[1])
+	assert.Equal(t, memo, s[2])
+	assert.Equal(t, personContact, s[3])
+	assert.Equal(t, event, s[4])
+	assert.Equal(t, article, s[5])
+	assert.Equal(t, personProjectCount, s[6])
+	assert.Equal(t, vehicle, s[7])
+	assert.Equal(t, car, s[8])
+	assert.Equal(t, truck, s[9])
+	assert.False(t, personProjectCount.IsTable())
+}
+
 func TestFileBogus(t *testing.T) {
 	dir := filepath.FromSlash("../internal/test/models/bogus/")
 	for file, msg := range map[string]error{
@@ -71,7 +211,19 @@ func TestFileBogus(t *testing.T) {
 		// "bogus8.go": errors.New(`reform: Bogus8 has pointer field Bogus with with "omitempty" label in "reform:" tag, it is not allowed`),
 		"bogus8.go":  errors.New(`reform: Bogus8 has field Bogus with invalid "reform:" tag value, it is not allowed`),
 		"bogus9.go":  errors.New(`reform: Bogus9 has field Bogus2 with "reform:" tag with duplicate column name bogus (used by Bogus1), it is not allowed`),
-		"bogus10.go": errors.New(`reform: Bogus10 has field Bogus2 with with duplicate "pk" label in "reform:" tag (first used by Bogus1), it is not allowed`),
+		"bogus10.go": errors.New(`reform: Bogus10 has field Bogus with both "pk" and "encrypted" labels in "reform:" tag, it is not allowed`),
+		"bogus11.go": errors.New(`reform: Bogus11 has non-integer field Bogus with "lock" label in "reform:" tag, it is not allowed`),
+		"bogus12.go": errors.New(`reform: Bogus12 has field Bogus with "softdelete" label in "reform:" tag of type other than *time.Time, it is not allowed`),
+		"bogus13.go": errors.New(`reform: Bogus13 has field Bogus with both "index" and "encrypted" labels in "reform:" tag, it is not allowed`),
+		"bogus14.go": errors.New(`reform: Bogus14 has field Bogus with "has_many" relation of type other than []*T, it is not allowed`),
+		"bogus15.go": errors.New(`reform: Bogus15 has field Bogus with "autocreate" or "autoupdate" label in "reform:" tag of type other than time.Time or *time.Time, it is not allowed`),
+		"bogus16.go": errors.New(`reform: Bogus16 has field Bogus with "generated" label in "reform:" tag of type other than string, it is not allowed`),
+		"bogus17.go": errors.New(`reform: Bogus17 has field Bogus with "json" label and other label except "sensitive" in "reform:" tag, it is not allowed`),
+		"bogus18.go": errors.New(`reform: Bogus18 has field Bogus with "array" label in "reform:" tag of type other than slice of strings, integers, floats or booleans, it is not allowed`),
+		"bogus19.go": errors.New(`reform: Bogus19 has embedded pointer field *BogusEmbedded with "reform:" tags, it is not allowed`),
+		"bogus20.go": errors.New(`reform: Bogus20 has duplicate field Bogus with "reform:" tag (from embedded struct), it is not allowed`),
+		"bogus21.go": errors.New(`reform: Bogus21 is marked as view in magic "reform:" comment, but has field with "pk" label, it is not allowed`),
+		"bogus22.go": errors.New(`reform: Bogus22 has embedded pointer field *contact.Contact with "reform:" tags, it is not allowed`),
 
 		"bogus_ignore.go": nil,
 	} {
@@ -93,6 +245,31 @@ func TestObjectGood(t *testing.T) {
 	s, err = Object(new(models.PersonProject), "person_project")
 	assert.NoError(t, err)
 	assert.Equal(t, &personProject, s)
+
+	s, err = Object(new(models.Secret), "secrets")
+	assert.NoError(t, err)
+	assert.Equal(t, &secret, s)
+
+	s, err = Object(new(models.ProjectRole), "project_roles")
+	assert.NoError(t, err)
+	assert.Equal(t, &projectRole, s)
+
+	s, err = Object(new(models.Memo), "memos")
+	assert.NoError(t, err)
+	assert.Equal(t, &memo, s)
+
+	s, err = Object(new(models.Event), "events")
+	assert.NoError(t, err)
+	assert.Equal(t, &event, s)
+
+	s, err = Object(new(models.Article), "articles")
+	assert.NoError(t, err)
+	assert.Equal(t, &article, s)
+
+	s, err = Object(new(models.PersonContact), "people")
+	assert.NoError(t, err)
+	s.View = true
+	assert.Equal(t, &personContact, s)
 }
 
 func TestObjectBogus(t *testing.T) {
@@ -107,7 +284,18 @@ func TestObjectBogus(t *testing.T) {
 		// new(bogus.Bogus8): errors.New(`reform: Bogus8 has pointer field Bogus with with "omitempty" label in "reform:" tag, it is not allowed`),
 		new(bogus.Bogus8):  errors.New(`reform: Bogus8 has field Bogus with invalid "reform:" tag value, it is not allowed`),
 		new(bogus.Bogus9):  errors.New(`reform: Bogus9 has field Bogus2 with "reform:" tag with duplicate column name bogus (used by Bogus1), it is not allowed`),
-		new(bogus.Bogus10): errors.New(`reform: Bogus10 has field Bogus2 with with duplicate "pk" label in "reform:" tag (first used by Bogus1), it is not allowed`),
+		new(bogus.Bogus10): errors.New(`reform: Bogus10 has field Bogus with both "pk" and "encrypted" labels in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus11): errors.New(`reform: Bogus11 has non-integer field Bogus with "lock" label in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus12): errors.New(`reform: Bogus12 has field Bogus with "softdelete" label in "reform:" tag of type other than *time.Time, it is not allowed`),
+		new(bogus.Bogus13): errors.New(`reform: Bogus13 has field Bogus with both "index" and "encrypted" labels in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus14): errors.New(`reform: Bogus14 has field Bogus with "has_many" relation of type other than []*T, it is not allowed`),
+		new(bogus.Bogus15): errors.New(`reform: Bogus15 has field Bogus with "autocreate" or "autoupdate" label in "reform:" tag of type other than time.Time or *time.Time, it is not allowed`),
+		new(bogus.Bogus16): errors.New(`reform: Bogus16 has field Bogus with "generated" label in "reform:" tag of type other than string, it is not allowed`),
+		new(bogus.Bogus17): errors.New(`reform: Bogus17 has field Bogus with "json" label and other label except "sensitive" in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus18): errors.New(`reform: Bogus18 has field Bogus with "array" label in "reform:" tag of type other than slice of strings, integers, floats or booleans, it is not allowed`),
+		new(bogus.Bogus19): errors.New(`reform: Bogus19 has embedded pointer field *BogusEmbedded with "reform:" tags, it is not allowed`),
+		new(bogus.Bogus20): errors.New(`reform: Bogus20 has duplicate field Bogus with "reform:" tag (from embedded struct), it is not allowed`),
+		new(bogus.Bogus22): errors.New(`reform: Bogus22 has embedded pointer field *contact.Contact with "reform:" tags, it is not allowed`),
 
 		// new(bogus.BogusIgnore): do not test,
 	} {
@@ -124,7 +312,11 @@ func TestHelpers(t *testing.T) {
 
 	assert.Equal(t, []string{"name", "id", "start", "end"}, project.Columns())
 	assert.True(t, project.IsTable())
-	assert.Equal(t, FieldInfo{Name: "ID", Type: "string", Column: "id"}, project.PKField())
+	assert.Equal(t, FieldInfo{Name: "ID", Type: "string", Column: "id", Generated: true}, project.PKField())
+
+	assert.True(t, projectRole.IsCompositePK())
+	assert.Equal(t, []FieldInfo{{Name: "ProjectID", Type: "string", Column: "project_id"}, {Name: "PersonID", Type: "int32", Column: "person_id"}}, projectRole.PKFields())
+	assert.False(t, project.IsCompositePK())
 
 	assert.Equal(t, []string{"person_id", "project_id"}, personProject.Columns())
 	assert.False(t, personProject.IsTable())
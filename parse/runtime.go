@@ -28,61 +28,150 @@ func Object(obj interface{}, table string) (res *StructInfo, err error) {
 		PKFieldIndex: -1,
 	}
 
-	var n int
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
+	if err = objectFields(res, t, t, nil); err != nil {
+		return nil, err
+	}
+
+	err = checkFields(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// objectFields adds fields of struct type st to res, flattening embedded structs.
+// parsing contains embedded struct types being parsed, to ignore recursive embedding.
+func objectFields(res *StructInfo, t, st reflect.Type, parsing []reflect.Type) error {
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
 		tag := f.Tag.Get("reform")
+
+		// flatten embedded struct without tags
+		if f.Anonymous && tag == "" && f.Tag.Get("reform-rel") == "" {
+			if err := objectEmbeddedStruct(res, t, f.Type, parsing); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if relTag := f.Tag.Get("reform-rel"); relTag != "" {
+			if tag != "" || f.Anonymous || f.PkgPath != "" {
+				return fmt.Errorf(`reform: %s has invalid field %s with "reform-rel:" tag, it is not allowed`, res.Type, f.Name)
+			}
+
+			// drop package name from related type if it is defined in this package
+			typ := f.Type.String()
+			elem := f.Type
+			for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Slice {
+				elem = elem.Elem()
+			}
+			if elem.PkgPath() == t.PkgPath() {
+				typ = strings.Replace(typ, elem.String(), elem.Name(), 1)
+			}
+
+			rel, err := parseRelation(res.Type, f.Name, typ, relTag)
+			if err != nil {
+				return err
+			}
+			res.Relations = append(res.Relations, *rel)
+			continue
+		}
 		if len(tag) == 0 {
 			continue
 		}
 
 		// check for anonymous fields
 		if f.Anonymous {
-			return nil, fmt.Errorf(`reform: %s has anonymous field %s with "reform:" tag, it is not allowed`, res.Type, f.Name)
+			return fmt.Errorf(`reform: %s has anonymous field %s with "reform:" tag, it is not allowed`, res.Type, f.Name)
 		}
 
 		// check for exported name
 		if f.PkgPath != "" {
-			return nil, fmt.Errorf(`reform: %s has non-exported field %s with "reform:" tag, it is not allowed`, res.Type, f.Name)
+			return fmt.Errorf(`reform: %s has non-exported field %s with "reform:" tag, it is not allowed`, res.Type, f.Name)
 		}
 
 		// parse tag and type
-		column, isPK := parseStructFieldTag(tag)
+		ft := parseStructFieldTag(tag)
+		column, isPK := ft.column, ft.pk
 		if column == "" {
-			return nil, fmt.Errorf(`reform: %s has field %s with invalid "reform:" tag value, it is not allowed`, res.Type, f.Name)
+			return fmt.Errorf(`reform: %s has field %s with invalid "reform:" tag value, it is not allowed`, res.Type, f.Name)
 		}
 		typ := f.Type.String()
 		if isPK && strings.HasPrefix(typ, "*") {
-			return nil, fmt.Errorf(`reform: %s has pointer field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
+			return fmt.Errorf(`reform: %s has pointer field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
 		}
-		if isPK && res.PKFieldIndex >= 0 {
-			return nil, fmt.Errorf(`reform: %s has field %s with with duplicate "pk" label in "reform:" tag (first used by %s), it is not allowed`, res.Type, f.Name, res.Fields[res.PKFieldIndex].Name)
+		if isPK && ft.encrypted {
+			return fmt.Errorf(`reform: %s has field %s with both "pk" and "encrypted" labels in "reform:" tag, it is not allowed`, res.Type, f.Name)
 		}
 		// if isPKOrOmitEmpty && strings.HasPrefix(typ, "*") {
-		// 	return nil, fmt.Errorf(`reform: %s has pointer field %s with with "omitempty" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
+		// 	return fmt.Errorf(`reform: %s has pointer field %s with with "omitempty" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
 		// }
 
-		// drop package name from qualified identifier if type is defined in this package
-		if strings.Contains(typ, ".") && t.PkgPath() == f.Type.PkgPath() {
-			typ = strings.Join(strings.Split(typ, ".")[1:], ".")
+		// reflect uses uint8 for byte
+		typ = strings.Replace(typ, "[]uint8", "[]byte", -1)
+
+		// drop package name from qualified identifier if type (or pointer, slice or map element type) is defined in this package
+		elem := f.Type
+		for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Slice || elem.Kind() == reflect.Map {
+			elem = elem.Elem()
+		}
+		if elem.Name() != "" && elem.PkgPath() == t.PkgPath() {
+			typ = strings.Replace(typ, elem.String(), elem.Name(), 1)
 		}
 
 		res.Fields = append(res.Fields, FieldInfo{
-			Name:   f.Name,
-			Type:   typ,
-			Column: column,
+			Name:       f.Name,
+			Type:       typ,
+			Column:     column,
+			Encrypted:  ft.encrypted,
+			Sensitive:  ft.sensitive,
+			Lock:       ft.lock,
+			SoftDelete: ft.softDelete,
+			Index:      ft.index,
+			AutoCreate: ft.autoCreate,
+			AutoUpdate: ft.autoUpdate,
+			Precision:  ft.precision,
+			Generated:  ft.generated,
+			JSON:       ft.json,
+			Array:      ft.array,
 			// PKOrOmitEmpty: isPKOrOmitEmpty,
 		})
 		if isPK {
-			res.PKFieldIndex = n
+			res.addPKField(len(res.Fields) - 1)
 		}
-		n++
 	}
 
-	err = checkFields(res)
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// objectEmbeddedStruct adds fields of embedded struct with given type to res.
+// Non-struct types are ignored.
+func objectEmbeddedStruct(res *StructInfo, t, et reflect.Type, parsing []reflect.Type) error {
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return nil
+	}
+	for _, p := range parsing {
+		if p == et {
+			return nil
+		}
 	}
+	parsing = append(parsing, et)
 
-	return
+	if isPtr {
+		tmp := &StructInfo{Type: res.Type, PKFieldIndex: -1}
+		if err := objectFields(tmp, t, et, parsing); err != nil || len(tmp.Fields) > 0 {
+			name := et.Name()
+			if et.PkgPath() != t.PkgPath() {
+				name = et.String()
+			}
+			return fmt.Errorf(`reform: %s has embedded pointer field *%s with "reform:" tags, it is not allowed`, res.Type, name)
+		}
+		return nil
+	}
+	return objectFields(res, t, et, parsing)
 }
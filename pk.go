@@ -0,0 +1,120 @@
+package reform
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// pkColumnIndexes returns indexes of primary key columns for given table.
+func pkColumnIndexes(table Table) []uint {
+	if t, ok := table.(CompositePKTable); ok {
+		return t.PKColumnIndexes()
+	}
+	return []uint{table.PKColumnIndex()}
+}
+
+// isPKColumn returns true if column with given index is a (part of) primary key of given table.
+func isPKColumn(table Table, index uint) bool {
+	for _, pk := range pkColumnIndexes(table) {
+		if pk == index {
+			return true
+		}
+	}
+	return false
+}
+
+// setLastInsertID sets record's primary key to id returned by sql.Result.LastInsertId.
+// It does nothing for primary keys of non-integer types (like strings): they can't be auto-incremented,
+// so they should be set by application. Primary key is set with reflection by its kind, not with SetPK,
+// so named integer types declared in other packages work too. Unsigned primary keys are converted as is,
+// so BIGINT UNSIGNED values above math.MaxInt64 (returned by drivers as negative int64) are restored.
+func setLastInsertID(record Record, id int64) {
+	v := reflect.ValueOf(record.PKPointer()).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(id))
+	}
+}
+
+// pkValues returns values of primary key for given record.
+func pkValues(record Record) []interface{} {
+	if r, ok := record.(CompositePKRecord); ok {
+		return r.PKValues()
+	}
+	return []interface{}{record.PKValue()}
+}
+
+// pkArg returns primary key argument for FindByPrimaryKeyTo and FindByPrimaryKeyFrom:
+// []interface{} for records with composite primary key, PKValue() for other records.
+func pkArg(record Record) interface{} {
+	if r, ok := record.(CompositePKRecord); ok {
+		return r.PKValues()
+	}
+	return record.PKValue()
+}
+
+// pkCondition returns condition for WHERE clause matching table's primary key.
+// Placeholders are numbered from start.
+func (q *Querier) pkCondition(table Table, start int) string {
+	indexes := pkColumnIndexes(table)
+	columns := table.Columns()
+	placeholders := q.Placeholders(start, len(indexes))
+	res := make([]string, len(indexes))
+	for i, pk := range indexes {
+		res[i] = q.QuoteIdentifier(columns[pk]) + " = " + placeholders[i]
+	}
+	return strings.Join(res, " AND ")
+}
+
+// findByPK queries table with primary key and scans first result to record.
+// Found record is cached if querier has RecordCache.
+func (q *Querier) findByPK(record Record, table Table, pk interface{}) error {
+	entry, cache := q.cacheKey(table, pk)
+	if cache && q.cacheGet(entry.key, record) {
+		return nil
+	}
+
+	err := q.findByPKUncached(record, table, pk)
+	if err == nil && cache {
+		q.cacheSet(entry, record)
+	}
+	return err
+}
+
+// findByPKUncached is findByPK without RecordCache.
+// For subtype table registered with RegisterSubtypes, only rows with its discriminator value are found.
+func (q *Querier) findByPKUncached(record Record, table Table, pk interface{}) error {
+	t, ok := table.(CompositePKTable)
+	_, sub := lookupSubtype(table)
+	if !ok && !sub {
+		return q.FindOneTo(record, table.Columns()[table.PKColumnIndex()], pk)
+	}
+
+	args := []interface{}{pk}
+	if ok {
+		if args, ok = pk.([]interface{}); !ok || len(args) != len(t.PKColumnIndexes()) {
+			return fmt.Errorf("reform: %s has composite primary key, pk should be []interface{} with %d values", table.Name(), len(t.PKColumnIndexes()))
+		}
+	}
+	cond, args := q.subtypeCondition(table, q.pkCondition(table, 1), args[:len(args):len(args)])
+	return q.SelectOneTo(record, "WHERE "+cond, args...)
+}
+
+// pkInCondition returns condition for WHERE clause matching any of n primary keys of table.
+// Placeholders are numbered from start, primary key values are expected one record after another.
+func (q *Querier) pkInCondition(table Table, n int, start int) string {
+	if _, ok := table.(CompositePKTable); !ok {
+		column := table.Columns()[table.PKColumnIndex()]
+		return q.QuoteIdentifier(column) + " IN (" + strings.Join(q.Placeholders(start, n), ", ") + ")"
+	}
+
+	size := len(pkColumnIndexes(table))
+	res := make([]string, n)
+	for i := range res {
+		res[i] = "(" + q.pkCondition(table, start+i*size) + ")"
+	}
+	return strings.Join(res, " OR ")
+}
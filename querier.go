@@ -1,35 +1,531 @@
 package reform
 
 import (
+	"context"
 	"database/sql"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// dbtx is an interface for *sql.DB and *sql.Tx.
+type dbtx interface {
+	DBTX
+	DBTXContext
+}
+
 // Querier performs queries and commands.
 type Querier struct {
-	dbtx DBTX
+	dbtx    dbtx
+	ctx     context.Context
+	retries int
+	cipher  Cipher
+
+	sensitive map[string][]string // additional sensitive columns by view name
+
+	transientPolicy *RetryPolicy
+	idempotent      bool
+
+	idGenerator IDGenerator
+
+	multipleRowsError bool
+
+	tag         string
+	tagComments bool
+
+	unscoped     bool
+	rowLock      RowLock
+	requireWhere bool
+	schema       string
+	tenant       *TenantScope
+	scopes       []scope
+
+	stmtCache   *StatementCache
+	txDB        *sql.DB // SQL database connection of transaction started by DB
+	timeout     time.Duration
+	auditor     *Auditor
+	recordCache *RecordCache
+	cacheTxKeys *[]string // keys of records changed in transaction
+
+	slowQueryThreshold time.Duration
+	slowQueryExplain   bool
+	recorder           *QueryRecorder
+	stats              *queryStats // set only for DB
+
 	Dialect
 	Logger Logger
 }
 
-func newQuerier(dbtx DBTX, dialect Dialect, logger Logger) *Querier {
+func newQuerier(dbtx dbtx, dialect Dialect, logger Logger) *Querier {
 	return &Querier{
 		dbtx:    dbtx,
+		ctx:     context.Background(),
 		Dialect: dialect,
 		Logger:  logger,
 	}
 }
 
+// clone returns a shallow copy of querier.
+func (q *Querier) clone() *Querier {
+	c := *q
+	return &c
+}
+
+// WithContext returns a copy of querier which uses given context for all queries and commands.
+// It is also passed to BeforeInserterContext, BeforeUpdaterContext and AfterFinderContext hooks.
+func (q *Querier) WithContext(ctx context.Context) *Querier {
+	nq := q.clone()
+	nq.ctx = ctx
+	return nq
+}
+
+// Context returns querier's context. It is never nil.
+func (q *Querier) Context() context.Context {
+	return q.ctx
+}
+
+// WithConnectionRetries returns a copy of querier which executes statements again
+// up to n times if they fail with connection-level error (see Dialect.IsConnectionError).
+// Only errors which happened before query was sent to the server are retried,
+// so non-idempotent statements like INSERT are never executed twice.
+// Retries are not performed inside transactions: connection loss aborts the whole transaction.
+func (q *Querier) WithConnectionRetries(n int) *Querier {
+	nq := q.clone()
+	nq.retries = n
+	return nq
+}
+
+// WithLogger returns a copy of querier which uses given Logger (nil disables logging).
+func (q *Querier) WithLogger(logger Logger) *Querier {
+	nq := q.clone()
+	nq.Logger = logger
+	return nq
+}
+
+// WithCipher returns a copy of querier which uses given Cipher to encrypt and decrypt values
+// of columns with "encrypted" label in "reform:" tag.
+func (q *Querier) WithCipher(c Cipher) *Querier {
+	nq := q.clone()
+	nq.cipher = c
+	return nq
+}
+
+// WithIDGenerator returns a copy of querier which uses given IDGenerator for primary keys
+// of GeneratedPKTable records inserted without them. Default is UUIDv4.
+func (q *Querier) WithIDGenerator(g IDGenerator) *Querier {
+	nq := q.clone()
+	nq.idGenerator = g
+	return nq
+}
+
+// WithMultipleRowsError returns a copy of querier which returns ErrMultipleRows from commands by primary key
+// (Update, UpdateColumns, Delete, etc.) if more than one row was affected. By default, they panic in that case,
+// because it means that "primary key" column is not unique: typically it's a bug in schema or struct definition.
+// Note that statement is already executed when error is returned: rollback transaction if needed.
+func (q *Querier) WithMultipleRowsError() *Querier {
+	nq := q.clone()
+	nq.multipleRowsError = true
+	return nq
+}
+
+// Tagged returns a copy of querier which passes given tag (application-level operation name
+// like "UserRepo.Activate") to Logger for all queries and commands if it implements TaggedLogger.
+// Executed SQL is not changed unless WithTagComments is also used.
+func (q *Querier) Tagged(tag string) *Querier {
+	nq := q.clone()
+	nq.tag = tag
+	return nq
+}
+
+// Tag returns querier's tag set by Tagged, or empty string.
+func (q *Querier) Tag() string {
+	return q.tag
+}
+
+// WithTagComments returns a copy of querier which prepends tag set by Tagged to all executed queries
+// and commands as SQL comment, so database tools like pg_stat_activity and slow query log
+// attribute load to application call sites:
+//
+//	q := db.Tagged("UserRepo.Activate").WithTagComments()
+//	err := q.Update(user) // UPDATE is executed as "/* UserRepo.Activate */ UPDATE ..."
+//
+// Each tag makes a different query text, so statements are prepared and cached (see WithStatementCache)
+// separately for each tag.
+func (q *Querier) WithTagComments() *Querier {
+	nq := q.clone()
+	nq.tagComments = true
+	return nq
+}
+
+// WithTag returns a copy of querier with given tag which is also prepended to executed queries as SQL comment.
+// It is a shorthand for Tagged(tag).WithTagComments().
+func (q *Querier) WithTag(tag string) *Querier {
+	return q.Tagged(tag).WithTagComments()
+}
+
+// tagQuery prepends tag comment to query if enabled.
+func (q *Querier) tagQuery(query string) string {
+	if !q.tagComments || q.tag == "" {
+		return query
+	}
+	return "/* " + strings.Replace(q.tag, "*/", "* /", -1) + " */ " + query
+}
+
+// Unscoped returns a copy of querier which ignores soft delete for SoftDeleteTable:
+// selectors and finders return soft deleted rows, Delete and DeleteFrom delete rows.
+func (q *Querier) Unscoped() *Querier {
+	nq := q.clone()
+	nq.unscoped = true
+	return nq
+}
+
+// softDeleteColumn returns soft delete timestamp column name and index for given view,
+// or empty string if view is not SoftDeleteTable or querier is unscoped.
+func (q *Querier) softDeleteColumn(view View) (string, uint) {
+	t, ok := view.(SoftDeleteTable)
+	if !ok || q.unscoped {
+		return "", 0
+	}
+	i := t.SoftDeleteColumnIndex()
+	return t.Columns()[i], i
+}
+
+// softDeleteTime returns timestamp for soft deleted rows. All soft delete commands use the client's clock
+// (like automatically set timestamps), not database's CURRENT_TIMESTAMP, so there is no skew between them.
+func softDeleteTime() time.Time {
+	return time.Now().UTC()
+}
+
+// retry calls f again while it returns error which is safe to retry, see retryDelay.
+// Connection-level, busy and transient errors share attempts counter, so their retries don't multiply.
+// Retries are not performed inside transactions.
+func (q *Querier) retry(query string, f func() error) error {
+	err := f()
+	if _, ok := q.dbtx.(*sql.Tx); ok {
+		return err
+	}
+
+	for attempt := 1; err != nil && q.ctx.Err() == nil; attempt++ {
+		delay, ok := q.retryDelay(query, err, attempt)
+		if !ok {
+			break
+		}
+		if delay > 0 {
+			if rl, ok := q.Logger.(RetryLogger); ok {
+				rl.LogRetry(q.ctx, attempt, delay, err)
+			}
+			t := time.NewTimer(delay)
+			select {
+			case <-t.C:
+			case <-q.ctx.Done():
+				t.Stop()
+				return err
+			}
+		}
+		err = f()
+	}
+	return err
+}
+
+// retryDelay returns delay before executing query failed with err again on given attempt (starting from 1),
+// and false if it should not be retried. Query is retried immediately on connection-level error
+// up to WithConnectionRetries times, and after delay given by WithTransientRetries policy
+// (or defaultBusyPolicy if it is not set) on busy error (see BusyErrorDialect),
+// or on transient error if query is idempotent.
+func (q *Querier) retryDelay(query string, err error, attempt int) (time.Duration, bool) {
+	if attempt <= q.retries && q.IsConnectionError(err) {
+		return 0, true
+	}
+
+	p := q.transientPolicy
+	bd, ok := q.Dialect.(BusyErrorDialect)
+	busy := ok && bd.IsBusy(err)
+	if p == nil && busy {
+		p = defaultBusyPolicy
+	}
+	if p == nil || attempt >= p.Attempts {
+		return 0, false
+	}
+	if busy {
+		return p.Backoff(attempt), true
+	}
+	if q.isIdempotent(query) && q.isTransientError(err) {
+		return p.Backoff(attempt), true
+	}
+	return 0, false
+}
+
+// scanRow executes query which returns a single row, and scans it into dest, retrying on errors like Exec.
+// It returns sql.ErrNoRows if query returned no rows.
+func (q *Querier) scanRow(view string, query string, args []interface{}, dest ...interface{}) error {
+	return q.retry(query, func() error {
+		return q.queryRowView(view, query, args...).Scan(dest...)
+	})
+}
+
+// beforeInsert sets generated primary key, automatically set timestamps and subtype's discriminator, and calls BeforeInserterTx, BeforeInserterContext or BeforeInserter hook if str implements it.
+func (q *Querier) beforeInsert(str Struct) error {
+	if err := q.generatePK(str); err != nil {
+		return err
+	}
+	setAutoTimestamps(str, true)
+	if err := checkDiscriminator(str); err != nil {
+		return err
+	}
+
+	switch h := str.(type) {
+	case BeforeInserterTx:
+		return h.BeforeInsertTx(q)
+	case BeforeInserterContext:
+		return h.BeforeInsert(q.ctx)
+	case BeforeInserter:
+		return h.BeforeInsert()
+	}
+	return nil
+}
+
+// afterInsert calls AfterInserterTx, AfterInserterContext or AfterInserter hook if str implements it.
+func (q *Querier) afterInsert(str Struct) error {
+	switch h := str.(type) {
+	case AfterInserterTx:
+		return h.AfterInsertTx(q)
+	case AfterInserterContext:
+		return h.AfterInsert(q.ctx)
+	case AfterInserter:
+		return h.AfterInsert()
+	}
+	return nil
+}
+
+// afterUpdate removes record from RecordCache and calls AfterUpdaterTx, AfterUpdaterContext or AfterUpdater hook if record implements it.
+func (q *Querier) afterUpdate(record Record) error {
+	if err := q.cacheInvalidate(record); err != nil {
+		return err
+	}
+
+	switch h := record.(type) {
+	case AfterUpdaterTx:
+		return h.AfterUpdateTx(q)
+	case AfterUpdaterContext:
+		return h.AfterUpdate(q.ctx)
+	case AfterUpdater:
+		return h.AfterUpdate()
+	}
+	return nil
+}
+
+// afterDelete removes record from RecordCache and calls AfterDeleterTx, AfterDeleterContext or AfterDeleter hook if record implements it.
+func (q *Querier) afterDelete(record Record) error {
+	if err := q.cacheInvalidate(record); err != nil {
+		return err
+	}
+
+	switch h := record.(type) {
+	case AfterDeleterTx:
+		return h.AfterDeleteTx(q)
+	case AfterDeleterContext:
+		return h.AfterDelete(q.ctx)
+	case AfterDeleter:
+		return h.AfterDelete()
+	}
+	return nil
+}
+
+// afterFind calls AfterFinderContext or AfterFinder hook if str implements it.
+func (q *Querier) afterFind(str Struct) error {
+	switch h := str.(type) {
+	case AfterFinderContext:
+		return h.AfterFind(q.ctx)
+	case AfterFinder:
+		return h.AfterFind()
+	}
+	return nil
+}
+
 func (q *Querier) logBefore(query string, args []interface{}) {
-	if q.Logger != nil {
-		q.Logger.Before(query, args)
+	if q.Logger == nil {
+		return
+	}
+	args = redactArgs(args)
+	if tl, ok := q.Logger.(TaggedLogger); ok && q.tag != "" {
+		tl.BeforeTagged(q.tag, query, args)
+		return
+	}
+	q.Logger.Before(query, args)
+}
+
+// logAfter logs query on view (if known) started at start time, records it to QueryRecorder, if any,
+// and counts it in DB.Stats.
+// View name and result res are used only for StructuredLogger and SlowQueryLogger, they may be empty and nil.
+func (q *Querier) logAfter(view string, query string, args []interface{}, start time.Time, res sql.Result, err error) {
+	if q.stats != nil {
+		q.stats.add(view, err)
+	}
+	if q.Logger == nil && q.recorder == nil {
+		return
+	}
+	logArgs := redactArgs(args)
+	d := time.Now().Sub(start)
+	if q.recorder != nil {
+		q.recorder.record(RecordedStatement{Tag: q.tag, Query: query, Args: logArgs, Duration: d, Err: err})
+		if q.Logger == nil {
+			return
+		}
+	}
+	newEvent := func() *QueryEvent {
+		event := &QueryEvent{
+			Tag:          q.tag,
+			View:         view,
+			Operation:    operation(query),
+			Query:        query,
+			Args:         logArgs,
+			Start:        start,
+			Duration:     d,
+			Err:          err,
+			RowsAffected: -1,
+			Caller:       caller(),
+		}
+		if res != nil && err == nil {
+			if n, e := res.RowsAffected(); e == nil {
+				event.RowsAffected = n
+			}
+		}
+		return event
+	}
+	if q.slowQueryThreshold > 0 && d >= q.slowQueryThreshold {
+		// rows of successful query are not read yet
+		defer q.logSlow(newEvent(), args, res == nil && err == nil)
+	}
+
+	if sl, ok := q.Logger.(StructuredLogger); ok {
+		sl.LogQuery(q.ctx, newEvent())
+		return
+	}
+	if tl, ok := q.Logger.(TaggedLogger); ok && q.tag != "" {
+		tl.AfterTagged(q.tag, query, logArgs, d, err)
+		return
+	}
+	q.Logger.After(query, logArgs, d, err)
+}
+
+// operation returns the first word of query in upper case (like SELECT), skipping tag comment.
+func operation(query string) string {
+	query = strings.TrimSpace(query)
+	if strings.HasPrefix(query, "/*") {
+		if i := strings.Index(query, "*/"); i >= 0 {
+			query = strings.TrimSpace(query[i+2:])
+		}
+	}
+	if i := strings.IndexAny(query, " \t\n("); i >= 0 {
+		query = query[:i]
+	}
+	return strings.ToUpper(query)
+}
+
+// reformPackage is an import path of this package.
+var reformPackage = reflect.TypeOf(Querier{}).PkgPath()
+
+// caller returns "file:line" of the first caller outside of this package, or empty string.
+func caller() string {
+	pc := make([]uintptr, 16)
+	n := runtime.Callers(3, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, reformPackage+".") {
+			return f.File + ":" + strconv.Itoa(f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// encryptedColumns returns flags for encrypted columns of given view, or nil if there are none.
+func (q *Querier) encryptedColumns(view View) ([]bool, error) {
+	ev, ok := view.(EncryptedView)
+	if !ok {
+		return nil, nil
+	}
+	if q.cipher == nil {
+		return nil, ErrNoCipher
+	}
+	return ev.EncryptedColumns(), nil
+}
+
+// values returns struct or record field values converted to representation suitable for the dialect,
+// with encrypted columns encrypted and sensitive columns marked for redaction in logs.
+func (q *Querier) values(str Struct) ([]interface{}, error) {
+	if err := q.tenantFill(str); err != nil {
+		return nil, err
+	}
+
+	view := str.View()
+	encrypted, err := q.encryptedColumns(view)
+	if err != nil {
+		return nil, err
+	}
+
+	values := str.Values()
+	for i, v := range values {
+		if encrypted != nil && encrypted[i] {
+			if values[i], err = encrypt(q.cipher, str, view.Columns()[i], v); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch v := v.(type) {
+		case bool:
+			values[i] = q.BoolValue(v)
+		case *bool:
+			if v != nil {
+				values[i] = q.BoolValue(*v)
+			}
+		}
 	}
+	q.markSensitive(view, values)
+	return values, nil
 }
 
-func (q *Querier) logAfter(query string, args []interface{}, d time.Duration, err error) {
-	if q.Logger != nil {
-		q.Logger.After(query, args, d, err)
+// pointers returns struct or record field pointers suitable for scanning.
+// Boolean fields are wrapped to reliably accept integer 0/1 values,
+// encrypted fields are wrapped to collect ciphertexts to returned row, which should be decrypted after scanning.
+func (q *Querier) pointers(str Struct) ([]interface{}, *decryptRow, error) {
+	view := str.View()
+	encrypted, err := q.encryptedColumns(view)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var row *decryptRow
+	if encrypted != nil {
+		row = &decryptRow{c: q.cipher}
+	}
+
+	pointers := str.Pointers()
+	for i, p := range pointers {
+		if encrypted != nil && encrypted[i] {
+			pointers[i] = decryptScanner{row: row, column: view.Columns()[i], p: p}
+			continue
+		}
+
+		switch p := p.(type) {
+		case *bool:
+			pointers[i] = boolScanner{p: p}
+		case **bool:
+			pointers[i] = nullBoolScanner{p: p}
+		}
 	}
+	return pointers, row, nil
+}
+
+// numberedPlaceholders returns true if dialect's placeholders are numbered (like "$1"), so they can be used in any order,
+// or false if they are positional (like "?"), so their order in query should follow args.
+func (q *Querier) numberedPlaceholders() bool {
+	return q.Placeholder(1) != q.Placeholder(2)
 }
 
 // QualifiedColumns returns a slice of quoted qulified column names for given view.
@@ -45,31 +541,151 @@ func (q *Querier) QualifiedColumns(view View) []string {
 // Exec executes a query without returning any rows.
 // The args are for any placeholder parameters in the query.
 func (q *Querier) Exec(query string, args ...interface{}) (sql.Result, error) {
-	start := time.Now()
-	q.logBefore(query, args)
-	res, err := q.dbtx.Exec(query, args...)
-	q.logAfter(query, args, time.Now().Sub(start), err)
-	return res, err
+	return q.execView("", query, args...)
+}
+
+// execView is Exec for a command on given view; its name is passed to StructuredLogger.
+func (q *Querier) execView(view string, query string, args ...interface{}) (sql.Result, error) {
+	args, err := q.checkWhere(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	query = q.tagQuery(q.timeoutQuery(query))
+	var res sql.Result
+	err = q.retry(query, func() error {
+		ctx, cancel := q.queryContext()
+		defer cancel()
+
+		var err error
+		start := time.Now()
+		q.logBefore(query, args)
+		if stmt, release := q.stmt(query); stmt != nil {
+			res, err = stmt.ExecContext(ctx, driverArgs(args)...)
+			release()
+		} else {
+			res, err = q.dbtx.ExecContext(ctx, query, driverArgs(args)...)
+		}
+		q.logAfter(view, query, args, start, res, err)
+		return err
+	})
+	return res, q.wrapError(err)
 }
 
 // Query executes a query that returns rows, typically a SELECT.
 // The args are for any placeholder parameters in the query.
+// If querier has timeout (see WithTimeout), its context is released by deadline, not by rows.Close(),
+// as *sql.Rows doesn't report that.
 func (q *Querier) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	start := time.Now()
-	q.logBefore(query, args)
-	rows, err := q.dbtx.Query(query, args...)
-	q.logAfter(query, args, time.Now().Sub(start), err)
+	return q.queryView("", query, args...)
+}
+
+// queryView is Query for a query on given view; its name is passed to StructuredLogger.
+func (q *Querier) queryView(view string, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, _, err := q.queryRows(view, query, args...)
 	return rows, err
 }
 
+// queryRows is queryView which also returns function releasing context of query;
+// it should be called after rows are closed.
+func (q *Querier) queryRows(view string, query string, args ...interface{}) (*sql.Rows, context.CancelFunc, error) {
+	query = q.tagQuery(q.timeoutQuery(query))
+
+	var rows *sql.Rows
+	var cancel context.CancelFunc
+	err := q.retry(query, func() error {
+		var ctx context.Context
+		ctx, cancel = q.queryContext()
+
+		start := time.Now()
+		q.logBefore(query, args)
+		var err error
+		if stmt, release := q.stmt(query); stmt != nil {
+			rows, err = stmt.QueryContext(ctx, driverArgs(args)...)
+			release()
+		} else {
+			rows, err = q.dbtx.QueryContext(ctx, query, driverArgs(args)...)
+		}
+		if err != nil {
+			cancel()
+		}
+		q.logAfter(view, query, args, start, nil, err)
+		return err
+	})
+	if err != nil {
+		return nil, nil, q.wrapError(err)
+	}
+	return rows, cancel, nil
+}
+
 // QueryRow executes a query that is expected to return at most one row.
 // QueryRow always returns a non-nil value. Errors are deferred until Row's Scan method is called.
+// If querier has timeout (see WithTimeout), its context is released by deadline, not by Scan,
+// as *sql.Row doesn't report that. For the same reason query is logged before Scan is called,
+// so Scan errors are not reported to Logger and Stats.
 func (q *Querier) QueryRow(query string, args ...interface{}) *sql.Row {
+	var r *row
+	q.retry(query, func() error {
+		if r != nil {
+			r.cancel() // previous attempt
+		}
+		r = q.queryRowView("", query, args...)
+		return r.Err()
+	})
+	if r.log != nil {
+		r.log(nil)
+	}
+	return r.Row
+}
+
+// row is a result of queryRowView.
+type row struct {
+	*sql.Row
+	cancel context.CancelFunc
+	log    func(err error) // logs query with Scan error, if not logged yet
+}
+
+// Scan copies the columns from the matched row into the values pointed at by dest like sql.Row.Scan,
+// logs query with its error (sql.ErrNoRows is not considered an error) and releases context of query.
+func (r *row) Scan(dest ...interface{}) error {
+	defer r.cancel()
+	err := r.Row.Scan(dest...)
+	if r.log != nil {
+		logErr := err
+		if logErr == sql.ErrNoRows {
+			logErr = nil
+		}
+		r.log(logErr)
+		r.log = nil
+	}
+	return err
+}
+
+// queryRowView is QueryRow for a query on given view without retries; its name is passed to StructuredLogger.
+// Scan of returned row should be called to log query and release its context. See also scanRow.
+func (q *Querier) queryRowView(view string, query string, args ...interface{}) *row {
+	query = q.tagQuery(q.timeoutQuery(query))
+
+	r := new(row)
+	var ctx context.Context
+	ctx, r.cancel = q.queryContext()
+
 	start := time.Now()
 	q.logBefore(query, args)
-	row := q.dbtx.QueryRow(query, args...)
-	q.logAfter(query, args, time.Now().Sub(start), nil)
-	return row
+	if stmt, release := q.stmt(query); stmt != nil {
+		r.Row = stmt.QueryRowContext(ctx, driverArgs(args)...)
+		release()
+	} else {
+		r.Row = q.dbtx.QueryRowContext(ctx, query, driverArgs(args)...)
+	}
+	if err := r.Row.Err(); err != nil {
+		q.logAfter(view, query, args, start, nil, err)
+		return r
+	}
+	r.log = func(err error) {
+		q.logAfter(view, query, args, start, nil, err)
+	}
+	return r
 }
 
 // check interface
@@ -0,0 +1,564 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// bulkRows returns columns and values for inserting given structs.
+// All structs should have the same view. For records primary key column is cut
+// if the first record has no primary key; all records should be consistent with it.
+// Primary key columns of records with composite primary key are never cut.
+// If struct implements BeforeInserter or BeforeInserterContext, it calls BeforeInsert().
+func (q *Querier) bulkRows(structs []Struct) (View, []string, [][]interface{}, error) {
+	// generate primary keys before deciding whether to cut them
+	for _, str := range structs {
+		if err := q.generatePK(str); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	view := structs[0].View()
+	columns := view.Columns()
+	pk := -1
+	_, composite := structs[0].(CompositePKRecord)
+	if record, ok := structs[0].(Record); ok && !composite && !record.HasPK() {
+		pk = int(view.(Table).PKColumnIndex())
+		columns = append(columns[:pk], columns[pk+1:]...)
+	}
+
+	rows := make([][]interface{}, len(structs))
+	for i, str := range structs {
+		if str.View() != view {
+			return nil, nil, nil, fmt.Errorf("reform: all structs should have the same view, got %s and %s", view.Name(), str.View().Name())
+		}
+		if pk >= 0 && str.(Record).HasPK() {
+			return nil, nil, nil, errors.New("reform: all records should either have or not have primary key")
+		}
+
+		if err := q.beforeInsert(str); err != nil {
+			return nil, nil, nil, err
+		}
+
+		values, err := q.values(str)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if pk >= 0 {
+			values = append(values[:pk], values[pk+1:]...)
+		}
+		rows[i] = values
+	}
+
+	return view, columns, rows, nil
+}
+
+// insertMulti inserts rows with multi-row INSERT statements, not exceeding Dialect.MaxPlaceholders per statement.
+func (q *Querier) insertMulti(view View, columns []string, rows [][]interface{}) (int64, error) {
+	return q.insertRows(view, "INSERT", columns, rows, "")
+}
+
+// insertRows is like insertMulti, but uses given INSERT keyword (like "INSERT OR IGNORE")
+// and appends suffix (like "ON CONFLICT DO NOTHING") to each statement.
+func (q *Querier) insertRows(view View, insert string, columns []string, rows [][]interface{}, suffix string) (int64, error) {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = q.QuoteIdentifier(c)
+	}
+	prefix := fmt.Sprintf("%s INTO %s (%s) VALUES ", insert, q.QualifiedView(view), strings.Join(quoted, ", "))
+
+	batch := q.MaxPlaceholders() / len(columns)
+	if batch == 0 {
+		batch = 1
+	}
+
+	var total int64
+	for len(rows) > 0 {
+		n := batch
+		if n > len(rows) {
+			n = len(rows)
+		}
+
+		tuples := make([]string, n)
+		args := make([]interface{}, 0, n*len(columns))
+		for i, row := range rows[:n] {
+			tuples[i] = "(" + strings.Join(q.Placeholders(len(args)+1, len(row)), ", ") + ")"
+			args = append(args, row...)
+		}
+
+		query := prefix + strings.Join(tuples, ", ")
+		if suffix != "" {
+			query += " " + suffix
+		}
+		res, err := q.execView(view.Name(), query, args...)
+		if err != nil {
+			return total, err
+		}
+		ra, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += ra
+		rows = rows[n:]
+	}
+
+	return total, nil
+}
+
+// copyIn loads rows with COPY FROM STDIN protocol in a transaction.
+func (q *Querier) copyIn(d CopyInDialect, view View, columns []string, rows [][]interface{}) (n int64, err error) {
+	var tx *sql.Tx
+	switch dbtx := q.primary().(type) {
+	case *sql.Tx:
+		tx = dbtx
+	case *sql.DB:
+		if tx, err = dbtx.BeginTx(q.ctx, nil); err != nil {
+			return
+		}
+		defer func() {
+			if err == nil {
+				err = tx.Commit()
+			} else {
+				tx.Rollback()
+			}
+			if err != nil {
+				n = 0
+			}
+		}()
+	default:
+		return q.insertMulti(view, columns, rows)
+	}
+
+	query := d.CopyIn(view.Name(), columns)
+	start := time.Now()
+	q.logBefore(query, nil)
+	defer func() {
+		q.logAfter(view.Name(), query, nil, start, nil, err)
+	}()
+
+	stmt, err := tx.PrepareContext(q.ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err = stmt.ExecContext(q.ctx, driverArgs(row)...); err != nil {
+			return
+		}
+	}
+	if _, err = stmt.ExecContext(q.ctx); err != nil {
+		return
+	}
+
+	n = int64(len(rows))
+	return
+}
+
+// driver returns database driver of querier's primary connection, or nil if it is not known.
+func (q *Querier) driver() driver.Driver {
+	switch dbtx := q.primary().(type) {
+	case *sql.DB:
+		return dbtx.Driver()
+	case *sql.Tx:
+		if q.txDB != nil {
+			return q.txDB.Driver()
+		}
+	}
+	return nil
+}
+
+// afterInsertAll calls AfterInserterContext or AfterInserter hooks for all structs, stopping on the first error.
+func (q *Querier) afterInsertAll(structs []Struct) error {
+	for _, str := range structs {
+		if err := q.afterInsert(str); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertMulti inserts structs into SQL database table with multi-row INSERT statements
+// (batched to respect Dialect.MaxPlaceholders). If struct implements BeforeInserter or BeforeInserterContext,
+// it calls BeforeInsert() before doing so. If struct implements AfterInserter or AfterInserterContext,
+// it calls AfterInsert() after all structs are inserted.
+//
+// All structs should have the same view. Primary key column is not inserted if the first record has no primary key,
+// all records should be consistent with it. Unlike Insert, InsertMulti doesn't set primary keys of records.
+// Use transaction to insert all structs or none of them.
+func (q *Querier) InsertMulti(structs ...Struct) error {
+	if len(structs) == 0 {
+		return nil
+	}
+
+	view, columns, rows, err := q.bulkRows(structs)
+	if err != nil {
+		return err
+	}
+
+	if _, err = q.insertMulti(view, columns, rows); err != nil {
+		return err
+	}
+	return q.afterInsertAll(structs)
+}
+
+// BulkCopy loads structs into SQL database table using the fastest available method
+// and returns a number of loaded rows. If struct implements BeforeInserter or BeforeInserterContext,
+// it calls BeforeInsert() before doing so. If struct implements AfterInserter or AfterInserterContext,
+// it calls AfterInsert() after all structs are loaded.
+//
+// All structs should have the same view. Primary key column is not loaded if the first record has no primary key,
+// all records should be consistent with it. Unlike Insert, BulkCopy doesn't set primary keys of records.
+//
+// If Dialect implements CopyFromDialect (see dialects/postgresql/pgxcopy and dialects/mysql/loaddata packages)
+// and it can be used, its fast path is used. Otherwise, if Dialect implements CopyInDialect
+// and database driver supports it, "COPY FROM STDIN" protocol is used (in a new transaction if Querier is not already in one).
+// Otherwise, multi-row INSERT statements are used. See also CopyFrom for streaming.
+func (q *Querier) BulkCopy(structs []Struct) (int64, error) {
+	if len(structs) == 0 {
+		return 0, nil
+	}
+
+	return q.copyFromBatch(structs)
+}
+
+// preparer is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// prepare returns prepared statement for query, or nil if DBTX can't prepare statements.
+func (q *Querier) prepare(query string) (*sql.Stmt, error) {
+	p, ok := q.dbtx.(preparer)
+	if !ok {
+		return nil, nil
+	}
+	return p.PrepareContext(q.ctx, q.tagQuery(query))
+}
+
+// execStmt is like execView, but uses prepared statement stmt for query if it is not nil.
+func (q *Querier) execStmt(view string, stmt *sql.Stmt, query string, args ...interface{}) (sql.Result, error) {
+	if stmt == nil {
+		return q.execView(view, query, args...)
+	}
+
+	query = q.tagQuery(query)
+	var res sql.Result
+	err := q.retry(query, func() error {
+		var err error
+		start := time.Now()
+		q.logBefore(query, args)
+		res, err = stmt.ExecContext(q.ctx, driverArgs(args)...)
+		q.logAfter(view, query, args, start, res, err)
+		return err
+	})
+	return res, q.wrapError(err)
+}
+
+// UpdateColumnsAll updates specified columns of rows specified by primary keys in SQL database table
+// with given records, using a single prepared statement for all of them.
+// If record implements BeforeUpdater or BeforeUpdaterContext, it calls BeforeUpdate() before updating it.
+// If record implements AfterUpdater or AfterUpdaterContext, it calls AfterUpdate() after successful update.
+//
+// All records should have the same table. Method stops on the first error; use transaction
+// to update all records or none of them.
+//
+// Method returns ErrNoRows if no rows were updated for some record.
+// Method returns ErrNoPK if primary key is not set for some record; nothing is updated in that case.
+func (q *Querier) UpdateColumnsAll(records []Record, columns ...string) error {
+	return q.updateEach(records, func(record Record) ([]string, []interface{}, error) {
+		return q.updateColumns(record, columns)
+	}, false)
+}
+
+// updateEach updates records with columns and values returned by given function,
+// using a single prepared statement for all of them. If skipMissing is true,
+// records without rows are skipped instead of returning ErrNoRows.
+func (q *Querier) updateEach(records []Record, columns func(Record) ([]string, []interface{}, error), skipMissing bool) (err error) {
+	if len(records) == 0 {
+		return nil
+	}
+
+	// check all records before updating any of them
+	table := records[0].Table()
+	for _, record := range records {
+		if record.Table() != table {
+			return fmt.Errorf("reform: all records should have the same table, got %s and %s", table.Name(), record.Table().Name())
+		}
+		if !record.HasPK() {
+			return ErrNoPK
+		}
+	}
+
+	var stmt *sql.Stmt
+	var prepared bool
+	defer func() {
+		if stmt != nil {
+			if e := stmt.Close(); err == nil {
+				err = e
+			}
+		}
+	}()
+
+	for _, record := range records {
+		if err = q.beforeUpdate(record); err != nil {
+			return
+		}
+
+		var values []interface{}
+		var cols []string
+		if cols, values, err = columns(record); err != nil {
+			return
+		}
+
+		// query is the same for all records, so it is prepared once
+		query, args, l := q.updateQuery(record, cols, values, nil)
+		if !prepared {
+			if stmt, err = q.prepare(query); err != nil {
+				return
+			}
+			prepared = true
+		}
+
+		var res sql.Result
+		if res, err = q.execStmt(table.Name(), stmt, query, args...); err != nil {
+			return
+		}
+		if _, err = q.updated(record, res, l, nil); err != nil {
+			if skipMissing && err == ErrNoRows {
+				err = nil
+				continue
+			}
+			return
+		}
+	}
+	return nil
+}
+
+// DeleteAll deletes rows from SQL database table by primary keys of given records with
+// "DELETE ... WHERE pk IN (...)" statements (batched to respect Dialect.MaxPlaceholders)
+// and returns a number of deleted rows.
+// If record implements AfterDeleter or AfterDeleterContext, it calls AfterDelete() after all rows are deleted.
+//
+// For SoftDeleteTable it sets soft delete timestamp column to the current time instead, unless querier is Unscoped.
+// Already soft deleted rows are not deleted again and are not included in the returned number.
+// Soft delete timestamp field is set for records which don't have it set.
+//
+// All records should have the same table. Use transaction to delete all records or none of them.
+//
+// Method never returns ErrNoRows.
+// Method returns ErrNoPK if primary key is not set for some record.
+func (q *Querier) DeleteAll(records ...Record) (uint, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	table := records[0].Table()
+	for _, record := range records {
+		if record.Table() != table {
+			return 0, fmt.Errorf("reform: all records should have the same table, got %s and %s", table.Name(), record.Table().Name())
+		}
+		if !record.HasPK() {
+			return 0, ErrNoPK
+		}
+	}
+
+	column, index := q.softDeleteColumn(table)
+	now := softDeleteTime()
+	batch := (q.MaxPlaceholders() - 1) / len(pkColumnIndexes(table))
+	if batch == 0 {
+		batch = 1
+	}
+
+	var total uint
+	for rest := records; len(rest) > 0; {
+		n := batch
+		if n > len(rest) {
+			n = len(rest)
+		}
+
+		var args []interface{}
+		if column != "" {
+			args = append(args, now)
+		}
+		where := q.pkInCondition(table, n, len(args)+1)
+		for _, record := range rest[:n] {
+			args = append(args, pkValues(record)...)
+		}
+		if c, _, _ := q.tenantColumn(table); c != "" {
+			where = "(" + where + ")"
+		}
+		where, args = q.tenantCondition(table, where, args)
+
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s",
+			q.QualifiedView(table),
+			where,
+		)
+		if column != "" {
+			query = fmt.Sprintf("UPDATE %s SET %s = %s WHERE (%s) AND %s IS NULL",
+				q.QualifiedView(table),
+				q.QuoteIdentifier(column),
+				q.Placeholder(1),
+				where,
+				q.QuoteIdentifier(column),
+			)
+		}
+
+		res, err := q.execView(table.Name(), query, args...)
+		if err != nil {
+			return total, err
+		}
+		ra, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += uint(ra)
+		rest = rest[n:]
+	}
+
+	for _, record := range records {
+		if column != "" {
+			if p := record.Pointers()[index].(**time.Time); *p == nil {
+				t := now
+				*p = &t
+			}
+		}
+		if err := q.afterDelete(record); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// ConflictStrategy defines how SaveAll handles records which conflict (or not) with existing rows.
+type ConflictStrategy int
+
+const (
+	// InsertOnly inserts new records and skips ones which conflict with existing rows
+	// by primary key or unique constraint.
+	InsertOnly ConflictStrategy = iota
+
+	// UpdateOnly updates existing rows by primary key and skips records without them.
+	UpdateOnly
+
+	// Upsert inserts new records and updates existing rows which conflict with them by primary key.
+	Upsert
+)
+
+// SaveAll saves records in SQL database table with given conflict strategy using batched statements,
+// which is useful for sync jobs reconciling many rows at once. InsertOnly and Upsert use multi-row INSERT
+// statements (batched to respect Dialect.MaxPlaceholders), UpdateOnly uses a single prepared UPDATE statement.
+//
+// InsertOnly and Upsert use dialect-specific syntax (see UpsertMethod) with the same caveats as Upsert method,
+// and return ErrUpsertNotSupported for NoUpsert. Like InsertMulti, they don't set primary keys of records:
+// primary key column is not inserted if the first record has no primary key, all records should be consistent with it.
+// If record implements BeforeInserter or BeforeInserterContext, they call BeforeInsert() before saving.
+// If record implements AfterInserter or AfterInserterContext, Upsert calls AfterInsert() after all records are saved
+// (for both inserted and updated rows); InsertOnly doesn't, as it is not known which records were skipped.
+//
+// UpdateOnly updates all columns except primary key. If record implements BeforeUpdater or BeforeUpdaterContext,
+// it calls BeforeUpdate() before updating it. If record implements AfterUpdater or AfterUpdaterContext,
+// it calls AfterUpdate() after successful update (skipped records are not updated).
+// It returns ErrNoPK without updating anything if primary key is not set for some record.
+//
+// All records should have the same table and distinct primary keys. Use transaction to save all records or none of them.
+func (q *Querier) SaveAll(records []Record, strategy ConflictStrategy) error {
+	switch strategy {
+	case UpdateOnly:
+		return q.updateEach(records, q.updateAll, true)
+	case InsertOnly, Upsert:
+		// handled below
+	default:
+		return fmt.Errorf("reform: unknown ConflictStrategy %d", strategy)
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+	if q.UpsertMethod() == NoUpsert {
+		return ErrUpsertNotSupported
+	}
+
+	structs := make([]Struct, len(records))
+	for i, record := range records {
+		structs[i] = record
+	}
+	view, columns, rows, err := q.bulkRows(structs)
+	if err != nil {
+		return err
+	}
+
+	var tenantWhere string
+	if strategy == Upsert {
+		if tenantWhere, err = q.tenantConflictWhere(view.(Table)); err != nil {
+			return err
+		}
+	}
+	insert, suffix := q.saveAllClauses(view.(Table), columns, strategy, tenantWhere)
+	if _, err = q.insertRows(view, insert, columns, rows, suffix); err != nil {
+		return err
+	}
+	if strategy == InsertOnly {
+		return nil
+	}
+
+	for _, record := range records {
+		if err = q.cacheInvalidate(record); err != nil {
+			return err
+		}
+		if err = q.afterInsert(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveAllClauses returns INSERT keyword and statement suffix for SaveAll with given inserted columns and strategy.
+// tenantWhere is appended to "ON CONFLICT ... DO UPDATE" clause, see tenantConflictWhere.
+func (q *Querier) saveAllClauses(table Table, columns []string, strategy ConflictStrategy, tenantWhere string) (string, string) {
+	pk := make(map[string]bool)
+	var quotedPK []string
+	for _, i := range pkColumnIndexes(table) {
+		c := table.Columns()[i]
+		pk[c] = true
+		quotedPK = append(quotedPK, q.QuoteIdentifier(c))
+	}
+
+	var update []string
+	if strategy == Upsert {
+		for _, c := range columns {
+			if !pk[c] {
+				update = append(update, q.QuoteIdentifier(c))
+			}
+		}
+	}
+
+	switch q.UpsertMethod() {
+	case OnConflict:
+		if len(update) == 0 {
+			return "INSERT", "ON CONFLICT DO NOTHING"
+		}
+		for i, c := range update {
+			update[i] = c + " = EXCLUDED." + c
+		}
+		return "INSERT", fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s%s", strings.Join(quotedPK, ", "), strings.Join(update, ", "), tenantWhere)
+
+	case OnDuplicateKey:
+		for i, c := range update {
+			update[i] = c + " = VALUES(" + c + ")"
+		}
+		if len(update) == 0 {
+			// no-op update skips conflicting row
+			update = []string{quotedPK[0] + " = " + quotedPK[0]}
+		}
+		return "INSERT", "ON DUPLICATE KEY UPDATE " + strings.Join(update, ", ")
+
+	default:
+		panic("reform: Unhandled UpsertMethod. Please report this bug.")
+	}
+}
@@ -0,0 +1,242 @@
+package reform_test
+
+import (
+	"errors"
+	"os"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestInsertMulti() {
+	s.NoError(s.q.InsertMulti())
+
+	structs := make([]reform.Struct, 2*s.q.MaxPlaceholders()/4+1) // 4 columns without primary key
+	for i := range structs {
+		structs[i] = &Person{Name: "Multi Person"}
+	}
+	rl := reform.NewRecordingLogger()
+	s.q.Logger = rl
+	s.NoError(s.q.InsertMulti(structs...))
+	s.Len(rl.Statements(), 3)
+
+	persons, err := s.q.FindAllFrom(PersonTable, "name", "Multi Person")
+	s.NoError(err)
+	s.Len(persons, len(structs))
+	s.False(persons[0].(*Person).CreatedAt.IsZero()) // BeforeInsert was called
+
+	err = s.q.InsertMulti(&Project{ID: "multi1", Name: "Multi Project 1", Start: queenStart}, &Project{ID: "multi2", Name: "Multi Project 2", Start: queenStart})
+	s.NoError(err)
+	project, err := s.q.FindByPrimaryKeyFrom(ProjectTable, "multi2")
+	s.NoError(err)
+	s.Equal("Multi Project 2", project.(*Project).Name)
+
+	err = s.q.InsertMulti(&Person{}, &Project{})
+	s.EqualError(err, "reform: all structs should have the same view, got people and projects")
+}
+
+func (s *ReformSuite) TestBulkCopy() {
+	n, err := s.q.BulkCopy(nil)
+	s.NoError(err)
+	s.Equal(int64(0), n)
+
+	structs := make([]reform.Struct, 1500)
+	for i := range structs {
+		structs[i] = &Person{Name: "Bulk Person"}
+	}
+	n, err = s.q.BulkCopy(structs)
+	s.NoError(err)
+	s.Equal(int64(len(structs)), n)
+
+	persons, err := s.q.FindAllFrom(PersonTable, "name", "Bulk Person")
+	s.NoError(err)
+	s.Len(persons, len(structs))
+	s.False(persons[0].(*Person).CreatedAt.IsZero()) // BeforeInsert was called
+
+	projects := []reform.Struct{
+		&Project{ID: "bulk1", Name: "Bulk Project 1", Start: queenStart},
+		&Project{ID: "bulk2", Name: "Bulk Project 2", Start: queenStart},
+	}
+	n, err = s.q.BulkCopy(projects)
+	s.NoError(err)
+	s.Equal(int64(2), n)
+
+	_, err = s.q.BulkCopy([]reform.Struct{&Person{}, &Person{ID: 1}})
+	s.EqualError(err, "reform: all records should either have or not have primary key")
+
+	_, err = s.q.BulkCopy([]reform.Struct{&Person{}, &Project{}})
+	s.EqualError(err, "reform: all structs should have the same view, got people and projects")
+
+	// "COPY FROM STDIN" is used only with drivers supporting it
+	r := reform.NewQueryRecorder(1)
+	_, err = s.q.WithQueryRecorder(r).BulkCopy([]reform.Struct{&Person{Name: "Bulk Person"}})
+	s.NoError(err)
+	s.Require().Len(r.Statements(), 1)
+	if os.Getenv("REFORM_TEST_DRIVER") == "postgres" {
+		s.Contains(r.Statements()[0].Query, "FROM STDIN")
+	} else {
+		s.NotContains(r.Statements()[0].Query, "FROM STDIN")
+	}
+}
+
+type failingSource struct {
+	reform.CopySource
+	err error
+}
+
+func (f *failingSource) Next() (reform.Struct, error) {
+	str, err := f.CopySource.Next()
+	if str == nil && err == nil {
+		err = f.err
+	}
+	return str, err
+}
+
+func (s *ReformSuite) TestCopyFrom() {
+	n, err := s.q.CopyFrom(PersonTable, reform.CopySlice(nil))
+	s.NoError(err)
+	s.Equal(int64(0), n)
+
+	structs := make([]reform.Struct, 25)
+	for i := range structs {
+		structs[i] = &Person{Name: "Copied Person"}
+	}
+	n, err = s.q.CopyFrom(PersonTable, reform.CopySlice(structs))
+	s.NoError(err)
+	s.Equal(int64(len(structs)), n)
+
+	count, err := s.q.Count(PersonTable, "WHERE name = "+s.q.Placeholder(1), "Copied Person")
+	s.NoError(err)
+	s.Equal(uint(len(structs)), count)
+
+	_, err = s.q.CopyFrom(PersonTable, reform.CopySlice([]reform.Struct{&Project{}}))
+	s.EqualError(err, "reform: all structs should have view people, got projects")
+
+	source := &failingSource{reform.CopySlice([]reform.Struct{&Person{Name: "Copied Person"}}), errors.New("source error")}
+	_, err = s.q.CopyFrom(PersonTable, source)
+	s.EqualError(err, "source error")
+}
+
+func (s *ReformSuite) TestUpdateColumnsAll() {
+	s.NoError(s.q.UpdateColumnsAll(nil, "name"))
+
+	person1, err := s.q.FindByPrimaryKeyFrom(PersonTable, 101)
+	s.Require().NoError(err)
+	person2, err := s.q.FindByPrimaryKeyFrom(PersonTable, 102)
+	s.Require().NoError(err)
+	person1.(*Person).Name = "Batch Name 1"
+	person2.(*Person).Name = "Batch Name 2"
+
+	rl := reform.NewRecordingLogger()
+	s.q.Logger = rl
+	s.NoError(s.q.UpdateColumnsAll([]reform.Record{person1, person2}, "name", "updated_at"))
+	s.Require().Len(rl.Statements(), 2)
+	s.Equal(rl.Statements()[0].Query, rl.Statements()[1].Query) // the same prepared statement
+	s.NotNil(person1.(*Person).UpdatedAt)                       // BeforeUpdate was called
+	s.q.Logger = nil
+
+	person, err := s.q.FindByPrimaryKeyFrom(PersonTable, 102)
+	s.NoError(err)
+	s.Equal("Batch Name 2", person.(*Person).Name)
+
+	err = s.q.UpdateColumnsAll([]reform.Record{person1, &Person{ID: 1000}}, "name")
+	s.Equal(reform.ErrNoRows, err)
+
+	err = s.q.UpdateColumnsAll([]reform.Record{person1, &Person{}}, "name")
+	s.Equal(reform.ErrNoPK, err)
+
+	err = s.q.UpdateColumnsAll([]reform.Record{person1}, "bad")
+	s.EqualError(err, "reform: unexpected columns: [bad]")
+
+	err = s.q.UpdateColumnsAll([]reform.Record{person1, &Project{ID: "baron"}}, "name")
+	s.EqualError(err, "reform: all records should have the same table, got people and projects")
+}
+
+func (s *ReformSuite) TestSaveAll() {
+	s.NoError(s.q.SaveAll(nil, reform.UpdateOnly))
+
+	name := func(id string) string {
+		project, err := s.q.FindByPrimaryKeyFrom(ProjectTable, id)
+		if err != nil {
+			return err.Error()
+		}
+		return project.(*Project).Name
+	}
+
+	// UpdateOnly doesn't depend on UpsertMethod
+	err := s.q.SaveAll([]reform.Record{
+		&Project{ID: "baron", Name: "Updated Baron", Start: baronStart},
+		&Project{ID: "saveall0", Name: "Skipped", Start: queenStart},
+	}, reform.UpdateOnly)
+	s.NoError(err)
+	s.Equal("Updated Baron", name("baron"))
+	s.Equal(reform.ErrNoRows.Error(), name("saveall0"))
+
+	err = s.q.SaveAll([]reform.Record{&Project{ID: "baron"}, &Project{}}, reform.UpdateOnly)
+	s.Equal(reform.ErrNoPK, err)
+	s.Equal("Updated Baron", name("baron")) // nothing is updated
+
+	err = s.q.SaveAll([]reform.Record{&Project{ID: "baron"}}, reform.ConflictStrategy(42))
+	s.EqualError(err, "reform: unknown ConflictStrategy 42")
+
+	err = s.q.SaveAll([]reform.Record{
+		&Project{ID: "baron", Name: "Inserted Baron", Start: baronStart},
+		&Project{ID: "saveall1", Name: "Inserted Project", Start: queenStart},
+	}, reform.InsertOnly)
+	if s.q.UpsertMethod() == reform.NoUpsert {
+		s.Equal(reform.ErrUpsertNotSupported, err)
+		return
+	}
+	s.NoError(err)
+	s.Equal("Updated Baron", name("baron"))
+	s.Equal("Inserted Project", name("saveall1"))
+
+	rl := reform.NewRecordingLogger()
+	s.q.Logger = rl
+	err = s.q.SaveAll([]reform.Record{
+		&Project{ID: "baron", Name: "Upserted Baron", Start: baronStart},
+		&Project{ID: "saveall2", Name: "Upserted Project", Start: queenStart},
+	}, reform.Upsert)
+	s.NoError(err)
+	s.Len(rl.Statements(), 1)
+	s.q.Logger = nil
+	s.Equal("Upserted Baron", name("baron"))
+	s.Equal("Upserted Project", name("saveall2"))
+
+	err = s.q.SaveAll([]reform.Record{&Project{ID: "baron"}, &Person{ID: 101}}, reform.Upsert)
+	s.EqualError(err, "reform: all structs should have the same view, got projects and people")
+}
+
+func (s *ReformSuite) TestDeleteAll() {
+	n, err := s.q.DeleteAll()
+	s.NoError(err)
+	s.Equal(uint(0), n)
+
+	rl := reform.NewRecordingLogger()
+	s.q.Logger = rl
+	n, err = s.q.DeleteAll(&Person{ID: 1}, &Person{ID: 2}, &Person{ID: 1000})
+	s.NoError(err)
+	s.Equal(uint(2), n)
+	s.Len(rl.Statements(), 1)
+	s.q.Logger = nil
+
+	count, err := s.q.Count(PersonTable, "WHERE id IN (1, 2)")
+	s.NoError(err)
+	s.Equal(uint(0), count)
+
+	n, err = s.q.DeleteAll(&ProjectRole{ProjectID: "baron", PersonID: 102}, &ProjectRole{ProjectID: "baron", PersonID: 103})
+	s.NoError(err)
+	s.Equal(uint(2), n)
+
+	memo1, memo2 := &Memo{ID: 1}, &Memo{ID: 2}
+	n, err = s.q.DeleteAll(memo1, memo2)
+	s.NoError(err)
+	s.Equal(uint(1), n) // memo 2 is already soft deleted
+	s.NotNil(memo1.DeletedAt)
+
+	_, err = s.q.DeleteAll(&Person{ID: 101}, &Person{})
+	s.Equal(reform.ErrNoPK, err)
+
+	_, err = s.q.DeleteAll(&Person{ID: 101}, &Project{ID: "baron"})
+	s.EqualError(err, "reform: all records should have the same table, got people and projects")
+}
@@ -1,24 +1,68 @@
 package reform
 
 import (
+	"database/sql"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 )
 
 // Insert inserts a struct into SQL database table.
-// If str implements BeforeInserter, it calls BeforeInsert() before doing so.
+// If str implements BeforeInserter or BeforeInserterContext, it calls BeforeInsert() before doing so.
+// If str implements AfterInserter or AfterInserterContext, it calls AfterInsert() after successful insert.
 func (q *Querier) Insert(str Struct) error {
-	if bi, ok := str.(BeforeInserter); ok {
-		err := bi.BeforeInsert()
-		if err != nil {
-			return err
-		}
+	if err := q.beforeInsert(str); err != nil {
+		return err
+	}
+	if err := q.insert(str, nil); err != nil {
+		return err
+	}
+	if err := q.audit(AuditInsert, str, nil, false); err != nil {
+		return err
+	}
+	return q.afterInsert(str)
+}
+
+// InsertColumns inserts a struct into SQL database table with given columns only, so SQL database sets
+// DEFAULT values, sequences or generated columns for others instead of struct's zero values:
+//
+//	err = q.InsertColumns(person, "name") // created_at is set by "DEFAULT now()"
+//
+// Primary key columns (if set), automatically set timestamp columns and TenantScope column are always inserted.
+// Use Reload to read values set by SQL database. Hooks are called like for Insert.
+// Method returns *UnexpectedColumnsError if struct's view or table has no given columns.
+func (q *Querier) InsertColumns(str Struct, columns ...string) error {
+	if err := q.beforeInsert(str); err != nil {
+		return err
 	}
+	if columns == nil {
+		columns = []string{}
+	}
+	if err := q.insert(str, columns); err != nil {
+		return err
+	}
+	if err := q.audit(AuditInsert, str, nil, false); err != nil {
+		return err
+	}
+	return q.afterInsert(str)
+}
 
+// insert inserts a struct into SQL database table and sets record's primary key.
+// If only is not nil, only given columns (and columns which are always inserted, see InsertColumns) are inserted.
+func (q *Querier) insert(str Struct, only []string) error {
 	view := str.View()
-	values := str.Values()
+	values, err := q.values(str)
+	if err != nil {
+		return err
+	}
 	columns := view.Columns()
 	record, _ := str.(Record)
+	if _, ok := str.(CompositePKRecord); ok {
+		// all primary key columns are inserted as is, nothing to set after insert
+		record = nil
+	}
 	var pk uint
 
 	if record != nil {
@@ -31,20 +75,26 @@ func (q *Querier) Insert(str Struct) error {
 		}
 	}
 
+	if only != nil {
+		if columns, values, err = q.insertColumns(view, columns, values, only); err != nil {
+			return err
+		}
+	}
+
 	for i, c := range columns {
 		columns[i] = q.QuoteIdentifier(c)
 	}
 	placeholders := q.Placeholders(1, len(columns))
 
 	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		q.QuoteIdentifier(view.Name()),
+		q.QualifiedView(view),
 		strings.Join(columns, ", "),
 		strings.Join(placeholders, ", "),
 	)
 
 	switch q.Dialect.LastInsertIdMethod() {
 	case LastInsertId:
-		res, err := q.Exec(query, values...)
+		res, err := q.execView(view.Name(), query, values...)
 		if err != nil {
 			return err
 		}
@@ -53,7 +103,7 @@ func (q *Querier) Insert(str Struct) error {
 			if err != nil {
 				return err
 			}
-			record.SetPK(id)
+			setLastInsertID(record, id)
 		}
 		return nil
 
@@ -61,51 +111,222 @@ func (q *Querier) Insert(str Struct) error {
 		var err error
 		if record != nil {
 			query += fmt.Sprintf(" RETURNING %s", q.QuoteIdentifier(view.Columns()[pk]))
-			err = q.QueryRow(query, values...).Scan(record.PKPointer())
+			err = q.scanRow(view.Name(), query, values, record.PKPointer())
 		} else {
-			_, err = q.Exec(query, values...)
+			_, err = q.execView(view.Name(), query, values...)
 		}
+		return q.wrapError(err)
+
+	case NoLastInsertId:
+		_, err := q.execView(view.Name(), query, values...)
+		return err
+
+	case ThenReturn:
+		// plain DML for records with primary key set by application, which is the usual case for Spanner
+		var err error
+		if record != nil && !record.HasPK() {
+			query += fmt.Sprintf(" THEN RETURN %s", q.QuoteIdentifier(view.Columns()[pk]))
+			err = q.scanRow(view.Name(), query, values, record.PKPointer())
+		} else {
+			_, err = q.execView(view.Name(), query, values...)
+		}
+		return q.wrapError(err)
+
+	case ReturningInto:
+		if record != nil {
+			query += fmt.Sprintf(" RETURNING %s INTO %s", q.QuoteIdentifier(view.Columns()[pk]), q.Placeholder(len(values)+1))
+			values = append(values, sql.Out{Dest: record.PKPointer()})
+		}
+		_, err := q.execView(view.Name(), query, values...)
 		return err
 
+	case OutputInserted:
+		var err error
+		if record != nil {
+			query = fmt.Sprintf("INSERT INTO %s (%s) OUTPUT INSERTED.%s VALUES (%s)",
+				q.QualifiedView(view),
+				strings.Join(columns, ", "),
+				q.QuoteIdentifier(view.Columns()[pk]),
+				strings.Join(placeholders, ", "),
+			)
+			err = q.scanRow(view.Name(), query, values, record.PKPointer())
+		} else {
+			_, err = q.execView(view.Name(), query, values...)
+		}
+		return q.wrapError(err)
+
 	default:
 		panic("reform: Unhandled LastInsertIdMethod. Please report this bug.")
 	}
 }
 
-func (q *Querier) update(record Record, columns []string, values []interface{}) error {
+// insertColumns returns columns and their values filtered for InsertColumns.
+func (q *Querier) insertColumns(view View, columns []string, values []interface{}, only []string) ([]string, []interface{}, error) {
+	columnsSet := make(map[string]struct{}, len(only))
+	for _, c := range only {
+		columnsSet[c] = struct{}{}
+	}
+
+	always := make(map[string]struct{})
+	allColumns := view.Columns()
+	if table, ok := view.(Table); ok {
+		always[allColumns[table.PKColumnIndex()]] = struct{}{}
+		if ct, ok := view.(CompositePKTable); ok {
+			for _, i := range ct.PKColumnIndexes() {
+				always[allColumns[i]] = struct{}{}
+			}
+		}
+	}
+	if v, ok := view.(AutoTimestampsView); ok {
+		for _, at := range v.AutoTimestamps() {
+			always[allColumns[at.Index]] = struct{}{}
+		}
+	}
+	if c, _, _ := q.tenantColumn(view); c != "" {
+		always[c] = struct{}{}
+	}
+
+	unexpected := make(map[string]struct{}, len(columnsSet))
+	for c := range columnsSet {
+		unexpected[c] = struct{}{}
+	}
+	for _, c := range allColumns {
+		delete(unexpected, c)
+	}
+	if len(unexpected) > 0 {
+		res := make([]string, 0, len(unexpected))
+		for c := range unexpected {
+			res = append(res, c)
+		}
+		sort.Strings(res)
+		return nil, nil, &UnexpectedColumnsError{Columns: res}
+	}
+
+	var resColumns []string
+	var resValues []interface{}
 	for i, c := range columns {
-		columns[i] = q.QuoteIdentifier(c)
+		_, ok := columnsSet[c]
+		if _, a := always[c]; ok || a {
+			resColumns = append(resColumns, c)
+			resValues = append(resValues, values[i])
+		}
 	}
-	placeholders := q.Placeholders(1, len(columns))
+	return resColumns, resValues, nil
+}
+
+// exists returns true if row with record's primary key (and matching extra condition, if any)
+// exists in SQL database table.
+func (q *Querier) exists(record Record, extra *extraCondition) (bool, error) {
+	table := record.Table()
+	args := extra.start(q)
+	where, args := q.tenantCondition(table, q.pkCondition(table, len(args)+1), append(args, pkValues(record)...))
+	where, args = q.subtypeCondition(table, where, args)
+	where, args = extra.appendTo(q, where, args)
+	query := fmt.Sprintf("SELECT 1 FROM %s WHERE %s",
+		q.QualifiedView(table),
+		where,
+	)
+
+	var one int
+	err := q.scanRow(table.Name(), query, args, &one)
+	switch err {
+	case nil:
+		return true, nil
+	case sql.ErrNoRows:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// updateQuery returns UPDATE query and its arguments for row specified by primary key.
+// Values of Expr type are rendered as SQL expressions, other values are passed as arguments.
+// Extra condition, if any, is added to WHERE clause.
+// For LockingTable it also checks and increments version, see lock method.
+func (q *Querier) updateQuery(record Record, columns []string, values []interface{}, extra *extraCondition) (string, []interface{}, *versionLock) {
+	table := record.Table()
+	columns, values, l := q.lock(record, columns, values)
 
 	p := make([]string, len(columns))
+	args := extra.start(q)
 	for i, c := range columns {
-		p[i] = c + " = " + placeholders[i]
+		if e, ok := values[i].(Expr); ok {
+			p[i] = q.QuoteIdentifier(c) + " = " + e.build(q.Dialect, &args)
+			continue
+		}
+		args = append(args, values[i])
+		p[i] = q.QuoteIdentifier(c) + " = " + q.Placeholder(len(args))
 	}
-	table := record.Table()
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = %s",
-		q.QuoteIdentifier(table.Name()),
+	where := q.pkCondition(table, len(args)+1)
+	args = append(args, pkValues(record)...)
+	if l != nil {
+		where += " AND " + q.QuoteIdentifier(l.column) + " = " + q.Placeholder(len(args)+1)
+		args = append(args, l.current)
+	}
+	where, args = q.tenantCondition(table, where, args)
+	where, args = q.subtypeCondition(table, where, args)
+	where, args = extra.appendTo(q, where, args)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		q.QualifiedView(table),
 		strings.Join(p, ", "),
-		q.QuoteIdentifier(table.Columns()[table.PKColumnIndex()]),
-		q.Placeholder(len(columns)+1),
+		where,
 	)
+	return query, args, l
+}
 
-	args := append(values, record.PKValue())
-	res, err := q.Exec(query, args...)
-	if err != nil {
-		return err
-	}
+// updated checks result of UPDATE query returned by updateQuery with the same extra condition
+// and returns true if row was changed.
+// If record implements AfterUpdater or AfterUpdaterContext, it calls AfterUpdate() after successful update.
+// Some databases (like MySQL without CLIENT_FOUND_ROWS flag) report zero affected rows
+// for matched, but not changed rows, so it checks for row existence in that case.
+func (q *Querier) updated(record Record, res sql.Result, l *versionLock, extra *extraCondition) (bool, error) {
 	ra, err := res.RowsAffected()
 	if err != nil {
-		return err
+		return false, err
 	}
 	if ra == 0 {
-		return ErrNoRows
+		exists, err := q.exists(record, extra)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, ErrNoRows
+		}
+		if l != nil {
+			return false, ErrStaleRecord
+		}
+		return false, q.afterUpdate(record)
 	}
 	if ra > 1 {
+		if q.multipleRowsError {
+			return false, ErrMultipleRows
+		}
 		panic(fmt.Errorf("reform: %d rows by UPDATE by primary key. Please report this bug.", ra))
 	}
-	return nil
+	if l != nil {
+		l.set()
+	}
+	return true, q.afterUpdate(record)
+}
+
+// update updates row specified by primary key (and extra condition, if any)
+// and returns true if it was changed, see updated method.
+func (q *Querier) update(record Record, columns []string, values []interface{}, extra *extraCondition) (bool, error) {
+	old, err := q.auditOld(record)
+	if err != nil {
+		return false, err
+	}
+
+	query, args, l := q.updateQuery(record, columns, values, extra)
+	res, err := q.execView(record.Table().Name(), query, args...)
+	if err != nil {
+		return false, err
+	}
+	changed, err := q.updated(record, res, l, extra)
+	if err != nil {
+		return false, err
+	}
+	return changed, q.audit(AuditUpdate, record, old, false)
 }
 
 func (q *Querier) beforeUpdate(record Record) error {
@@ -113,18 +334,51 @@ func (q *Querier) beforeUpdate(record Record) error {
 		return ErrNoPK
 	}
 
-	if bu, ok := record.(BeforeUpdater); ok {
-		err := bu.BeforeUpdate()
-		if err != nil {
-			return err
-		}
+	setAutoTimestamps(record, false)
+	if err := checkDiscriminator(record); err != nil {
+		return err
 	}
 
+	switch h := record.(type) {
+	case BeforeUpdaterTx:
+		return h.BeforeUpdateTx(q)
+	case BeforeUpdaterContext:
+		return h.BeforeUpdate(q.ctx)
+	case BeforeUpdater:
+		return h.BeforeUpdate()
+	}
 	return nil
 }
 
+// updateAll returns all columns and values of record except primary key.
+func (q *Querier) updateAll(record Record) ([]string, []interface{}, error) {
+	table := record.Table()
+	allValues, err := q.values(record)
+	if err != nil {
+		return nil, nil, err
+	}
+	allColumns := table.Columns()
+
+	// cut primary key
+	columns := make([]string, 0, len(allColumns))
+	values := make([]interface{}, 0, len(allValues))
+	for i, c := range allColumns {
+		if isPKColumn(table, uint(i)) {
+			continue
+		}
+		columns = append(columns, c)
+		values = append(values, allValues[i])
+	}
+
+	if len(values) == 0 {
+		return nil, nil, ErrNothingToUpdate
+	}
+	return columns, values, nil
+}
+
 // Update updates all columns of row specified by primary key in SQL database table with given record.
-// If record implements BeforeUpdater, it calls BeforeUpdate() before doing so.
+// If record implements BeforeUpdater or BeforeUpdaterContext, it calls BeforeUpdate() before doing so.
+// If record implements AfterUpdater or AfterUpdaterContext, it calls AfterUpdate() after successful update.
 //
 // Method returns ErrNoRows if no rows were updated.
 // Method returns ErrNoPK if primary key is not set.
@@ -134,67 +388,368 @@ func (q *Querier) Update(record Record) error {
 		return err
 	}
 
-	table := record.Table()
-	values := record.Values()
-	columns := table.Columns()
+	columns, values, err := q.updateAll(record)
+	if err != nil {
+		return err
+	}
+	_, err = q.update(record, columns, values, nil)
+	return err
+}
 
-	// cut primary key
-	pk := table.PKColumnIndex()
-	values = append(values[:pk], values[pk+1:]...)
-	columns = append(columns[:pk], columns[pk+1:]...)
+// UpdateChanged is like Update, but also reports whether row was changed.
+// It returns false, nil if row was found, but database reported that no data was changed
+// (MySQL does that if all values are the same, unless CLIENT_FOUND_ROWS flag is set;
+// other databases always report matched rows as changed).
+//
+// Method returns ErrNoRows if no rows were found.
+// Method returns ErrNoPK if primary key is not set.
+func (q *Querier) UpdateChanged(record Record) (bool, error) {
+	err := q.beforeUpdate(record)
+	if err != nil {
+		return false, err
+	}
 
-	return q.update(record, columns, values)
+	columns, values, err := q.updateAll(record)
+	if err != nil {
+		return false, err
+	}
+	return q.update(record, columns, values, nil)
+}
+
+// UpdateReturning is like Update, but also sets all record's fields to values stored in SQL database
+// after update, including ones set by defaults and triggers.
+// For dialects with Returning or ThenReturn LastInsertIdMethod it uses a single query with RETURNING clause;
+// for other dialects (like MySQL) it reloads record after update.
+//
+// Method returns ErrNoRows if no rows were updated.
+// Method returns ErrNoPK if primary key is not set.
+func (q *Querier) UpdateReturning(record Record) error {
+	err := q.beforeUpdate(record)
+	if err != nil {
+		return err
+	}
+
+	columns, values, err := q.updateAll(record)
+	if err != nil {
+		return err
+	}
+	return q.updateReturning(record, columns, values)
+}
+
+// updateReturning updates given columns of row specified by primary key with given values
+// and sets all record's fields to values stored in SQL database after update, see UpdateReturning.
+func (q *Querier) updateReturning(record Record, columns []string, values []interface{}) error {
+	if q.returningKeyword() == "" {
+		if _, err := q.update(record, columns, values, nil); err != nil {
+			return err
+		}
+		return q.Reload(record)
+	}
+
+	old, err := q.auditOld(record)
+	if err != nil {
+		return err
+	}
+	query, args, l := q.updateQuery(record, columns, values, nil)
+	err = q.returning(record, query, args)
+	if err == ErrNoRows && l != nil {
+		// version mismatch or no row at all
+		exists, e := q.exists(record, nil)
+		if e != nil {
+			return e
+		}
+		if exists {
+			return ErrStaleRecord
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err = q.audit(AuditUpdate, record, old, false); err != nil {
+		return err
+	}
+	return q.afterUpdate(record)
 }
 
 // UpdateColumns updates specified columns of row specified by primary key in SQL database table with given record.
-// If record implements BeforeUpdater, it calls BeforeUpdate() before doing so.
+// If record implements BeforeUpdater or BeforeUpdaterContext, it calls BeforeUpdate() before doing so.
+// If record implements AfterUpdater or AfterUpdaterContext, it calls AfterUpdate() after successful update.
 //
 // Method returns ErrNoRows if no rows were updated.
 // Method returns ErrNoPK if primary key is not set.
+// Method returns *UnexpectedColumnsError if record's table has no given columns,
+// and ErrNothingToUpdate if there are no columns to update.
 func (q *Querier) UpdateColumns(record Record, columns ...string) error {
 	err := q.beforeUpdate(record)
 	if err != nil {
 		return err
 	}
 
+	columns, values, err := q.updateColumns(record, columns)
+	if err != nil {
+		return err
+	}
+	_, err = q.update(record, columns, values, nil)
+	return err
+}
+
+// updateColumns returns given columns (and autoupdate columns, if any) in table order and their values of record.
+func (q *Querier) updateColumns(record Record, columns []string) ([]string, []interface{}, error) {
 	columnsSet := make(map[string]struct{}, len(columns))
 	for _, c := range columns {
 		columnsSet[c] = struct{}{}
 	}
+	for _, c := range autoUpdateColumns(record.Table()) {
+		columnsSet[c] = struct{}{}
+	}
 
-	table := record.Table()
-	allColumns := table.Columns()
-	allValues := record.Values()
-	columns = make([]string, 0, len(columnsSet))
-	values := make([]interface{}, 0, len(columns))
+	allColumns := record.Table().Columns()
+	allValues, err := q.values(record)
+	if err != nil {
+		return nil, nil, err
+	}
+	resColumns := make([]string, 0, len(columnsSet))
+	values := make([]interface{}, 0, len(columnsSet))
 	for i, c := range allColumns {
 		if _, ok := columnsSet[c]; ok {
 			delete(columnsSet, c)
-			columns = append(columns, c)
+			resColumns = append(resColumns, c)
 			values = append(values, allValues[i])
 		}
 	}
 
 	if len(columnsSet) > 0 {
-		columns = make([]string, 0, len(columnsSet))
+		unexpected := make([]string, 0, len(columnsSet))
 		for c := range columnsSet {
-			columns = append(columns, c)
+			unexpected = append(unexpected, c)
+		}
+		sort.Strings(unexpected)
+		return nil, nil, &UnexpectedColumnsError{Columns: unexpected}
+	}
+
+	if len(values) == 0 {
+		return nil, nil, ErrNothingToUpdate
+	}
+	return resColumns, values, nil
+}
+
+// UpdateDiff is like UpdateColumns, but updates only columns which values differ between record
+// and its original copy, typically made with generated Clone method right after loading:
+//
+//	original := person.Clone()
+//	person.Name = "New Name"
+//	columns, err := q.UpdateDiff(person, original)
+//
+// Smaller UPDATE statements reduce lock contention and write-ahead log volume for wide tables.
+// Primary key columns are never updated. If nothing was changed, no query is executed
+// and hooks are not called; otherwise it returns changed columns (without autoupdate columns, if any).
+//
+// Method returns ErrNoRows if no rows were updated.
+// Method returns ErrNoPK if primary key is not set.
+func (q *Querier) UpdateDiff(record, original Record) ([]string, error) {
+	columns, err := ChangedColumns(original, record)
+	if err != nil || len(columns) == 0 {
+		return nil, err
+	}
+	if err := q.UpdateColumns(record, columns...); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// ChangedColumns returns non-primary key columns which values differ between original and changed records
+// of the same table, in table order. Time values are compared with time.Time.Equal,
+// other values are compared deeply (so pointers are compared by pointed values).
+// It returns error if records are of different tables.
+func ChangedColumns(original, changed Record) ([]string, error) {
+	table := changed.Table()
+	if original.Table().Name() != table.Name() {
+		return nil, fmt.Errorf("reform: ChangedColumns: records of different tables %s and %s", original.Table().Name(), table.Name())
+	}
+
+	pk := make(map[uint]bool)
+	for _, i := range pkColumnIndexes(table) {
+		pk[i] = true
+	}
+
+	columns := table.Columns()
+	originalValues, changedValues := original.Values(), changed.Values()
+	var res []string
+	for i, c := range columns {
+		if !pk[uint(i)] && !equalValues(originalValues[i], changedValues[i]) {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+// equalValues returns true if field values returned by Values are equal.
+func equalValues(a, b interface{}) bool {
+	switch a := a.(type) {
+	case time.Time:
+		b, ok := b.(time.Time)
+		return ok && a.Equal(b)
+	case *time.Time:
+		b, ok := b.(*time.Time)
+		if !ok || a == nil || b == nil {
+			return ok && a == b
+		}
+		return a.Equal(*b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// UpdateColumnsWhere is like UpdateColumns, but updates row only if it also matches given condition
+// (like "status = $1") with given args, which is useful for state machine transitions and other guarded updates:
+//
+//	order.Status = "shipped"
+//	err = q.UpdateColumnsWhere(order, "status = $1", []interface{}{"paid"}, "status")
+//	if err == reform.ErrNoRows {
+//		// order was not paid or was already shipped
+//	}
+//
+// Placeholders in condition are numbered from 1, like in tails.
+//
+// Method returns ErrNoRows if no rows were updated: row doesn't exist or doesn't match condition.
+// Method returns ErrNoPK if primary key is not set.
+// Method returns *UnexpectedColumnsError if record's table has no given columns,
+// and ErrNothingToUpdate if there are no columns to update.
+func (q *Querier) UpdateColumnsWhere(record Record, condition string, args []interface{}, columns ...string) error {
+	err := q.beforeUpdate(record)
+	if err != nil {
+		return err
+	}
+
+	columns, values, err := q.updateColumns(record, columns)
+	if err != nil {
+		return err
+	}
+	_, err = q.update(record, columns, values, &extraCondition{condition: condition, args: args})
+	return err
+}
+
+// extraCondition is an additional condition of WHERE clause for row specified by primary key,
+// see UpdateColumnsWhere. Placeholders in it are numbered from 1.
+type extraCondition struct {
+	condition string
+	args      []interface{}
+}
+
+// start returns initial args of query with extra condition: its args for numbered placeholders,
+// so placeholders generated after that are numbered after them; nothing for positional ones.
+func (e *extraCondition) start(q *Querier) []interface{} {
+	if e == nil || !q.numberedPlaceholders() {
+		return nil
+	}
+	return append([]interface{}(nil), e.args...)
+}
+
+// appendTo adds extra condition to WHERE clause condition, and its args for positional placeholders to args.
+func (e *extraCondition) appendTo(q *Querier, where string, args []interface{}) (string, []interface{}) {
+	if e == nil {
+		return where, args
+	}
+	if !q.numberedPlaceholders() {
+		args = append(args, e.args...)
+	}
+	return where + " AND (" + e.condition + ")", args
+}
+
+// UpdateExpr updates columns of row specified by primary key in SQL database table with given SQL expressions
+// (and autoupdate columns with record's values), which allows atomic changes like increments:
+//
+//	err = q.UpdateExpr(article, map[string]reform.Expr{
+//		"views":  reform.Inc("views", 1),
+//		"rating": reform.Raw("(likes * 100) / (likes + dislikes)"),
+//	})
+//
+// Like UpdateReturning, it then sets all record's fields to values stored in SQL database after update.
+// If record implements BeforeUpdater or BeforeUpdaterContext, it calls BeforeUpdate() before doing so.
+// If record implements AfterUpdater or AfterUpdaterContext, it calls AfterUpdate() after successful update.
+//
+// Method returns ErrNoRows if no rows were updated.
+// Method returns ErrNoPK if primary key is not set.
+// Method returns *UnexpectedColumnsError if record's table has no given columns,
+// and ErrNothingToUpdate if there are no expressions.
+func (q *Querier) UpdateExpr(record Record, exprs map[string]Expr) error {
+	if len(exprs) == 0 {
+		return ErrNothingToUpdate
+	}
+
+	err := q.beforeUpdate(record)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(exprs))
+	for c := range exprs {
+		names = append(names, c)
+	}
+	columns, values, err := q.updateColumns(record, names)
+	if err != nil {
+		return err
+	}
+	for i, c := range columns {
+		if e, ok := exprs[c]; ok {
+			values[i] = e
 		}
-		// TODO make exported type for that error
-		return fmt.Errorf("reform: unexpected columns: %v", columns)
+	}
+	return q.updateReturning(record, columns, values)
+}
+
+// UpdateNonZero updates columns with non-zero values of row specified by primary key in SQL database table
+// with given record. Column value is zero if it is equal to Go's zero value of field type:
+// 0, "", false, nil pointer, zero time.Time, etc. Primary key column is never updated.
+// If record implements BeforeUpdater or BeforeUpdaterContext, it calls BeforeUpdate() before doing so,
+// so fields set by BeforeUpdate() are also considered.
+// If record implements AfterUpdater or AfterUpdaterContext, it calls AfterUpdate() after successful update.
+//
+// It is useful for partial updates (PATCH), but it can't express "set column to zero value":
+// use UpdateColumns for that.
+//
+// Method returns ErrNoRows if no rows were updated.
+// Method returns ErrNoPK if primary key is not set.
+// Method returns ErrNothingToUpdate if all columns have zero values.
+func (q *Querier) UpdateNonZero(record Record) error {
+	err := q.beforeUpdate(record)
+	if err != nil {
+		return err
+	}
+
+	table := record.Table()
+	allColumns := table.Columns()
+	allValues, err := q.values(record)
+	if err != nil {
+		return err
+	}
+	columns := make([]string, 0, len(allColumns))
+	values := make([]interface{}, 0, len(allValues))
+	for i, v := range record.Values() {
+		switch w := v.(type) {
+		case JSON:
+			v = w.V
+		case Array:
+			v = w.V
+		}
+		if isPKColumn(table, uint(i)) || v == nil || reflect.ValueOf(v).IsZero() {
+			continue
+		}
+		columns = append(columns, allColumns[i])
+		values = append(values, allValues[i])
 	}
 
 	if len(values) == 0 {
-		// TODO make exported type for that error
-		return fmt.Errorf("reform: nothing to update")
+		return ErrNothingToUpdate
 	}
 
-	return q.update(record, columns, values)
+	_, err = q.update(record, columns, values, nil)
+	return err
 }
 
 // Save saves record in SQL database table.
 // If primary key is set, it first calls Update and checks if row was updated.
 // If primary key is absent or no row was updated, it calls Insert.
+// In the latter case both BeforeUpdate() and BeforeInsert() are called for record, if implemented.
 func (q *Querier) Save(record Record) error {
 	if record.HasPK() {
 		err := q.Update(record)
@@ -206,7 +761,218 @@ func (q *Querier) Save(record Record) error {
 	return q.Insert(record)
 }
 
+// Upsert atomically inserts record into SQL database table or updates existing row
+// if it conflicts with record on given columns (primary key columns by default).
+// If record implements BeforeInserter or BeforeInserterContext, it calls BeforeInsert() before doing so.
+// If record implements AfterInserter or AfterInserterContext, it calls AfterInsert() after success
+// (for both inserted and updated row).
+// BeforeUpdate() and AfterUpdate() are never called. Since all record's columns except conflicting
+// and primary key ones are written to updated row, values set by BeforeInsert() (for example,
+// creation time) overwrite existing ones; set them in record before calling Upsert to avoid that.
+// If primary key is absent, it is set to primary key of inserted or updated row.
+//
+// Dialect-specific syntax is used (see UpsertMethod), with the following caveats:
+// with OnDuplicateKey (MySQL) conflictColumns are ignored, any unique key conflict leads to update.
+// Method returns ErrUpsertNotSupported for NoUpsert.
+func (q *Querier) Upsert(record Record, conflictColumns ...string) error {
+	if q.Dialect.UpsertMethod() == NoUpsert {
+		return ErrUpsertNotSupported
+	}
+
+	if err := q.beforeInsert(record); err != nil {
+		return err
+	}
+
+	table := record.Table()
+	values, err := q.values(record)
+	if err != nil {
+		return err
+	}
+	columns := table.Columns()
+	pk := table.PKColumnIndex()
+	pkColumn := columns[pk]
+	_, composite := record.(CompositePKRecord)
+	hasPK := composite || record.HasPK()
+
+	var old map[string]interface{}
+	if hasPK {
+		if old, err = q.auditOld(record); err != nil {
+			return err
+		}
+	}
+	tenantWhere, err := q.tenantConflictWhere(table)
+	if err != nil {
+		return err
+	}
+
+	pkColumns := make(map[string]bool)
+	for _, i := range pkColumnIndexes(table) {
+		pkColumns[columns[i]] = true
+	}
+
+	// cut primary key
+	if !hasPK {
+		values = append(values[:pk], values[pk+1:]...)
+		columns = append(columns[:pk], columns[pk+1:]...)
+	}
+
+	if len(conflictColumns) == 0 {
+		for _, i := range pkColumnIndexes(table) {
+			conflictColumns = append(conflictColumns, table.Columns()[i])
+		}
+	}
+	conflict := make(map[string]bool, len(conflictColumns))
+	quotedConflict := make([]string, len(conflictColumns))
+	for i, c := range conflictColumns {
+		conflict[c] = true
+		quotedConflict[i] = q.QuoteIdentifier(c)
+	}
+
+	var update []string
+	for i, c := range columns {
+		columns[i] = q.QuoteIdentifier(c)
+		if !conflict[c] && !pkColumns[c] {
+			update = append(update, columns[i])
+		}
+	}
+	placeholders := q.Placeholders(1, len(columns))
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		q.QualifiedView(table),
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+	)
+
+	quotedPK := q.QuoteIdentifier(pkColumn)
+	switch q.Dialect.UpsertMethod() {
+	case OnConflict:
+		query += fmt.Sprintf(" ON CONFLICT (%s) DO ", strings.Join(quotedConflict, ", "))
+		if len(update) == 0 {
+			// make row to be returned
+			update = []string{quotedConflict[0]}
+		}
+		for i, c := range update {
+			update[i] = c + " = EXCLUDED." + c
+		}
+		query += "UPDATE SET " + strings.Join(update, ", ") + tenantWhere
+
+	case OnDuplicateKey:
+		for i, c := range update {
+			update[i] = c + " = VALUES(" + c + ")"
+		}
+		if !composite {
+			// make LastInsertId to return primary key of updated row
+			update = append(update, fmt.Sprintf("%s = LAST_INSERT_ID(%s)", quotedPK, quotedPK))
+		}
+		if len(update) == 0 {
+			update = []string{quotedConflict[0] + " = " + quotedConflict[0]}
+		}
+		query += " ON DUPLICATE KEY UPDATE " + strings.Join(update, ", ")
+
+	default:
+		panic("reform: Unhandled UpsertMethod. Please report this bug.")
+	}
+
+	switch q.Dialect.LastInsertIdMethod() {
+	case NoLastInsertId:
+		res, err := q.execView(table.Name(), query, values...)
+		if err != nil {
+			return err
+		}
+		if err = upserted(res, tenantWhere); err != nil {
+			return err
+		}
+		return q.afterUpsert(record, old)
+
+	case LastInsertId:
+		if !hasPK && q.Dialect.UpsertMethod() == OnConflict {
+			// LastInsertId is not set for updated row (SQLite), so use RETURNING clause
+			query += " RETURNING " + quotedPK
+			err = q.scanRow(table.Name(), query, values, record.PKPointer())
+			if err == sql.ErrNoRows {
+				return ErrNoRows
+			}
+			if err != nil {
+				return q.wrapError(err)
+			}
+			return q.afterUpsert(record, old)
+		}
+
+		res, err := q.execView(table.Name(), query, values...)
+		if err != nil {
+			return err
+		}
+		if err = upserted(res, tenantWhere); err != nil {
+			return err
+		}
+		if !hasPK {
+			id, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			setLastInsertID(record, id)
+		}
+		return q.afterUpsert(record, old)
+
+	case Returning, ThenReturn:
+		if hasPK {
+			var res sql.Result
+			if res, err = q.execView(table.Name(), query, values...); err == nil {
+				err = upserted(res, tenantWhere)
+			}
+		} else {
+			query += " " + q.returningKeyword() + " " + quotedPK
+			err = q.scanRow(table.Name(), query, values, record.PKPointer())
+			if err == sql.ErrNoRows {
+				err = ErrNoRows
+			}
+		}
+		if err == ErrNoRows {
+			return err
+		}
+		if err != nil {
+			return q.wrapError(err)
+		}
+		return q.afterUpsert(record, old)
+
+	default:
+		panic("reform: Unhandled LastInsertIdMethod. Please report this bug.")
+	}
+}
+
+// upserted checks result of Upsert command for scoped table (tenantWhere is not empty) and returns ErrNoRows
+// if row was neither inserted nor updated because conflicting row belongs to another tenant.
+func upserted(res sql.Result, tenantWhere string) error {
+	if tenantWhere == "" {
+		return nil
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNoRows
+	}
+	return nil
+}
+
+// afterUpsert records audit row for upserted record, removes it from RecordCache
+// and calls AfterInserterContext or AfterInserter hook.
+func (q *Querier) afterUpsert(record Record, old map[string]interface{}) error {
+	if err := q.cacheInvalidate(record); err != nil {
+		return err
+	}
+	if err := q.audit(AuditUpsert, record, old, false); err != nil {
+		return err
+	}
+	return q.afterInsert(record)
+}
+
 // Delete deletes record from SQL database table by primary key.
+// If record implements AfterDeleter or AfterDeleterContext, it calls AfterDelete() after successful delete.
+//
+// For SoftDeleteTable it sets soft delete timestamp column (in both SQL database and record)
+// to the current time instead, unless querier is Unscoped. Already soft deleted row is not deleted again.
 //
 // Method returns ErrNoRows if no rows were deleted.
 // Method returns ErrNoPK if primary key is not set.
@@ -215,15 +981,14 @@ func (q *Querier) Delete(record Record) error {
 		return ErrNoPK
 	}
 
-	table := record.Table()
-	pk := table.PKColumnIndex()
-	query := fmt.Sprintf("DELETE FROM %s WHERE %s = %s",
-		q.QuoteIdentifier(table.Name()),
-		q.QuoteIdentifier(table.Columns()[pk]),
-		q.Placeholder(1),
-	)
+	old, err := q.auditOld(record)
+	if err != nil {
+		return err
+	}
 
-	res, err := q.Exec(query, record.PKValue())
+	table := record.Table()
+	query, args, now := q.deleteQuery(record)
+	res, err := q.execView(table.Name(), query, args...)
 	if err != nil {
 		return err
 	}
@@ -235,21 +1000,166 @@ func (q *Querier) Delete(record Record) error {
 		return ErrNoRows
 	}
 	if ra > 1 {
+		if q.multipleRowsError {
+			return ErrMultipleRows
+		}
 		panic(fmt.Errorf("reform: %d rows by DELETE by primary key. Please report this bug.", ra))
 	}
-	return nil
+	if now != nil {
+		_, index := q.softDeleteColumn(table)
+		*record.Pointers()[index].(**time.Time) = now
+	}
+	if err = q.audit(AuditDelete, record, old, now == nil); err != nil {
+		return err
+	}
+	return q.afterDelete(record)
+}
+
+// deleteQuery returns DELETE query and its arguments for row specified by primary key.
+// For SoftDeleteTable (unless querier is Unscoped) it returns UPDATE query instead,
+// and soft delete timestamp set by it; otherwise, that timestamp is nil.
+func (q *Querier) deleteQuery(record Record) (string, []interface{}, *time.Time) {
+	table := record.Table()
+	column, _ := q.softDeleteColumn(table)
+	if column == "" {
+		where, args := q.tenantCondition(table, q.pkCondition(table, 1), pkValues(record))
+		where, args = q.subtypeCondition(table, where, args)
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s",
+			q.QualifiedView(table),
+			where,
+		)
+		return query, args, nil
+	}
+
+	now := softDeleteTime()
+	where, args := q.tenantCondition(table, q.pkCondition(table, 2), append([]interface{}{now}, pkValues(record)...))
+	where, args = q.subtypeCondition(table, where, args)
+	query := fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s AND %s IS NULL",
+		q.QualifiedView(table),
+		q.QuoteIdentifier(column),
+		q.Placeholder(1),
+		where,
+		q.QuoteIdentifier(column),
+	)
+	return query, args, &now
+}
+
+// returningKeyword returns "RETURNING" or "THEN RETURN" keyword for dialects supporting it, or empty string.
+func (q *Querier) returningKeyword() string {
+	switch q.LastInsertIdMethod() {
+	case Returning:
+		return "RETURNING"
+	case ThenReturn:
+		return "THEN RETURN"
+	default:
+		return ""
+	}
+}
+
+// returning adds RETURNING (or THEN RETURN) clause with all view's columns to query and scans the first result to str.
+// It returns ErrNoRows if there are no rows in result.
+func (q *Querier) returning(str Struct, query string, args []interface{}) error {
+	view := str.View()
+	columns := view.Columns()
+	for i, c := range columns {
+		columns[i] = q.QuoteIdentifier(c)
+	}
+	query += " " + q.returningKeyword() + " " + strings.Join(columns, ", ")
+
+	pointers, row, err := q.pointers(str)
+	if err != nil {
+		return err
+	}
+	err = q.scanRow(view.Name(), query, args, pointers...)
+	if err == sql.ErrNoRows {
+		return ErrNoRows
+	}
+	if err != nil {
+		return q.wrapError(err)
+	}
+	return row.decrypt(str)
+}
+
+// DeleteReturning is like Delete, but also sets all record's fields to values stored in SQL database
+// before delete (or after soft delete for SoftDeleteTable).
+// For dialects with Returning or ThenReturn LastInsertIdMethod it uses a single query with RETURNING clause;
+// for other dialects it reloads record before deleting it.
+//
+// Method returns ErrNoRows if no rows were deleted.
+// Method returns ErrNoPK if primary key is not set.
+func (q *Querier) DeleteReturning(record Record) error {
+	if !record.HasPK() {
+		return ErrNoPK
+	}
+
+	if q.returningKeyword() == "" {
+		if err := q.Reload(record); err != nil {
+			return err
+		}
+		return q.Delete(record)
+	}
+
+	old, err := q.auditOld(record)
+	if err != nil {
+		return err
+	}
+	query, args, now := q.deleteQuery(record)
+	if err = q.returning(record, query, args); err != nil {
+		return err
+	}
+	if err = q.audit(AuditDelete, record, old, now == nil); err != nil {
+		return err
+	}
+	return q.afterDelete(record)
+}
+
+// HardDelete deletes record from SQL database table by primary key even if it is SoftDeleteTable.
+// It is a shortcut for q.Unscoped().Delete(record).
+func (q *Querier) HardDelete(record Record) error {
+	return q.Unscoped().Delete(record)
 }
 
 // DeleteFrom deletes rows from view with tail and args and returns a number of deleted rows.
 //
+// For SoftDeleteTable it sets soft delete timestamp column to the current client time instead (like Delete),
+// unless querier is Unscoped.
+// Timestamp of already soft deleted rows is not changed, but they may be included in the returned number,
+// depending on database.
+//
+// Empty tail deletes all rows; querier with WithRequireWhere requires AllRows marker in args for that.
+// For querier with TenantScope only current tenant's rows are deleted, and tail should contain only WHERE clause.
+//
 // Method never returns ErrNoRows.
 func (q *Querier) DeleteFrom(view View, tail string, args ...interface{}) (uint, error) {
+	tail, args, err := q.tenantTail(view, tail, args)
+	if err != nil {
+		return 0, err
+	}
+
 	query := fmt.Sprintf("DELETE FROM %s %s",
-		q.QuoteIdentifier(view.Name()),
+		q.QualifiedView(view),
 		tail,
 	)
+	if column, _ := q.softDeleteColumn(view); column != "" {
+		// check tail before adding soft delete timestamp; that also removes AllRows marker
+		if args, err = q.checkWhere("DELETE "+tail, args); err != nil {
+			return 0, err
+		}
+
+		var p string
+		p, args = q.prependArg(softDeleteTime(), args)
+		column = q.QuoteIdentifier(column)
+		query = fmt.Sprintf("UPDATE %s SET %s = COALESCE(%s, %s) %s",
+			q.QualifiedView(view),
+			column,
+			column,
+			p,
+			tail,
+		)
+		args = append(args, AllRows)
+	}
 
-	res, err := q.Exec(query, args...)
+	res, err := q.execView(view.Name(), query, args...)
 	if err != nil {
 		return 0, err
 	}
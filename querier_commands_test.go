@@ -1,15 +1,17 @@
 package reform_test
 
 import (
-	"errors"
+	"fmt"
 	"time"
 
 	"github.com/AlekSi/pointer"
 	"github.com/enodata/faker"
 
 	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
 	"github.com/AlekSi/reform/dialects/postgresql"
 	. "github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
 )
 
 func (s *ReformSuite) TestInsert() {
@@ -70,6 +72,20 @@ func (s *ReformSuite) TestInsertWithPrimaryKey() {
 	s.Error(err)
 }
 
+func (s *ReformSuite) TestInsertLastInsertId() {
+	f := reformtest.New(mysql.Dialect)
+	defer f.Close()
+
+	person := &Person{Name: "LastInsertId"}
+	s.Require().NoError(f.DB.Insert(person))
+	s.Equal(int32(1), person.ID)
+
+	// LastInsertId is not used for string primary key
+	record := &legacyRecord{PersonID: 1}
+	s.Require().NoError(f.DB.Insert(record))
+	s.Equal("", record.ProjectID)
+}
+
 func (s *ReformSuite) TestInsertReturning() {
 	if s.q.Dialect != postgresql.Dialect {
 		s.T().Skip("only PostgreSQL supports RETURNING syntax, other dialects support only integers from LastInsertId")
@@ -88,6 +104,29 @@ func (s *ReformSuite) TestInsertReturning() {
 	s.Error(err)
 }
 
+func (s *ReformSuite) TestInsertColumns() {
+	email := faker.Internet().Email()
+	person := &Person{Name: "Columns", Email: &email, CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	s.Require().NoError(s.q.InsertColumns(person, "name", "created_at"))
+	s.NotEqual(int32(0), person.ID)
+	s.Require().NoError(s.q.Reload(person))
+	s.Equal("Columns", person.Name)
+	s.Nil(person.Email) // not inserted
+
+	err := s.q.InsertColumns(&Person{}, "name", "foo")
+	s.Equal(&reform.UnexpectedColumnsError{Columns: []string{"foo"}}, err)
+
+	// primary key and automatically set timestamps are always inserted
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+	s.Require().NoError(f.DB.InsertColumns(&Person{ID: 1, Name: "ID"}, "name"))
+	s.Require().NoError(f.DB.InsertColumns(&Memo{Text: "memo"}, "text"))
+	statements := f.Statements()
+	s.Require().Len(statements, 2)
+	s.Equal(`INSERT INTO "people" ("id", "name") VALUES ($1, $2) RETURNING "id"`, statements[0].Query)
+	s.Equal(`INSERT INTO "memos" ("text", "created_at", "updated_at") VALUES ($1, $2, $3) RETURNING "id"`, statements[1].Query)
+}
+
 func (s *ReformSuite) TestInsertIntoView() {
 	pp := &PersonProject{PersonID: 1, ProjectID: "baron"}
 	err := s.q.Insert(pp)
@@ -127,6 +166,33 @@ func (s *ReformSuite) TestUpdate() {
 	s.Equal(&person, person2)
 }
 
+func (s *ReformSuite) TestUpdateNotChanged() {
+	var project Project
+	err := s.q.FindByPrimaryKeyTo(&project, "baron")
+	s.NoError(err)
+
+	// the same values
+	err = s.q.Update(&project)
+	s.NoError(err)
+	changed, err := s.q.UpdateChanged(&project)
+	s.NoError(err)
+	if s.q.Dialect == mysql.Dialect {
+		s.False(changed)
+	} else {
+		s.True(changed)
+	}
+
+	project.Name = "Friendly Baron"
+	changed, err = s.q.UpdateChanged(&project)
+	s.NoError(err)
+	s.True(changed)
+
+	project.ID = "no_such_project"
+	changed, err = s.q.UpdateChanged(&project)
+	s.Equal(reform.ErrNoRows, err)
+	s.False(changed)
+}
+
 func (s *ReformSuite) TestUpdateOverwrite() {
 	newEmail := faker.Internet().Email()
 	person := Person{ID: 102, Email: pointer.ToString(newEmail)}
@@ -173,8 +239,8 @@ func (s *ReformSuite) TestUpdateColumns() {
 
 	person := &Person{ID: 102, Name: newName, Email: &newEmail, CreatedAt: personCreated}
 	for e, columns := range map[error][]string{
-		errors.New("reform: unexpected columns: [foo]"): {"foo"},
-		errors.New("reform: nothing to update"):         {},
+		&reform.UnexpectedColumnsError{Columns: []string{"foo"}}: {"foo"},
+		reform.ErrNothingToUpdate:                                {},
 	} {
 		err := s.q.UpdateColumns(person, columns...)
 		s.Error(err)
@@ -184,6 +250,168 @@ func (s *ReformSuite) TestUpdateColumns() {
 	}
 }
 
+func (s *ReformSuite) TestUpdateDiff() {
+	var person Person
+	s.Require().NoError(s.q.FindByPrimaryKeyTo(&person, 102))
+	original := person.Clone()
+
+	columns, err := s.q.UpdateDiff(&person, original)
+	s.NoError(err)
+	s.Nil(columns)
+	s.Nil(person.UpdatedAt) // hooks are not called
+
+	newEmail := faker.Internet().Email()
+	person.Email = &newEmail
+	person.CreatedAt = person.CreatedAt.In(time.FixedZone("UTC+1", 3600))
+	columns, err = s.q.UpdateDiff(&person, original)
+	s.NoError(err)
+	s.Equal([]string{"email"}, columns)
+	s.Require().NotNil(person.UpdatedAt)
+
+	person2, err := s.q.FindByPrimaryKeyFrom(PersonTable, person.ID)
+	s.NoError(err)
+	s.Equal(&newEmail, person2.(*Person).Email)
+
+	columns, err = reform.ChangedColumns(&Person{ID: 1, Email: &newEmail}, &Person{ID: 2, Name: "n"})
+	s.NoError(err)
+	s.Equal([]string{"name", "email"}, columns)
+	_, err = reform.ChangedColumns(&Person{ID: 1}, &Project{ID: "p"})
+	s.EqualError(err, "reform: ChangedColumns: records of different tables people and projects")
+
+	// fresh copy: UpdatedAt was set by the previous update
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+	original = person.Clone()
+	person.Name = "Diff"
+	columns, err = f.DB.UpdateDiff(&person, original)
+	s.NoError(err)
+	s.Equal([]string{"name"}, columns)
+	statements := f.Statements()
+	s.Require().Len(statements, 1)
+	s.Equal(`UPDATE "people" SET "name" = $1 WHERE "id" = $2`, statements[0].Query)
+}
+
+func (s *ReformSuite) TestUpdateColumnsConstants() {
+	s.Equal("email", PersonColumns.Email)
+	s.Equal("end", ProjectColumns.End)
+
+	var person Person
+	err := s.q.FindByPrimaryKeyTo(&person, 102)
+	s.NoError(err)
+
+	newEmail := faker.Internet().Email()
+	person.Email = &newEmail
+	err = s.q.UpdateColumns(&person, PersonColumns.Email, PersonColumns.UpdatedAt)
+	s.NoError(err)
+
+	person2, err := s.q.FindByPrimaryKeyFrom(PersonTable, person.ID)
+	s.NoError(err)
+	s.Equal(&person, person2)
+
+	// table's column constants
+	s.Equal(PersonColumns, PersonTable.C)
+	tail, args, err := reform.Where(reform.Eq(PersonTable.C.Email, newEmail)).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	person3, err := s.q.SelectOneFrom(PersonTable, tail, args...)
+	s.NoError(err)
+	s.Equal(&person, person3)
+}
+
+func (s *ReformSuite) TestUpdateNonZero() {
+	var person Person
+	err := s.q.UpdateNonZero(&person)
+	s.Equal(reform.ErrNoPK, err)
+
+	newEmail := faker.Internet().Email()
+	person = Person{ID: 102, Email: &newEmail}
+	err = s.q.UpdateNonZero(&person)
+	s.NoError(err)
+
+	person2, err := s.q.FindByPrimaryKeyFrom(PersonTable, person.ID)
+	s.NoError(err)
+	s.Equal("Elfrieda Abbott", person2.(*Person).Name) // not changed
+	s.Equal(&newEmail, person2.(*Person).Email)
+	s.Require().NotNil(person2.(*Person).UpdatedAt)
+	s.WithinDuration(time.Now(), *person2.(*Person).UpdatedAt, time.Second)
+
+	person = Person{ID: 99, Name: "No Such Person"}
+	err = s.q.UpdateNonZero(&person)
+	s.Equal(reform.ErrNoRows, err)
+}
+
+func (s *ReformSuite) TestUpdateReturning() {
+	person, err := s.q.FindByPrimaryKeyFrom(PersonTable, 102)
+	s.Require().NoError(err)
+	person.(*Person).Name = "Returning"
+	s.NoError(s.q.UpdateReturning(person))
+	s.NotNil(person.(*Person).UpdatedAt)
+
+	expected, err := s.q.FindByPrimaryKeyFrom(PersonTable, 102)
+	s.NoError(err)
+	s.Equal(expected, person)
+
+	role1 := &ProjectRole{ProjectID: "baron", PersonID: 102}
+	s.NoError(s.q.Reload(role1))
+	role2 := role1.Clone()
+	role1.Role = "owner"
+	s.NoError(s.q.UpdateReturning(role1))
+	s.Equal(int32(1), role1.Version)
+	s.Equal(reform.ErrStaleRecord, s.q.UpdateReturning(role2))
+
+	s.Equal(reform.ErrNoRows, s.q.UpdateReturning(&Person{ID: 1000, Name: "No"}))
+	s.Equal(reform.ErrNoPK, s.q.UpdateReturning(&Person{Name: "No"}))
+}
+
+func (s *ReformSuite) TestUpdateColumnsWhere() {
+	person := &Person{ID: 102, Name: "Guarded"}
+	condition := "name = " + s.q.Placeholder(1)
+	s.Equal(reform.ErrNoRows, s.q.UpdateColumnsWhere(person, condition, []interface{}{"Wrong"}, "name"))
+	s.NoError(s.q.UpdateColumnsWhere(person, condition, []interface{}{"Elfrieda Abbott"}, "name"))
+	s.Equal(reform.ErrNoRows, s.q.UpdateColumnsWhere(person, condition, []interface{}{"Elfrieda Abbott"}, "name"))
+
+	expected, err := s.q.FindByPrimaryKeyFrom(PersonTable, 102)
+	s.NoError(err)
+	s.Equal("Guarded", expected.(*Person).Name)
+
+	// with version lock
+	role := &ProjectRole{ProjectID: "baron", PersonID: 102}
+	s.NoError(s.q.Reload(role))
+	role.Role = "owner"
+	condition = "role = " + s.q.Placeholder(1)
+	s.Equal(reform.ErrNoRows, s.q.UpdateColumnsWhere(role, condition, []interface{}{"developer"}, "role"))
+	s.NoError(s.q.UpdateColumnsWhere(role, condition, []interface{}{"lead"}, "role"))
+	s.Equal(int32(1), role.Version)
+
+	s.Equal(reform.ErrNoPK, s.q.UpdateColumnsWhere(&Person{}, condition, nil, "name"))
+}
+
+func (s *ReformSuite) TestUpdateExpr() {
+	person := &Person{ID: 102}
+	s.NoError(s.q.UpdateExpr(person, map[string]reform.Expr{"name": reform.Raw("UPPER(name)")}))
+	s.Equal("ELFRIEDA ABBOTT", person.Name)
+	s.Equal(pointer.ToString("elfrieda_abbott@example.org"), person.Email)
+
+	expected, err := s.q.FindByPrimaryKeyFrom(PersonTable, 102)
+	s.NoError(err)
+	s.Equal(expected, person)
+
+	s.Equal(reform.ErrNothingToUpdate, s.q.UpdateExpr(person, nil))
+	s.Equal(&reform.UnexpectedColumnsError{Columns: []string{"views"}}, s.q.UpdateExpr(person, map[string]reform.Expr{"views": reform.Inc("views", 1)}))
+	s.Equal(reform.ErrNoRows, s.q.UpdateExpr(&Person{ID: 1000}, map[string]reform.Expr{"name": reform.Raw("name")}))
+	s.Equal(reform.ErrNoPK, s.q.UpdateExpr(&Person{}, map[string]reform.Expr{"name": reform.Raw("name")}))
+
+	// arguments are numbered before primary key
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+	s.Require().NoError(f.Stub(PersonTable, &Person{ID: 1}))
+	s.NoError(f.DB.UpdateExpr(&Person{ID: 1}, map[string]reform.Expr{"name": reform.Inc("name", 2), "email": reform.Dec("email", 3)}))
+	commands := f.Commands(PersonTable)
+	s.Require().Len(commands, 1)
+	s.Equal(`UPDATE "people" SET "name" = "name" + $1, "email" = "email" - $2 WHERE "id" = $3 RETURNING `+
+		`"id", "name", "email", "created_at", "updated_at"`, commands[0].Query)
+	s.Equal([]interface{}{2, 3, int32(1)}, commands[0].Args)
+}
+
 func (s *ReformSuite) TestSave() {
 	newName := faker.Name().Name()
 	person := &Person{Name: newName}
@@ -230,6 +458,21 @@ func (s *ReformSuite) TestDelete() {
 	s.Equal(reform.ErrNoRows, err)
 }
 
+func (s *ReformSuite) TestDeleteReturning() {
+	person := &Person{ID: 1}
+	s.NoError(s.q.DeleteReturning(person))
+	s.Equal("Denis Mills", person.Name)
+	s.Equal(reform.ErrNoRows, s.q.Reload(person))
+	s.Equal(reform.ErrNoRows, s.q.DeleteReturning(person))
+
+	memo := &Memo{ID: 1}
+	s.NoError(s.q.DeleteReturning(memo))
+	s.NotEmpty(memo.Text)
+	s.NotNil(memo.DeletedAt)
+
+	s.Equal(reform.ErrNoPK, s.q.DeleteReturning(&Person{}))
+}
+
 func (s *ReformSuite) TestDeleteFrom() {
 	ra, err := s.q.DeleteFrom(PersonTable, "WHERE email IS NULL")
 	s.NoError(err)
@@ -252,3 +495,297 @@ func (s *ReformSuite) TestDeleteFrom() {
 	s.Error(err)
 	s.Equal(uint(0), ra)
 }
+
+func (s *ReformSuite) TestDeleteFromRequireWhere() {
+	q := s.q.WithRequireWhere()
+
+	ra, err := q.DeleteFrom(ProjectTable, "")
+	s.Equal(reform.ErrNoWhere, err)
+	s.Equal(uint(0), ra)
+
+	_, err = q.Exec("/* tag */ update people SET name = name")
+	s.Equal(reform.ErrNoWhere, err)
+
+	ra, err = q.DeleteFrom(ProjectTable, "WHERE id = "+q.Placeholder(1), "baron")
+	s.NoError(err)
+	s.Equal(uint(1), ra)
+
+	// check is best-effort: trivial condition is not rejected
+	ra, err = q.DeleteFrom(ProjectTable, "WHERE 1 = 0")
+	s.NoError(err)
+	s.Equal(uint(0), ra)
+
+	ra, err = q.DeleteFrom(ProjectTable, "", reform.AllRows)
+	s.NoError(err)
+	s.Equal(uint(4), ra)
+
+	// marker is ignored without WithRequireWhere
+	_, err = s.q.Exec("UPDATE people SET name = name", reform.AllRows)
+	s.NoError(err)
+}
+
+// legacyTable is a hand-written reform.Table for person_project table with non-unique "primary key" project_id.
+type legacyTable struct{}
+
+func (legacyTable) Name() string             { return "person_project" }
+func (legacyTable) Columns() []string        { return []string{"person_id", "project_id"} }
+func (legacyTable) NewStruct() reform.Struct { return new(legacyRecord) }
+func (legacyTable) NewRecord() reform.Record { return new(legacyRecord) }
+func (legacyTable) PKColumnIndex() uint      { return 1 }
+
+// legacyRecord is a hand-written reform.Record for legacyTable.
+type legacyRecord struct {
+	PersonID  int32
+	ProjectID string
+}
+
+func (r legacyRecord) String() string           { return fmt.Sprintf("%d %s", r.PersonID, r.ProjectID) }
+func (r *legacyRecord) Values() []interface{}   { return []interface{}{r.PersonID, r.ProjectID} }
+func (r *legacyRecord) Pointers() []interface{} { return []interface{}{&r.PersonID, &r.ProjectID} }
+func (r *legacyRecord) View() reform.View       { return legacyTable{} }
+func (r *legacyRecord) Table() reform.Table     { return legacyTable{} }
+func (r *legacyRecord) PKValue() interface{}    { return r.ProjectID }
+func (r *legacyRecord) PKPointer() interface{}  { return &r.ProjectID }
+func (r *legacyRecord) HasPK() bool             { return r.ProjectID != "" }
+func (r *legacyRecord) SetPK(pk interface{})    { r.ProjectID = pk.(string) }
+
+func (s *ReformSuite) TestUpsert() {
+	project := &Project{ID: "baron", Name: "Upserted Baron", Start: baronStart}
+	s.NoError(s.q.Upsert(project))
+	s.NoError(s.q.Reload(project))
+	s.Equal("Upserted Baron", project.Name)
+	s.Nil(project.End)
+
+	project = &Project{ID: "upserted", Name: "Upserted Project", Start: queenStart}
+	s.NoError(s.q.Upsert(project, ProjectColumns.ID))
+	s.NoError(s.q.Reload(project))
+	s.Equal("Upserted Project", project.Name)
+
+	createdAt := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	person := &Person{Name: "Upserted Person", CreatedAt: createdAt}
+	s.NoError(s.q.Upsert(person))
+	s.True(person.HasPK())
+	id := person.ID
+
+	person.Name = "Upserted Person 2"
+	s.NoError(s.q.Upsert(person))
+	s.Equal(id, person.ID)
+	s.NoError(s.q.Reload(person))
+	s.Equal("Upserted Person 2", person.Name)
+	s.Equal(createdAt, person.CreatedAt)
+	s.Nil(person.UpdatedAt, "BeforeUpdate should not be called")
+
+	// BeforeInsert is called for updated row too, so it overwrites creation time
+	person = &Person{ID: id, Name: "Upserted Person 3"}
+	s.NoError(s.q.Upsert(person))
+	s.NoError(s.q.Reload(person))
+	s.Equal("Upserted Person 3", person.Name)
+	s.NotEqual(createdAt, person.CreatedAt)
+}
+
+func (s *ReformSuite) TestCompositePK() {
+	role, err := s.q.FindByPrimaryKeyFrom(ProjectRoleTable, []interface{}{"baron", int32(102)})
+	s.Require().NoError(err)
+	s.Equal(&ProjectRole{ProjectID: "baron", PersonID: 102, Role: "lead"}, role)
+
+	_, err = s.q.FindByPrimaryKeyFrom(ProjectRoleTable, "baron")
+	s.EqualError(err, "reform: project_roles has composite primary key, pk should be []interface{} with 2 values")
+
+	r := role.(*ProjectRole)
+	r.Role = "owner"
+	s.NoError(s.q.Update(r))
+	s.NoError(s.q.Reload(r))
+	s.Equal("owner", r.Role)
+
+	other := &ProjectRole{ProjectID: "baron", PersonID: 103}
+	s.NoError(s.q.Reload(other))
+	s.Equal("developer", other.Role)
+
+	r = &ProjectRole{ProjectID: "queen", PersonID: 102, Role: "lead"}
+	s.Equal(reform.ErrNoRows, s.q.Update(r))
+	s.NoError(s.q.Save(r))
+	s.NoError(s.q.Reload(r))
+
+	r.Role = "developer"
+	s.NoError(s.q.Upsert(r))
+	s.NoError(s.q.Reload(r))
+	s.Equal("developer", r.Role)
+
+	s.NoError(s.q.Delete(r))
+	s.Equal(reform.ErrNoRows, s.q.Reload(r))
+	s.Equal(reform.ErrNoRows, s.q.Delete(r))
+	s.Equal(reform.ErrNoPK, s.q.Delete(&ProjectRole{ProjectID: "queen"}))
+}
+
+func (s *ReformSuite) TestAfterHooks() {
+	role := &ProjectRole{ProjectID: "queen", PersonID: 102, Role: "lead"}
+	s.NoError(s.q.Insert(role))
+	s.NoError(s.q.UpdateColumns(role, ProjectRoleColumns.Role))
+	s.NoError(s.q.Delete(role))
+	s.Equal([]string{"AfterInsert", "AfterUpdate", "AfterDelete"}, role.Hooks)
+
+	s.Equal(reform.ErrNoRows, s.q.Update(role))
+	s.Len(role.Hooks, 3) // not called on error
+
+	roles := []reform.Struct{
+		&ProjectRole{ProjectID: "queen", PersonID: 102, Role: "lead"},
+		&ProjectRole{ProjectID: "queen", PersonID: 103, Role: "developer"},
+	}
+	s.NoError(s.q.InsertMulti(roles...))
+	for _, r := range roles {
+		s.Equal([]string{"AfterInsert"}, r.(*ProjectRole).Hooks)
+	}
+}
+
+// txHookedProject is a Project with hooks which run queries with given Querier.
+type txHookedProject struct {
+	*Project
+	members int // set by BeforeUpdateTx
+}
+
+func (p *txHookedProject) BeforeInsertTx(q *reform.Querier) error {
+	return p.Project.BeforeInsert()
+}
+
+func (p *txHookedProject) AfterInsertTx(q *reform.Querier) error {
+	return q.Insert(&PersonProject{PersonID: 101, ProjectID: p.ID})
+}
+
+func (p *txHookedProject) BeforeUpdateTx(q *reform.Querier) error {
+	n, err := q.Count(PersonProjectView, "WHERE project_id = "+q.Placeholder(1), p.ID)
+	p.members = int(n)
+	return err
+}
+
+func (p *txHookedProject) AfterDeleteTx(q *reform.Querier) error {
+	_, err := q.DeleteFrom(PersonProjectView, "WHERE project_id = "+q.Placeholder(1), p.ID)
+	return err
+}
+
+func (s *ReformSuite) TestTxHooks() {
+	project := &txHookedProject{Project: &Project{ID: "hooked", Name: "Hooked", Start: time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))}}
+	s.NoError(s.q.Insert(project))
+	s.Equal(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), project.Start) // BeforeInsertTx was called
+	count, err := s.q.Count(PersonProjectView, "WHERE project_id = "+s.q.Placeholder(1), "hooked")
+	s.NoError(err)
+	s.Equal(uint(1), count)
+
+	s.NoError(s.q.Update(project))
+	s.Equal(1, project.members)
+
+	s.NoError(s.q.Delete(project))
+	count, err = s.q.Count(PersonProjectView, "WHERE project_id = "+s.q.Placeholder(1), "hooked")
+	s.NoError(err)
+	s.Equal(uint(0), count)
+}
+
+func (s *ReformSuite) TestOptimisticLocking() {
+	role1 := &ProjectRole{ProjectID: "baron", PersonID: 102}
+	s.NoError(s.q.Reload(role1))
+	role2 := role1.Clone()
+	s.Equal(int32(0), role1.Version)
+
+	role1.Role = "owner"
+	s.NoError(s.q.Update(role1))
+	s.Equal(int32(1), role1.Version)
+
+	role2.Role = "manager"
+	s.Equal(reform.ErrStaleRecord, s.q.Update(role2))
+	s.Equal(reform.ErrStaleRecord, s.q.UpdateColumns(role2, ProjectRoleColumns.Role))
+	s.Equal(int32(0), role2.Version)
+
+	s.NoError(s.q.Reload(role2))
+	s.Equal("owner", role2.Role)
+	s.Equal(int32(1), role2.Version)
+
+	role2.Version = 42 // ignored, always incremented
+	role2.Role = "manager"
+	s.Equal(reform.ErrStaleRecord, s.q.UpdateColumns(role2, ProjectRoleColumns.Role, ProjectRoleColumns.Version))
+	role2.Version = 1
+	s.NoError(s.q.UpdateColumns(role2, ProjectRoleColumns.Role, ProjectRoleColumns.Version))
+	s.Equal(int32(2), role2.Version)
+
+	s.Equal(reform.ErrNoRows, s.q.Update(&ProjectRole{ProjectID: "queen", PersonID: 101}))
+}
+
+func (s *ReformSuite) TestSoftDelete() {
+	memos, err := s.q.SelectAllFrom(MemoTable, "")
+	s.NoError(err)
+	s.Len(memos, 1)
+	memos, err = s.q.Unscoped().SelectAllFrom(MemoTable, "ORDER BY "+s.q.QuoteIdentifier("memos")+"."+s.q.QuoteIdentifier("id"))
+	s.NoError(err)
+	s.Len(memos, 2)
+
+	_, err = s.q.FindByPrimaryKeyFrom(MemoTable, 2)
+	s.Equal(reform.ErrNoRows, err)
+
+	memo := &Memo{Text: "soft"}
+	s.NoError(s.q.Insert(memo))
+	s.NoError(s.q.Delete(memo))
+	s.NotNil(memo.DeletedAt)
+	s.Equal(reform.ErrNoRows, s.q.Reload(memo))
+	s.Equal(reform.ErrNoRows, s.q.Delete(memo))
+	s.NoError(s.q.Unscoped().Reload(memo))
+	s.NotNil(memo.DeletedAt)
+
+	s.NoError(s.q.HardDelete(memo))
+	s.Equal(reform.ErrNoRows, s.q.Unscoped().Reload(memo))
+
+	n, err := s.q.DeleteFrom(MemoTable, "WHERE text = "+s.q.Placeholder(1), "active")
+	s.NoError(err)
+	s.Equal(uint(1), n)
+	memos, err = s.q.SelectAllFrom(MemoTable, "")
+	s.NoError(err)
+	s.Empty(memos)
+	memos, err = s.q.Unscoped().SelectAllFrom(MemoTable, "")
+	s.NoError(err)
+	s.Len(memos, 2)
+
+	// DeleteFrom uses the same client clock as Delete, not database's one
+	f := reformtest.New(s.q.Dialect)
+	_, err = f.DB.DeleteFrom(MemoTable, "", reform.AllRows)
+	s.NoError(err)
+	statements := f.Statements()
+	s.Require().Len(statements, 1)
+	s.NotContains(statements[0].Query, "CURRENT_TIMESTAMP")
+	s.Require().Len(statements[0].Args, 1)
+	s.IsType(time.Time{}, statements[0].Args[0])
+}
+
+func (s *ReformSuite) TestAutoTimestamps() {
+	memo := &Memo{Text: "auto"}
+	s.NoError(s.q.Insert(memo))
+	s.False(memo.CreatedAt.IsZero())
+	s.Equal(time.UTC, memo.CreatedAt.Location())
+	s.Equal(memo.CreatedAt, memo.CreatedAt.Truncate(time.Second))
+	s.Require().NotNil(memo.UpdatedAt)
+	s.Equal(memo.CreatedAt, *memo.UpdatedAt)
+	createdAt := memo.CreatedAt
+
+	memo.UpdatedAt = nil
+	memo.Text = "auto update"
+	s.NoError(s.q.UpdateColumns(memo, "text"))
+	s.Equal(createdAt, memo.CreatedAt)
+	s.NotNil(memo.UpdatedAt) // autoupdate column is always updated
+
+	memo2 := &Memo{ID: memo.ID}
+	s.NoError(s.q.Reload(memo2))
+	s.NotNil(memo2.UpdatedAt)
+
+	explicit := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	memo = &Memo{Text: "explicit", Timestamps: Timestamps{CreatedAt: explicit}}
+	s.NoError(s.q.Insert(memo))
+	s.Equal(explicit, memo.CreatedAt) // autocreate column is set only if zero
+}
+
+func (s *ReformSuite) TestMultipleRows() {
+	record := &legacyRecord{PersonID: 101, ProjectID: "baron"}
+	s.Panics(func() {
+		s.q.Delete(record)
+	})
+
+	s.RestartTransaction()
+
+	err := s.q.WithMultipleRowsError().Delete(record)
+	s.Equal(reform.ErrMultipleRows, err)
+}
@@ -0,0 +1,250 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Context variants of Querier methods. They are shortcuts for q.WithContext(ctx).Method(...):
+// context cancellation or deadline aborts query execution.
+
+// ExecContext executes a query without returning any rows with given context.
+// The args are for any placeholder parameters in the query.
+func (q *Querier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return q.WithContext(ctx).Exec(query, args...)
+}
+
+// QueryContext executes a query that returns rows, typically a SELECT, with given context.
+// The args are for any placeholder parameters in the query.
+func (q *Querier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return q.WithContext(ctx).Query(query, args...)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row with given context.
+// QueryRowContext always returns a non-nil value. Errors are deferred until Row's Scan method is called.
+func (q *Querier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return q.WithContext(ctx).QueryRow(query, args...)
+}
+
+// ExecNamedContext is a Context variant of ExecNamed.
+func (q *Querier) ExecNamedContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	return q.WithContext(ctx).ExecNamed(query, arg)
+}
+
+// QueryNamedContext is a Context variant of QueryNamed.
+func (q *Querier) QueryNamedContext(ctx context.Context, query string, arg interface{}) (*sql.Rows, error) {
+	return q.WithContext(ctx).QueryNamed(query, arg)
+}
+
+// InsertContext is a Context variant of Insert.
+func (q *Querier) InsertContext(ctx context.Context, str Struct) error {
+	return q.WithContext(ctx).Insert(str)
+}
+
+// InsertColumnsContext is a Context variant of InsertColumns.
+func (q *Querier) InsertColumnsContext(ctx context.Context, str Struct, columns ...string) error {
+	return q.WithContext(ctx).InsertColumns(str, columns...)
+}
+
+// InsertMultiContext is a Context variant of InsertMulti.
+func (q *Querier) InsertMultiContext(ctx context.Context, structs ...Struct) error {
+	return q.WithContext(ctx).InsertMulti(structs...)
+}
+
+// UpdateContext is a Context variant of Update.
+func (q *Querier) UpdateContext(ctx context.Context, record Record) error {
+	return q.WithContext(ctx).Update(record)
+}
+
+// UpdateColumnsContext is a Context variant of UpdateColumns.
+func (q *Querier) UpdateColumnsContext(ctx context.Context, record Record, columns ...string) error {
+	return q.WithContext(ctx).UpdateColumns(record, columns...)
+}
+
+// UpdateDiffContext is a Context variant of UpdateDiff.
+func (q *Querier) UpdateDiffContext(ctx context.Context, record, original Record) ([]string, error) {
+	return q.WithContext(ctx).UpdateDiff(record, original)
+}
+
+// UpdateColumnsWhereContext is a Context variant of UpdateColumnsWhere.
+func (q *Querier) UpdateColumnsWhereContext(ctx context.Context, record Record, condition string, args []interface{}, columns ...string) error {
+	return q.WithContext(ctx).UpdateColumnsWhere(record, condition, args, columns...)
+}
+
+// UpdateExprContext is a Context variant of UpdateExpr.
+func (q *Querier) UpdateExprContext(ctx context.Context, record Record, exprs map[string]Expr) error {
+	return q.WithContext(ctx).UpdateExpr(record, exprs)
+}
+
+// UpdateNonZeroContext is a Context variant of UpdateNonZero.
+func (q *Querier) UpdateNonZeroContext(ctx context.Context, record Record) error {
+	return q.WithContext(ctx).UpdateNonZero(record)
+}
+
+// UpsertContext is a Context variant of Upsert.
+func (q *Querier) UpsertContext(ctx context.Context, record Record, conflictColumns ...string) error {
+	return q.WithContext(ctx).Upsert(record, conflictColumns...)
+}
+
+// SaveContext is a Context variant of Save.
+func (q *Querier) SaveContext(ctx context.Context, record Record) error {
+	return q.WithContext(ctx).Save(record)
+}
+
+// DeleteContext is a Context variant of Delete.
+func (q *Querier) DeleteContext(ctx context.Context, record Record) error {
+	return q.WithContext(ctx).Delete(record)
+}
+
+// HardDeleteContext is a Context variant of HardDelete.
+func (q *Querier) HardDeleteContext(ctx context.Context, record Record) error {
+	return q.WithContext(ctx).HardDelete(record)
+}
+
+// DeleteFromContext is a Context variant of DeleteFrom.
+func (q *Querier) DeleteFromContext(ctx context.Context, view View, tail string, args ...interface{}) (uint, error) {
+	return q.WithContext(ctx).DeleteFrom(view, tail, args...)
+}
+
+// TruncateContext is a Context variant of Truncate.
+func (q *Querier) TruncateContext(ctx context.Context, view View, options ...TruncateOption) error {
+	return q.WithContext(ctx).Truncate(view, options...)
+}
+
+// SelectOneToContext is a Context variant of SelectOneTo.
+func (q *Querier) SelectOneToContext(ctx context.Context, str Struct, tail string, args ...interface{}) error {
+	return q.WithContext(ctx).SelectOneTo(str, tail, args...)
+}
+
+// SelectOneFromContext is a Context variant of SelectOneFrom.
+func (q *Querier) SelectOneFromContext(ctx context.Context, view View, tail string, args ...interface{}) (Struct, error) {
+	return q.WithContext(ctx).SelectOneFrom(view, tail, args...)
+}
+
+// SelectOneColumnsToContext is a Context variant of SelectOneColumnsTo.
+func (q *Querier) SelectOneColumnsToContext(ctx context.Context, str Struct, columns []string, tail string, args ...interface{}) error {
+	return q.WithContext(ctx).SelectOneColumnsTo(str, columns, tail, args...)
+}
+
+// SelectOneColumnsFromContext is a Context variant of SelectOneColumnsFrom.
+func (q *Querier) SelectOneColumnsFromContext(ctx context.Context, view View, columns []string, tail string, args ...interface{}) (Struct, error) {
+	return q.WithContext(ctx).SelectOneColumnsFrom(view, columns, tail, args...)
+}
+
+// SelectAllColumnsFromContext is a Context variant of SelectAllColumnsFrom.
+func (q *Querier) SelectAllColumnsFromContext(ctx context.Context, view View, columns []string, tail string, args ...interface{}) ([]Struct, error) {
+	return q.WithContext(ctx).SelectAllColumnsFrom(view, columns, tail, args...)
+}
+
+// SelectIntoContext is a Context variant of SelectInto.
+func (q *Querier) SelectIntoContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.WithContext(ctx).SelectInto(dest, query, args...)
+}
+
+// SelectRowsContext is a Context variant of SelectRows.
+func (q *Querier) SelectRowsContext(ctx context.Context, view View, tail string, args ...interface{}) (*sql.Rows, error) {
+	return q.WithContext(ctx).SelectRows(view, tail, args...)
+}
+
+// IterateContext is a Context variant of Iterate.
+func (q *Querier) IterateContext(ctx context.Context, view View, tail string, args ...interface{}) (*Iterator, error) {
+	return q.WithContext(ctx).Iterate(view, tail, args...)
+}
+
+// SelectPageContext is a Context variant of SelectPage.
+func (q *Querier) SelectPageContext(ctx context.Context, view View, cursor Cursor) ([]Struct, string, error) {
+	return q.WithContext(ctx).SelectPage(view, cursor)
+}
+
+// SelectAllFromContext is a Context variant of SelectAllFrom.
+func (q *Querier) SelectAllFromContext(ctx context.Context, view View, tail string, args ...interface{}) ([]Struct, error) {
+	return q.WithContext(ctx).SelectAllFrom(view, tail, args...)
+}
+
+// CountContext is a Context variant of Count.
+func (q *Querier) CountContext(ctx context.Context, view View, tail string, args ...interface{}) (uint, error) {
+	return q.WithContext(ctx).Count(view, tail, args...)
+}
+
+// ExistsContext is a Context variant of Exists.
+func (q *Querier) ExistsContext(ctx context.Context, view View, tail string, args ...interface{}) (bool, error) {
+	return q.WithContext(ctx).Exists(view, tail, args...)
+}
+
+// FindOneToContext is a Context variant of FindOneTo.
+func (q *Querier) FindOneToContext(ctx context.Context, str Struct, column string, arg interface{}) error {
+	return q.WithContext(ctx).FindOneTo(str, column, arg)
+}
+
+// FindOneFromContext is a Context variant of FindOneFrom.
+func (q *Querier) FindOneFromContext(ctx context.Context, view View, column string, arg interface{}) (Struct, error) {
+	return q.WithContext(ctx).FindOneFrom(view, column, arg)
+}
+
+// FindRowsContext is a Context variant of FindRows.
+func (q *Querier) FindRowsContext(ctx context.Context, view View, column string, arg interface{}) (*sql.Rows, error) {
+	return q.WithContext(ctx).FindRows(view, column, arg)
+}
+
+// FindAllFromContext is a Context variant of FindAllFrom.
+func (q *Querier) FindAllFromContext(ctx context.Context, view View, column string, args ...interface{}) ([]Struct, error) {
+	return q.WithContext(ctx).FindAllFrom(view, column, args...)
+}
+
+// FindAllGroupedContext is a Context variant of FindAllGrouped.
+func (q *Querier) FindAllGroupedContext(ctx context.Context, view View, column string, values ...interface{}) (GroupedStructs, error) {
+	return q.WithContext(ctx).FindAllGrouped(view, column, values...)
+}
+
+// FindByPrimaryKeyToContext is a Context variant of FindByPrimaryKeyTo.
+func (q *Querier) FindByPrimaryKeyToContext(ctx context.Context, record Record, pk interface{}) error {
+	return q.WithContext(ctx).FindByPrimaryKeyTo(record, pk)
+}
+
+// FindByPrimaryKeyFromContext is a Context variant of FindByPrimaryKeyFrom.
+func (q *Querier) FindByPrimaryKeyFromContext(ctx context.Context, table Table, pk interface{}) (Record, error) {
+	return q.WithContext(ctx).FindByPrimaryKeyFrom(table, pk)
+}
+
+// FindByPrimaryKeyForUpdateToContext is a Context variant of FindByPrimaryKeyForUpdateTo.
+func (q *Querier) FindByPrimaryKeyForUpdateToContext(ctx context.Context, record Record, pk interface{}) error {
+	return q.WithContext(ctx).FindByPrimaryKeyForUpdateTo(record, pk)
+}
+
+// ReloadContext is a Context variant of Reload.
+func (q *Querier) ReloadContext(ctx context.Context, record Record) error {
+	return q.WithContext(ctx).Reload(record)
+}
+
+// check interface
+var _ DBTXContext = new(Querier)
+
+// UpdateColumnsAllContext is a Context variant of UpdateColumnsAll.
+func (q *Querier) UpdateColumnsAllContext(ctx context.Context, records []Record, columns ...string) error {
+	return q.WithContext(ctx).UpdateColumnsAll(records, columns...)
+}
+
+// SaveAllContext is a Context variant of SaveAll.
+func (q *Querier) SaveAllContext(ctx context.Context, records []Record, strategy ConflictStrategy) error {
+	return q.WithContext(ctx).SaveAll(records, strategy)
+}
+
+// DeleteAllContext is a Context variant of DeleteAll.
+func (q *Querier) DeleteAllContext(ctx context.Context, records ...Record) (uint, error) {
+	return q.WithContext(ctx).DeleteAll(records...)
+}
+
+// UpdateReturningContext is a Context variant of UpdateReturning.
+func (q *Querier) UpdateReturningContext(ctx context.Context, record Record) error {
+	return q.WithContext(ctx).UpdateReturning(record)
+}
+
+// DeleteReturningContext is a Context variant of DeleteReturning.
+func (q *Querier) DeleteReturningContext(ctx context.Context, record Record) error {
+	return q.WithContext(ctx).DeleteReturning(record)
+}
+
+// CopyFromContext is a Context variant of CopyFrom.
+func (q *Querier) CopyFromContext(ctx context.Context, view View, source CopySource) (int64, error) {
+	return q.WithContext(ctx).CopyFrom(view, source)
+}
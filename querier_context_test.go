@@ -0,0 +1,59 @@
+package reform_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestContextCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.q.FindByPrimaryKeyFromContext(ctx, PersonTable, 1)
+	s.Equal(context.Canceled, err)
+
+	err = s.q.InsertContext(ctx, &Person{Name: "Canceled"})
+	s.Equal(context.Canceled, err)
+
+	_, err = s.q.ExecContext(ctx, "SELECT 1")
+	s.Equal(context.Canceled, err)
+
+	person, err := s.q.FindByPrimaryKeyFromContext(context.Background(), PersonTable, 1)
+	s.NoError(err)
+	s.Equal(int32(1), person.(*Person).ID)
+}
+
+func (s *ReformSuite) TestWithTimeout() {
+	rl := reform.NewRecordingLogger()
+	s.q.Logger = rl
+	q := s.q.WithTimeout(time.Minute)
+
+	person, err := q.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.NoError(err)
+	s.Equal(int32(1), person.(*Person).ID)
+	persons, err := q.SelectAllFrom(PersonTable, "")
+	s.NoError(err)
+	s.NotEmpty(persons)
+	s.NoError(q.Insert(&Person{Name: "Timeout"}))
+
+	statements := rl.Statements()
+	s.Require().Len(statements, 3)
+	if s.q.Dialect == mysql.Dialect {
+		s.True(strings.HasPrefix(statements[0].Query, "SELECT /*+ MAX_EXECUTION_TIME(60000) */ "), "%s", statements[0].Query)
+	}
+
+	_, err = s.q.WithTimeout(time.Nanosecond).FindByPrimaryKeyFrom(PersonTable, 1)
+	s.True(errors.Is(err, context.DeadlineExceeded), "%+v", err)
+
+	s.RestartTransaction()
+
+	s.NoError(s.q.SetStatementTimeout(time.Minute))
+	_, err = s.q.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.NoError(err)
+}
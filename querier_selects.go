@@ -1,17 +1,149 @@
 package reform
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 )
 
-// selectQuery returns full SELECT query for given view and tail.
-func (q *Querier) selectQuery(view View, tail string) string {
-	return fmt.Sprintf("SELECT %s FROM %s %s", strings.Join(q.QualifiedColumns(view), ", "), q.QuoteIdentifier(view.Name()), tail)
+// selectQuery returns full SELECT query and its arguments for given view, tail and args.
+// For SoftDeleteTable soft deleted rows (and other tenants' rows, see WithTenantScope) are filtered out
+// by subquery with the same name, so tail may contain any clauses and qualified column names.
+// Locking clause set by WithRowLock is appended after tail.
+func (q *Querier) selectQuery(view View, tail string, args []interface{}) (string, []interface{}, error) {
+	lock, err := q.rowLockClause()
+	if err != nil {
+		return "", nil, err
+	}
+	from, args := q.from(view, args)
+	return fmt.Sprintf("SELECT %s FROM %s %s%s", strings.Join(q.QualifiedColumns(view), ", "), from, tail, lock), args, nil
+}
+
+// from returns FROM clause content for given view and args for query with it, filtering out
+// soft deleted rows for SoftDeleteTable, other tenants' rows for querier with TenantScope,
+// and rows not matching querier's scopes for that view.
+func (q *Querier) from(view View, args []interface{}) (string, []interface{}) {
+	from := q.QualifiedView(view)
+	var conditions []string
+	var prepended int // a number of args prepended for positional placeholders
+	if column, _ := q.softDeleteColumn(view); column != "" {
+		conditions = append(conditions, q.QuoteIdentifier(column)+" IS NULL")
+	}
+	if column, _, value := q.tenantColumn(view); column != "" {
+		var p string
+		p, args = q.prependArg(value, args)
+		conditions = append(conditions, q.QuoteIdentifier(column)+" = "+p)
+		prepended++
+	}
+	for i := range q.scopes {
+		if q.scopes[i].view != view.Name() {
+			continue
+		}
+		var c string
+		c, args = q.scopes[i].apply(q.Dialect, args, prepended)
+		conditions = append(conditions, "("+c+")")
+		prepended += len(q.scopes[i].args)
+	}
+	if conditions != nil {
+		// no AS keyword: Oracle doesn't support it for table aliases
+		from = fmt.Sprintf("(SELECT * FROM %s WHERE %s) %s", from, strings.Join(conditions, " AND "), q.QuoteIdentifier(view.Name()))
+	}
+	return from, args
+}
+
+// Count queries view with tail and args and returns a number of matching rows.
+// Tail should contain only WHERE clause (or be empty). Soft deleted rows of SoftDeleteTable are not counted.
+func (q *Querier) Count(view View, tail string, args ...interface{}) (uint, error) {
+	from, args := q.from(view, args)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", from, tail)
+	var count uint
+	if err := q.scanRow(view.Name(), query, args, &count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Exists queries view with tail and args and returns true if at least one row matches.
+// Tail should contain only WHERE clause (or be empty). Soft deleted rows of SoftDeleteTable are not considered.
+func (q *Querier) Exists(view View, tail string, args ...interface{}) (bool, error) {
+	from, args := q.from(view, args)
+	query := fmt.Sprintf("SELECT 1 FROM %s %s %s", from, tail, limitClause(q.Dialect, 1, false))
+	var one int
+	err := q.scanRow(view.Name(), query, args, &one)
+	switch err {
+	case nil:
+		return true, nil
+	case sql.ErrNoRows:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// scanTarget returns pointers to str fields for scanning given column indexes (nil for all columns, see pointers),
+// and function which should be called after scanning: it decrypts encrypted columns and calls AfterFind hooks.
+func (q *Querier) scanTarget(str Struct, indexes []int) ([]interface{}, func() error, error) {
+	pointers, row, err := q.pointers(str)
+	if err != nil {
+		return nil, nil, err
+	}
+	if indexes != nil {
+		res := make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			res[i] = pointers[index]
+		}
+		pointers = res
+	}
+
+	found := func() error {
+		if err := row.decrypt(str); err != nil {
+			return err
+		}
+		return q.afterFind(str)
+	}
+	return pointers, found, nil
+}
+
+// scanAll scans all remaining rows. For each row, next returns pointers for scanning
+// and function which is called after successful scan.
+// It is a shared scanning path for all methods returning all rows.
+func scanAll(rows *sql.Rows, next func() ([]interface{}, func() error, error)) error {
+	for rows.Next() {
+		pointers, done, err := next()
+		if err != nil {
+			return err
+		}
+		if err = rows.Scan(pointers...); err != nil {
+			return err
+		}
+		if err = done(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// scanStructs scans given column indexes (nil for all columns) of all remaining rows to Structs returned by newStruct;
+// add is called for each Struct after its columns are decrypted and AfterFind hooks are called.
+func (q *Querier) scanStructs(rows *sql.Rows, indexes []int, newStruct func() Struct, add func(Struct)) error {
+	return scanAll(rows, func() ([]interface{}, func() error, error) {
+		str := newStruct()
+		pointers, found, err := q.scanTarget(str, indexes)
+		done := func() error {
+			if err := found(); err != nil {
+				return err
+			}
+			add(str)
+			return nil
+		}
+		return pointers, done, err
+	})
 }
 
-// NextRow scans next result row from rows to str. If str implements AfterFinder, it also calls AfterFind().
+// NextRow scans next result row from rows to str.
+// If str implements AfterFinder or AfterFinderContext, it also calls AfterFind().
 // It is caller's responsibility to call rows.Close().
 //
 // If there is no next result row, it returns ErrNoRows. It also may return rows.Next(), rows.Scan()
@@ -29,37 +161,45 @@ func (q *Querier) NextRow(str Struct, rows *sql.Rows) error {
 		return err
 	}
 
-	err = rows.Scan(str.Pointers()...)
+	pointers, found, err := q.scanTarget(str, nil)
 	if err != nil {
 		return err
 	}
-
-	if af, ok := str.(AfterFinder); ok {
-		err = af.AfterFind()
+	err = rows.Scan(pointers...)
+	if err != nil {
+		return err
 	}
-	return err
+
+	return found()
 }
 
 // SelectOneTo queries str's View with tail and args and scans first result to str.
-// If str implements AfterFinder, it also calls AfterFind().
+// If str implements AfterFinder or AfterFinderContext, it also calls AfterFind().
 //
 // If there are no rows in result, it returns ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
 func (q *Querier) SelectOneTo(str Struct, tail string, args ...interface{}) error {
-	query := q.selectQuery(str.View(), tail)
-	err := q.QueryRow(query, args...).Scan(str.Pointers()...)
+	pointers, found, err := q.scanTarget(str, nil)
 	if err != nil {
 		return err
 	}
-
-	if af, ok := str.(AfterFinder); ok {
-		err = af.AfterFind()
+	query, args, err := q.selectQuery(str.View(), tail, args)
+	if err != nil {
+		return err
 	}
-	return err
+	err = q.scanRow(str.View().Name(), query, args, pointers...)
+	if err == sql.ErrNoRows {
+		return ErrNoRows
+	}
+	if err != nil {
+		return err
+	}
+
+	return found()
 }
 
 // SelectOneFrom queries view with tail and args and scans first result to new Struct str.
-// If str implements AfterFinder, it also calls AfterFind().
+// If str implements AfterFinder or AfterFinderContext, it also calls AfterFind().
 //
 // If there are no rows in result, it returns nil, ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
@@ -74,39 +214,217 @@ func (q *Querier) SelectOneFrom(view View, tail string, args ...interface{}) (St
 
 // SelectRows queries view with tail and args and returns rows. They can then be iterated with NextRow().
 // It is caller's responsibility to call rows.Close().
+// If querier has timeout (see WithTimeout), its context is released by deadline, not by rows.Close().
 //
 // In case of error rows will be nil. Error is never ErrNoRows.
 //
 // See example for ideomatic usage.
 func (q *Querier) SelectRows(view View, tail string, args ...interface{}) (*sql.Rows, error) {
-	query := q.selectQuery(view, tail)
-	return q.Query(query, args...)
+	rows, _, err := q.selectRows(view, tail, args...)
+	return rows, err
+}
+
+// selectRows is SelectRows which also returns function releasing context of query;
+// it should be called after rows are closed.
+func (q *Querier) selectRows(view View, tail string, args ...interface{}) (*sql.Rows, context.CancelFunc, error) {
+	query, args, err := q.selectQuery(view, tail, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return q.queryRows(view.Name(), query, args...)
 }
 
 // SelectAllFrom queries view with tail and args and returns a slice of new Structs.
-// If view's Struct implements AfterFinder, it also calls AfterFind().
+// If view's Struct implements AfterFinder or AfterFinderContext, it also calls AfterFind().
 //
 // In case of query error slice will be nil. If error is encountered during iteration,
 // partial result and error will be returned. Error is never ErrNoRows.
 func (q *Querier) SelectAllFrom(view View, tail string, args ...interface{}) ([]Struct, error) {
-	rows, err := q.SelectRows(view, tail, args...)
+	rows, cancel, err := q.selectRows(view, tail, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer cancel()
 	defer rows.Close()
 
 	var structs []Struct
-	for {
-		str := view.NewStruct()
-		err = q.NextRow(str, rows)
-		if err == ErrNoRows {
-			return structs, nil
-		} else if err != nil {
-			return structs, err
+	err = q.scanStructs(rows, nil, view.NewStruct, func(str Struct) {
+		structs = append(structs, str)
+	})
+	return structs, err
+}
+
+// SelectAllInto queries T's View with tail and args and appends results to dest.
+// If T implements AfterFinder or AfterFinderContext, it also calls AfterFind().
+//
+// Unlike SelectAllFrom, it scans rows directly into elements of dest without allocating
+// a separate struct per row; dest grows as needed, so preallocating it with make([]T, 0, n)
+// avoids reallocations completely.
+// In case of query error dest is not changed. If error is encountered during iteration,
+// partial result is appended to dest and error is returned. Error is never ErrNoRows.
+func SelectAllInto[T any, PT interface {
+	*T
+	Struct
+}](q *Querier, dest *[]T, tail string, args ...interface{}) error {
+	var zero T
+	rows, cancel, err := q.selectRows(PT(&zero).View(), tail, args...)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	res, n := *dest, len(*dest)
+	err = q.scanStructs(rows, nil, func() Struct {
+		res = append(res[:n], zero)
+		return PT(&res[n])
+	}, func(Struct) {
+		n++
+	})
+	*dest = res[:n]
+	return err
+}
+
+// QueryStructs queries T's View with tail and args and returns a slice of new Structs of type T,
+// so there is no need to cast []Struct returned by SelectAllFrom:
+//
+//	persons, err := reform.QueryStructs[*Person](q, "WHERE name = $1", name)
+//
+// T should be a pointer to generated struct (View method is called on its nil value).
+// If T implements AfterFinder or AfterFinderContext, it also calls AfterFind().
+//
+// In case of query error slice will be nil. If error is encountered during iteration,
+// partial result and error will be returned. Error is never ErrNoRows.
+func QueryStructs[T Struct](q *Querier, tail string, args ...interface{}) ([]T, error) {
+	var zero T
+	view := zero.View()
+	rows, cancel, err := q.selectRows(view, tail, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	var structs []T
+	err = q.scanStructs(rows, nil, view.NewStruct, func(str Struct) {
+		structs = append(structs, str.(T))
+	})
+	return structs, err
+}
+
+// columnIndexes returns indexes of given columns in view.
+// It returns *UnexpectedColumnsError if view has no given columns, and error if there are no columns.
+func columnIndexes(view View, columns []string) ([]int, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("reform: no columns to select from %s", view.Name())
+	}
+
+	all := make(map[string]int, len(view.Columns()))
+	for i, c := range view.Columns() {
+		all[c] = i
+	}
+	res := make([]int, len(columns))
+	var unexpected []string
+	for i, c := range columns {
+		index, ok := all[c]
+		if !ok {
+			unexpected = append(unexpected, c)
+			continue
 		}
+		res[i] = index
+	}
+	if unexpected != nil {
+		sort.Strings(unexpected)
+		return nil, &UnexpectedColumnsError{Columns: unexpected}
+	}
+	return res, nil
+}
 
-		structs = append(structs, str)
+// selectColumnsQuery is selectQuery for given columns only.
+func (q *Querier) selectColumnsQuery(view View, columns []string, tail string, args []interface{}) (string, []interface{}, error) {
+	t := q.QuoteIdentifier(view.Name())
+	qualified := make([]string, len(columns))
+	for i, c := range columns {
+		qualified[i] = t + "." + q.QuoteIdentifier(c)
+	}
+	lock, err := q.rowLockClause()
+	if err != nil {
+		return "", nil, err
+	}
+	from, args := q.from(view, args)
+	return fmt.Sprintf("SELECT %s FROM %s %s%s", strings.Join(qualified, ", "), from, tail, lock), args, nil
+}
+
+// SelectOneColumnsTo is like SelectOneTo, but queries only given columns of str's View
+// and scans them to corresponding fields; other fields of str are not changed.
+// It is useful for wide tables and large columns (like blobs) which are not needed.
+// If str implements AfterFinder or AfterFinderContext, it also calls AfterFind().
+//
+// If there are no rows in result, it returns ErrNoRows.
+// Method returns *UnexpectedColumnsError if view has no given columns.
+func (q *Querier) SelectOneColumnsTo(str Struct, columns []string, tail string, args ...interface{}) error {
+	view := str.View()
+	indexes, err := columnIndexes(view, columns)
+	if err != nil {
+		return err
+	}
+	pointers, found, err := q.scanTarget(str, indexes)
+	if err != nil {
+		return err
+	}
+	query, args, err := q.selectColumnsQuery(view, columns, tail, args)
+	if err != nil {
+		return err
+	}
+	err = q.scanRow(view.Name(), query, args, pointers...)
+	if err == sql.ErrNoRows {
+		return ErrNoRows
 	}
+	if err != nil {
+		return err
+	}
+
+	return found()
+}
+
+// SelectOneColumnsFrom is like SelectOneFrom, but queries only given columns of view.
+// Other fields of returned Struct have zero values. See SelectOneColumnsTo.
+func (q *Querier) SelectOneColumnsFrom(view View, columns []string, tail string, args ...interface{}) (Struct, error) {
+	str := view.NewStruct()
+	err := q.SelectOneColumnsTo(str, columns, tail, args...)
+	if err != nil {
+		return nil, err
+	}
+	return str, nil
+}
+
+// SelectAllColumnsFrom is like SelectAllFrom, but queries only given columns of view.
+// Other fields of returned Structs have zero values. See SelectOneColumnsTo.
+//
+// In case of query error slice will be nil. If error is encountered during iteration,
+// partial result and error will be returned. Error is never ErrNoRows.
+// Method returns *UnexpectedColumnsError if view has no given columns.
+func (q *Querier) SelectAllColumnsFrom(view View, columns []string, tail string, args ...interface{}) ([]Struct, error) {
+	indexes, err := columnIndexes(view, columns)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := q.selectColumnsQuery(view, columns, tail, args)
+	if err != nil {
+		return nil, err
+	}
+	rows, cancel, err := q.queryRows(view.Name(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	var structs []Struct
+	err = q.scanStructs(rows, indexes, view.NewStruct, func(str Struct) {
+		structs = append(structs, str)
+	})
+	return structs, err
 }
 
 // findTail returns tail of  SELECT query for given view, column and arg.
@@ -120,14 +438,14 @@ func (q *Querier) findTail(view string, column string, arg interface{}, limit1 b
 	}
 
 	if limit1 {
-		tail += " LIMIT 1"
+		tail += " " + limitClause(q.Dialect, 1, false)
 	}
 
 	return
 }
 
 // FindOneTo queries str's View with column and arg and scans first result to str.
-// If str implements AfterFinder, it also calls AfterFind().
+// If str implements AfterFinder or AfterFinderContext, it also calls AfterFind().
 //
 // If there are no rows in result, it returns ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
@@ -140,7 +458,7 @@ func (q *Querier) FindOneTo(str Struct, column string, arg interface{}) error {
 }
 
 // FindOneFrom queries view with column and arg and scans first result to new Struct str.
-// If str implements AfterFinder, it also calls AfterFind().
+// If str implements AfterFinder or AfterFinderContext, it also calls AfterFind().
 //
 // If there are no rows in result, it returns nil, ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
@@ -167,7 +485,7 @@ func (q *Querier) FindRows(view View, column string, arg interface{}) (*sql.Rows
 }
 
 // FindAllFrom queries view with column and args and returns a slice of new Structs.
-// If view's Struct implements AfterFinder, it also calls AfterFind().
+// If view's Struct implements AfterFinder or AfterFinderContext, it also calls AfterFind().
 //
 // In case of query error slice will be nil. If error is encountered during iteration,
 // partial result and error will be returned. Error is never ErrNoRows.
@@ -179,30 +497,39 @@ func (q *Querier) FindAllFrom(view View, column string, args ...interface{}) ([]
 }
 
 // FindByPrimaryKeyTo queries record's Table with primary key and scans first result to record.
-// If record implements AfterFinder, it also calls AfterFind().
+// If record implements AfterFinder or AfterFinderContext, it also calls AfterFind().
+// For table with composite primary key pk should be []interface{} with values in order of PKColumnIndexes.
 //
 // If there are no rows in result, it returns ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
 func (q *Querier) FindByPrimaryKeyTo(record Record, pk interface{}) error {
-	table := record.Table()
-	return q.FindOneTo(record, table.Columns()[table.PKColumnIndex()], pk)
+	return q.findByPK(record, record.Table(), pk)
 }
 
 // FindByPrimaryKeyFrom queries table with primary key and scans first result to new Record.
-// If record implements AfterFinder, it also calls AfterFind().
+// If record implements AfterFinder or AfterFinderContext, it also calls AfterFind().
+// For table with composite primary key pk should be []interface{} with values in order of PKColumnIndexes.
+//
+// For base table registered with RegisterSubtypes, it returns a record of subtype table for row's discriminator value.
 //
 // If there are no rows in result, it returns nil, ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
 func (q *Querier) FindByPrimaryKeyFrom(table Table, pk interface{}) (Record, error) {
+	if in := lookupInheritance(table); in != nil {
+		return q.findSubtypeByPK(in, pk)
+	}
+
 	record := table.NewRecord()
-	err := q.FindOneTo(record, table.Columns()[table.PKColumnIndex()], pk)
+	err := q.findByPK(record, table, pk)
 	if err != nil {
 		return nil, err
 	}
 	return record, nil
 }
 
-// Reload is a shortcut for FindByPrimaryKeyTo for given record.
+// Reload is a shortcut for FindByPrimaryKeyTo for given record: it selects row by record's primary key
+// into the same record, so there is no need to copy fields from another one.
+// If row doesn't exist (for example, it was deleted), it returns ErrNoRows and record is not changed.
 func (q *Querier) Reload(record Record) error {
-	return q.FindByPrimaryKeyTo(record, record.PKValue())
+	return q.FindByPrimaryKeyTo(record, pkArg(record))
 }
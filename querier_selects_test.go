@@ -1,11 +1,17 @@
 package reform_test
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
 	"time"
 
 	"github.com/AlekSi/pointer"
 
 	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
 	. "github.com/AlekSi/reform/internal/test/models"
 )
 
@@ -32,6 +38,7 @@ func (s *ReformSuite) TestSelectOneTo() {
 	err = s.q.SelectOneTo(&project, "WHERE id IS NULL")
 	s.Equal(expected, project) // expect old value
 	s.Equal(reform.ErrNoRows, err)
+	s.True(errors.Is(err, sql.ErrNoRows))
 
 	err = s.q.SelectOneTo(&project, "WHERE invalid_tail")
 	s.Equal(expected, project) // expect old value
@@ -113,6 +120,227 @@ func (s *ReformSuite) TestSelectAllFrom() {
 	s.NotEqual(reform.ErrNoRows, err)
 }
 
+func (s *ReformSuite) TestSelectAllFromMaterializedView() {
+	if s.q.Dialect != postgresql.Dialect {
+		s.T().Skip("PostgreSQL-specific test")
+	}
+
+	s.Require().NoError(postgresql.RefreshMaterializedView(s.q.Querier, PersonProjectCountView))
+	structs, err := s.q.SelectAllFrom(PersonProjectCountView, "ORDER BY person_id")
+	s.Require().NoError(err)
+	s.Require().NotEmpty(structs)
+	s.Equal(&PersonProjectCount{PersonID: 101, Projects: 1}, structs[0])
+
+	s.Require().NoError(s.q.Insert(&PersonProject{PersonID: 101, ProjectID: "queen"}))
+	s.Require().NoError(postgresql.RefreshMaterializedView(s.q.Querier, PersonProjectCountView))
+	str, err := s.q.SelectOneFrom(PersonProjectCountView, "WHERE person_id = $1", 101)
+	s.Require().NoError(err)
+	s.Equal(&PersonProjectCount{PersonID: 101, Projects: 2}, str)
+}
+
+func (s *ReformSuite) TestSelectColumns() {
+	person := Person{Name: "Old"}
+	err := s.q.SelectOneColumnsTo(&person, []string{"id", "email"}, "WHERE id = "+s.q.Placeholder(1), 102)
+	s.NoError(err)
+	s.Equal(Person{ID: 102, Name: "Old", Email: pointer.ToString("elfrieda_abbott@example.org")}, person)
+
+	str, err := s.q.SelectOneColumnsFrom(ProjectTable, []string{"name"}, "WHERE id = "+s.q.Placeholder(1), "baron")
+	s.NoError(err)
+	s.Equal(&Project{Name: "Vicious Baron"}, str)
+
+	str, err = s.q.SelectOneColumnsFrom(ProjectTable, []string{"name"}, "WHERE id IS NULL")
+	s.Nil(str)
+	s.Equal(reform.ErrNoRows, err)
+
+	structs, err := s.q.SelectAllColumnsFrom(PersonTable, []string{"name", "id"}, "WHERE name = "+s.q.Placeholder(1)+" ORDER BY id", "Elfrieda Abbott")
+	s.NoError(err)
+	s.Equal([]reform.Struct{
+		&Person{ID: 102, Name: "Elfrieda Abbott"},
+		&Person{ID: 103, Name: "Elfrieda Abbott"},
+	}, structs)
+
+	structs, err = s.q.SelectAllColumnsFrom(PersonTable, []string{"id", "age", "bio"}, "")
+	s.Nil(structs)
+	s.Equal(&reform.UnexpectedColumnsError{Columns: []string{"age", "bio"}}, err)
+}
+
+func (s *ReformSuite) TestIterate() {
+	iter, err := s.q.Iterate(PersonTable, "WHERE name = "+s.q.Placeholder(1)+" ORDER BY id", "Elfrieda Abbott")
+	s.Require().NoError(err)
+	var ids []int32
+	var person Person
+	for iter.Next() {
+		s.NoError(iter.Scan(&person))
+		ids = append(ids, person.ID)
+	}
+	s.NoError(iter.Err())
+	s.NoError(iter.Close())
+	s.Equal([]int32{102, 103}, ids)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iter, err = s.q.IterateContext(ctx, PersonTable, "ORDER BY id")
+	s.Require().NoError(err)
+	s.True(iter.Next())
+	s.NoError(iter.Scan(&person))
+	s.Equal(int32(1), person.ID)
+	cancel()
+	s.False(iter.Next())
+	s.Equal(context.Canceled, iter.Err())
+	s.NoError(iter.Close())
+
+	// scan error closes iterator
+	iter, err = s.q.Iterate(PersonTable, "ORDER BY id")
+	s.Require().NoError(err)
+	s.True(iter.Next())
+	err = iter.Scan(new(Project))
+	s.Error(err)
+	s.False(iter.Next())
+	s.Equal(err, iter.Err())
+	s.NoError(iter.Close())
+
+	iter, err = s.q.Iterate(ProjectTable, "WHERE invalid_tail")
+	s.Nil(iter)
+	s.Error(err)
+}
+
+func (s *ReformSuite) TestCount() {
+	count, err := s.q.Count(PersonTable, "")
+	s.NoError(err)
+	s.Equal(uint(5), count)
+
+	tail, args, err := reform.Where(reform.Eq("name", "Elfrieda Abbott")).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	count, err = s.q.Count(PersonTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(2), count)
+
+	count, err = s.q.Count(MemoTable, "")
+	s.NoError(err)
+	s.Equal(uint(1), count) // soft deleted memo is not counted
+	count, err = s.q.Unscoped().Count(MemoTable, "")
+	s.NoError(err)
+	s.Equal(uint(2), count)
+
+	_, err = s.q.Count(PersonTable, "WHERE invalid_tail")
+	s.Error(err)
+}
+
+func (s *ReformSuite) TestExists() {
+	exists, err := s.q.Exists(PersonTable, "WHERE name = "+s.q.Placeholder(1), "Elfrieda Abbott")
+	s.NoError(err)
+	s.True(exists)
+
+	exists, err = s.q.Exists(PersonTable, "WHERE name = "+s.q.Placeholder(1), "Nobody")
+	s.NoError(err)
+	s.False(exists)
+
+	tail, args, err := reform.Where(reform.Eq("id", 2)).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	exists, err = s.q.Exists(MemoTable, tail, args...)
+	s.NoError(err)
+	s.False(exists) // soft deleted
+	exists, err = s.q.Unscoped().Exists(MemoTable, tail, args...)
+	s.NoError(err)
+	s.True(exists)
+
+	_, err = s.q.Exists(PersonTable, "WHERE invalid_tail")
+	s.Error(err)
+}
+
+func (s *ReformSuite) TestSelectAllInto() {
+	persons := make([]Person, 0, 1)
+	err := reform.SelectAllInto(s.q.Querier, &persons, "WHERE name = "+s.q.Placeholder(1)+" ORDER BY id", "Elfrieda Abbott")
+	s.NoError(err)
+	s.Equal([]Person{
+		{ID: 102, Name: "Elfrieda Abbott", Email: pointer.ToString("elfrieda_abbott@example.org"), CreatedAt: personCreated},
+		{ID: 103, Name: "Elfrieda Abbott", CreatedAt: personCreated},
+	}, persons)
+
+	var projects []Project
+	err = reform.SelectAllInto(s.q.Querier, &projects, "WHERE id IS NULL")
+	s.NoError(err)
+	s.Empty(projects)
+
+	err = reform.SelectAllInto(s.q.Querier, &projects, "WHERE invalid_tail")
+	s.Error(err)
+	s.NotEqual(reform.ErrNoRows, err)
+	s.Empty(projects)
+}
+
+func (s *ReformSuite) TestQueryStructs() {
+	persons, err := reform.QueryStructs[*Person](s.q.Querier, "WHERE name = "+s.q.Placeholder(1)+" ORDER BY id", "Elfrieda Abbott")
+	s.NoError(err)
+	s.Equal([]*Person{
+		{ID: 102, Name: "Elfrieda Abbott", Email: pointer.ToString("elfrieda_abbott@example.org"), CreatedAt: personCreated},
+		{ID: 103, Name: "Elfrieda Abbott", CreatedAt: personCreated},
+	}, persons)
+
+	projects, err := reform.QueryStructs[*Project](s.q.Querier, "WHERE id IS NULL")
+	s.NoError(err)
+	s.Nil(projects)
+
+	projects, err = reform.QueryStructs[*Project](s.q.Querier, "WHERE invalid_tail")
+	s.Error(err)
+	s.NotEqual(reform.ErrNoRows, err)
+	s.Nil(projects)
+}
+
+func (s *ReformSuite) TestSelectInto() {
+	type Named struct {
+		Name string `db:"name"`
+	}
+	var stats []struct {
+		Named
+		People  int64  `db:"people"`
+		Ignored string `db:"-"`
+	}
+	query := "SELECT name, COUNT(*) AS people FROM people WHERE name = " + s.q.Placeholder(1) + " GROUP BY name"
+	s.Require().NoError(s.q.SelectInto(&stats, query, "Elfrieda Abbott"))
+	s.Require().Len(stats, 1)
+	s.Equal("Elfrieda Abbott", stats[0].Name)
+	s.Equal(int64(2), stats[0].People)
+
+	var person struct {
+		ID    int32   `db:"id"`
+		Email *string `db:"email"`
+	}
+	s.Require().NoError(s.q.SelectInto(&person, "SELECT id, email FROM people WHERE id = "+s.q.Placeholder(1), 102))
+	s.Equal(int32(102), person.ID)
+	s.Equal(pointer.ToString("elfrieda_abbott@example.org"), person.Email)
+
+	var named []*Named
+	s.Equal(reform.ErrNoRows, s.q.SelectInto(&person, "SELECT id, email FROM people WHERE id IS NULL"))
+	s.NoError(s.q.SelectInto(&named, "SELECT name FROM people WHERE id IS NULL"))
+	s.Empty(named)
+
+	err := s.q.SelectInto(&named, "SELECT id, name FROM people")
+	s.EqualError(err, `reform: SelectInto: reform_test.Named has no field for column "id"`)
+	err = s.q.SelectInto(named, "SELECT name FROM people")
+	s.EqualError(err, "reform: SelectInto: dest should be a non-nil pointer, got []*reform_test.Named")
+}
+
+func BenchmarkSelectAllFrom(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := DB.SelectAllFrom(PersonTable, "")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSelectAllInto(b *testing.B) {
+	b.ReportAllocs()
+	var persons []Person
+	for i := 0; i < b.N; i++ {
+		persons = persons[:0]
+		err := reform.SelectAllInto(DB.Querier, &persons, "")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func (s *ReformSuite) TestFindOneTo() {
 	var person Person
 	err := s.q.FindOneTo(&person, "id", 102)
@@ -154,6 +382,11 @@ func (s *ReformSuite) TestFindOneFrom() {
 	s.Nil(project)
 	s.Equal(reform.ErrNoRows, err)
 
+	project, err = s.q.FindOneFrom(ProjectTable, "name", "No Such Project")
+	s.Nil(project)
+	s.Equal(reform.ErrNoRows, err)
+	s.True(errors.Is(err, sql.ErrNoRows))
+
 	project, err = s.q.FindOneFrom(ProjectTable, "invalid_column", nil)
 	s.Nil(project)
 	s.Error(err)
@@ -224,6 +457,29 @@ func (s *ReformSuite) TestFindAllFrom() {
 	s.NotEqual(reform.ErrNoRows, err)
 }
 
+func (s *ReformSuite) TestGeneratedFindBy() {
+	person, err := PersonTable.FindByEmail(s.q.Querier, pointer.ToString("elfrieda_abbott@example.org"))
+	s.NoError(err)
+	s.Equal(int32(102), person.ID)
+
+	person, err = PersonTable.FindByEmail(s.q.Querier, pointer.ToString("nobody@example.org"))
+	s.Nil(person)
+	s.Equal(reform.ErrNoRows, err)
+
+	persons, err := PersonTable.FindAllByName(s.q.Querier, "Elfrieda Abbott")
+	s.NoError(err)
+	s.Require().Len(persons, 2)
+	s.Equal("Elfrieda Abbott", persons[1].Name)
+
+	persons, err = PersonTable.FindAllByEmail(s.q.Querier, nil)
+	s.NoError(err)
+	s.Len(persons, 3)
+
+	persons, err = PersonTable.FindAllByName(s.q.Querier, "Nobody")
+	s.NoError(err)
+	s.Nil(persons)
+}
+
 func (s *ReformSuite) TestFindByPrimaryKeyTo() {
 	var person Person
 	err := s.q.FindByPrimaryKeyTo(&person, 1)
@@ -241,6 +497,54 @@ func (s *ReformSuite) TestFindByPrimaryKeyTo() {
 	s.Equal(reform.ErrNoRows, err)
 }
 
+func (s *ReformSuite) TestFindByPrimaryKeyForUpdateTo() {
+	clause := func(lock reform.RowLock) string {
+		if rd, ok := s.q.Dialect.(reform.RowLockingDialect); ok {
+			return rd.RowLockClause(lock)
+		}
+		return lock.Clause()
+	}
+
+	rl := reform.NewRecordingLogger()
+	s.q.Logger = rl
+
+	var person Person
+	err := s.q.FindByPrimaryKeyForUpdateTo(&person, 1)
+	if clause(reform.ForUpdate) == "" {
+		s.Equal(reform.ErrRowLockNotSupported, err)
+		s.Empty(rl.Statements(), "query should not be executed without locking")
+	} else {
+		s.NoError(err)
+		s.Equal(Person{ID: 1, Name: "Denis Mills", CreatedAt: goCreated}, person)
+		statements := rl.Statements()
+		s.Require().Len(statements, 1)
+		s.True(strings.HasSuffix(statements[0].Query, " "+clause(reform.ForUpdate)), "%s", statements[0].Query)
+	}
+
+	rl.Reset()
+	project, err := s.q.WithRowLock(reform.ForShare|reform.SkipLocked).FindByPrimaryKeyFrom(ProjectTable, "baron")
+	if clause(reform.ForShare|reform.SkipLocked) == "" {
+		s.Equal(reform.ErrRowLockNotSupported, err)
+		s.Empty(rl.Statements(), "query should not be executed without locking")
+	} else {
+		s.NoError(err)
+		s.Equal("Vicious Baron", project.(*Project).Name)
+		statements := rl.Statements()
+		s.Require().Len(statements, 1)
+		s.True(strings.HasSuffix(statements[0].Query, " FOR SHARE SKIP LOCKED"), "%s", statements[0].Query)
+	}
+}
+
+func (s *ReformSuite) TestRowLockClause() {
+	s.Equal("", reform.RowLock(0).Clause())
+	s.Equal("FOR UPDATE", reform.ForUpdate.Clause())
+	s.Equal("FOR UPDATE NOWAIT", (reform.ForUpdate | reform.NoWait).Clause())
+	s.Equal("FOR SHARE SKIP LOCKED", (reform.ForShare | reform.SkipLocked).Clause())
+	s.Panics(func() { s.q.WithRowLock(reform.SkipLocked) })
+	s.Panics(func() { s.q.WithRowLock(reform.ForUpdate | reform.ForShare) })
+	s.Panics(func() { s.q.WithRowLock(reform.ForUpdate | reform.SkipLocked | reform.NoWait) })
+}
+
 func (s *ReformSuite) TestFindByPrimaryKeyFrom() {
 	person, err := s.q.FindByPrimaryKeyFrom(PersonTable, 1)
 	s.NoError(err)
@@ -271,4 +575,13 @@ func (s *ReformSuite) TestReload() {
 	err = s.q.Reload(&project)
 	s.Equal(Project{}, project) // expect old value
 	s.Equal(reform.ErrNoRows, err)
+
+	// deleted row
+	project = expected
+	_, err = s.q.DeleteFrom(ProjectTable, "WHERE id = "+s.q.Placeholder(1), "baron")
+	s.Require().NoError(err)
+	project.Name = "Changed"
+	err = s.q.Reload(&project)
+	s.Equal(reform.ErrNoRows, err)
+	s.Equal("Changed", project.Name)
 }
@@ -0,0 +1,131 @@
+package reform
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// QueryRecorder keeps the last executed statements with args, durations and errors in a fixed-size ring buffer,
+// so panics and bug reports can include SQL history leading up to a failure.
+// Unlike Logger, it has a constant memory footprint and may be always enabled in production.
+// Values of sensitive columns are recorded as Redacted. It is safe for concurrent use.
+type QueryRecorder struct {
+	m          sync.Mutex
+	statements []RecordedStatement
+	next       int
+	full       bool
+}
+
+// NewQueryRecorder creates a new QueryRecorder for given number of last statements.
+func NewQueryRecorder(size int) *QueryRecorder {
+	if size <= 0 {
+		panic("reform: NewQueryRecorder: size should be positive")
+	}
+	return &QueryRecorder{
+		statements: make([]RecordedStatement, size),
+	}
+}
+
+// record adds statement to the buffer, overwriting the oldest one if it is full.
+func (r *QueryRecorder) record(s RecordedStatement) {
+	if s.Args != nil {
+		args := make([]interface{}, len(s.Args))
+		copy(args, s.Args)
+		s.Args = args
+	}
+
+	r.m.Lock()
+	r.statements[r.next] = s
+	r.next++
+	if r.next == len(r.statements) {
+		r.next = 0
+		r.full = true
+	}
+	r.m.Unlock()
+}
+
+// Statements returns a copy of recorded statements in order of execution.
+func (r *QueryRecorder) Statements() []RecordedStatement {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if !r.full {
+		res := make([]RecordedStatement, r.next)
+		copy(res, r.statements)
+		return res
+	}
+
+	res := make([]RecordedStatement, 0, len(r.statements))
+	res = append(res, r.statements[r.next:]...)
+	return append(res, r.statements[:r.next]...)
+}
+
+// Reset removes all recorded statements.
+func (r *QueryRecorder) Reset() {
+	r.m.Lock()
+	for i := range r.statements {
+		r.statements[i] = RecordedStatement{}
+	}
+	r.next = 0
+	r.full = false
+	r.m.Unlock()
+}
+
+// String returns recorded statements one per line, in the same format as PrintfLogger.
+func (r *QueryRecorder) String() string {
+	var res strings.Builder
+	for _, s := range r.Statements() {
+		if s.Tag != "" {
+			fmt.Fprintf(&res, "[%s] ", s.Tag)
+		}
+		res.WriteString(s.Query)
+		if s.Args != nil {
+			ss := make([]string, len(s.Args))
+			for i, arg := range s.Args {
+				ss[i] = Inspect(arg, false)
+			}
+			fmt.Fprintf(&res, " [%s]", strings.Join(ss, ", "))
+		}
+		fmt.Fprintf(&res, " %s", s.Duration)
+		if s.Err != nil {
+			res.WriteString(": " + s.Err.Error())
+		}
+		res.WriteByte('\n')
+	}
+	return res.String()
+}
+
+// WithQueryRecorder returns a copy of querier which records executed statements to given recorder
+// (nil disables it) in addition to Logger. Transactions started by DB use its recorder.
+//
+//	db := reform.NewDB(sqlDB, postgresql.Dialect, nil)
+//	db.Querier = db.WithQueryRecorder(reform.NewQueryRecorder(50))
+//	defer func() {
+//		if p := recover(); p != nil {
+//			log.Printf("%v\nrecent queries:\n%s", p, db.QueryRecorder())
+//			panic(p)
+//		}
+//	}()
+func (q *Querier) WithQueryRecorder(r *QueryRecorder) *Querier {
+	nq := q.clone()
+	nq.recorder = r
+	return nq
+}
+
+// QueryRecorder returns querier's QueryRecorder, or nil.
+func (q *Querier) QueryRecorder() *QueryRecorder {
+	return q.recorder
+}
+
+// RecentQueries returns statements recorded by querier's QueryRecorder in order of execution,
+// or nil if querier has no recorder.
+func (q *Querier) RecentQueries() []RecordedStatement {
+	if q.recorder == nil {
+		return nil
+	}
+	return q.recorder.Statements()
+}
+
+// check interface
+var _ fmt.Stringer = new(QueryRecorder)
@@ -0,0 +1,46 @@
+package reform_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	. "github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func (s *ReformSuite) TestQueryRecorder() {
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+	r := reform.NewQueryRecorder(2)
+	q := f.DB.WithQueryRecorder(r)
+	s.Equal(r, q.QueryRecorder())
+	s.Empty(q.RecentQueries())
+	s.Nil(f.DB.RecentQueries())
+
+	_, err := q.Exec("SELECT 1")
+	s.Require().NoError(err)
+	f.StubError(PersonTable, errors.New("boom"))
+	s.Error(q.Tagged("report").UpdateColumns(&Person{ID: 1, Name: "Alice"}, "name"))
+	_, err = q.Exec("SELECT 2")
+	s.Require().NoError(err)
+
+	statements := r.Statements()
+	s.Require().Len(statements, 2)
+	s.Equal("report", statements[0].Tag)
+	s.Contains(statements[0].Query, `UPDATE "people" SET "name" = $1`)
+	s.Equal([]interface{}{"Alice", int32(1)}, statements[0].Args)
+	s.EqualError(statements[0].Err, "boom")
+	s.Equal("SELECT 2", statements[1].Query)
+	s.Equal(statements, q.RecentQueries())
+
+	statements[1].Duration = time.Second
+	s.NotEqual(statements, r.Statements())
+
+	s.Contains(r.String(), "[report] UPDATE \"people\" SET \"name\" = $1 WHERE \"id\" = $2 [`Alice`, 1] ")
+	s.Contains(r.String(), "\nSELECT 2 ")
+	r.Reset()
+	s.Empty(r.Statements())
+	s.Empty(r.String())
+}
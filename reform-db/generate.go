@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// initialisms are name parts which are upper-cased entirely in Go identifiers.
+var initialisms = map[string]bool{
+	"api":  true,
+	"html": true,
+	"http": true,
+	"id":   true,
+	"ip":   true,
+	"json": true,
+	"sql":  true,
+	"uri":  true,
+	"url":  true,
+	"uuid": true,
+	"xml":  true,
+}
+
+// goName converts SQL name to exported Go identifier: "person_id" -> "PersonID".
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var res string
+	for _, p := range parts {
+		p = strings.ToLower(p)
+		if initialisms[p] {
+			res += strings.ToUpper(p)
+			continue
+		}
+		res += strings.ToUpper(p[:1]) + p[1:]
+	}
+	if res == "" || !unicode.IsLetter([]rune(res)[0]) {
+		res = "T" + res
+	}
+	return res
+}
+
+// fieldData represents a single struct field in generated code.
+type fieldData struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// structData represents a single struct in generated code.
+type structData struct {
+	Type   string
+	Table  string
+	View   bool
+	Fields []fieldData
+}
+
+// fileData represents a generated file.
+type fileData struct {
+	Package  string
+	SQL      bool
+	Time     bool
+	Generate bool
+	Structs  []structData
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// This file was generated by reform-db, it is safe to edit.
+
+package {{ .Package }}
+
+{{- if or .SQL .Time }}
+
+import (
+{{- if .SQL }}
+	"database/sql"
+{{- end }}
+{{- if .Time }}
+	"time"
+{{- end }}
+)
+{{- end }}
+
+{{- if .Generate }}
+
+//go:generate reform
+{{- end }}
+
+{{- range .Structs }}
+
+{{- if .View }}
+
+// {{ .Type }} represents a row in {{ .Table }} view.
+//reform:{{ .Table }} view
+{{- else }}
+
+// {{ .Type }} represents a row in {{ .Table }} table.
+//reform:{{ .Table }}
+{{- end }}
+type {{ .Type }} struct {
+{{- range .Fields }}
+	{{ .Name }} {{ .Type }} ` + "`" + `reform:"{{ .Tag }}"` + "`" + `
+{{- end }}
+}
+{{- end }}
+`))
+
+// sqlNullTypes are database/sql types for nullable columns used instead of pointers with -nulls=sql flag.
+var sqlNullTypes = map[string]string{
+	"bool":      "sql.NullBool",
+	"float64":   "sql.NullFloat64",
+	"int32":     "sql.NullInt32",
+	"int64":     "sql.NullInt64",
+	"string":    "sql.NullString",
+	"time.Time": "sql.NullTime",
+}
+
+// newStructData returns data for generating struct for given table.
+// Nullable columns are represented by pointers, or by sql.Null* types (if there is one) if sqlNulls is true.
+func newStructData(t table, sqlNulls bool) structData {
+	sd := structData{
+		Type:  goName(t.Name),
+		Table: t.Name,
+		View:  t.View,
+	}
+	for _, c := range t.Columns {
+		fd := fieldData{
+			Name: goName(c.Name),
+			Type: c.Type,
+			Tag:  c.Name,
+		}
+		if c.PK {
+			fd.Tag += ",pk"
+		}
+
+		// primary key can't be a pointer, and nil slice is already NULL
+		if c.Nullable && !c.PK && !strings.HasPrefix(c.Type, "[]") {
+			if typ := sqlNullTypes[c.Type]; sqlNulls && typ != "" {
+				fd.Type = typ
+			} else {
+				fd.Type = "*" + fd.Type
+			}
+		}
+		sd.Fields = append(sd.Fields, fd)
+	}
+	return sd
+}
+
+// generateFile returns formatted Go code with struct for given table.
+// See newStructData for sqlNulls.
+func generateFile(pack string, t table, generate bool, sqlNulls bool) ([]byte, error) {
+	sd := newStructData(t, sqlNulls)
+	fd := fileData{
+		Package:  pack,
+		Generate: generate,
+		Structs:  []structData{sd},
+	}
+	for _, f := range sd.Fields {
+		if strings.HasPrefix(f.Type, "sql.") {
+			fd.SQL = true
+		}
+		if strings.TrimPrefix(f.Type, "*") == "time.Time" {
+			fd.Time = true
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, &fd); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
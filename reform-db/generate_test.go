@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/reform/parse"
+)
+
+func TestGoName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"people":         "People",
+		"person_project": "PersonProject",
+		"id":             "ID",
+		"person_id":      "PersonID",
+		"APIKey":         "Apikey",
+		"home-url":       "HomeURL",
+		"2fa":            "T2fa",
+	} {
+		assert.Equal(t, expected, goName(name), "%s", name)
+	}
+}
+
+func TestGenerateFile(t *testing.T) {
+	tbl := table{
+		Name: "project_roles",
+		Columns: []column{
+			{Name: "project_id", Type: "string", PK: true},
+			{Name: "person_id", Type: "int32", PK: true},
+			{Name: "role", Type: "string", Nullable: true},
+			{Name: "data", Type: "[]byte", Nullable: true},
+			{Name: "created_at", Type: "time.Time"},
+		},
+	}
+	b, err := generateFile("models", tbl, true, false)
+	require.NoError(t, err)
+
+	dir, err := os.MkdirTemp("", "reform-db")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "project_roles.go")
+	require.NoError(t, os.WriteFile(path, b, 0644))
+
+	s, err := parse.File(path)
+	require.NoError(t, err)
+	require.Len(t, s, 1)
+	expected := parse.StructInfo{
+		Type:    "ProjectRoles",
+		SQLName: "project_roles",
+		Fields: []parse.FieldInfo{
+			{Name: "ProjectID", Type: "string", Column: "project_id"},
+			{Name: "PersonID", Type: "int32", Column: "person_id"},
+			{Name: "Role", Type: "*string", Column: "role"},
+			{Name: "Data", Type: "[]byte", Column: "data"},
+			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0, 1},
+	}
+	assert.Equal(t, expected, s[0])
+}
+
+func TestGenerateFileSQLNulls(t *testing.T) {
+	tbl := table{
+		Name: "events",
+		Columns: []column{
+			{Name: "id", Type: "int64", PK: true},
+			{Name: "name", Type: "string", Nullable: true},
+			{Name: "score", Type: "float64", Nullable: true},
+			{Name: "counter", Type: "uint64", Nullable: true},
+			{Name: "happened_at", Type: "time.Time", Nullable: true},
+		},
+	}
+	b, err := generateFile("models", tbl, false, true)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "\t\"database/sql\"\n")
+	assert.NotContains(t, string(b), "\"time\"")
+
+	dir, err := os.MkdirTemp("", "reform-db")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.go")
+	require.NoError(t, os.WriteFile(path, b, 0644))
+
+	s, err := parse.File(path)
+	require.NoError(t, err)
+	require.Len(t, s, 1)
+	assert.Equal(t, []parse.FieldInfo{
+		{Name: "ID", Type: "int64", Column: "id"},
+		{Name: "Name", Type: "sql.NullString", Column: "name"},
+		{Name: "Score", Type: "sql.NullFloat64", Column: "score"},
+		{Name: "Counter", Type: "*uint64", Column: "counter"},
+		{Name: "HappenedAt", Type: "sql.NullTime", Column: "happened_at"},
+	}, s[0].Fields)
+}
+
+func TestGenerateFileView(t *testing.T) {
+	tbl := table{
+		Name: "monthly_sales",
+		View: true,
+		Columns: []column{
+			{Name: "month", Type: "string"},
+			{Name: "total", Type: "int64", Nullable: true},
+		},
+	}
+	b, err := generateFile("models", tbl, false, false)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "\n//reform:monthly_sales view\n")
+
+	dir, err := os.MkdirTemp("", "reform-db")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "monthly_sales.go")
+	require.NoError(t, os.WriteFile(path, b, 0644))
+
+	s, err := parse.File(path)
+	require.NoError(t, err)
+	require.Len(t, s, 1)
+	assert.True(t, s[0].View)
+	assert.False(t, s[0].IsTable())
+}
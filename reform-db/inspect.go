@@ -0,0 +1,315 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/dialects/sqlite3"
+)
+
+// column describes a single column of table or view.
+type column struct {
+	Name     string
+	Type     string // Go type without pointer for nullable column
+	Nullable bool
+	PK       bool
+}
+
+// table describes a single table or view.
+type table struct {
+	Name    string
+	View    bool
+	Columns []column
+}
+
+// inspector returns tables and views of the current database or schema.
+type inspector func(db *reform.DB) ([]table, error)
+
+// dialectFor returns dialect and inspector for given driver name.
+func dialectFor(driver string) (reform.Dialect, inspector, error) {
+	switch driver {
+	case "postgres", "pgx":
+		return postgresql.Dialect, inspectPostgreSQL, nil
+	case "mysql":
+		return mysql.Dialect, informationSchemaInspector("DATABASE()", mysqlType), nil
+	case "sqlite3":
+		return sqlite3.Dialect, inspectSQLite3, nil
+	default:
+		return nil, nil, fmt.Errorf("no dialect for driver %q", driver)
+	}
+}
+
+// informationSchemaInspector returns inspector which uses standard information_schema views
+// for given schema expression and function converting SQL data type to Go type.
+func informationSchemaInspector(schema string, goType func(string) string) inspector {
+	return func(db *reform.DB) ([]table, error) {
+		rows, err := db.Query(fmt.Sprintf(`SELECT table_name, table_type FROM information_schema.tables `+
+			`WHERE table_schema = %s ORDER BY table_name`, schema))
+		if err != nil {
+			return nil, err
+		}
+		var res []table
+		for rows.Next() {
+			var name, typ string
+			if err = rows.Scan(&name, &typ); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			res = append(res, table{Name: name, View: typ == "VIEW"})
+		}
+		if err = rows.Close(); err != nil {
+			return nil, err
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+
+		for i, t := range res {
+			pk := make(map[string]bool)
+			if !t.View {
+				rows, err = db.Query(fmt.Sprintf(`SELECT kcu.column_name FROM information_schema.table_constraints tc `+
+					`JOIN information_schema.key_column_usage kcu ON tc.constraint_name = kcu.constraint_name `+
+					`AND tc.table_schema = kcu.table_schema AND tc.table_name = kcu.table_name `+
+					`WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = %s AND tc.table_name = %s`,
+					schema, db.Placeholder(1)), t.Name)
+				if err != nil {
+					return nil, err
+				}
+				for rows.Next() {
+					var name string
+					if err = rows.Scan(&name); err != nil {
+						rows.Close()
+						return nil, err
+					}
+					pk[name] = true
+				}
+				if err = rows.Close(); err != nil {
+					return nil, err
+				}
+				if err = rows.Err(); err != nil {
+					return nil, err
+				}
+			}
+
+			rows, err = db.Query(fmt.Sprintf(`SELECT column_name, data_type, is_nullable FROM information_schema.columns `+
+				`WHERE table_schema = %s AND table_name = %s ORDER BY ordinal_position`,
+				schema, db.Placeholder(1)), t.Name)
+			if err != nil {
+				return nil, err
+			}
+			for rows.Next() {
+				var name, typ, nullable string
+				if err = rows.Scan(&name, &typ, &nullable); err != nil {
+					rows.Close()
+					return nil, err
+				}
+				res[i].Columns = append(res[i].Columns, column{
+					Name:     name,
+					Type:     goType(strings.ToLower(typ)),
+					Nullable: nullable == "YES",
+					PK:       pk[name],
+				})
+			}
+			if err = rows.Close(); err != nil {
+				return nil, err
+			}
+			if err = rows.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// inspectPostgreSQL is an inspector for PostgreSQL which uses information_schema views for tables and views,
+// and pg_catalog for materialized views which are not present in information_schema.
+func inspectPostgreSQL(db *reform.DB) ([]table, error) {
+	res, err := informationSchemaInspector("current_schema()", postgresType)(db)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT matviewname FROM pg_matviews WHERE schemaname = current_schema() ORDER BY matviewname`)
+	if err != nil {
+		return nil, err
+	}
+	var views []table
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		views = append(views, table{Name: name, View: true})
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, t := range views {
+		rows, err = db.Query(`SELECT a.attname, format_type(a.atttypid, NULL), a.attnotnull FROM pg_attribute a `+
+			`JOIN pg_class c ON a.attrelid = c.oid JOIN pg_namespace n ON c.relnamespace = n.oid `+
+			`WHERE n.nspname = current_schema() AND c.relname = $1 AND a.attnum > 0 AND NOT a.attisdropped `+
+			`ORDER BY a.attnum`, t.Name)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var name, typ string
+			var notNull bool
+			if err = rows.Scan(&name, &typ, &notNull); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			views[i].Columns = append(views[i].Columns, column{
+				Name:     name,
+				Type:     postgresType(typ),
+				Nullable: !notNull,
+			})
+		}
+		if err = rows.Close(); err != nil {
+			return nil, err
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	res = append(res, views...)
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+// inspectSQLite3 is an inspector for SQLite3 which uses sqlite_master table and table_info pragma.
+func inspectSQLite3(db *reform.DB) ([]table, error) {
+	rows, err := db.Query(`SELECT name, type FROM sqlite_master ` +
+		`WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	var res []table
+	for rows.Next() {
+		var name, typ string
+		if err = rows.Scan(&name, &typ); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		res = append(res, table{Name: name, View: typ == "view"})
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, t := range res {
+		rows, err = db.Query("PRAGMA table_info(" + db.QuoteIdentifier(t.Name) + ")")
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var cid, pk int
+			var name, typ string
+			var notNull bool
+			var dflt sql.NullString
+			if err = rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			res[i].Columns = append(res[i].Columns, column{
+				Name:     name,
+				Type:     sqlite3Type(strings.ToLower(typ)),
+				Nullable: !notNull,
+				PK:       pk > 0,
+			})
+		}
+		if err = rows.Close(); err != nil {
+			return nil, err
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// postgresType returns Go type for PostgreSQL data type.
+func postgresType(typ string) string {
+	switch {
+	case typ == "smallint":
+		return "int16"
+	case typ == "integer":
+		return "int32"
+	case typ == "bigint":
+		return "int64"
+	case typ == "real":
+		return "float32"
+	case typ == "double precision":
+		return "float64"
+	case typ == "boolean":
+		return "bool"
+	case typ == "bytea":
+		return "[]byte"
+	case strings.HasPrefix(typ, "timestamp"), typ == "date":
+		return "time.Time"
+	default:
+		// text, varchar, numeric, uuid, json and others are scanned to string without precision loss
+		return "string"
+	}
+}
+
+// mysqlType returns Go type for MySQL data type.
+func mysqlType(typ string) string {
+	switch typ {
+	case "tinyint":
+		return "int8"
+	case "smallint":
+		return "int16"
+	case "mediumint", "int":
+		return "int32"
+	case "bigint":
+		return "int64"
+	case "float":
+		return "float32"
+	case "double":
+		return "float64"
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "[]byte"
+	case "date", "datetime", "timestamp":
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
+
+// sqlite3Type returns Go type for SQLite3 column type declaration
+// using rules similar to SQLite3 type affinity.
+func sqlite3Type(typ string) string {
+	switch {
+	case strings.Contains(typ, "int"):
+		return "int64"
+	case strings.Contains(typ, "char"), strings.Contains(typ, "clob"), strings.Contains(typ, "text"):
+		return "string"
+	case typ == "", strings.Contains(typ, "blob"):
+		return "[]byte"
+	case strings.Contains(typ, "real"), strings.Contains(typ, "floa"), strings.Contains(typ, "doub"):
+		return "float64"
+	case strings.Contains(typ, "bool"):
+		return "bool"
+	case strings.Contains(typ, "date"), strings.Contains(typ, "time"):
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/dialects/sqlite3"
+)
+
+// setupSQLite3 returns DB for in-memory SQLite3 database with test schema.
+func setupSQLite3(t *testing.T) *reform.DB {
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	sqlDB.SetMaxOpenConns(1) // each connection has its own in-memory database
+
+	for _, q := range []string{
+		`CREATE TABLE people (id INTEGER PRIMARY KEY, name VARCHAR(255) NOT NULL, email TEXT, created_at DATETIME NOT NULL)`,
+		`CREATE TABLE project_roles (project_id TEXT NOT NULL, person_id INTEGER NOT NULL, score REAL, data BLOB, ` +
+			`PRIMARY KEY (project_id, person_id))`,
+		`CREATE VIEW people_names AS SELECT id, name FROM people`,
+	} {
+		_, err = sqlDB.Exec(q)
+		require.NoError(t, err)
+	}
+	return reform.NewDB(sqlDB, sqlite3.Dialect, nil)
+}
+
+func TestDialectFor(t *testing.T) {
+	for driver, expected := range map[string]reform.Dialect{
+		"postgres": postgresql.Dialect,
+		"pgx":      postgresql.Dialect,
+		"mysql":    mysql.Dialect,
+		"sqlite3":  sqlite3.Dialect,
+	} {
+		dialect, inspect, err := dialectFor(driver)
+		require.NoError(t, err, "%s", driver)
+		assert.Equal(t, expected, dialect, "%s", driver)
+		assert.NotNil(t, inspect, "%s", driver)
+	}
+
+	_, _, err := dialectFor("mssql")
+	assert.EqualError(t, err, `no dialect for driver "mssql"`)
+}
+
+func TestInspectSQLite3(t *testing.T) {
+	db := setupSQLite3(t)
+	tables, err := inspectSQLite3(db)
+	require.NoError(t, err)
+	expected := []table{{
+		Name: "people",
+		Columns: []column{
+			{Name: "id", Type: "int64", Nullable: true, PK: true},
+			{Name: "name", Type: "string"},
+			{Name: "email", Type: "string", Nullable: true},
+			{Name: "created_at", Type: "time.Time"},
+		},
+	}, {
+		Name: "people_names",
+		View: true,
+		Columns: []column{
+			{Name: "id", Type: "int64", Nullable: true},
+			{Name: "name", Type: "string", Nullable: true},
+		},
+	}, {
+		Name: "project_roles",
+		Columns: []column{
+			{Name: "project_id", Type: "string", PK: true},
+			{Name: "person_id", Type: "int64", PK: true},
+			{Name: "score", Type: "float64", Nullable: true},
+			{Name: "data", Type: "[]byte", Nullable: true},
+		},
+	}}
+	assert.Equal(t, expected, tables)
+}
+
+func TestInitModels(t *testing.T) {
+	db := setupSQLite3(t)
+	dir, err := os.MkdirTemp("", "reform-db")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the first file exists and has no go:generate directive, so the next one gets it
+	existing := []byte("package models\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "people.go"), existing, 0644))
+	require.NoError(t, initModels(db, inspectSQLite3, dir, false))
+
+	b, err := os.ReadFile(filepath.Join(dir, "people.go"))
+	require.NoError(t, err)
+	assert.Equal(t, existing, b)
+
+	var generate int
+	for _, name := range []string{"peoplenames.go", "projectroles.go"} {
+		b, err = os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		generate += strings.Count(string(b), "//go:generate reform")
+	}
+	assert.Equal(t, 1, generate)
+
+	// directive is not added again
+	require.NoError(t, os.Remove(filepath.Join(dir, "projectroles.go")))
+	require.NoError(t, initModels(db, inspectSQLite3, dir, false))
+	b, err = os.ReadFile(filepath.Join(dir, "projectroles.go"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "//go:generate reform")
+}
+
+func TestTypes(t *testing.T) {
+	for typ, expected := range map[string]string{
+		"smallint":                    "int16",
+		"integer":                     "int32",
+		"bigint":                      "int64",
+		"real":                        "float32",
+		"double precision":            "float64",
+		"boolean":                     "bool",
+		"bytea":                       "[]byte",
+		"timestamp with time zone":    "time.Time",
+		"timestamp without time zone": "time.Time",
+		"date":                        "time.Time",
+		"numeric":                     "string",
+		"uuid":                        "string",
+	} {
+		assert.Equal(t, expected, postgresType(typ), "%s", typ)
+	}
+
+	for typ, expected := range map[string]string{
+		"tinyint":  "int8",
+		"int":      "int32",
+		"bigint":   "int64",
+		"double":   "float64",
+		"longblob": "[]byte",
+		"datetime": "time.Time",
+		"decimal":  "string",
+		"varchar":  "string",
+	} {
+		assert.Equal(t, expected, mysqlType(typ), "%s", typ)
+	}
+
+	for typ, expected := range map[string]string{
+		"":             "[]byte",
+		"integer":      "int64",
+		"varchar(255)": "string",
+		"blob":         "[]byte",
+		"double":       "float64",
+		"boolean":      "bool",
+		"datetime":     "time.Time",
+		"numeric":      "string",
+	} {
+		assert.Equal(t, expected, sqlite3Type(typ), "%s", typ)
+	}
+}
@@ -0,0 +1,239 @@
+// Command reform-db generates reform models from existing database schema and applies migrations.
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/stdlib"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/migrate"
+)
+
+var (
+	DebugF   = flag.Bool("debug", false, "Enable debug logging")
+	DriverF  = flag.String("db-driver", "", "Database driver: postgres, pgx, mysql or sqlite3")
+	SourceF  = flag.String("db-source", "", "Database connection string")
+	PackageF = flag.String("package", "", "Package name for generated files (default is directory name)")
+	ReformF  = flag.Bool("reform", true, "Run reform for generated files (init command)")
+	NullsF   = flag.String("nulls", "pointers", "Go types for nullable columns: pointers (*string) or sql (sql.NullString) (init command)")
+
+	logger = NewLogger()
+)
+
+// packageName returns package name for directory.
+func packageName(dir string) (string, error) {
+	if *PackageF != "" {
+		return *PackageF, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, filepath.Base(abs))), nil
+}
+
+// initModels inspects database schema and writes file with struct for each table and view to dir.
+// Existing files are not overwritten. The first written file gets go:generate directive for reform,
+// unless one of existing files already has it.
+// Nullable columns are represented by sql.Null* types if sqlNulls is true, by pointers otherwise.
+func initModels(db *reform.DB, inspect inspector, dir string, sqlNulls bool) error {
+	pack, err := packageName(dir)
+	if err != nil {
+		return err
+	}
+
+	tables, err := inspect(db)
+	if err != nil {
+		return err
+	}
+	logger.Debugf("%#v", tables)
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	paths := make([]string, len(tables))
+	existing := make([]bool, len(tables))
+	generate := true
+	for i, t := range tables {
+		paths[i] = filepath.Join(dir, strings.ToLower(goName(t.Name))+".go")
+		b, err := os.ReadFile(paths[i])
+		if err != nil {
+			continue
+		}
+		existing[i] = true
+		if bytes.Contains(b, []byte("//go:generate reform")) {
+			generate = false
+		}
+	}
+
+	for i, t := range tables {
+		if existing[i] {
+			logger.Printf("%s already exists, skipping %s", paths[i], t.Name)
+			continue
+		}
+
+		// add go:generate comment only once
+		b, err := generateFile(pack, t, generate, sqlNulls)
+		if err != nil {
+			return fmt.Errorf("%s: %s", t.Name, err)
+		}
+		if err = os.WriteFile(paths[i], b, 0644); err != nil {
+			return err
+		}
+		generate = false
+		logger.Debugf("%s written", paths[i])
+	}
+
+	return nil
+}
+
+// runMigrate runs migrate command (up, down or status) for migrations in dir.
+func runMigrate(db *reform.DB, command string, dir string) error {
+	migrations, err := migrate.LoadDir(dir)
+	if err != nil {
+		return err
+	}
+	m, err := migrate.New(db, migrations)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		applied, err := m.Up()
+		for _, migration := range applied {
+			logger.Printf("applied %d_%s", migration.Version, migration.Name)
+		}
+		return err
+
+	case "down":
+		migration, err := m.Down()
+		if err != nil {
+			return err
+		}
+		if migration == nil {
+			logger.Printf("no applied migrations")
+			return nil
+		}
+		logger.Printf("rolled back %d_%s", migration.Version, migration.Name)
+		return nil
+
+	case "status":
+		statuses, err := m.Status()
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied at " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%d_%s\t%s\n", s.Version, s.Name, state)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unexpected migrate command %q", command)
+	}
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "reform-db - reform models generator and migrations tool for existing database schema\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\n")
+		fmt.Fprintf(os.Stderr, "  %s [flags] init [directory]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s [flags] migrate up|down|status [directory]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Command init writes file with struct for each table and view to directory\n")
+		fmt.Fprintf(os.Stderr, "(current directory by default), then runs reform for it.\n\n")
+		fmt.Fprintf(os.Stderr, "Command migrate applies all pending migrations (up), rolls back the last one (down)\n")
+		fmt.Fprintf(os.Stderr, "or prints their state (status). Migrations are loaded from SQL files in directory\n")
+		fmt.Fprintf(os.Stderr, "(current directory by default), see github.com/AlekSi/reform/migrate.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *DebugF {
+		logger.Debug = true
+	}
+
+	args := flag.Args()
+	var command string
+	switch {
+	case len(args) > 0 && args[0] == "init":
+		command, args = "init", args[1:]
+	case len(args) > 1 && args[0] == "migrate":
+		command, args = args[1], args[2:]
+		if command != "up" && command != "down" && command != "status" {
+			args = nil
+			command = ""
+		}
+	}
+	if command == "" || len(args) > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	if *NullsF != "pointers" && *NullsF != "sql" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dialect, inspect, err := dialectFor(*DriverF)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	sqlDB, err := sql.Open(*DriverF, *SourceF)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	defer sqlDB.Close()
+
+	var l reform.Logger
+	if *DebugF {
+		l = reform.NewPrintfLogger(logger.Printf)
+	}
+	db := reform.NewDB(sqlDB, dialect, l)
+
+	if command != "init" {
+		if err = runMigrate(db, command, dir); err != nil {
+			logger.Fatal(err)
+		}
+		return
+	}
+
+	if err = initModels(db, inspect, dir, *NullsF == "sql"); err != nil {
+		logger.Fatal(err)
+	}
+
+	if *ReformF {
+		cmd := exec.Command("reform", dir)
+		logger.Debugf("%s", strings.Join(cmd.Args, " "))
+		b, err := cmd.CombinedOutput()
+		if err != nil {
+			logger.Fatalf("reform error: %s\n%s", err, b)
+		}
+		logger.Debugf("reform output: %s", b)
+	}
+}
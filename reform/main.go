@@ -3,7 +3,10 @@ package main
 import (
 	"flag"
 	"fmt"
+	"go/ast"
 	"go/build"
+	"go/parser"
+	"go/token"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,6 +18,12 @@ import (
 var (
 	DebugF = flag.Bool("debug", false, "Enable debug logging")
 	GofmtF = flag.Bool("gofmt", true, "Format with gofmt")
+	EqualF = flag.Bool("equal", false, "Generate GoString and Equal methods")
+	DDLF   = flag.Bool("ddl", false, "Generate DDLColumns and CreateTableSQL methods for tables")
+	JSONF  = flag.Bool("json", false, "Generate MarshalJSON and UnmarshalJSON methods with column names as keys")
+
+	TablePrefixF = flag.String("table-prefix", "", "Prefix added to view and table names from magic comments")
+	TableSuffixF = flag.String("table-suffix", "", "Suffix added to view and table names from magic comments")
 
 	logger = NewLogger()
 )
@@ -47,8 +56,11 @@ func processFile(path, file, pack string) error {
 		return err
 	}
 
+	named := packageTypes(path, pack)
 	sds := make([]StructData, 0, len(structs))
 	for _, str := range structs {
+		str.SQLName = *TablePrefixF + str.SQLName + *TableSuffixF
+
 		// decide about view/table suffix
 		t := strings.ToLower(str.Type[0:1]) + str.Type[1:]
 		v := str.Type
@@ -61,9 +73,15 @@ func processFile(path, file, pack string) error {
 		}
 
 		sd := StructData{
-			StructInfo: str,
-			TableType:  t,
-			TableVar:   v,
+			StructInfo:    str,
+			TableType:     t,
+			TableVar:      v,
+			GenerateEqual: *EqualF,
+			GenerateDDL:   *DDLF,
+			GenerateJSON:  *JSONF,
+		}
+		if str.IsTable() {
+			sd.PKIntType = intType(str.PKField().Type, named)
 		}
 		sds = append(sds, sd)
 
@@ -79,6 +97,36 @@ func processFile(path, file, pack string) error {
 	return nil
 }
 
+// packageTypes returns names of underlying types (like "int64" or "OtherID") for named types
+// declared in package in given directory. Types with composite underlying types are not included.
+func packageTypes(path, pack string) map[string]string {
+	filter := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	packs, _ := parser.ParseDir(token.NewFileSet(), path, filter, 0) // ignore errors in unrelated files
+
+	res := make(map[string]string)
+	if packs[pack] == nil {
+		return res
+	}
+	for _, fileNode := range packs[pack].Files {
+		for _, decl := range fileNode.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					if ident, ok := ts.Type.(*ast.Ident); ok {
+						res[ts.Name.Name] = ident.Name
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
 func gofmt(path string) {
 	if *GofmtF {
 		cmd := exec.Command("gofmt", "-s", "-w", path)
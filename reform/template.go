@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/AlekSi/reform/parse"
@@ -8,8 +10,167 @@ import (
 
 type StructData struct {
 	parse.StructInfo
-	TableType string
-	TableVar  string
+	TableType     string
+	TableVar      string
+	GenerateEqual bool
+	GenerateDDL   bool
+	GenerateJSON  bool
+	PKIntType     bool // true if SetPK should convert int64 to primary key type
+}
+
+// intType returns true if SetPK for primary key field of type t should convert int64 values.
+// That's true for integer types and named types declared in the same package with integer underlying types
+// (named contains underlying types of package's named types); conversion of int64 to other types
+// is wrong or doesn't compile. Named types declared in other packages are not converted.
+func intType(t string, named map[string]string) bool {
+	seen := make(map[string]bool)
+	for !seen[t] {
+		switch t {
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+			return true
+		}
+		seen[t] = true
+		t = named[t]
+	}
+	return false
+}
+
+// ddlTypes maps Go field types to reform.ColumnType constants and nullability.
+var ddlTypes = map[string]struct {
+	t        string
+	nullable bool
+}{
+	"bool":            {"BoolColumn", false},
+	"int8":            {"IntColumn", false},
+	"int16":           {"IntColumn", false},
+	"int32":           {"IntColumn", false},
+	"uint8":           {"IntColumn", false},
+	"uint16":          {"IntColumn", false},
+	"int":             {"BigIntColumn", false},
+	"int64":           {"BigIntColumn", false},
+	"uint":            {"BigIntColumn", false},
+	"uint32":          {"BigIntColumn", false},
+	"uint64":          {"BigIntColumn", false},
+	"float32":         {"FloatColumn", false},
+	"float64":         {"FloatColumn", false},
+	"string":          {"StringColumn", false},
+	"[]byte":          {"BytesColumn", false},
+	"time.Time":       {"TimeColumn", false},
+	"sql.NullBool":    {"BoolColumn", true},
+	"sql.NullInt16":   {"IntColumn", true},
+	"sql.NullInt32":   {"IntColumn", true},
+	"sql.NullInt64":   {"BigIntColumn", true},
+	"sql.NullFloat64": {"FloatColumn", true},
+	"sql.NullString":  {"StringColumn", true},
+	"sql.NullTime":    {"TimeColumn", true},
+}
+
+// ddlColumn returns Go code for reform.Column of i-th field of table s.
+// Column type is inferred from field type and labels; unknown types are mapped to strings.
+func ddlColumn(s parse.StructInfo, i int) string {
+	f := s.Fields[i]
+	typ := strings.TrimPrefix(f.Type, "*")
+	nullable := typ != f.Type
+
+	var t string
+	switch {
+	case f.JSON:
+		t = "JSONColumn"
+		nullable = nullable || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[")
+	case f.Encrypted:
+		t = "BytesColumn"
+	case f.Array:
+		t = ddlTypes[strings.TrimPrefix(typ, "[]")].t
+		nullable = true // nil slices are stored as NULL
+	default:
+		dt := ddlTypes[typ]
+		t = dt.t
+		nullable = nullable || dt.nullable
+	}
+	if t == "" {
+		t = "StringColumn"
+	}
+
+	res := fmt.Sprintf("{Name: %q, Type: reform.%s", f.Column, t)
+	if f.Array {
+		res += ", Array: true"
+	}
+	if nullable {
+		res += ", Nullable: true"
+	}
+	pk := i == s.PKFieldIndex
+	for _, j := range s.PKFieldIndexes {
+		pk = pk || i == j
+	}
+	if pk {
+		res += ", PK: true"
+		if !s.IsCompositePK() && !f.Generated && (t == "IntColumn" || t == "BigIntColumn") {
+			res += ", AutoIncrement: true"
+		}
+	}
+	return res + "}"
+}
+
+// sensitiveIndexes returns Go code for indexes of sensitive fields of s with leading ", ",
+// or empty string if there are no such fields.
+func sensitiveIndexes(s parse.StructInfo) string {
+	var res string
+	for i, f := range s.Fields {
+		if f.Sensitive {
+			res += fmt.Sprintf(", %d", i)
+		}
+	}
+	return res
+}
+
+// precision returns Go code for reform.TimestampPrecision of automatically set timestamp field f.
+func precision(f parse.FieldInfo) string {
+	switch f.Precision {
+	case "ms":
+		return "reform.MillisecondPrecision"
+	case "us":
+		return "reform.MicrosecondPrecision"
+	default:
+		return "reform.SecondPrecision"
+	}
+}
+
+// cloneField returns Go code for Clone method which deep copies pointer, slice, map and JSON field f
+// from s to c. For other fields it returns empty string: they are already copied by value.
+func cloneField(f parse.FieldInfo) string {
+	switch {
+	case f.JSON || strings.HasPrefix(f.Type, "map["):
+		return fmt.Sprintf(`
+	c.%[1]s, _ = reform.DeepCopy(s.%[1]s).(%[2]s)`, f.Name, f.Type)
+
+	case strings.HasPrefix(f.Type, "*[]"):
+		return fmt.Sprintf(`
+	if s.%[1]s != nil {
+		v := *s.%[1]s
+		if v != nil {
+			v = make(%[2]s, len(v))
+			copy(v, *s.%[1]s)
+		}
+		c.%[1]s = &v
+	}`, f.Name, f.Type[1:])
+
+	case strings.HasPrefix(f.Type, "*"):
+		return fmt.Sprintf(`
+	if s.%[1]s != nil {
+		v := *s.%[1]s
+		c.%[1]s = &v
+	}`, f.Name)
+
+	case strings.HasPrefix(f.Type, "[]"):
+		return fmt.Sprintf(`
+	if s.%[1]s != nil {
+		c.%[1]s = make(%[2]s, len(s.%[1]s))
+		copy(c.%[1]s, s.%[1]s)
+	}`, f.Name, f.Type)
+
+	default:
+		return ""
+	}
 }
 
 var (
@@ -25,10 +186,17 @@ import (
 )
 `))
 
-	structTemplate = template.Must(template.New("struct").Parse(`
+	structTemplate = template.Must(template.New("struct").Funcs(template.FuncMap{"clone": cloneField, "precision": precision, "ddl": ddlColumn, "sensitive": sensitiveIndexes}).Parse(`
 type {{ .TableType }} struct {
 	s parse.StructInfo
 	z []interface{}
+
+	// C contains column names of that view or table in SQL database, see {{ .Type }}Columns.
+	C struct {
+		{{- range .Fields }}
+		{{ .Name }} string
+		{{- end }}
+	}
 }
 
 // Name returns a view or table name in SQL database ({{ .SQLName }}).
@@ -46,6 +214,64 @@ func (v *{{ .TableType }}) NewStruct() reform.Struct {
 	return new({{ .Type }})
 }
 
+{{- if .HasEncryptedColumns }}
+
+// EncryptedColumns returns a new slice of flags: true for encrypted columns for that view or table in SQL database.
+func (v *{{ .TableType }}) EncryptedColumns() []bool {
+	return {{ printf "%#v" .EncryptedColumns }}
+}
+
+{{- end }}
+
+{{- if .HasSensitiveColumns }}
+
+// SensitiveColumns returns a new slice of flags: true for sensitive columns for that view or table in SQL database.
+func (v *{{ .TableType }}) SensitiveColumns() []bool {
+	return {{ printf "%#v" .SensitiveColumns }}
+}
+
+{{- end }}
+
+{{- if .HasLockField }}
+
+// LockColumnIndex returns an index of optimistic locking version column for that table in SQL database.
+func (v *{{ .TableType }}) LockColumnIndex() uint {
+	return {{ .LockFieldIndex }}
+}
+
+{{- end }}
+
+{{- if .HasGeneratedPK }}
+
+// GeneratedPK marks that table as having client-generated primary key.
+func (v *{{ .TableType }}) GeneratedPK() {}
+
+{{- end }}
+
+{{- if .HasAutoTimestampFields }}
+
+// AutoTimestamps returns automatically set timestamp columns for that view or table in SQL database.
+func (v *{{ .TableType }}) AutoTimestamps() []reform.AutoTimestamp {
+	return []reform.AutoTimestamp{
+	{{- range $i, $f := .Fields }}
+	{{- if or $f.AutoCreate $f.AutoUpdate }}
+		{Index: {{ $i }}, OnUpdate: {{ $f.AutoUpdate }}, Precision: {{ precision $f }}},
+	{{- end }}
+	{{- end }}
+	}
+}
+
+{{- end }}
+
+{{- if .HasSoftDeleteField }}
+
+// SoftDeleteColumnIndex returns an index of soft delete timestamp column for that table in SQL database.
+func (v *{{ .TableType }}) SoftDeleteColumnIndex() uint {
+	return {{ .SoftDeleteFieldIndex }}
+}
+
+{{- end }}
+
 {{- if .IsTable }}
 
 // NewRecord makes a new record for that table.
@@ -53,44 +279,237 @@ func (v *{{ .TableType }}) NewRecord() reform.Record {
 	return new({{ .Type }})
 }
 
-// PKColumnIndex returns an index of primary key column for that table in SQL database.
+// PKColumnIndex returns an index of {{ if .IsCompositePK }}the first {{ end }}primary key column for that table in SQL database.
 func (v *{{ .TableType }}) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+{{- if .IsCompositePK }}
+
+// PKColumnIndexes returns indexes of primary key columns for that table in SQL database.
+func (v *{{ .TableType }}) PKColumnIndexes() []uint {
+	return []uint{ {{- range $i, $pk := .PKFieldIndexes }}{{ if $i }}, {{ end }}{{ $pk }}{{ end -}} }
+}
+
+{{- end }}
+
+{{- if .GenerateDDL }}
+
+// DDLColumns returns a new slice of column definitions for that table in SQL database.
+func (v *{{ .TableType }}) DDLColumns() []reform.Column {
+	return []reform.Column{
+	{{- range $i, $f := .Fields }}
+		{{ ddl $.StructInfo $i }},
+	{{- end }}
+	}
+}
+
+// CreateTableSQL returns CREATE TABLE statement for that table in given SQL dialect.
+func (v *{{ .TableType }}) CreateTableSQL(dialect reform.Dialect) (string, error) {
+	return reform.CreateTableSQL(dialect, v)
+}
+
+{{- end }}
+
+{{- end }}
+
+{{- $sd := . }}
+{{- range .IndexFields }}
+
+// FindBy{{ .Name }} queries {{ $sd.SQLName }} with {{ .Column }} column and arg and returns the first found {{ $sd.Type }}.
+// Nil arg matches NULL values. If there are no rows in result, it returns nil, reform.ErrNoRows.
+func (v *{{ $sd.TableType }}) FindBy{{ .Name }}(q *reform.Querier, arg {{ .Type }}) (*{{ $sd.Type }}, error) {
+	tail, args, err := reform.Where(reform.Eq({{ printf "%q" .Column }}, arg)).Limit(1).Build(q.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	str, err := q.SelectOneFrom(v, tail, args...)
+	if err != nil {
+		return nil, err
+	}
+	return str.(*{{ $sd.Type }}), nil
+}
+
+// FindAllBy{{ .Name }} queries {{ $sd.SQLName }} with {{ .Column }} column and arg and returns a slice of found {{ $sd.Type }}s.
+// Nil arg matches NULL values. If error is encountered during iteration, partial result and error will be returned.
+// Error is never reform.ErrNoRows.
+func (v *{{ $sd.TableType }}) FindAllBy{{ .Name }}(q *reform.Querier, arg {{ .Type }}) ([]*{{ $sd.Type }}, error) {
+	tail, args, err := reform.Where(reform.Eq({{ printf "%q" .Column }}, arg)).Build(q.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	structs, err := q.SelectAllFrom(v, tail, args...)
+	if structs == nil {
+		return nil, err
+	}
+	res := make([]*{{ $sd.Type }}, len(structs))
+	for i, str := range structs {
+		res[i] = str.(*{{ $sd.Type }})
+	}
+	return res, err
+}
+
+{{- end }}
+
+{{- range .Relations }}
+
+// Preload{{ .Name }} loads {{ .Kind }} relation {{ .Name }} for all given records
+// with a single query (or a few for a large number of records) instead of a query per record.
+func (v *{{ $sd.TableType }}) Preload{{ .Name }}(q *reform.Querier, records []*{{ $sd.Type }}) error {
+	{{- if eq .Kind "belongs_to" }}
+	{{- $key := ($sd.ColumnField .Column).Name }}
+	keys := make([]interface{}, len(records))
+	for i, r := range records {
+		keys[i] = r.{{ $key }}
+	}
+	related := new({{ .Type }}).Table()
+	groups, err := q.FindAllGrouped(related, related.Columns()[related.PKColumnIndex()], keys...)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		r.{{ .Name }} = nil
+		if structs := groups.Get(r.{{ $key }}); len(structs) > 0 {
+			r.{{ .Name }} = structs[0].(*{{ .Type }})
+		}
+	}
+	{{- else }}
+	{{- $key := $sd.PKField.Name }}
+	keys := make([]interface{}, len(records))
+	for i, r := range records {
+		keys[i] = r.{{ $key }}
+	}
+	groups, err := q.FindAllGrouped(new({{ .Type }}).View(), {{ printf "%q" .Column }}, keys...)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		r.{{ .Name }} = nil
+		{{- if eq .Kind "has_many" }}
+		for _, str := range groups.Get(r.{{ $key }}) {
+			r.{{ .Name }} = append(r.{{ .Name }}, str.(*{{ .Type }}))
+		}
+		{{- else }}
+		if structs := groups.Get(r.{{ $key }}); len(structs) > 0 {
+			r.{{ .Name }} = structs[0].(*{{ .Type }})
+		}
+		{{- end }}
+	}
+	{{- end }}
+	return nil
+}
+
 {{- end }}
 
 // {{ .TableVar }} represents {{ .SQLName }} view or table in SQL database.
 var {{ .TableVar }} = &{{ .TableType }} {
 	s: {{ printf "%#v" .StructInfo }},
 	z: new({{ .Type }}).Values(),
+	C: {{ .Type }}Columns,
+}
+
+// {{ .Type }}Columns contains column names of {{ .SQLName }} view or table in SQL database.
+// Use them (or {{ .TableVar }}.C) instead of string literals, for example, with Querier.UpdateColumns
+// and reform.Eq: renamed or removed field causes compile errors instead of runtime ones.
+var {{ .Type }}Columns = struct {
+	{{- range .Fields }}
+	{{ .Name }} string
+	{{- end }}
+}{
+	{{- range .Fields }}
+	{{ .Name }}: {{ printf "%q" .Column }},
+	{{- end }}
 }
 
 // String returns a string representation of this struct or record.
 func (s {{ .Type }}) String() string {
 	res := make([]string, {{ len .Fields }})
 	{{- range $i, $f := .Fields }}
+	{{- if $f.Sensitive }}
+	res[{{ $i }}] = "{{ $f.Name }}: " + reform.Redacted
+	{{- else }}
 	res[{{ $i }}] = "{{ $f.Name }}: " + reform.Inspect(s.{{ $f.Name }}, true)
 	{{- end }}
+	{{- end }}
 	return strings.Join(res, ", ")
 }
 
+{{- if .GenerateEqual }}
+
+// GoString returns a string representation of this struct or record for %#v format verb.
+// Like String, it doesn't expose values of sensitive columns.
+func (s {{ .Type }}) GoString() string {
+	return "{{ .Type }}{" + s.String() + "}"
+}
+
+// Equal returns true if column values of this struct or record and other are equal.
+func (s *{{ .Type }}) Equal(other *{{ .Type }}) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return reform.EqualValues(s.Values(), other.Values())
+}
+
+{{- end }}
+
+{{- if .GenerateJSON }}
+
+// MarshalJSON encodes this struct or record as JSON object with column names as keys
+{{- if sensitive .StructInfo }}, omitting sensitive fields{{ end }}.
+func (s {{ .Type }}) MarshalJSON() ([]byte, error) {
+	return reform.MarshalStructJSON(&s{{ sensitive .StructInfo }})
+}
+
+// UnmarshalJSON decodes JSON object with column names as keys into this struct or record
+{{- if sensitive .StructInfo }}, ignoring sensitive fields{{ end }}.
+func (s *{{ .Type }}) UnmarshalJSON(b []byte) error {
+	return reform.UnmarshalStructJSON(s, b{{ sensitive .StructInfo }})
+}
+
+{{- end }}
+
+// Clone returns a deep copy of this struct or record.
+// Pointer, slice and map fields (used for nullable, binary and JSON columns) are copied by value,
+// so changes to the clone don't affect the original.
+func (s *{{ .Type }}) Clone() *{{ .Type }} {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	{{- range .Fields }}{{ clone . }}{{ end }}
+	return &c
+}
+
 // Values returns a slice of struct or record field values.
 // Returned interface{} values are never untyped nils.
+{{- if .HasJSONFields }} Values of fields with "json" label are wrapped with reform.JSON.{{ end }}
+{{- if .HasArrayFields }} Values of fields with "array" label are wrapped with reform.Array.{{ end }}
 func (s *{{ .Type }}) Values() []interface{} {
 	return []interface{}{ {{- range .Fields }}
-		s.{{ .Name }}, {{- end }}
+		{{ if .JSON }}reform.JSON{V: s.{{ .Name }}}{{ else if .Array }}reform.Array{V: s.{{ .Name }}}{{ else }}s.{{ .Name }}{{ end }}, {{- end }}
 	}
 }
 
 // Pointers returns a slice of pointers to struct or record fields.
 // Returned interface{} values are never untyped nils.
+{{- if .HasJSONFields }} Pointers to fields with "json" label are wrapped with reform.JSON.{{ end }}
+{{- if .HasArrayFields }} Pointers to fields with "array" label are wrapped with reform.Array.{{ end }}
 func (s *{{ .Type }}) Pointers() []interface{} {
 	return []interface{}{ {{- range .Fields }}
-		&s.{{ .Name }}, {{- end }}
+		{{ if .JSON }}&reform.JSON{V: &s.{{ .Name }}}{{ else if .Array }}&reform.Array{V: &s.{{ .Name }}}{{ else }}&s.{{ .Name }}{{ end }}, {{- end }}
 	}
 }
 
+{{- $sd := . }}
+{{- range .Relations }}
+
+// Load{{ .Name }} loads {{ .Kind }} relation {{ .Name }} for this {{ if $sd.IsTable }}record{{ else }}struct{{ end }}.
+func (s *{{ $sd.Type }}) Load{{ .Name }}(q *reform.Querier) error {
+	return {{ $sd.TableVar }}.Preload{{ .Name }}(q, []*{{ $sd.Type }}{s})
+}
+
+{{- end }}
+
 // View returns View object for that struct.
 func (s *{{ .Type }}) View() reform.View {
 	return {{ .TableVar }}
@@ -103,30 +522,67 @@ func (s *{{ .Type }}) Table() reform.Table {
 	return {{ .TableVar }}
 }
 
-// PKValue returns a value of primary key for that record.
+// PKValue returns a value of {{ if .IsCompositePK }}the first field of {{ end }}primary key for that record.
 // Returned interface{} value is never untyped nil.
 func (s *{{ .Type }}) PKValue() interface{} {
 	return s.{{ .PKField.Name }}
 }
 
-// PKPointer returns a pointer to primary key field for that record.
+// PKPointer returns a pointer to {{ if .IsCompositePK }}the first {{ end }}primary key field for that record.
 // Returned interface{} value is never untyped nil.
 func (s *{{ .Type }}) PKPointer() interface{} {
 	return &s.{{ .PKField.Name }}
 }
 
+{{- if .IsCompositePK }}
+
+// PKValues returns values of primary key for that record.
+// Returned interface{} values are never untyped nils.
+func (s *{{ .Type }}) PKValues() []interface{} {
+	return []interface{}{ {{- range .PKFields }}
+		s.{{ .Name }}, {{- end }}
+	}
+}
+
+// PKPointers returns pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *{{ .Type }}) PKPointers() []interface{} {
+	return []interface{}{ {{- range .PKFields }}
+		&s.{{ .Name }}, {{- end }}
+	}
+}
+
+// HasPK returns true if record has all primary key fields set to non-zero values, false otherwise.
+func (s *{{ .Type }}) HasPK() bool {
+	{{- $tv := .TableVar }}
+	{{- range $i, $pk := .PKFieldIndexes }}
+	if s.{{ (index $.Fields $pk).Name }} == {{ $tv }}.z[{{ $pk }}] {
+		return false
+	}
+	{{- end }}
+	return true
+}
+
+{{- else }}
+
 // HasPK returns true if record has non-zero primary key set, false otherwise.
 func (s *{{ .Type }}) HasPK() bool {
 	return s.{{ .PKField.Name }} != {{ .TableVar }}.z[{{ .TableVar }}.s.PKFieldIndex]
 }
 
-// SetPK sets record primary key.
+{{- end }}
+
+// SetPK sets record primary key{{ if .IsCompositePK }} (the first field of it){{ end }}.
 func (s *{{ .Type }}) SetPK(pk interface{}) {
+	{{- if .PKIntType }}
 	if i64, ok := pk.(int64); ok {
 		s.{{ .PKField.Name }} = {{ .PKField.Type }}(i64)
 	} else {
 		s.{{ .PKField.Name }} = pk.({{ .PKField.Type }})
 	}
+	{{- else }}
+	s.{{ .PKField.Name }} = pk.({{ .PKField.Type }})
+	{{- end }}
 }
 
 {{- end }}
@@ -138,8 +594,36 @@ var (
 {{- if .IsTable }}
 	_ reform.Table  = {{ .TableVar }}
 	_ reform.Record = new({{ .Type }})
+{{- end }}
+{{- if .IsCompositePK }}
+	_ reform.CompositePKTable  = {{ .TableVar }}
+	_ reform.CompositePKRecord = new({{ .Type }})
+{{- end }}
+{{- if .HasLockField }}
+	_ reform.LockingTable = {{ .TableVar }}
+{{- end }}
+{{- if .HasSoftDeleteField }}
+	_ reform.SoftDeleteTable = {{ .TableVar }}
+{{- end }}
+{{- if .HasGeneratedPK }}
+	_ reform.GeneratedPKTable = {{ .TableVar }}
+{{- end }}
+{{- if .HasAutoTimestampFields }}
+	_ reform.AutoTimestampsView = {{ .TableVar }}
+{{- end }}
+{{- if .HasEncryptedColumns }}
+	_ reform.EncryptedView = {{ .TableVar }}
+{{- end }}
+{{- if .HasSensitiveColumns }}
+	_ reform.SensitiveView = {{ .TableVar }}
+{{- end }}
+{{- if and .IsTable .GenerateDDL }}
+	_ reform.DDLTable = {{ .TableVar }}
 {{- end }}
 	_ fmt.Stringer   = new({{ .Type }})
+{{- if .GenerateEqual }}
+	_ fmt.GoStringer = new({{ .Type }})
+{{- end }}
 )
 `))
 
@@ -0,0 +1,491 @@
+// Package reformtest provides a fake SQL database for unit tests of code which uses reform.
+//
+// Fake doesn't interpret SQL: it records executed statements and returns stubbed rows and results
+// for the view (table) each statement refers to. That makes it possible to test service-layer code
+// without a live database and without matching SQL strings:
+//
+//	fake := reformtest.New(postgresql.Dialect)
+//	defer fake.Close()
+//	fake.Stub(PersonTable, &Person{ID: 1, Name: "Alice"})
+//
+//	svc := NewService(fake.DB)
+//	...
+//	commands := fake.Commands(PersonTable)
+//
+// SELECT statements return all stubbed rows of the view (WHERE, ORDER BY, LIMIT, etc. are ignored);
+// SELECT COUNT(*) returns their number, SELECT 1 returns one row for each of them.
+// INSERT statements return sequential primary keys via LastInsertId or RETURNING clause.
+// Other commands affect one row by default, see StubRowsAffected.
+package reformtest // TODO add canonical import path via gopkg.in
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/AlekSi/reform"
+)
+
+// Statement represents a single statement executed by Fake.
+type Statement struct {
+	View      string        // view or table name the statement refers to, or empty string
+	Operation string        // the first keyword of statement in upper case, like SELECT, INSERT or COMMIT
+	Query     string        // SQL query
+	Args      []interface{} // query arguments as passed by reform
+}
+
+// Fake is a fake SQL database. It is safe for concurrent use.
+type Fake struct {
+	// DB is reform's DB which uses this fake; it may be used directly or via its transactions.
+	DB *reform.DB
+
+	db           *sql.DB
+	m            sync.Mutex
+	rows         map[string][][]driver.Value
+	columns      map[string][]string
+	errs         map[string]error
+	rowsAffected map[string]int64
+	statements   []Statement
+	lastID       int64
+}
+
+// New creates a new fake SQL database with given dialect.
+// Dialect affects generated queries and the way primary keys of inserted records are returned.
+func New(dialect reform.Dialect) *Fake {
+	f := &Fake{
+		rows:         make(map[string][][]driver.Value),
+		columns:      make(map[string][]string),
+		errs:         make(map[string]error),
+		rowsAffected: make(map[string]int64),
+	}
+	f.db = sql.OpenDB(connector{f})
+	f.DB = reform.NewDB(f.db, dialect, nil)
+	return f
+}
+
+// Close closes underlying *sql.DB.
+func (f *Fake) Close() error {
+	return f.db.Close()
+}
+
+// Stub sets rows returned by SELECT statements on given view: column values of given structs.
+// It replaces previously stubbed rows; no structs means no rows.
+// Struct values are converted like database/sql does for query arguments.
+// Columns with NULL values in stubbed rows are reported as nullable (see sql.ColumnType.Nullable).
+func (f *Fake) Stub(view reform.View, structs ...reform.Struct) error {
+	rows := make([][]driver.Value, len(structs))
+	for i, str := range structs {
+		values := str.Values()
+		row := make([]driver.Value, len(values))
+		for j, v := range values {
+			dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+			if err != nil {
+				return fmt.Errorf("reformtest: %s: %s", view.Columns()[j], err)
+			}
+			row[j] = dv
+		}
+		rows[i] = row
+	}
+
+	f.m.Lock()
+	f.rows[view.Name()] = rows
+	f.columns[view.Name()] = view.Columns()
+	f.m.Unlock()
+	return nil
+}
+
+// StubError sets error returned by all statements on given view; nil removes it.
+func (f *Fake) StubError(view reform.View, err error) {
+	f.m.Lock()
+	if err == nil {
+		delete(f.errs, view.Name())
+	} else {
+		f.errs[view.Name()] = err
+	}
+	f.m.Unlock()
+}
+
+// StubRowsAffected sets number of rows affected by UPDATE and DELETE commands on given view; default is 1.
+// For example, zero makes reform's Update and Delete return ErrNoRows (if there are no stubbed rows).
+func (f *Fake) StubRowsAffected(view reform.View, n int64) {
+	f.m.Lock()
+	f.rowsAffected[view.Name()] = n
+	f.m.Unlock()
+}
+
+// Statements returns a copy of all executed statements in order of execution.
+func (f *Fake) Statements() []Statement {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	res := make([]Statement, len(f.statements))
+	copy(res, f.statements)
+	return res
+}
+
+// Commands returns executed INSERT, UPDATE and DELETE statements on given view in order of execution.
+func (f *Fake) Commands(view reform.View) []Statement {
+	var res []Statement
+	for _, s := range f.Statements() {
+		if s.View != view.Name() {
+			continue
+		}
+		switch s.Operation {
+		case "INSERT", "UPDATE", "DELETE":
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// Reset removes all stubs and recorded statements.
+func (f *Fake) Reset() {
+	f.m.Lock()
+	f.rows = make(map[string][][]driver.Value)
+	f.columns = make(map[string][]string)
+	f.errs = make(map[string]error)
+	f.rowsAffected = make(map[string]int64)
+	f.statements = nil
+	f.lastID = 0
+	f.m.Unlock()
+}
+
+// record records executed statement and returns parsed information about it.
+func (f *Fake) record(query string, args []driver.NamedValue) (*parsed, error) {
+	p := parse(query)
+	a := make([]interface{}, len(args))
+	for i, arg := range args {
+		a[i] = arg.Value
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.statements = append(f.statements, Statement{
+		View:      p.view,
+		Operation: p.operation,
+		Query:     query,
+		Args:      a,
+	})
+	if err := f.errs[p.view]; err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// exec executes command.
+func (f *Fake) exec(query string, args []driver.NamedValue) (driver.Result, error) {
+	p, err := f.record(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	switch p.operation {
+	case "INSERT":
+		n := p.insertedRows(len(args))
+		f.lastID += n
+		return result{lastID: f.lastID, rowsAffected: n}, nil
+	case "UPDATE", "DELETE":
+		n, ok := f.rowsAffected[p.view]
+		if !ok {
+			n = 1
+		}
+		return result{lastID: f.lastID, rowsAffected: n}, nil
+	default:
+		return result{lastID: f.lastID}, nil
+	}
+}
+
+// query executes query.
+func (f *Fake) query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	p, err := f.record(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	switch {
+	case p.operation == "INSERT":
+		// RETURNING, OUTPUT INSERTED, etc.: return generated primary keys
+		n := p.insertedRows(len(args))
+		res := &rows{columns: []string{"id"}}
+		for i := int64(0); i < n; i++ {
+			f.lastID++
+			res.values = append(res.values, []driver.Value{f.lastID})
+		}
+		return res, nil
+
+	case p.count:
+		return &rows{
+			columns: []string{"count"},
+			values:  [][]driver.Value{{int64(len(f.rows[p.view]))}},
+		}, nil
+
+	case p.one:
+		res := &rows{columns: []string{"1"}}
+		for range f.rows[p.view] {
+			res.values = append(res.values, []driver.Value{int64(1)})
+		}
+		return res, nil
+
+	default:
+		return &rows{
+			columns: f.columns[p.view],
+			values:  f.rows[p.view],
+		}, nil
+	}
+}
+
+// parsed represents information about statement extracted from its text.
+type parsed struct {
+	operation string
+	view      string
+	count     bool // SELECT COUNT(*)
+	one       bool // SELECT 1
+	columns   int  // number of columns in INSERT statement, or 0 if unknown
+}
+
+// parse extracts information about statement from its text.
+func parse(query string) *parsed {
+	fields := strings.Fields(skipComments(query))
+	if len(fields) == 0 {
+		return new(parsed)
+	}
+	res := &parsed{operation: strings.ToUpper(fields[0])}
+
+	// keyword which is followed by view name
+	var keyword string
+	switch res.operation {
+	case "SELECT":
+		keyword = "FROM"
+		if len(fields) > 1 {
+			res.count = strings.HasPrefix(strings.ToUpper(fields[1]), "COUNT(")
+			res.one = fields[1] == "1"
+		}
+	case "INSERT", "REPLACE":
+		res.operation = "INSERT"
+		keyword = "INTO"
+	case "UPDATE":
+		res.view = unquote(next(fields, 1))
+		return res
+	case "DELETE":
+		keyword = "FROM"
+	default:
+		return res
+	}
+
+	// skip subqueries like "FROM (SELECT * FROM people WHERE deleted_at IS NULL) people"
+	// used for soft delete, tenant and scope filters: view name is in the innermost one
+	for i, f := range fields {
+		if strings.EqualFold(f, keyword) {
+			if res.view = unquote(next(fields, i+1)); res.view != "" {
+				break
+			}
+		}
+	}
+
+	if res.operation == "INSERT" {
+		if i := strings.Index(query, "("); i >= 0 {
+			if j := strings.Index(query[i:], ")"); j >= 0 {
+				res.columns = strings.Count(query[i:i+j], ",") + 1
+			}
+		}
+	}
+	return res
+}
+
+// insertedRows returns number of rows inserted by INSERT statement with given number of arguments.
+func (p *parsed) insertedRows(args int) int64 {
+	if p.columns == 0 || args < p.columns {
+		return 1
+	}
+	return int64(args / p.columns)
+}
+
+// skipComments removes leading SQL comments like tag comments.
+func skipComments(query string) string {
+	query = strings.TrimSpace(query)
+	for strings.HasPrefix(query, "/*") {
+		i := strings.Index(query, "*/")
+		if i < 0 {
+			return query
+		}
+		query = strings.TrimSpace(query[i+2:])
+	}
+	return query
+}
+
+// next returns fields[i] with everything after the first "(" removed, or empty string.
+func next(fields []string, i int) string {
+	if i >= len(fields) {
+		return ""
+	}
+	f := fields[i]
+	if j := strings.Index(f, "("); j >= 0 {
+		f = f[:j]
+	}
+	return f
+}
+
+// unquote removes identifier quotes of all supported dialects and schema prefix.
+func unquote(identifier string) string {
+	identifier = strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(identifier)
+	if i := strings.LastIndex(identifier, "."); i >= 0 {
+		identifier = identifier[i+1:]
+	}
+	return identifier
+}
+
+// connector implements driver.Connector for Fake.
+type connector struct {
+	f *Fake
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return &conn{c.f}, nil }
+func (c connector) Driver() driver.Driver                        { return fakeDriver{c.f} }
+
+// fakeDriver implements driver.Driver for Fake.
+type fakeDriver struct {
+	f *Fake
+}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) { return &conn{d.f}, nil }
+
+// conn implements driver.Conn for Fake.
+type conn struct {
+	f *Fake
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) { return &stmt{c.f, query}, nil }
+func (c *conn) Close() error                              { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.f.record("BEGIN", nil)
+	return tx{c.f}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.f.exec(query, args)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.f.query(query, args)
+}
+
+// CheckNamedValue accepts all arguments as is, so they are recorded unchanged.
+func (c *conn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+// stmt implements driver.Stmt for Fake.
+type stmt struct {
+	f     *Fake
+	query string
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.f.exec(s.query, named(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.f.query(s.query, named(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.f.exec(s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.f.query(s.query, args)
+}
+
+// CheckNamedValue accepts all arguments as is, so they are recorded unchanged.
+func (s *stmt) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+// named converts values to named values.
+func named(args []driver.Value) []driver.NamedValue {
+	res := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		res[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return res
+}
+
+// tx implements driver.Tx for Fake.
+type tx struct {
+	f *Fake
+}
+
+func (t tx) Commit() error {
+	_, err := t.f.record("COMMIT", nil)
+	return err
+}
+
+func (t tx) Rollback() error {
+	_, err := t.f.record("ROLLBACK", nil)
+	return err
+}
+
+// result implements driver.Result for Fake.
+type result struct {
+	lastID       int64
+	rowsAffected int64
+}
+
+func (r result) LastInsertId() (int64, error) { return r.lastID, nil }
+func (r result) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+// rows implements driver.Rows for Fake.
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+	i       int
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+// ColumnTypeNullable reports column as nullable if it has NULL values in stubbed rows;
+// nullability is unknown if there are no rows.
+func (r *rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	for _, row := range r.values {
+		if row[index] == nil {
+			return true, true
+		}
+	}
+	return false, len(r.values) > 0
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.i >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.i])
+	r.i++
+	return nil
+}
+
+// check interfaces
+var (
+	_ driver.Connector         = connector{}
+	_ driver.ExecerContext     = new(conn)
+	_ driver.QueryerContext    = new(conn)
+	_ driver.NamedValueChecker = new(conn)
+	_ driver.StmtExecContext   = new(stmt)
+	_ driver.StmtQueryContext  = new(stmt)
+	_ driver.NamedValueChecker = new(stmt)
+	_ driver.Result            = result{}
+	_ driver.Rows              = new(rows)
+
+	_ driver.RowsColumnTypeNullable = new(rows)
+)
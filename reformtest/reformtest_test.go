@@ -0,0 +1,110 @@
+package reformtest
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/internal/test/models"
+)
+
+func TestFake(t *testing.T) {
+	for _, dialect := range []reform.Dialect{postgresql.Dialect, mysql.Dialect} {
+		f := New(dialect)
+
+		require.NoError(t, f.Stub(models.PersonTable, &models.Person{ID: 1, Name: "Alice"}, &models.Person{ID: 2, Name: "Bob"}))
+		record, err := f.DB.FindByPrimaryKeyFrom(models.PersonTable, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", record.(*models.Person).Name)
+		n, err := f.DB.Count(models.PersonTable, "")
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), n)
+
+		person := &models.Person{Name: "Carol"}
+		require.NoError(t, f.DB.Insert(person))
+		assert.Equal(t, int32(1), person.ID)
+		require.NoError(t, f.DB.InsertMulti(&models.Person{Name: "Dave"}, &models.Person{Name: "Eve"}))
+		person2 := &models.Person{Name: "Frank"}
+		require.NoError(t, f.DB.Insert(person2))
+		assert.Equal(t, int32(4), person2.ID)
+
+		require.NoError(t, f.DB.InTransaction(func(tx *reform.TX) error {
+			return tx.Update(person)
+		}))
+
+		f.StubRowsAffected(models.PersonTable, 0)
+		require.NoError(t, f.Stub(models.PersonTable))
+		assert.Equal(t, reform.ErrNoRows, f.DB.Delete(person))
+		_, err = f.DB.FindByPrimaryKeyFrom(models.PersonTable, 1)
+		assert.Equal(t, reform.ErrNoRows, err)
+		assert.True(t, errors.Is(err, sql.ErrNoRows), "%+v", err)
+
+		errBoom := errors.New("boom")
+		f.StubError(models.PersonTable, errBoom)
+		assert.Equal(t, errBoom, f.DB.Delete(person))
+
+		commands := f.Commands(models.PersonTable)
+		require.Len(t, commands, 6)
+		for i, op := range []string{"INSERT", "INSERT", "INSERT", "UPDATE", "DELETE", "DELETE"} {
+			assert.Equal(t, op, commands[i].Operation)
+			assert.Equal(t, "people", commands[i].View)
+		}
+		assert.Equal(t, "Carol", commands[3].Args[0])
+
+		f.Reset()
+		assert.Empty(t, f.Statements())
+		require.NoError(t, f.Close())
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	f := New(postgresql.Dialect)
+	defer f.Close()
+
+	// soft deleted rows are filtered by subquery, but stubbed rows are returned as is
+	require.NoError(t, f.Stub(models.MemoTable, &models.Memo{ID: 1, Text: "memo"}))
+	record, err := f.DB.FindByPrimaryKeyFrom(models.MemoTable, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "memo", record.(*models.Memo).Text)
+	n, err := f.DB.Count(models.MemoTable, "")
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), n)
+
+	require.NoError(t, f.DB.Delete(record))
+
+	statements := f.Statements()
+	require.Len(t, statements, 3)
+	for i, op := range []string{"SELECT", "SELECT", "UPDATE"} {
+		assert.Equal(t, op, statements[i].Operation)
+		assert.Equal(t, "memos", statements[i].View)
+	}
+	assert.Contains(t, statements[0].Query, "(SELECT * FROM")
+}
+
+func TestParse(t *testing.T) {
+	for query, expected := range map[string]parsed{
+		`SELECT "people"."id" FROM "people" WHERE id = $1`:        {operation: "SELECT", view: "people"},
+		"/* tag */ SELECT COUNT(*) FROM `people`":                 {operation: "SELECT", view: "people", count: true},
+		"SELECT 1 FROM [dbo].[people] WHERE id = @p1":             {operation: "SELECT", view: "people", one: true},
+		`INSERT INTO "people" ("name", "email") VALUES ($1, $2)`:  {operation: "INSERT", view: "people", columns: 2},
+		`UPDATE "people" SET "name" = $1 WHERE "id" = $2`:         {operation: "UPDATE", view: "people"},
+		`DELETE FROM "people" WHERE "id" = $1`:                    {operation: "DELETE", view: "people"},
+		"COMMIT":                                                  {operation: "COMMIT"},
+		`INSERT INTO "people"("name") VALUES ($1) RETURNING "id"`: {operation: "INSERT", view: "people", columns: 1},
+		`REPLACE INTO "people" ("id", "name") VALUES ($1, $2)`:    {operation: "INSERT", view: "people", columns: 2},
+		`SELECT "memos"."id" FROM (SELECT * FROM "memos" WHERE "deleted_at" IS NULL) "memos" WHERE id = $1`: {
+			operation: "SELECT", view: "memos",
+		},
+		`SELECT COUNT(*) FROM (SELECT * FROM "memos" WHERE "deleted_at" IS NULL) "memos"`: {
+			operation: "SELECT", view: "memos", count: true,
+		},
+	} {
+		assert.Equal(t, &expected, parse(query), "%s", query)
+	}
+}
@@ -0,0 +1,87 @@
+package reform
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GroupedStructs contains structs grouped by column value. See Querier.FindAllGrouped.
+type GroupedStructs map[interface{}][]Struct
+
+// Get returns structs for given column value. Pointers are dereferenced, so *T and T values
+// are considered equal; nil value matches nothing.
+func (g GroupedStructs) Get(value interface{}) []Struct {
+	k := groupKey(value)
+	if k == nil {
+		return nil
+	}
+	return g[k]
+}
+
+// groupKey returns map key for given value: pointers are dereferenced, []byte is converted to string.
+// It returns nil for nil and nil pointers.
+func groupKey(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if b, ok := v.Interface().([]byte); ok {
+		return string(b)
+	}
+	return v.Interface()
+}
+
+// FindAllGrouped queries view for rows with column value in values and returns them grouped by that value.
+// Nil values and duplicates are skipped; a large number of values is split into several queries
+// according to Dialect.MaxPlaceholders. Values should have the same type as struct field for column
+// (or a pointer to it).
+//
+// It is used by generated Load and Preload methods for relations defined with "reform-rel:" tag.
+func (q *Querier) FindAllGrouped(view View, column string, values ...interface{}) (GroupedStructs, error) {
+	index := -1
+	for i, c := range view.Columns() {
+		if c == column {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("reform: %s has no column %s", view.Name(), column)
+	}
+
+	seen := make(map[interface{}]bool, len(values))
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		k := groupKey(v)
+		if k == nil || seen[k] {
+			continue
+		}
+		seen[k] = true
+		args = append(args, k)
+	}
+
+	res := make(GroupedStructs)
+	max := q.MaxPlaceholders()
+	for len(args) > 0 {
+		n := len(args)
+		if n > max {
+			n = max
+		}
+		structs, err := q.FindAllFrom(view, column, args[:n]...)
+		if err != nil {
+			return nil, err
+		}
+		for _, str := range structs {
+			k := groupKey(str.Values()[index])
+			res[k] = append(res[k], str)
+		}
+		args = args[n:]
+	}
+	return res, nil
+}
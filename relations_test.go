@@ -0,0 +1,56 @@
+package reform_test
+
+import (
+	"github.com/AlekSi/pointer"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestRelations() {
+	persons, err := PersonTable.FindAllByName(s.q.Querier, "Elfrieda Abbott")
+	s.NoError(err)
+	s.Require().Len(persons, 2)
+	person1, err := PersonTable.FindByName(s.q.Querier, "Denis Mills")
+	s.NoError(err)
+	persons = append(persons, person1)
+
+	rl := reform.NewRecordingLogger()
+	s.q.Logger = rl
+	s.NoError(PersonTable.PreloadRoles(s.q.Querier, persons))
+	s.Len(rl.Statements(), 1)
+	for _, p := range persons {
+		switch p.ID {
+		case 102:
+			s.Require().Len(p.Roles, 1)
+			s.Equal("lead", p.Roles[0].Role)
+		case 103:
+			s.Require().Len(p.Roles, 1)
+			s.Equal("developer", p.Roles[0].Role)
+		default:
+			s.Nil(p.Roles)
+		}
+	}
+
+	role := persons[0].Roles[0]
+	s.NoError(role.LoadProject(s.q.Querier))
+	s.Require().NotNil(role.Project)
+	s.Equal("Vicious Baron", role.Project.Name)
+
+	s.NoError(PersonTable.PreloadRoles(s.q.Querier, nil))
+	s.NoError(person1.LoadRoles(s.q.Querier))
+	s.Nil(person1.Roles)
+}
+
+func (s *ReformSuite) TestFindAllGrouped() {
+	groups, err := s.q.FindAllGrouped(ProjectRoleTable, "project_id", "baron", pointer.ToString("baron"), nil, "queen")
+	s.NoError(err)
+	s.Len(groups, 1)
+	s.Len(groups.Get("baron"), 2)
+	s.Len(groups.Get(pointer.ToString("baron")), 2)
+	s.Nil(groups.Get("queen"))
+	s.Nil(groups.Get(nil))
+
+	_, err = s.q.FindAllGrouped(ProjectRoleTable, "bogus", "baron")
+	s.EqualError(err, "reform: project_roles has no column bogus")
+}
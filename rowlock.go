@@ -0,0 +1,93 @@
+package reform
+
+import (
+	"strings"
+)
+
+// RowLock is a row-level locking mode for SELECT queries: ForUpdate or ForShare,
+// optionally combined with SkipLocked or NoWait, for example, ForUpdate|SkipLocked.
+// See Querier.WithRowLock.
+type RowLock int
+
+const (
+	// ForUpdate locks selected rows for update: "FOR UPDATE".
+	ForUpdate RowLock = 1 << iota
+
+	// ForShare locks selected rows against concurrent updates, but not reads: "FOR SHARE".
+	ForShare
+
+	// SkipLocked skips rows which can't be locked immediately: "SKIP LOCKED".
+	SkipLocked
+
+	// NoWait returns error instead of waiting for rows which can't be locked immediately: "NOWAIT".
+	NoWait
+)
+
+// Clause returns SQL locking clause for this mode, like "FOR UPDATE SKIP LOCKED".
+// It returns empty string for zero mode, and panics for invalid combinations.
+func (l RowLock) Clause() string {
+	if l == 0 {
+		return ""
+	}
+
+	var parts []string
+	switch l & (ForUpdate | ForShare) {
+	case ForUpdate:
+		parts = append(parts, "FOR UPDATE")
+	case ForShare:
+		parts = append(parts, "FOR SHARE")
+	default:
+		panic("reform: RowLock should contain either ForUpdate or ForShare")
+	}
+
+	switch l & (SkipLocked | NoWait) {
+	case 0:
+	case SkipLocked:
+		parts = append(parts, "SKIP LOCKED")
+	case NoWait:
+		parts = append(parts, "NOWAIT")
+	default:
+		panic("reform: RowLock can't contain both SkipLocked and NoWait")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// WithRowLock returns a copy of querier which appends locking clause for given mode
+// to SELECT queries of selectors and finders (lock 0 removes it).
+// Rows are locked until the end of transaction, so it should be used with TX:
+//
+//	err = tx.WithRowLock(reform.ForUpdate).FindByPrimaryKeyTo(person, 1)
+//
+// Selectors and finders return ErrRowLockNotSupported if mode is not supported by Dialect (see RowLockingDialect),
+// instead of running query without locking.
+func (q *Querier) WithRowLock(lock RowLock) *Querier {
+	lock.Clause() // check combination early
+	nq := q.clone()
+	nq.rowLock = lock
+	return nq
+}
+
+// rowLockClause returns locking clause for querier's mode and Dialect, with leading space,
+// or empty string if mode is not set.
+// It returns ErrRowLockNotSupported if mode is not supported by Dialect.
+func (q *Querier) rowLockClause() (string, error) {
+	if q.rowLock == 0 {
+		return "", nil
+	}
+
+	clause := q.rowLock.Clause()
+	if rd, ok := q.Dialect.(RowLockingDialect); ok {
+		clause = rd.RowLockClause(q.rowLock)
+	}
+	if clause == "" {
+		return "", ErrRowLockNotSupported
+	}
+	return " " + clause, nil
+}
+
+// FindByPrimaryKeyForUpdateTo is like FindByPrimaryKeyTo, but also locks found row with "FOR UPDATE"
+// until the end of transaction, so it can be safely changed and updated. It should be used with TX.
+func (q *Querier) FindByPrimaryKeyForUpdateTo(record Record, pk interface{}) error {
+	return q.WithRowLock(ForUpdate).FindByPrimaryKeyTo(record, pk)
+}
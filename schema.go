@@ -0,0 +1,208 @@
+package reform
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// WithSchema returns a copy of querier which qualifies table and view names in generated queries and commands
+// with given schema (database in MySQL terms), so one set of generated views can be used
+// for schema-per-tenant deployments:
+//
+//	tenant := DB.WithSchema("tenant_42")
+//	person, err := tenant.FindByPrimaryKeyFrom(PersonTable, 1) // SELECT ... FROM "tenant_42"."people" ...
+//
+// Empty schema disables qualification. Only FROM, INSERT INTO, UPDATE and DELETE FROM targets are qualified;
+// column names are still qualified with unqualified table name, so tails like "WHERE people.id = $1" work as before.
+// Raw queries passed to Exec, Query and QueryRow are not changed.
+// Records are cached by RecordCache separately for each schema.
+// CopyFrom uses multi-row INSERT statements instead of COPY-like protocols for querier with schema.
+func (q *Querier) WithSchema(schema string) *Querier {
+	nq := q.clone()
+	nq.schema = schema
+	return nq
+}
+
+// Schema returns schema set by WithSchema, or empty string.
+func (q *Querier) Schema() string {
+	return q.schema
+}
+
+// QualifiedView returns quoted view or table name qualified with querier's schema, if it is set.
+func (q *Querier) QualifiedView(view View) string {
+	return q.quoteTable(view.Name())
+}
+
+// quoteTable returns quoted table name qualified with querier's schema, if it is set.
+func (q *Querier) quoteTable(name string) string {
+	if q.schema == "" {
+		return q.QuoteIdentifier(name)
+	}
+	return q.QuoteIdentifier(q.schema) + "." + q.QuoteIdentifier(name)
+}
+
+// SchemaMismatch describes a difference between generated view or table and SQL database.
+type SchemaMismatch struct {
+	View    string // view or table name
+	Column  string // column name
+	Problem string // description, like "missing column"
+}
+
+// String returns a string representation of mismatch.
+func (m SchemaMismatch) String() string {
+	return m.View + "." + m.Column + ": " + m.Problem
+}
+
+// SchemaError is returned by Querier.CheckSchema when SQL database doesn't match generated views and tables.
+type SchemaError struct {
+	Mismatches []SchemaMismatch
+}
+
+// Error implements error interface.
+func (e *SchemaError) Error() string {
+	res := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		res[i] = m.String()
+	}
+	return "reform: schema mismatch: " + strings.Join(res, "; ")
+}
+
+// CheckSchema checks that given views and tables (qualified with querier's schema, see WithSchema)
+// exist in SQL database and have all generated columns with types compatible with struct fields,
+// so deployment with outdated schema fails at startup instead of the first query:
+//
+//	if err := DB.CheckSchema(PersonTable, ProjectTable); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// It returns *SchemaError with all found mismatches, or query error for missing view or table.
+// Column names are compared case-insensitively. Types are checked only if database driver reports them
+// (see sql.ColumnType.ScanType) as Go basic types or time.Time: for example, string column doesn't match int32 field,
+// but any column matches string field. Nullable columns don't match fields which can't store NULL
+// (not pointers, slices, sql.NullXXX-like structs or sql.Scanner implementations), if driver reports nullability
+// (see sql.ColumnType.Nullable); if all columns are reported as nullable (as some drivers do), it is not checked.
+// Extra columns in SQL database are not reported.
+func (q *Querier) CheckSchema(views ...View) error {
+	var mismatches []SchemaMismatch
+	for _, view := range views {
+		m, err := q.checkView(view)
+		if err != nil {
+			return err
+		}
+		mismatches = append(mismatches, m...)
+	}
+	if len(mismatches) > 0 {
+		return &SchemaError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+// checkView returns mismatches between given view and SQL database.
+func (q *Querier) checkView(view View) ([]SchemaMismatch, error) {
+	rows, cancel, err := q.queryRows(view.Name(), "SELECT * FROM "+q.QualifiedView(view)+" WHERE 1 = 0")
+	if err != nil {
+		return nil, fmt.Errorf("reform: %s: %w", view.Name(), err)
+	}
+	defer cancel()
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	actual := make(map[string]*sql.ColumnType, len(types))
+	checkNull := false
+	for _, t := range types {
+		actual[strings.ToLower(t.Name())] = t
+		if nullable, ok := t.Nullable(); ok && !nullable {
+			checkNull = true
+		}
+	}
+
+	var res []SchemaMismatch
+	pointers := view.NewStruct().Pointers()
+	for i, c := range view.Columns() {
+		t := actual[strings.ToLower(c)]
+		if t == nil {
+			res = append(res, SchemaMismatch{View: view.Name(), Column: c, Problem: "missing column"})
+			continue
+		}
+
+		field := reflect.TypeOf(pointers[i]).Elem()
+		if nullable, _ := t.Nullable(); checkNull && nullable && !canBeNull(field) {
+			res = append(res, SchemaMismatch{
+				View:    view.Name(),
+				Column:  c,
+				Problem: fmt.Sprintf("nullable column is incompatible with field type %s", field),
+			})
+			continue
+		}
+
+		fc, cc := typeClass(field, false), typeClass(t.ScanType(), true)
+		if fc == "" || cc == "" || fc == cc || fc == "string" || (fc == "bool" && cc == "number") {
+			continue
+		}
+		dt := t.DatabaseTypeName()
+		if dt == "" {
+			dt = cc
+		}
+		res = append(res, SchemaMismatch{
+			View:    view.Name(),
+			Column:  c,
+			Problem: fmt.Sprintf("column type %s is incompatible with field type %s", dt, field),
+		})
+	}
+	return res, rows.Err()
+}
+
+// scannerType is a reflect.Type of sql.Scanner interface.
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// canBeNull returns true if field of given type can store NULL value.
+func canBeNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return reflect.PtrTo(t).Implements(scannerType)
+}
+
+// typeClass returns "number", "bool", "string" or "time" for Go type, or empty string for other types
+// (slices, structs, etc.) which are not checked by CheckSchema. Pointers and sql.NullXXX-like structs are unwrapped.
+// Field types are classified by kind; driver's scan types are classified only if they are basic or time.Time,
+// as driver-specific named types may be scanned into anything.
+func typeClass(t reflect.Type, scan bool) string {
+	if t == nil {
+		return ""
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t.NumField() == 2 {
+		if valid, ok := t.FieldByName("Valid"); ok && valid.Index[0] == 1 {
+			t = t.Field(0).Type
+		}
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return "time"
+	}
+	if scan && t.PkgPath() != "" {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,106 @@
+package reform_test
+
+import (
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func (s *ReformSuite) TestWithSchema() {
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+
+	tenant := f.DB.WithSchema("tenant_42")
+	s.Equal("tenant_42", tenant.Schema())
+	s.Equal(`"tenant_42"."people"`, tenant.QualifiedView(models.PersonTable))
+	s.Equal(`"people"`, f.DB.QualifiedView(models.PersonTable))
+
+	person := &models.Person{ID: 1, Name: "Tenant"}
+	s.Require().NoError(tenant.Insert(person))
+	s.Require().NoError(f.Stub(models.PersonTable, person))
+	_, err := tenant.FindByPrimaryKeyFrom(models.PersonTable, 1)
+	s.Require().NoError(err)
+	s.Require().NoError(tenant.Delete(person))
+
+	statements := f.Statements()
+	s.Require().Len(statements, 3)
+	s.Contains(statements[0].Query, `INSERT INTO "tenant_42"."people" `)
+	s.Contains(statements[1].Query, `FROM "tenant_42"."people" WHERE "people"."id" = $1`)
+	s.Contains(statements[2].Query, `DELETE FROM "tenant_42"."people" WHERE`)
+
+	// real database
+	if s.q.Dialect != postgresql.Dialect {
+		s.T().Skip("PostgreSQL-specific test")
+	}
+	q := s.q.WithSchema("public")
+	person = &models.Person{Name: "Public"}
+	s.Require().NoError(q.Insert(person))
+	record, err := q.FindByPrimaryKeyFrom(models.PersonTable, person.ID)
+	s.Require().NoError(err)
+	s.Equal("Public", record.(*models.Person).Name)
+	s.NoError(q.Delete(person))
+}
+
+// oldPeople is a view of people table before email column was added.
+type oldPeople struct {
+	reform.View
+}
+
+func (oldPeople) Columns() []string {
+	return []string{"id", "name", "created_at", "updated_at"}
+}
+
+// missingPeople is a view of people table which doesn't exist.
+type missingPeople struct {
+	reform.View
+}
+
+func (missingPeople) Name() string {
+	return "missing_people"
+}
+
+// swappedPeople is a view of people table with swapped name and email columns,
+// so stubbed NULL email is returned as name.
+type swappedPeople struct {
+	reform.View
+}
+
+func (swappedPeople) Columns() []string {
+	return []string{"id", "email", "name", "created_at", "updated_at"}
+}
+
+func (s *ReformSuite) TestCheckSchema() {
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+
+	s.Require().NoError(f.Stub(models.PersonTable))
+	s.Require().NoError(f.Stub(models.ProjectTable))
+	s.NoError(f.DB.CheckSchema(models.PersonTable, models.ProjectTable))
+	s.Equal(`SELECT * FROM "people" WHERE 1 = 0`, f.Statements()[0].Query)
+
+	s.Require().NoError(f.Stub(oldPeople{models.PersonTable}))
+	err := f.DB.CheckSchema(models.PersonTable, models.ProjectTable)
+	s.Require().IsType(new(reform.SchemaError), err)
+	s.Equal([]reform.SchemaMismatch{{View: "people", Column: "email", Problem: "missing column"}}, err.(*reform.SchemaError).Mismatches)
+	s.EqualError(err, "reform: schema mismatch: people.email: missing column")
+
+	person := &models.Person{ID: 1, Name: "Nullable"}
+	s.Require().NoError(f.Stub(models.PersonTable, person))
+	s.NoError(f.DB.CheckSchema(models.PersonTable))
+	s.Require().NoError(f.Stub(swappedPeople{models.PersonTable}, person))
+	err = f.DB.CheckSchema(models.PersonTable)
+	s.Require().IsType(new(reform.SchemaError), err)
+	s.Equal([]reform.SchemaMismatch{
+		{View: "people", Column: "name", Problem: "nullable column is incompatible with field type string"},
+	}, err.(*reform.SchemaError).Mismatches)
+
+	// real database
+	s.NoError(s.q.CheckSchema(models.PersonTable, models.ProjectTable, models.PersonProjectView,
+		models.SecretTable, models.ProjectRoleTable, models.MemoTable))
+
+	// missing table; that aborts PostgreSQL transaction, so that should be the last query
+	err = s.q.CheckSchema(models.PersonTable, missingPeople{models.PersonTable})
+	s.Require().Error(err)
+	s.NotContains(err.Error(), "schema mismatch")
+}
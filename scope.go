@@ -0,0 +1,104 @@
+package reform
+
+import (
+	"strconv"
+	"strings"
+)
+
+// scope is a condition added to generated SELECT queries for a view, see Querier.WithScope.
+type scope struct {
+	view     string        // view or table name
+	parts    []string      // condition split by numbered placeholders
+	numbers  []int         // numbers of placeholders between parts
+	args     []interface{} // condition's args
+	numbered bool          // true if dialect uses numbered placeholders
+}
+
+// WithScope returns a copy of querier which adds given condition (like "name LIKE $1",
+// optionally with WHERE keyword) with given args to all generated SELECT queries for given view or table:
+// selectors, finders, Count and Exists. Scopes are chained: each call adds a condition,
+// so reusable filters can be combined like GORM scopes:
+//
+//	active := DB.WithScope(OrderTable, "WHERE status = $1", "active")
+//	recent := active.WithScope(OrderTable, "created_at > $1", since)
+//	structs, err := recent.SelectAllFrom(OrderTable, "WHERE customer_id = $1", id) // all three conditions
+//
+// Like soft delete and TenantScope filters, conditions are applied in subquery with the same name
+// as view, so tails may contain any clauses, and placeholders in both scope and tail are numbered from 1;
+// placeholders in string literals, quoted identifiers and comments of condition are left as is.
+// Queries for other views, including preloaded relations, are not changed. Update and delete commands
+// and raw queries passed to Exec, Query and QueryRow are not changed either.
+// Records of view are not cached by RecordCache for querier with its scope.
+func (q *Querier) WithScope(view View, condition string, args ...interface{}) *Querier {
+	if m := tenantWhereRE.FindStringSubmatch(condition); m != nil {
+		condition = m[1]
+	}
+	condition = strings.TrimSpace(condition)
+	s := scope{
+		view:     view.Name(),
+		args:     args,
+		numbered: q.numberedPlaceholders(),
+	}
+
+	// split condition by numbered placeholders to renumber them with dialect, see apply
+	prefix := strings.TrimSuffix(q.Placeholder(1), "1")
+	backslashEscapes := q.backslashEscapes()
+	var start int
+	for i := 0; s.numbered && i < len(condition); i++ {
+		if end := skippedEnd(condition, i, backslashEscapes); end > i {
+			i = end - 1
+			continue
+		}
+		if !strings.HasPrefix(condition[i:], prefix) {
+			continue
+		}
+		j := i + len(prefix)
+		for j < len(condition) && condition[j] >= '0' && condition[j] <= '9' {
+			j++
+		}
+		n, err := strconv.Atoi(condition[i+len(prefix) : j])
+		if err != nil {
+			continue
+		}
+		s.parts = append(s.parts, condition[start:i])
+		s.numbers = append(s.numbers, n)
+		start = j
+		i = j - 1
+	}
+	s.parts = append(s.parts, condition[start:])
+
+	nq := q.clone()
+	nq.scopes = append(q.scopes[:len(q.scopes):len(q.scopes)], s)
+	return nq
+}
+
+// hasScope returns true if querier has scope for given view.
+func (q *Querier) hasScope(view View) bool {
+	for _, s := range q.scopes {
+		if s.view == view.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// apply returns scope condition and args of query with it. For numbered placeholders, condition's placeholders
+// are renumbered after existing args with dialect, and scope's args are appended. For positional placeholders,
+// scope's args are inserted at index i (after args of previous conditions of FROM clause, before tail's args).
+func (s *scope) apply(d Dialect, args []interface{}, i int) (string, []interface{}) {
+	if s.numbered {
+		var res strings.Builder
+		for j, p := range s.parts {
+			res.WriteString(p)
+			if j < len(s.numbers) {
+				res.WriteString(d.Placeholder(s.numbers[j] + len(args)))
+			}
+		}
+		return res.String(), append(args[:len(args):len(args)], s.args...)
+	}
+
+	res := make([]interface{}, 0, len(args)+len(s.args))
+	res = append(res, args[:i]...)
+	res = append(res, s.args...)
+	return s.parts[0], append(res, args[i:]...)
+}
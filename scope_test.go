@@ -0,0 +1,70 @@
+package reform_test
+
+import (
+	"context"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	. "github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func (s *ReformSuite) TestWithScope() {
+	q := s.q.WithScope(PersonTable, "WHERE name = "+s.q.Placeholder(1), "Elfrieda Abbott")
+	n, err := q.Count(PersonTable, "")
+	s.Require().NoError(err)
+	s.Equal(uint(2), n)
+	_, err = q.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.Equal(reform.ErrNoRows, err)
+
+	// scopes are chained and composed with tails
+	q = q.WithScope(PersonTable, "email IS NULL")
+	structs, err := q.SelectAllFrom(PersonTable, "WHERE id > "+s.q.Placeholder(1)+" ORDER BY id", 100)
+	s.Require().NoError(err)
+	s.Require().Len(structs, 1)
+	s.Equal(int32(103), structs[0].(*Person).ID)
+
+	// other views are not scoped
+	_, err = q.FindByPrimaryKeyFrom(ProjectTable, "baron")
+	s.NoError(err)
+
+	// original querier is not changed
+	n, err = s.q.Count(PersonTable, "WHERE id > "+s.q.Placeholder(1), 100)
+	s.Require().NoError(err)
+	s.Equal(uint(3), n)
+}
+
+func (s *ReformSuite) TestWithScopePlaceholders() {
+	scope := &reform.TenantScope{
+		Column: "project_id",
+		Value:  func(context.Context) interface{} { return "baron" },
+	}
+
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+	q := f.DB.WithTenantScope(scope).WithScope(PersonProjectView, "person_id > $1", 100)
+	_, err := q.Count(PersonProjectView, "WHERE person_id < $1", 200)
+	s.Require().NoError(err)
+	s.Equal(`SELECT COUNT(*) FROM (SELECT * FROM "person_project" WHERE "project_id" = $2 AND (person_id > $3)) "person_project" WHERE person_id < $1`,
+		f.Statements()[0].Query)
+	s.Equal([]interface{}{200, "baron", 100}, f.Statements()[0].Args)
+
+	// placeholders in literals and comments are not renumbered
+	f.Reset()
+	q = f.DB.WithScope(PersonTable, `name <> '$1' AND "$1" > $1 /* $1 */`, 100)
+	_, err = q.Count(PersonTable, "WHERE id < $1", 200)
+	s.Require().NoError(err)
+	s.Equal(`SELECT COUNT(*) FROM (SELECT * FROM "people" WHERE (name <> '$1' AND "$1" > $2 /* $1 */)) "people" WHERE id < $1`,
+		f.Statements()[0].Query)
+	s.Equal([]interface{}{200, 100}, f.Statements()[0].Args)
+
+	f = reformtest.New(mysql.Dialect)
+	defer f.Close()
+	q = f.DB.WithTenantScope(scope).WithScope(PersonProjectView, "person_id > ?", 100)
+	_, err = q.Count(PersonProjectView, "WHERE person_id < ?", 200)
+	s.Require().NoError(err)
+	s.Equal("SELECT COUNT(*) FROM (SELECT * FROM `person_project` WHERE `project_id` = ? AND (person_id > ?)) `person_project` WHERE person_id < ?",
+		f.Statements()[0].Query)
+	s.Equal([]interface{}{"baron", 100, 200}, f.Statements()[0].Args)
+}
@@ -0,0 +1,149 @@
+package reform
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SelectInto executes query with args and scans results into dest without generated View,
+// for one-off queries with joins and aggregates:
+//
+//	var stats []struct {
+//		Project string `db:"project"`
+//		People  int64  `db:"people"`
+//	}
+//	err = q.SelectInto(&stats, "SELECT project_id AS project, COUNT(*) AS people FROM person_project GROUP BY project_id")
+//
+// Dest is either a pointer to a slice of structs or pointers to structs (all rows are appended to it),
+// or a pointer to a struct (the first row is scanned to it; ErrNoRows is returned if there are no rows).
+// Result columns are matched to struct fields by "db" tags; fields of embedded structs without tags are also matched,
+// fields with "-" tag and without tags are ignored. Error is returned if query returns a column without matching field.
+// Hooks are not called, encrypted columns are not decrypted.
+//
+// In case of query error dest is not changed. If error is encountered during iteration,
+// partial result is appended to dest and error is returned.
+func (q *Querier) SelectInto(dest interface{}, query string, args ...interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("reform: SelectInto: dest should be a non-nil pointer, got %T", dest)
+	}
+	v = v.Elem()
+
+	t := v.Type()
+	var elemPtr bool
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+			elemPtr = true
+		}
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("reform: SelectInto: dest should be a pointer to a struct or a slice of structs, got %T", dest)
+	}
+
+	rows, cancel, err := q.queryRows("", query, args...)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	fields := intoFields(t)
+	indexes := make([][]int, len(columns))
+	for i, c := range columns {
+		if indexes[i] = fields[c]; indexes[i] == nil {
+			return fmt.Errorf("reform: SelectInto: %s has no field for column %q", t, c)
+		}
+	}
+
+	if v.Kind() == reflect.Struct {
+		if !rows.Next() {
+			if err = rows.Err(); err == nil {
+				err = ErrNoRows
+			}
+			return err
+		}
+		if err = rows.Scan(intoPointers(v, indexes)...); err != nil {
+			return err
+		}
+		return rows.Close()
+	}
+
+	return scanAll(rows, func() ([]interface{}, func() error, error) {
+		elem := reflect.New(t)
+		done := func() error {
+			if !elemPtr {
+				elem = elem.Elem()
+			}
+			v.Set(reflect.Append(v, elem))
+			return nil
+		}
+		return intoPointers(elem.Elem(), indexes), done, nil
+	})
+}
+
+// intoFieldsCache contains results of intoFields by struct type.
+var intoFieldsCache sync.Map
+
+// intoFields returns indexes of struct type t fields by column names from "db" tags.
+// Fields of embedded structs are included, fields of outer struct take precedence.
+func intoFields(t reflect.Type) map[string][]int {
+	if res, ok := intoFieldsCache.Load(t); ok {
+		return res.(map[string][]int)
+	}
+
+	res := make(map[string][]int)
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("db")
+		if j := strings.IndexByte(tag, ','); j >= 0 {
+			tag = tag[:j]
+		}
+
+		switch {
+		case tag == "-":
+		case tag != "":
+			if f.PkgPath == "" {
+				res[tag] = f.Index
+			}
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			embedded = append(embedded, f)
+		}
+	}
+
+	for _, f := range embedded {
+		for c, index := range intoFields(f.Type) {
+			if _, ok := res[c]; !ok {
+				res[c] = append(f.Index[:len(f.Index):len(f.Index)], index...)
+			}
+		}
+	}
+
+	intoFieldsCache.Store(t, res)
+	return res
+}
+
+// intoPointers returns pointers to fields of addressable struct value v with given indexes suitable for scanning.
+// Boolean fields are wrapped to reliably accept integer 0/1 values.
+func intoPointers(v reflect.Value, indexes [][]int) []interface{} {
+	res := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		switch p := v.FieldByIndex(index).Addr().Interface().(type) {
+		case *bool:
+			res[i] = boolScanner{p: p}
+		case **bool:
+			res[i] = nullBoolScanner{p: p}
+		default:
+			res[i] = p
+		}
+	}
+	return res
+}
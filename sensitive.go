@@ -0,0 +1,100 @@
+package reform
+
+// SensitiveView is an optional interface for View which is used by Querier to redact column values in logs.
+// It is implemented by generated code for structs with "sensitive" labels.
+type SensitiveView interface {
+	View
+
+	// SensitiveColumns returns a new slice of flags: true for sensitive columns for that view or table in SQL database.
+	SensitiveColumns() []bool
+}
+
+// WithSensitiveColumns returns a copy of querier which additionally treats given columns of view as sensitive,
+// for example, for views without "sensitive" labels or generated by older reform version:
+//
+//	q = q.WithSensitiveColumns(UserTable, "password_hash", "api_token")
+//
+// Values of sensitive columns written by Insert, Update, Upsert, Save, ExecNamed and similar methods
+// are passed to Logger (including StructuredLogger and SlowQueryLogger) as Redacted.
+// Arguments passed explicitly to Exec, Query and Select/Find methods are logged as is.
+func (q *Querier) WithSensitiveColumns(view View, columns ...string) *Querier {
+	nq := q.clone()
+	nq.sensitive = make(map[string][]string, len(q.sensitive)+1)
+	for name, c := range q.sensitive {
+		nq.sensitive[name] = c
+	}
+	c := q.sensitive[view.Name()]
+	nq.sensitive[view.Name()] = append(c[:len(c):len(c)], columns...)
+	return nq
+}
+
+// sensitiveValue wraps query argument for sensitive column.
+// It is replaced with Redacted for Logger and unwrapped before passing to the database driver.
+type sensitiveValue struct {
+	v interface{}
+}
+
+// sensitiveColumns returns flags for sensitive columns of given view, or nil if there are none.
+func (q *Querier) sensitiveColumns(view View) []bool {
+	var res []bool
+	if sv, ok := view.(SensitiveView); ok {
+		res = sv.SensitiveColumns()
+	}
+
+	extra := q.sensitive[view.Name()]
+	if len(extra) == 0 {
+		return res
+	}
+	if res == nil {
+		res = make([]bool, len(view.Columns()))
+	}
+	for i, c := range view.Columns() {
+		for _, e := range extra {
+			if c == e {
+				res[i] = true
+			}
+		}
+	}
+	return res
+}
+
+// markSensitive wraps values of sensitive columns of view in place.
+func (q *Querier) markSensitive(view View, values []interface{}) {
+	sensitive := q.sensitiveColumns(view)
+	for i, s := range sensitive {
+		if s {
+			values[i] = sensitiveValue{values[i]}
+		}
+	}
+}
+
+// redactArgs returns args for Logger with values of sensitive columns replaced with Redacted.
+func redactArgs(args []interface{}) []interface{} {
+	return replaceSensitive(args, func(sensitiveValue) interface{} { return Redacted })
+}
+
+// driverArgs returns args for database driver with values of sensitive columns unwrapped.
+func driverArgs(args []interface{}) []interface{} {
+	return replaceSensitive(args, func(s sensitiveValue) interface{} { return s.v })
+}
+
+// replaceSensitive returns a copy of args with sensitive values replaced by f,
+// or args as is if there are no such values.
+func replaceSensitive(args []interface{}, f func(sensitiveValue) interface{}) []interface{} {
+	var res []interface{}
+	for i, a := range args {
+		s, ok := a.(sensitiveValue)
+		if !ok {
+			continue
+		}
+		if res == nil {
+			res = make([]interface{}, len(args))
+			copy(res, args)
+		}
+		res[i] = f(s)
+	}
+	if res == nil {
+		return args
+	}
+	return res
+}
@@ -0,0 +1,71 @@
+package reform_test
+
+import (
+	"context"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	. "github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func (s *ReformSuite) TestSensitiveColumns() {
+	c, err := reform.NewAESGCM(map[uint8][]byte{1: make([]byte, 16)}, 1)
+	s.Require().NoError(err)
+
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+	l := reform.NewRecordingLogger()
+	q := f.DB.WithCipher(c).WithLogger(l)
+
+	secret := &Secret{ID: 1, Name: "password", Data: "hunter2"}
+	s.Require().NoError(q.Insert(secret))
+	secret.Name = "token"
+	s.Require().NoError(q.WithSensitiveColumns(SecretTable, "name").UpdateColumns(secret, "name"))
+	_, err = q.ExecNamed("UPDATE secrets SET data = :data WHERE id = :id", secret)
+	s.Require().NoError(err)
+
+	logged := l.Statements()
+	s.Require().Len(logged, 3)
+	s.Equal([]interface{}{int32(1), "password", reform.Redacted}, logged[0].Args[:3])
+	s.Equal([]interface{}{reform.Redacted, int32(1)}, logged[1].Args)
+	s.Equal([]interface{}{reform.Redacted, int32(1)}, logged[2].Args)
+
+	// database receives actual values
+	executed := f.Statements()
+	s.Require().Len(executed, 3)
+	s.Equal("password", executed[0].Args[1])
+	s.IsType([]byte(nil), executed[0].Args[2])
+	s.Equal("token", executed[1].Args[0])
+	s.IsType([]byte(nil), executed[2].Args[0])
+
+	_, args, err := q.BindNamed("SELECT :data", secret)
+	s.Require().NoError(err)
+	s.IsType([]byte(nil), args[0])
+}
+
+// copyFromDialect is a CopyFromDialect which records loaded rows.
+type copyFromDialect struct {
+	reform.Dialect
+	rows [][]interface{}
+}
+
+func (d *copyFromDialect) CanCopyFrom(reform.DBTXContext) bool { return true }
+
+func (d *copyFromDialect) CopyFrom(ctx context.Context, dbtx reform.DBTXContext, table string, columns []string, rows [][]interface{}) (int64, error) {
+	d.rows = append(d.rows, rows...)
+	return int64(len(rows)), nil
+}
+
+func (s *ReformSuite) TestSensitiveColumnsCopyFrom() {
+	d := &copyFromDialect{Dialect: postgresql.Dialect}
+	f := reformtest.New(d)
+	defer f.Close()
+
+	q := f.DB.WithSensitiveColumns(PersonTable, "name")
+	n, err := q.CopyFrom(PersonTable, reform.CopySlice([]reform.Struct{&Person{Name: "Alice"}}))
+	s.Require().NoError(err)
+	s.Equal(int64(1), n)
+	s.Require().Len(d.rows, 1)
+	s.Equal("Alice", d.rows[0][0])
+}
@@ -0,0 +1,198 @@
+package reform
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// ErrNoShardKey is returned by ShardedDB when shard key of a struct is nil, for example,
+// when the default shard key (primary key) is not set.
+var ErrNoShardKey = errors.New("reform: no shard key")
+
+// ShardKeyFunc returns shard key of a struct, like tenant ID, or nil if it is not known.
+// Keys are compared by their fmt.Sprint representation, so 42 and int64(42) are the same key.
+type ShardKeyFunc func(str Struct) interface{}
+
+// ShardedDB maps a shard key to one of N databases (shards) with the same schema.
+// Insert, Update, Save, Delete and other methods for a single record route it to a shard by its shard key.
+// Use Shard for other queries and transactions (they can't span several shards), and ForEachShard for fan-out queries:
+//
+//	sharded := reform.NewShardedDB([]*reform.DB{db0, db1, db2}, func(str reform.Struct) interface{} {
+//		return str.(*Order).CustomerID
+//	})
+//	err = sharded.Insert(order)
+//	orders, err := sharded.Shard(customerID).SelectAllFrom(OrderTable, "WHERE customer_id = $1", customerID)
+//
+// Shard for a key is chosen by FNV-1a hash of the key modulo number of shards,
+// so changing the number of shards moves most keys: plan resharding accordingly.
+type ShardedDB struct {
+	shards []*DB
+	key    ShardKeyFunc
+}
+
+// NewShardedDB creates new ShardedDB for given shards and shard key function.
+// If key is nil, record's primary key is used as shard key: with auto-incremented primary keys
+// different shards generate the same values, so use it with GeneratedPKTable (see Querier.WithIDGenerator).
+func NewShardedDB(shards []*DB, key ShardKeyFunc) *ShardedDB {
+	if len(shards) == 0 {
+		panic("reform: ShardedDB should have at least one shard")
+	}
+	return &ShardedDB{
+		shards: shards,
+		key:    key,
+	}
+}
+
+// Shards returns all shards.
+func (s *ShardedDB) Shards() []*DB {
+	return s.shards
+}
+
+// ShardIndex returns index of shard for given shard key.
+func (s *ShardedDB) ShardIndex(key interface{}) int {
+	h := fnv.New32a()
+	fmt.Fprint(h, key)
+	return int(h.Sum32() % uint32(len(s.shards)))
+}
+
+// Shard returns shard for given shard key.
+func (s *ShardedDB) Shard(key interface{}) *DB {
+	return s.shards[s.ShardIndex(key)]
+}
+
+// ShardFor returns shard for given struct by its shard key. With the default shard key (primary key),
+// primary key of GeneratedPKTable record is generated with the first shard's IDGenerator if it is not set.
+// It returns ErrNoShardKey if shard key is nil.
+func (s *ShardedDB) ShardFor(str Struct) (*DB, error) {
+	var key interface{}
+	if s.key != nil {
+		key = s.key(str)
+	} else {
+		if err := s.shards[0].generatePK(str); err != nil {
+			return nil, err
+		}
+		if record, ok := str.(Record); ok && record.HasPK() {
+			key = pkArg(record)
+		}
+	}
+
+	if key == nil {
+		return nil, ErrNoShardKey
+	}
+	return s.Shard(key), nil
+}
+
+// shardForPK returns shard for table's record with given primary key.
+// It works only with the default shard key (primary key).
+func (s *ShardedDB) shardForPK(table Table, pk interface{}) (*DB, error) {
+	if s.key != nil {
+		return nil, fmt.Errorf("reform: ShardedDB can't find %s by primary key with custom shard key, use Shard(key)", table.Name())
+	}
+	return s.Shard(pk), nil
+}
+
+// Insert inserts a struct into the shard for its shard key, see Querier.Insert.
+func (s *ShardedDB) Insert(str Struct) error {
+	db, err := s.ShardFor(str)
+	if err != nil {
+		return err
+	}
+	return db.Insert(str)
+}
+
+// Update updates a record in the shard for its shard key, see Querier.Update.
+func (s *ShardedDB) Update(record Record) error {
+	db, err := s.ShardFor(record)
+	if err != nil {
+		return err
+	}
+	return db.Update(record)
+}
+
+// UpdateColumns updates given columns of a record in the shard for its shard key, see Querier.UpdateColumns.
+func (s *ShardedDB) UpdateColumns(record Record, columns ...string) error {
+	db, err := s.ShardFor(record)
+	if err != nil {
+		return err
+	}
+	return db.UpdateColumns(record, columns...)
+}
+
+// Save saves a record in the shard for its shard key, see Querier.Save.
+func (s *ShardedDB) Save(record Record) error {
+	db, err := s.ShardFor(record)
+	if err != nil {
+		return err
+	}
+	return db.Save(record)
+}
+
+// Delete deletes a record from the shard for its shard key, see Querier.Delete.
+func (s *ShardedDB) Delete(record Record) error {
+	db, err := s.ShardFor(record)
+	if err != nil {
+		return err
+	}
+	return db.Delete(record)
+}
+
+// Reload reloads a record from the shard for its shard key, see Querier.Reload.
+func (s *ShardedDB) Reload(record Record) error {
+	db, err := s.ShardFor(record)
+	if err != nil {
+		return err
+	}
+	return db.Reload(record)
+}
+
+// FindByPrimaryKeyTo finds a record by primary key in the shard for it, see Querier.FindByPrimaryKeyTo.
+// It works only with the default shard key (primary key) and returns error for custom shard key function:
+// use Shard(key).FindByPrimaryKeyTo for it.
+func (s *ShardedDB) FindByPrimaryKeyTo(record Record, pk interface{}) error {
+	db, err := s.shardForPK(record.Table(), pk)
+	if err != nil {
+		return err
+	}
+	return db.FindByPrimaryKeyTo(record, pk)
+}
+
+// FindByPrimaryKeyFrom finds a record by primary key in the shard for it, see Querier.FindByPrimaryKeyFrom
+// and FindByPrimaryKeyTo.
+func (s *ShardedDB) FindByPrimaryKeyFrom(table Table, pk interface{}) (Record, error) {
+	db, err := s.shardForPK(table, pk)
+	if err != nil {
+		return nil, err
+	}
+	return db.FindByPrimaryKeyFrom(table, pk)
+}
+
+// ForEachShard calls f for each shard concurrently and waits for all calls to return.
+// It returns the first (by shard index) non-nil error.
+//
+//	counts := make([]uint, len(sharded.Shards()))
+//	err = sharded.ForEachShard(func(i int, db *reform.DB) error {
+//		var err error
+//		counts[i], err = db.Count(OrderTable, "")
+//		return err
+//	})
+func (s *ShardedDB) ForEachShard(f func(i int, db *DB) error) error {
+	errs := make([]error, len(s.shards))
+	var wg sync.WaitGroup
+	for i, db := range s.shards {
+		wg.Add(1)
+		go func(i int, db *DB) {
+			defer wg.Done()
+			errs[i] = f(i, db)
+		}(i, db)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package reform_test
+
+import (
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestShardedDB() {
+	err := s.q.Rollback()
+	s.Require().NoError(err)
+	s.q = nil
+
+	closed := reform.NewDB(s.closedDB(), DB.Dialect, DB.Logger)
+	sharded := reform.NewShardedDB([]*reform.DB{DB, closed}, nil)
+	s.Equal(sharded.ShardIndex(1), sharded.ShardIndex(int32(1)))
+	s.Equal(sharded.ShardIndex([]interface{}{1, "a"}), sharded.ShardIndex([]interface{}{int64(1), "a"}))
+
+	// people 1 and 2 are routed to the open and closed shards
+	s.Equal(DB, sharded.Shard(int32(1)))
+	s.Equal(closed, sharded.Shard(int32(2)))
+
+	person, err := sharded.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.NoError(err)
+	s.Equal("Denis Mills", person.(*Person).Name)
+	_, err = sharded.FindByPrimaryKeyFrom(PersonTable, 2)
+	s.Error(err)
+	s.NotEqual(reform.ErrNoRows, err)
+
+	// people have auto-incremented primary keys
+	s.Equal(reform.ErrNoShardKey, sharded.Insert(&Person{Name: "Sharded"}))
+
+	// custom shard key
+	sharded = reform.NewShardedDB([]*reform.DB{closed, DB}, func(str reform.Struct) interface{} {
+		return str.(*Person).ID % 2
+	})
+	db, err := sharded.ShardFor(&Person{ID: 1})
+	s.NoError(err)
+	s.Equal(sharded.Shard(int32(1)), db)
+	s.Equal(closed, db)
+	_, err = sharded.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.EqualError(err, "reform: ShardedDB can't find people by primary key with custom shard key, use Shard(key)")
+	person, err = sharded.Shard(int32(0)).FindByPrimaryKeyFrom(PersonTable, 2)
+	s.NoError(err)
+	s.Equal("Garrick Muller", person.(*Person).Name)
+
+	// fan-out
+	sharded = reform.NewShardedDB([]*reform.DB{DB, DB, closed}, nil)
+	counts := make([]uint, 3)
+	err = sharded.ForEachShard(func(i int, db *reform.DB) error {
+		var err error
+		counts[i], err = db.Count(PersonTable, "")
+		return err
+	})
+	s.Error(err)
+	s.NotZero(counts[0])
+	s.Equal(counts[0], counts[1])
+	s.Zero(counts[2])
+}
@@ -0,0 +1,80 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// QueryStats contains counters of executed statements.
+type QueryStats struct {
+	Queries uint64 // number of executed queries and commands
+	Errors  uint64 // number of queries and commands which returned error
+}
+
+// DBStats contains database connection pool statistics and counters of statements
+// executed by DB and its transactions.
+type DBStats struct {
+	sql.DBStats
+	QueryStats
+
+	// Counters by view name. Statements not bound to view (like Exec, Query or BEGIN) are counted only in QueryStats.
+	Views map[string]QueryStats
+}
+
+// queryStats collects QueryStats of DB; it is shared by all copies of DB's Querier.
+type queryStats struct {
+	m     sync.Mutex
+	total QueryStats
+	views map[string]QueryStats
+}
+
+// add counts statement on view (may be empty) which returned err.
+func (s *queryStats) add(view string, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.total.Queries++
+	if err != nil {
+		s.total.Errors++
+	}
+	if view == "" {
+		return
+	}
+
+	vs := s.views[view]
+	vs.Queries++
+	if err != nil {
+		vs.Errors++
+	}
+	s.views[view] = vs
+}
+
+// Ping verifies that connection to the database is still alive, establishing a connection if necessary.
+// It is intended to be used by health checks.
+func (db *DB) Ping(ctx context.Context) error {
+	return db.db.PingContext(ctx)
+}
+
+// Stats returns database connection pool statistics and counters of statements executed by DB
+// (including its copies with different settings) and its transactions since DB creation.
+// Statements which failed before reaching the database (for example, with ErrNoWhere) are not counted.
+func (db *DB) Stats() DBStats {
+	res := DBStats{
+		DBStats: db.db.Stats(),
+	}
+
+	s := db.stats
+	if s == nil {
+		return res
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	res.QueryStats = s.total
+	res.Views = make(map[string]QueryStats, len(s.views))
+	for view, vs := range s.views {
+		res.Views[view] = vs
+	}
+	return res
+}
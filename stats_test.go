@@ -0,0 +1,38 @@
+package reform_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	. "github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func (s *ReformSuite) TestStats() {
+	f := reformtest.New(postgresql.Dialect)
+	defer f.Close()
+	s.Require().NoError(f.DB.Ping(context.Background()))
+
+	_, err := f.DB.Exec("SELECT 1")
+	s.Require().NoError(err)
+	s.Require().NoError(f.DB.Tagged("tagged").Insert(&Person{ID: 1, Name: "Alice"}))
+	f.StubError(PersonTable, errors.New("boom"))
+	s.Error(f.DB.Delete(&Person{ID: 1}))
+	_, err = f.DB.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.Error(err)
+	s.Require().NoError(f.DB.InTransaction(func(tx *reform.TX) error {
+		return tx.Insert(&Project{ID: "p", Name: "Project"})
+	}))
+	_, err = f.DB.FindByPrimaryKeyFrom(ProjectTable, "no_such_project")
+	s.Equal(reform.ErrNoRows, err)
+
+	stats := f.DB.Stats()
+	s.Equal(reform.QueryStats{Queries: 8, Errors: 2}, stats.QueryStats)
+	s.Equal(map[string]reform.QueryStats{
+		"people":   {Queries: 3, Errors: 2},
+		"projects": {Queries: 2},
+	}, stats.Views)
+	s.Zero(stats.InUse)
+}
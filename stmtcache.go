@@ -0,0 +1,236 @@
+package reform
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// StatementCacheStats contains StatementCache metrics.
+type StatementCacheStats struct {
+	Hits      uint64 // number of queries executed with already prepared statement
+	Misses    uint64 // number of prepared statements
+	Evictions uint64 // number of statements closed to keep cache size
+	Failures  uint64 // number of queries which can't be prepared
+	Size      int    // current number of prepared statements
+}
+
+// cachedStmt is a prepared statement in StatementCache.
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int  // number of callers using statement, protected by StatementCache.m
+	evicted bool // true if statement is removed from cache and should be closed when refs is 0
+}
+
+// prepareErrorTTL is a time during which queries which can't be prepared are not prepared again.
+const prepareErrorTTL = time.Minute
+
+// StatementCache prepares statements for queries and commands and reuses them by SQL text,
+// closing the least recently used ones when cache is full. See Querier.WithStatementCache.
+// It is safe for concurrent use.
+type StatementCache struct {
+	db   *sql.DB
+	size int
+
+	m      sync.Mutex
+	lru    *list.List // of *cachedStmt, most recently used first
+	stmts  map[string]*list.Element
+	failed map[string]time.Time // queries which can't be prepared, with time of failure
+	stats  StatementCacheStats
+}
+
+// NewStatementCache creates new StatementCache for given SQL database connection
+// holding up to size prepared statements.
+func NewStatementCache(db *sql.DB, size int) *StatementCache {
+	if size <= 0 {
+		panic("reform: StatementCache size should be positive")
+	}
+	return &StatementCache{
+		db:     db,
+		size:   size,
+		lru:    list.New(),
+		stmts:  make(map[string]*list.Element, size),
+		failed: make(map[string]time.Time),
+	}
+}
+
+// lookup returns already prepared statement for query, or nil.
+// Returned statement should be released with release after use; evicted statements are closed only after that.
+func (c *StatementCache) lookup(query string) *cachedStmt {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	e := c.stmts[query]
+	if e == nil {
+		return nil
+	}
+	c.lru.MoveToFront(e)
+	c.stats.Hits++
+	cs := e.Value.(*cachedStmt)
+	cs.refs++
+	return cs
+}
+
+// get returns prepared statement for query, preparing it if needed.
+// Returned statement should be released with release after use; evicted statements are closed only after that.
+func (c *StatementCache) get(ctx context.Context, query string) (*cachedStmt, error) {
+	if cs := c.lookup(query); cs != nil {
+		return cs, nil
+	}
+
+	c.m.Lock()
+	if t, ok := c.failed[query]; ok {
+		if time.Since(t) < prepareErrorTTL {
+			c.m.Unlock()
+			return nil, errPrepareFailed
+		}
+		delete(c.failed, query)
+	}
+	c.m.Unlock()
+
+	// prepare without lock; concurrent callers may prepare the same query, only one statement is kept
+	stmt, err := c.db.PrepareContext(ctx, query)
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if err != nil {
+		if ctx.Err() == nil {
+			c.stats.Failures++
+			if len(c.failed) >= c.size {
+				c.failed = make(map[string]time.Time)
+			}
+			c.failed[query] = time.Now()
+		}
+		return nil, err
+	}
+
+	if e := c.stmts[query]; e != nil {
+		stmt.Close()
+		c.lru.MoveToFront(e)
+		c.stats.Hits++
+		cs := e.Value.(*cachedStmt)
+		cs.refs++
+		return cs, nil
+	}
+
+	c.stats.Misses++
+	cs := &cachedStmt{query: query, stmt: stmt, refs: 1}
+	c.stmts[query] = c.lru.PushFront(cs)
+	for c.lru.Len() > c.size {
+		c.evict(c.lru.Back())
+		c.stats.Evictions++
+	}
+	return cs, nil
+}
+
+// errPrepareFailed is returned by get for queries which recently failed to prepare.
+var errPrepareFailed = errors.New("reform: statement can't be prepared")
+
+// evict removes statement from cache, closing it if it is not used. It should be called with lock held.
+func (c *StatementCache) evict(e *list.Element) {
+	cs := c.lru.Remove(e).(*cachedStmt)
+	delete(c.stmts, cs.query)
+	cs.evicted = true
+	if cs.refs == 0 {
+		cs.stmt.Close() // actually closed after all its queries are done
+	}
+}
+
+// release marks statement returned by get as no longer used by caller, closing it if it was evicted.
+func (c *StatementCache) release(cs *cachedStmt) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	cs.refs--
+	if cs.evicted && cs.refs == 0 {
+		cs.stmt.Close()
+	}
+}
+
+// Stats returns cache metrics.
+func (c *StatementCache) Stats() StatementCacheStats {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	res := c.stats
+	res.Size = c.lru.Len()
+	return res
+}
+
+// Close closes all prepared statements and empties cache. Cache can be used after that.
+// Statements used by running queries and commands are closed when they are done.
+func (c *StatementCache) Close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	var err error
+	for e := c.lru.Front(); e != nil; e = c.lru.Front() {
+		cs := c.lru.Remove(e).(*cachedStmt)
+		cs.evicted = true
+		if cs.refs > 0 {
+			continue
+		}
+		if cerr := cs.stmt.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	c.stmts = make(map[string]*list.Element, c.size)
+	c.failed = make(map[string]time.Time)
+	return err
+}
+
+// WithStatementCache returns a copy of querier which executes queries and commands with prepared statements
+// from given cache (nil disables it), avoiding repeated parsing of the same generated SQL by database.
+// Cache is used only by DB created for the same SQL database connection and its transactions;
+// transactions use only statements already prepared outside of them.
+//
+//	cache := reform.NewStatementCache(sqlDB, 100)
+//	defer cache.Close()
+//	db := reform.NewDB(sqlDB, postgresql.Dialect, nil)
+//	db.Querier = db.WithStatementCache(cache)
+func (q *Querier) WithStatementCache(c *StatementCache) *Querier {
+	nq := q.clone()
+	nq.stmtCache = c
+	return nq
+}
+
+// stmt returns prepared statement for query from querier's StatementCache and function which should be called
+// after statement is used, or nil if cache is not set or can't be used. Queries which can't be prepared
+// (for example, with several statements) are executed as usual.
+func (q *Querier) stmt(query string) (*sql.Stmt, func()) {
+	c := q.stmtCache
+	if c == nil {
+		return nil, nil
+	}
+
+	switch dbtx := q.dbtx.(type) {
+	case *sql.DB:
+		if dbtx != c.db {
+			return nil, nil
+		}
+		cs, err := c.get(q.ctx, query)
+		if err != nil {
+			return nil, nil
+		}
+		return cs.stmt, func() { c.release(cs) }
+	case *sql.Tx:
+		// transaction should be started by DB for the same SQL database connection;
+		// statements are not prepared there as that requires another connection from the pool
+		if q.txDB != c.db {
+			return nil, nil
+		}
+		cs := c.lookup(query)
+		if cs == nil {
+			return nil, nil
+		}
+		// transaction-specific statement is closed with transaction
+		return dbtx.StmtContext(q.ctx, cs.stmt), func() { c.release(cs) }
+	default:
+		return nil, nil
+	}
+}
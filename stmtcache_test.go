@@ -0,0 +1,58 @@
+package reform_test
+
+import (
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestStatementCache() {
+	err := s.q.Rollback()
+	s.Require().NoError(err)
+	s.q = nil
+
+	cache := reform.NewStatementCache(sqlDB, 2)
+	defer func() {
+		s.NoError(cache.Close())
+		s.Equal(0, cache.Stats().Size)
+	}()
+
+	db := reform.NewDB(sqlDB, DB.Dialect, DB.Logger)
+	db.Querier = db.WithStatementCache(cache)
+	for i := 0; i < 3; i++ {
+		person, err := db.FindByPrimaryKeyFrom(PersonTable, 1)
+		s.NoError(err)
+		s.Equal("Denis Mills", person.(*Person).Name)
+	}
+	s.Equal(reform.StatementCacheStats{Hits: 2, Misses: 1, Size: 1}, cache.Stats())
+
+	// transaction uses only already prepared statements
+	tx, err := db.Begin()
+	s.Require().NoError(err)
+	s.NoError(tx.Save(&Person{Name: "Cached"}))
+	person, err := tx.FindByPrimaryKeyFrom(PersonTable, 1)
+	s.NoError(err)
+	s.Equal("Denis Mills", person.(*Person).Name)
+	s.NoError(tx.Rollback())
+	s.Equal(reform.StatementCacheStats{Hits: 3, Misses: 1, Size: 1}, cache.Stats())
+
+	// transaction not started by DB doesn't use cache
+	sqlTx, err := sqlDB.Begin()
+	s.Require().NoError(err)
+	_, err = reform.NewTX(sqlTx, DB.Dialect, DB.Logger).WithStatementCache(cache).FindByPrimaryKeyFrom(PersonTable, 1)
+	s.NoError(err)
+	s.NoError(sqlTx.Rollback())
+	s.Equal(uint64(3), cache.Stats().Hits)
+
+	_, err = db.FindOneFrom(PersonTable, "name", "Denis Mills")
+	s.NoError(err)
+	_, err = db.SelectAllFrom(PersonTable, "")
+	s.NoError(err)
+	s.Equal(reform.StatementCacheStats{Hits: 3, Misses: 3, Evictions: 1, Size: 2}, cache.Stats())
+
+	// queries which can't be prepared are executed as usual, without preparing them again
+	for i := 0; i < 2; i++ {
+		_, err = db.Exec("SELECT * FROM no_such_table")
+		s.Error(err)
+	}
+	s.Equal(reform.StatementCacheStats{Hits: 3, Misses: 3, Evictions: 1, Failures: 1, Size: 2}, cache.Stats())
+}
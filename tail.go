@@ -0,0 +1,171 @@
+package reform
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Condition represents a single condition of WHERE clause.
+// Use Eq, Ne, Gt, Ge, Lt and Le (or Any, ArrayContains, ArrayOverlaps, JSONContains and JSONContainedBy
+// for PostgreSQL) to create it.
+type Condition struct {
+	column string
+	op     string
+	arg    interface{}
+	paren  bool // placeholder is enclosed in parentheses, like in "column = ANY($1)"
+}
+
+// Eq returns condition "column = arg".
+// If arg is nil (including typed nil pointer like (*string)(nil)), it returns condition "column IS NULL",
+// because "column = NULL" never matches anything in SQL.
+func Eq(column string, arg interface{}) Condition {
+	return Condition{column: column, op: "=", arg: arg}
+}
+
+// Ne returns condition "column <> arg".
+// If arg is nil (including typed nil pointer like (*string)(nil)), it returns condition "column IS NOT NULL",
+// because "column <> NULL" never matches anything in SQL.
+func Ne(column string, arg interface{}) Condition {
+	return Condition{column: column, op: "<>", arg: arg}
+}
+
+// Gt returns condition "column > arg".
+// Nil arg can't be compared, so Tail.Build returns error for it; the same is true for Ge, Lt and Le.
+func Gt(column string, arg interface{}) Condition {
+	return Condition{column: column, op: ">", arg: arg}
+}
+
+// Ge returns condition "column >= arg".
+func Ge(column string, arg interface{}) Condition {
+	return Condition{column: column, op: ">=", arg: arg}
+}
+
+// Lt returns condition "column < arg".
+func Lt(column string, arg interface{}) Condition {
+	return Condition{column: column, op: "<", arg: arg}
+}
+
+// Le returns condition "column <= arg".
+func Le(column string, arg interface{}) Condition {
+	return Condition{column: column, op: "<=", arg: arg}
+}
+
+// isNil returns true if arg is untyped nil or typed nil pointer.
+func isNil(arg interface{}) bool {
+	if arg == nil {
+		return true
+	}
+	v := reflect.ValueOf(arg)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// splitOrder splits ORDER BY item like "name DESC" to column name and direction with leading space
+// (" ASC", " DESC" or empty string).
+func splitOrder(item string) (column, dir string) {
+	switch {
+	case strings.HasSuffix(strings.ToUpper(item), " ASC"):
+		item, dir = item[:len(item)-4], " ASC"
+	case strings.HasSuffix(strings.ToUpper(item), " DESC"):
+		item, dir = item[:len(item)-5], " DESC"
+	}
+	return strings.TrimSpace(item), dir
+}
+
+// Tail represents a tail of SQL query: WHERE clause with conditions joined by AND,
+// optional ORDER BY and LIMIT clauses. Column names are always quoted, and values are always passed as args.
+// It is rendered with Build for a concrete dialect, and the result can be passed to any method accepting tail and args:
+//
+//	tail, args, err := reform.Where(reform.Eq("email", email)).Build(q.Dialect)
+//	if err != nil {
+//		return err
+//	}
+//	n, err := q.DeleteFrom(PersonTable, tail, args...)
+//
+//	tail, args, err = reform.Where(reform.Gt("id", 10)).OrderBy("created_at DESC").Limit(10).Build(q.Dialect)
+//	...
+//	structs, err := q.SelectAllFrom(PersonTable, tail, args...)
+type Tail struct {
+	conditions []Condition
+	orderBy    []string
+	limit      int
+}
+
+// Where returns a new tail with given conditions joined by AND.
+func Where(conditions ...Condition) *Tail {
+	return &Tail{conditions: conditions}
+}
+
+// OrderBy returns a copy of tail with ORDER BY clause for given columns, replacing the previous one.
+// Each column may have " ASC" or " DESC" suffix.
+func (t *Tail) OrderBy(columns ...string) *Tail {
+	nt := *t
+	nt.orderBy = columns
+	return &nt
+}
+
+// Limit returns a copy of tail with LIMIT clause. Zero or negative limit removes it.
+func (t *Tail) Limit(limit int) *Tail {
+	nt := *t
+	nt.limit = limit
+	return &nt
+}
+
+// Build renders tail for given dialect and returns it with args for placeholders.
+// Placeholders are numbered from 1. It returns error if nil arg is passed to Gt, Ge, Lt or Le:
+// comparison with NULL is never true in SQL.
+func (t *Tail) Build(dialect Dialect) (string, []interface{}, error) {
+	var args []interface{}
+	var clauses []string
+
+	if len(t.conditions) > 0 {
+		parts := make([]string, len(t.conditions))
+		for i, c := range t.conditions {
+			column := dialect.QuoteIdentifier(c.column)
+			if isNil(c.arg) {
+				switch c.op {
+				case "=":
+					parts[i] = column + " IS NULL"
+					continue
+				case "<>":
+					parts[i] = column + " IS NOT NULL"
+					continue
+				default:
+					return "", nil, fmt.Errorf("reform: %s %s NULL condition never matches, use Eq or Ne for NULL values", c.column, c.op)
+				}
+			}
+
+			args = append(args, c.arg)
+			if c.paren {
+				parts[i] = column + " " + c.op + "(" + dialect.Placeholder(len(args)) + ")"
+				continue
+			}
+			parts[i] = column + " " + c.op + " " + dialect.Placeholder(len(args))
+		}
+		clauses = append(clauses, "WHERE "+strings.Join(parts, " AND "))
+	}
+
+	if len(t.orderBy) > 0 {
+		parts := make([]string, len(t.orderBy))
+		for i, c := range t.orderBy {
+			column, dir := splitOrder(c)
+			parts[i] = dialect.QuoteIdentifier(column) + dir
+		}
+		clauses = append(clauses, "ORDER BY "+strings.Join(parts, ", "))
+	}
+
+	if t.limit > 0 {
+		clauses = append(clauses, limitClause(dialect, t.limit, len(t.orderBy) > 0))
+	}
+
+	return strings.Join(clauses, " "), args, nil
+}
+
+// limitClause returns clause which limits a number of rows returned by SELECT query, see LimitDialect.
+func limitClause(dialect Dialect, limit int, ordered bool) string {
+	if d, ok := dialect.(LimitDialect); ok {
+		return d.LimitClause(limit, ordered)
+	}
+	return "LIMIT " + strconv.Itoa(limit)
+}
@@ -0,0 +1,95 @@
+package reform_test
+
+import (
+	"strings"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/oracle"
+	"github.com/AlekSi/reform/dialects/sqlserver"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestTailBuild() {
+	tail, args, err := reform.Where().Build(s.q.Dialect)
+	s.NoError(err)
+	s.Equal("", tail)
+	s.Nil(args)
+
+	var email *string
+	tail, args, err = reform.Where(reform.Eq("email", email), reform.Ne("name", nil), reform.Eq("id", 1)).Build(s.q.Dialect)
+	s.NoError(err)
+	expected := "WHERE " + s.q.QuoteIdentifier("email") + " IS NULL AND " +
+		s.q.QuoteIdentifier("name") + " IS NOT NULL AND " +
+		s.q.QuoteIdentifier("id") + " = " + s.q.Placeholder(1)
+	s.Equal(expected, tail)
+	s.Equal([]interface{}{1}, args)
+
+	tail, args, err = reform.Where(reform.Gt("id", 1), reform.Le("id", 3)).OrderBy("name DESC", "id").Limit(2).Build(s.q.Dialect)
+	s.NoError(err)
+	expected = "WHERE " + s.q.QuoteIdentifier("id") + " > " + s.q.Placeholder(1) + " AND " +
+		s.q.QuoteIdentifier("id") + " <= " + s.q.Placeholder(2) +
+		" ORDER BY " + s.q.QuoteIdentifier("name") + " DESC, " + s.q.QuoteIdentifier("id") + " LIMIT 2"
+	if s.q.Dialect == sqlserver.Dialect {
+		expected = strings.Replace(expected, "LIMIT 2", "OFFSET 0 ROWS FETCH NEXT 2 ROWS ONLY", 1)
+	}
+	s.Equal(expected, tail)
+	s.Equal([]interface{}{1, 3}, args)
+
+	tail, args, err = reform.Where().OrderBy("id").Build(s.q.Dialect)
+	s.NoError(err)
+	s.Equal("ORDER BY "+s.q.QuoteIdentifier("id"), tail)
+	s.Nil(args)
+
+	// comparison with NULL never matches
+	for _, c := range []reform.Condition{reform.Gt("id", nil), reform.Ge("id", nil), reform.Lt("email", email), reform.Le("id", nil)} {
+		tail, args, err = reform.Where(reform.Eq("name", "Denis"), c).Build(s.q.Dialect)
+		s.Error(err)
+		s.Empty(tail)
+		s.Nil(args)
+	}
+	_, _, err = reform.Where(reform.Lt("email", email)).Build(s.q.Dialect)
+	s.EqualError(err, "reform: email < NULL condition never matches, use Eq or Ne for NULL values")
+}
+
+func (s *ReformSuite) TestTailSelectAllFrom() {
+	tail, args, err := reform.Where(reform.Ge("id", 2), reform.Lt("id", 103)).OrderBy("id DESC").Limit(2).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	structs, err := s.q.SelectAllFrom(PersonTable, tail, args...)
+	s.NoError(err)
+	s.Require().Len(structs, 2)
+	s.Equal(int32(102), structs[0].(*Person).ID)
+	s.Equal(int32(101), structs[1].(*Person).ID)
+}
+
+func (s *ReformSuite) TestTailDeleteFrom() {
+	var email *string
+	tail, args, err := reform.Where(reform.Eq("email", email)).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	ra, err := s.q.DeleteFrom(PersonTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(3), ra)
+
+	tail, args, err = reform.Where(reform.Ne("end", nil)).Build(s.q.Dialect)
+	s.Require().NoError(err)
+	ra, err = s.q.DeleteFrom(ProjectTable, tail, args...)
+	s.NoError(err)
+	s.Equal(uint(1), ra)
+}
+
+func (s *ReformSuite) TestTailLimitSQLServer() {
+	tail, args, err := reform.Where(reform.Eq("id", 1)).Limit(1).Build(sqlserver.Dialect)
+	s.NoError(err)
+	s.Equal("WHERE [id] = @p1 ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY", tail)
+	s.Equal([]interface{}{1}, args)
+
+	tail, _, err = reform.Where().OrderBy("id").Limit(1).Build(sqlserver.Dialect)
+	s.NoError(err)
+	s.Equal("ORDER BY [id] OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY", tail)
+}
+
+func (s *ReformSuite) TestTailLimitOracle() {
+	tail, args, err := reform.Where(reform.Eq("id", 1)).OrderBy("name DESC").Limit(1).Build(oracle.Dialect)
+	s.NoError(err)
+	s.Equal(`WHERE "ID" = :1 ORDER BY "NAME" DESC FETCH FIRST 1 ROWS ONLY`, tail)
+	s.Equal([]interface{}{1}, args)
+}
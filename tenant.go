@@ -0,0 +1,176 @@
+package reform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrNoTenant is returned by querier with TenantScope when struct should be inserted or updated,
+	// but current tenant is not known.
+	ErrNoTenant = errors.New("reform: no tenant")
+
+	// ErrTenantMismatch is returned by querier with TenantScope when inserted or updated struct
+	// belongs to another tenant.
+	ErrTenantMismatch = errors.New("reform: struct belongs to another tenant")
+)
+
+// TenantScope configures row-level multi-tenancy, see Querier.WithTenantScope.
+type TenantScope struct {
+	// Column is a tenant column name, like "org_id". Views and tables without it are not scoped.
+	Column string
+
+	// Value returns current tenant (like organization ID) for querier's context, or nil if it is not known.
+	Value func(ctx context.Context) interface{}
+}
+
+// WithTenantScope returns a copy of querier which restricts all generated queries and commands
+// for views and tables with tenant column to the current tenant (nil scope disables it):
+//
+//	q := DB.WithTenantScope(&reform.TenantScope{
+//		Column: "org_id",
+//		Value:  func(ctx context.Context) interface{} { return OrgID(ctx) },
+//	}).WithContext(ctx)
+//	projects, err := q.SelectAllFrom(ProjectTable, "WHERE name = $1", name) // only current organization's projects
+//
+// Selectors, finders, Count and Exists query only current tenant's rows: they are filtered by subquery
+// with the same name, like soft deleted rows, so tail may contain any clauses.
+// Update (and its variants), Delete (and its variants), DeleteFrom, UpdateColumnsAll and DeleteAll
+// change only current tenant's rows. Insert (and its variants), Upsert, Update and SaveAll set tenant column
+// if it has zero value, and return ErrTenantMismatch if it has another tenant's value; they return ErrNoTenant
+// if current tenant is not known. Other queries and commands match no rows in that case.
+// Tenant values are compared by their fmt.Sprint representation.
+//
+// DeleteFrom tail should contain only WHERE clause (or be empty) for scoped view.
+// Upsert requires OnConflict UpsertMethod for scoped table: conflicting row of another tenant is not updated,
+// and ErrNoRows is returned for it when primary key is returned with RETURNING clause.
+// Raw queries passed to Exec, Query and QueryRow are not changed.
+func (q *Querier) WithTenantScope(s *TenantScope) *Querier {
+	nq := q.clone()
+	nq.tenant = s
+	return nq
+}
+
+// tenantColumn returns tenant column name, index and current tenant value for given view,
+// or empty string if querier has no TenantScope or view has no tenant column.
+func (q *Querier) tenantColumn(view View) (string, int, interface{}) {
+	if q.tenant == nil {
+		return "", 0, nil
+	}
+	for i, c := range view.Columns() {
+		if c == q.tenant.Column {
+			return c, i, q.tenant.Value(q.ctx)
+		}
+	}
+	return "", 0, nil
+}
+
+// tenantCondition appends condition matching current tenant's rows to WHERE clause condition
+// and tenant value to args. It does nothing if view is not scoped.
+func (q *Querier) tenantCondition(view View, where string, args []interface{}) (string, []interface{}) {
+	column, _, value := q.tenantColumn(view)
+	if column == "" {
+		return where, args
+	}
+	return where + " AND " + q.QuoteIdentifier(column) + " = " + q.Placeholder(len(args)+1), append(args, value)
+}
+
+// tenantConflictWhere returns WHERE clause for "ON CONFLICT ... DO UPDATE" which prevents updating
+// conflicting row of another tenant, or empty string if table is not scoped.
+// It returns error for dialects with other UpsertMethods, as they can't do that.
+func (q *Querier) tenantConflictWhere(table Table) (string, error) {
+	column, _, _ := q.tenantColumn(table)
+	if column == "" {
+		return "", nil
+	}
+	if q.UpsertMethod() != OnConflict {
+		return "", fmt.Errorf("reform: upsert into %s with TenantScope requires OnConflict UpsertMethod", table.Name())
+	}
+	c := q.QuoteIdentifier(column)
+	return " WHERE " + q.QuoteIdentifier(table.Name()) + "." + c + " = EXCLUDED." + c, nil
+}
+
+// tenantWhereRE matches tail with only WHERE clause and captures its condition.
+var tenantWhereRE = regexp.MustCompile(`(?is)^\s*WHERE\b(.*)$`)
+
+// tenantTail returns DeleteFrom tail and args restricted to current tenant's rows.
+// It does nothing if view is not scoped.
+func (q *Querier) tenantTail(view View, tail string, args []interface{}) (string, []interface{}, error) {
+	column, _, value := q.tenantColumn(view)
+	if column == "" {
+		return tail, args, nil
+	}
+
+	// check original tail before adding tenant condition; that also removes AllRows marker
+	args, err := q.checkWhere("DELETE "+tail, args)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var p string
+	p, args = q.prependArg(value, args)
+	where := "WHERE " + q.QuoteIdentifier(column) + " = " + p
+	if strings.TrimSpace(tail) == "" {
+		return where, args, nil
+	}
+	m := tenantWhereRE.FindStringSubmatch(tail)
+	if m == nil {
+		return "", nil, fmt.Errorf("reform: tail for %s with TenantScope should contain only WHERE clause", view.Name())
+	}
+	return where + " AND (" + m[1] + ")", args, nil
+}
+
+// prependArg returns placeholder for value (like tenant) used before all other placeholders of query,
+// and args with that value.
+func (q *Querier) prependArg(value interface{}, args []interface{}) (string, []interface{}) {
+	if q.numberedPlaceholders() {
+		return q.Placeholder(len(args) + 1), append(args[:len(args):len(args)], value)
+	}
+	return q.Placeholder(1), append([]interface{}{value}, args...)
+}
+
+// tenantFill sets tenant field of struct to current tenant if it has zero value,
+// or checks that it belongs to current tenant.
+func (q *Querier) tenantFill(str Struct) error {
+	column, i, value := q.tenantColumn(str.View())
+	if column == "" {
+		return nil
+	}
+	if value == nil {
+		return ErrNoTenant
+	}
+
+	field := reflect.ValueOf(str.Pointers()[i]).Elem()
+	current := field
+	if current.Kind() == reflect.Ptr && !current.IsNil() {
+		current = current.Elem()
+	}
+	if !current.IsZero() {
+		if fmt.Sprint(current.Interface()) != fmt.Sprint(value) {
+			return ErrTenantMismatch
+		}
+		return nil
+	}
+
+	// set field converting value to its type like ShardedDB does for primary key
+	v := reflect.ValueOf(value)
+	t := field.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !v.Type().ConvertibleTo(t) {
+		return fmt.Errorf("reform: can't use %T as %s of %s", value, column, str.View().Name())
+	}
+	v = v.Convert(t)
+	if field.Kind() == reflect.Ptr {
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		v = p
+	}
+	field.Set(v)
+	return nil
+}
@@ -0,0 +1,60 @@
+package reform_test
+
+import (
+	"context"
+
+	"github.com/AlekSi/reform"
+	. "github.com/AlekSi/reform/internal/test/models"
+)
+
+func (s *ReformSuite) TestTenantScope() {
+	var tenant interface{} = "baron"
+	q := s.q.WithTenantScope(&reform.TenantScope{
+		Column: "project_id",
+		Value:  func(context.Context) interface{} { return tenant },
+	})
+
+	// selects
+	n, err := q.Count(PersonProjectView, "")
+	s.Require().NoError(err)
+	s.Equal(uint(3), n)
+	structs, err := q.SelectAllFrom(PersonProjectView, "WHERE person_id = "+s.q.Placeholder(1), 102)
+	s.Require().NoError(err)
+	s.Equal([]reform.Struct{&PersonProject{PersonID: 102, ProjectID: "baron"}}, structs)
+	_, err = q.FindByPrimaryKeyFrom(ProjectRoleTable, []interface{}{"baron", 102})
+	s.NoError(err)
+	_, err = q.FindByPrimaryKeyFrom(ProjectRoleTable, []interface{}{"queen", 102})
+	s.Equal(reform.ErrNoRows, err)
+
+	// views and tables without tenant column are not scoped
+	n, err = q.Count(PersonTable, "")
+	s.Require().NoError(err)
+	m, err := s.q.Count(PersonTable, "")
+	s.Require().NoError(err)
+	s.Equal(m, n)
+
+	// inserts fill and check tenant column
+	pp := &PersonProject{PersonID: 1}
+	s.Require().NoError(q.Insert(pp))
+	s.Equal("baron", pp.ProjectID)
+	s.Equal(reform.ErrTenantMismatch, q.Insert(&PersonProject{PersonID: 1, ProjectID: "queen"}))
+
+	// updates and deletes of other tenants' rows
+	role := &ProjectRole{ProjectID: "queen", PersonID: 102, Role: "lead"}
+	s.Require().NoError(s.q.Insert(role))
+	s.Equal(reform.ErrTenantMismatch, q.Update(role))
+	s.Equal(reform.ErrNoRows, q.Delete(role))
+	n, err = q.DeleteFrom(PersonProjectView, "WHERE person_id = "+s.q.Placeholder(1), 102)
+	s.Require().NoError(err)
+	s.Equal(uint(1), n)
+	n, err = s.q.Count(PersonProjectView, "WHERE person_id = "+s.q.Placeholder(1), 102)
+	s.Require().NoError(err)
+	s.Equal(uint(1), n)
+
+	// unknown tenant
+	tenant = nil
+	s.Equal(reform.ErrNoTenant, q.Insert(&PersonProject{PersonID: 101}))
+	n, err = q.Count(PersonProjectView, "")
+	s.Require().NoError(err)
+	s.Zero(n)
+}
@@ -0,0 +1,68 @@
+package reform
+
+import (
+	"context"
+	"time"
+)
+
+// TimeoutDialect is an optional interface for Dialect which limits query execution time on server side.
+// See Querier.WithTimeout and TX.SetStatementTimeout.
+type TimeoutDialect interface {
+	Dialect
+
+	// TimeoutQuery returns query with execution time limit d, or query as is if it can't be limited.
+	TimeoutQuery(query string, d time.Duration) string
+
+	// StatementTimeout returns statement which limits execution time of all following statements
+	// in the current transaction to d, or empty string if it is not supported.
+	StatementTimeout(d time.Duration) string
+}
+
+// WithTimeout returns a copy of querier which limits execution time of each query and command to d
+// (zero disables it), so a single slow query can't hold a connection forever.
+// Each query and command is executed with querier's context with deadline: when it expires,
+// the query is canceled, and Rows iteration stops with context.DeadlineExceeded error.
+//
+// If dialect implements TimeoutDialect, queries also get server-side limit where possible:
+// for example, MySQL SELECT queries get MAX_EXECUTION_TIME optimizer hint.
+// For PostgreSQL, use TX.SetStatementTimeout to set statement_timeout for transaction.
+func (q *Querier) WithTimeout(d time.Duration) *Querier {
+	nq := q.clone()
+	nq.timeout = d
+	return nq
+}
+
+// queryContext returns context for a single query or command with querier's timeout, if any.
+func (q *Querier) queryContext() (context.Context, context.CancelFunc) {
+	if q.timeout <= 0 {
+		return q.ctx, func() {}
+	}
+	return context.WithTimeout(q.ctx, q.timeout)
+}
+
+// timeoutQuery adds server-side execution time limit to query if querier's timeout is set and dialect supports it.
+func (q *Querier) timeoutQuery(query string) string {
+	if q.timeout <= 0 {
+		return query
+	}
+	if d, ok := q.Dialect.(TimeoutDialect); ok {
+		return d.TimeoutQuery(query, q.timeout)
+	}
+	return query
+}
+
+// SetStatementTimeout limits execution time of each following statement in the transaction to d
+// on server side, like "SET LOCAL statement_timeout" for PostgreSQL.
+// It does nothing if dialect doesn't support that, see TimeoutDialect.
+func (tx *TX) SetStatementTimeout(d time.Duration) error {
+	td, ok := tx.Dialect.(TimeoutDialect)
+	if !ok {
+		return nil
+	}
+	statement := td.StatementTimeout(d)
+	if statement == "" {
+		return nil
+	}
+	_, err := tx.Exec(statement)
+	return err
+}
@@ -0,0 +1,86 @@
+package reform
+
+import (
+	"time"
+)
+
+// TimestampPrecision is a precision of automatically set timestamp column.
+type TimestampPrecision int
+
+// Timestamp precisions. Default is seconds for compatibility with columns like MySQL's DATETIME;
+// use "ms" or "us" label in "reform:" tag for columns with higher precision.
+const (
+	SecondPrecision TimestampPrecision = iota
+	MillisecondPrecision
+	MicrosecondPrecision
+)
+
+// duration returns a duration to truncate timestamps to.
+func (p TimestampPrecision) duration() time.Duration {
+	switch p {
+	case SecondPrecision:
+		return time.Second
+	case MillisecondPrecision:
+		return time.Millisecond
+	case MicrosecondPrecision:
+		return time.Microsecond
+	default:
+		panic("reform: unhandled TimestampPrecision. Please report this bug.")
+	}
+}
+
+// AutoTimestamp describes automatically set timestamp column, see AutoTimestampsView.
+type AutoTimestamp struct {
+	Index     uint               // column index
+	OnUpdate  bool               // true for autoupdate column, false for autocreate
+	Precision TimestampPrecision // timestamp is truncated to it
+}
+
+// setAutoTimestamps sets automatically set timestamp fields of str if its view implements AutoTimestampsView:
+// autocreate fields with zero values (only for insert) and autoupdate fields.
+// Field should be time.Time or *time.Time.
+func setAutoTimestamps(str Struct, insert bool) {
+	v, ok := str.View().(AutoTimestampsView)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	pointers := str.Pointers()
+	for _, at := range v.AutoTimestamps() {
+		if !insert && !at.OnUpdate {
+			continue
+		}
+
+		t := now.Truncate(at.Precision.duration())
+		switch p := pointers[at.Index].(type) {
+		case *time.Time:
+			if at.OnUpdate || p.IsZero() {
+				*p = t
+			}
+		case **time.Time:
+			if at.OnUpdate || *p == nil {
+				*p = &t
+			}
+		default:
+			panic("reform: unhandled automatically set timestamp type. Please report this bug.")
+		}
+	}
+}
+
+// autoUpdateColumns returns names of autoupdate columns of table, if any.
+func autoUpdateColumns(table Table) []string {
+	v, ok := table.(AutoTimestampsView)
+	if !ok {
+		return nil
+	}
+
+	var res []string
+	columns := table.Columns()
+	for _, at := range v.AutoTimestamps() {
+		if at.OnUpdate {
+			res = append(res, columns[at.Index])
+		}
+	}
+	return res
+}
@@ -0,0 +1,68 @@
+package reform
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"regexp"
+	"syscall"
+)
+
+// defaultBusyPolicy is used to retry statements failed because database is busy
+// if retry policy is not set with WithTransientRetries.
+var defaultBusyPolicy = &RetryPolicy{Attempts: 5}
+
+// WithTransientRetries returns a copy of querier which executes idempotent statements again according
+// to given retry policy (nil restores default behavior) if they fail with transient error: driver.ErrBadConn,
+// network errors like connection reset, and errors detected by Dialect implementing TransientErrorDialect
+// (like server shutdown during failover). Delays between attempts are randomized, see RetryPolicy.Backoff;
+// each retry is logged if Logger implements RetryLogger.
+//
+// Unlike WithConnectionRetries, statement may be executed again after it reached the server,
+// so only SELECT queries are retried by default; use Idempotent for other statements which are safe to repeat.
+// Retries are not performed inside transactions: connection loss aborts the whole transaction
+// (see DB.InTransactionContext). Errors returned while reading rows are not retried.
+// Statements failed because database is busy (see BusyErrorDialect) are retried even if they are not idempotent;
+// without retry policy, only they are retried, up to 5 attempts. Policy with Attempts: 1 disables both kinds of retries.
+// Retries on connection-level errors (see WithConnectionRetries) use the same attempts counter,
+// so the total number of attempts never exceeds the largest limit.
+func (q *Querier) WithTransientRetries(policy *RetryPolicy) *Querier {
+	nq := q.clone()
+	nq.transientPolicy = policy
+	return nq
+}
+
+// Idempotent returns a copy of querier which marks all statements as safe to execute more than once,
+// so they are retried on transient errors, see WithTransientRetries:
+//
+//	_, err = q.WithTransientRetries(policy).Idempotent().Exec("UPDATE people SET name = $1 WHERE id = $2", name, id)
+func (q *Querier) Idempotent() *Querier {
+	nq := q.clone()
+	nq.idempotent = true
+	return nq
+}
+
+// selectRE matches SELECT queries, optionally with leading comments.
+var selectRE = regexp.MustCompile(`(?is)^\s*(/\*.*?\*/\s*)*SELECT\b`)
+
+// isIdempotent returns true if query is safe to execute more than once.
+func (q *Querier) isIdempotent(query string) bool {
+	return q.idempotent || selectRE.MatchString(query)
+}
+
+// isTransientError returns true if statement failed with err may succeed on a new connection.
+func (q *Querier) isTransientError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if td, ok := q.Dialect.(TransientErrorDialect); ok {
+		return td.IsTransientError(err)
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package reform
+
+import (
+	"errors"
+)
+
+// TruncateOption is an option for Querier.Truncate. Options may be combined, for example, Cascade|RestartIdentity.
+// They are supported only by some dialects (see TruncateDialect) and ignored by others.
+type TruncateOption int
+
+const (
+	// Cascade also truncates tables which reference truncated table with foreign keys: "CASCADE".
+	Cascade TruncateOption = 1 << iota
+
+	// RestartIdentity resets sequences owned by columns of truncated table: "RESTART IDENTITY".
+	RestartIdentity
+)
+
+// ErrTruncateTenant is returned by Truncate for querier with TenantScope,
+// because TRUNCATE removes rows of all tenants. Use DeleteFrom instead.
+var ErrTruncateTenant = errors.New("reform: Truncate can't be used with TenantScope, use DeleteFrom")
+
+// TruncateDialect is an optional interface for Dialect which supports TRUNCATE options
+// or doesn't support TRUNCATE statement at all. It is used by Querier.Truncate.
+type TruncateDialect interface {
+	Dialect
+
+	// TruncateQuery returns statement which removes all rows from given quoted (and qualified) table.
+	TruncateQuery(table string, options TruncateOption) string
+}
+
+// Truncate quickly removes all rows from given view's table with "TRUNCATE TABLE" statement
+// (or "DELETE FROM" for dialects without it, like SQLite3), primarily for test teardown and batch reload jobs:
+//
+//	err = q.Truncate(PersonTable, reform.Cascade, reform.RestartIdentity)
+//
+// Soft delete and scopes are not used, hooks are not called, and RecordCache is not invalidated.
+// Note that some databases (like MySQL) implicitly commit transaction on TRUNCATE,
+// and others fail if table is referenced by foreign keys without Cascade option.
+func (q *Querier) Truncate(view View, options ...TruncateOption) error {
+	if c, _, _ := q.tenantColumn(view); c != "" {
+		return ErrTruncateTenant
+	}
+
+	var opts TruncateOption
+	for _, o := range options {
+		opts |= o
+	}
+
+	table := q.QualifiedView(view)
+	query := "TRUNCATE TABLE " + table
+	if td, ok := q.Dialect.(TruncateDialect); ok {
+		query = td.TruncateQuery(table, opts)
+	}
+
+	_, err := q.execView(view.Name(), query, AllRows)
+	return err
+}
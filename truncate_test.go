@@ -0,0 +1,46 @@
+package reform_test
+
+import (
+	"context"
+
+	"github.com/AlekSi/reform"
+	"github.com/AlekSi/reform/dialects/mysql"
+	"github.com/AlekSi/reform/dialects/postgresql"
+	"github.com/AlekSi/reform/dialects/sqlite3"
+	. "github.com/AlekSi/reform/internal/test/models"
+	"github.com/AlekSi/reform/reformtest"
+)
+
+func (s *ReformSuite) TestTruncate() {
+	for dialect, expected := range map[reform.Dialect]string{
+		postgresql.Dialect: `TRUNCATE TABLE "people" RESTART IDENTITY CASCADE`,
+		mysql.Dialect:      "TRUNCATE TABLE `people`",
+		sqlite3.Dialect:    `DELETE FROM "people"`,
+	} {
+		f := reformtest.New(dialect)
+		s.Require().NoError(f.DB.WithRequireWhere().Truncate(PersonTable, reform.Cascade, reform.RestartIdentity))
+		statements := f.Statements()
+		s.Require().Len(statements, 1)
+		s.Equal(expected, statements[0].Query)
+		s.Empty(statements[0].Args)
+		s.NoError(f.Close())
+	}
+
+	q := s.q.WithTenantScope(&reform.TenantScope{
+		Column: "project_id",
+		Value:  func(context.Context) interface{} { return "baron" },
+	})
+	s.Equal(reform.ErrTruncateTenant, q.Truncate(PersonProjectView))
+
+	// real database
+	switch s.q.Dialect {
+	case postgresql.Dialect, sqlite3.Dialect:
+	default:
+		s.T().Skip("PostgreSQL- and SQLite3-specific test")
+	}
+	s.Require().NoError(s.q.Insert(&Memo{Text: "truncated"}))
+	s.Require().NoError(s.q.Truncate(MemoTable, reform.RestartIdentity))
+	n, err := s.q.Count(MemoTable, "")
+	s.Require().NoError(err)
+	s.Zero(n)
+}
@@ -2,13 +2,17 @@ package reform
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 )
 
 // TX represents a SQL database transaction.
 type TX struct {
 	*Querier
-	tx *sql.Tx
+	tx         *sql.Tx
+	savepoints int
+	onCommit   []func()
+	onRollback []func()
 }
 
 // NewTX creates new TX object for given SQL database transaction.
@@ -20,20 +24,131 @@ func NewTX(tx *sql.Tx, dialect Dialect, logger Logger) *TX {
 }
 
 // Commit commits the transaction.
+// Records changed in the transaction are removed from RecordCache after that, see Querier.WithRecordCache.
+// If commit fails, the transaction is ended anyway, and functions registered with OnRollback are called.
 func (tx *TX) Commit() error {
 	start := time.Now()
 	tx.logBefore("COMMIT", nil)
 	err := tx.tx.Commit()
-	tx.logAfter("COMMIT", nil, time.Now().Sub(start), err)
+	tx.logAfter("", "COMMIT", nil, start, nil, err)
+	if err != nil {
+		tx.end(false)
+		return err
+	}
+	err = tx.cacheCommitted()
+	tx.end(true)
 	return err
 }
 
 // Rollback aborts the transaction.
+// Functions registered with OnRollback are called even if it returns error (for example, sql.ErrTxDone
+// for transaction already rolled back due to context cancellation), as the transaction is ended anyway.
 func (tx *TX) Rollback() error {
 	start := time.Now()
 	tx.logBefore("ROLLBACK", nil)
 	err := tx.tx.Rollback()
-	tx.logAfter("ROLLBACK", nil, time.Now().Sub(start), err)
+	tx.logAfter("", "ROLLBACK", nil, start, nil, err)
+	tx.end(false)
+	return err
+}
+
+// end calls functions registered with OnCommit or OnRollback for ended transaction and removes all of them.
+func (tx *TX) end(committed bool) {
+	callbacks := tx.onRollback
+	if committed {
+		callbacks = tx.onCommit
+	}
+	tx.onCommit, tx.onRollback = nil, nil
+	runCallbacks(callbacks)
+}
+
+// OnCommit registers function which is called by Commit after the transaction is successfully committed,
+// so side effects like publishing events or invalidating caches happen only for committed changes.
+// Functions are called in registration order, even if removing records from RecordCache fails.
+// Functions registered in InSavepoint are discarded if savepoint is rolled back.
+func (tx *TX) OnCommit(f func()) {
+	tx.onCommit = append(tx.onCommit, f)
+}
+
+// OnRollback registers function which is called when the transaction is ended without commit:
+// by Rollback or failed Commit, for example, to remove files uploaded for rows which were not stored.
+// Functions are called in registration order.
+// Functions registered in InSavepoint are called if savepoint is rolled back.
+func (tx *TX) OnRollback(f func()) {
+	tx.onRollback = append(tx.onRollback, f)
+}
+
+// runCallbacks calls given functions in order.
+func runCallbacks(callbacks []func()) {
+	for _, f := range callbacks {
+		f()
+	}
+}
+
+// Savepoint creates a savepoint with given name within the transaction.
+func (tx *TX) Savepoint(name string) error {
+	query := "SAVEPOINT " + tx.QuoteIdentifier(name)
+	if sd, ok := tx.Dialect.(SavepointDialect); ok {
+		query = sd.SavepointQuery(tx.QuoteIdentifier(name))
+	}
+	_, err := tx.Exec(query)
+	return err
+}
+
+// RollbackToSavepoint rolls back all changes made after savepoint with given name was created.
+// The transaction and the savepoint itself remain active.
+func (tx *TX) RollbackToSavepoint(name string) error {
+	query := "ROLLBACK TO SAVEPOINT " + tx.QuoteIdentifier(name)
+	if sd, ok := tx.Dialect.(SavepointDialect); ok {
+		query = sd.RollbackToSavepointQuery(tx.QuoteIdentifier(name))
+	}
+	_, err := tx.Exec(query)
+	return err
+}
+
+// ReleaseSavepoint destroys savepoint with given name, keeping all changes made after it was created.
+// It does nothing for databases which don't support that (see SavepointDialect).
+func (tx *TX) ReleaseSavepoint(name string) error {
+	query := "RELEASE SAVEPOINT " + tx.QuoteIdentifier(name)
+	if sd, ok := tx.Dialect.(SavepointDialect); ok {
+		if query = sd.ReleaseSavepointQuery(tx.QuoteIdentifier(name)); query == "" {
+			return nil
+		}
+	}
+	_, err := tx.Exec(query)
+	return err
+}
+
+// InSavepoint wraps function execution in savepoint, rolling back to it in case of error or panic,
+// releasing it otherwise. Unlike InTransaction, it does not end the transaction:
+// changes made by f are committed or rolled back together with it.
+// It allows to compose code which uses transactions inside an outer transaction.
+func (tx *TX) InSavepoint(f func(t *TX) error) error {
+	tx.savepoints++
+	name := fmt.Sprintf("reform_savepoint_%d", tx.savepoints)
+	if err := tx.Savepoint(name); err != nil {
+		return err
+	}
+
+	onCommit, onRollback := len(tx.onCommit), len(tx.onRollback)
+	var released bool
+	defer func() {
+		if !released {
+			if tx.RollbackToSavepoint(name) == nil {
+				callbacks := tx.onRollback[onRollback:]
+				tx.onCommit, tx.onRollback = tx.onCommit[:onCommit], tx.onRollback[:onRollback]
+				runCallbacks(callbacks)
+			}
+		}
+	}()
+
+	err := f(tx)
+	if err == nil {
+		err = tx.ReleaseSavepoint(name)
+	}
+	if err == nil {
+		released = true
+	}
 	return err
 }
 
@@ -0,0 +1,56 @@
+package reform
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrNoWhere is returned by querier with WithRequireWhere for DELETE and UPDATE statements without WHERE clause.
+var ErrNoWhere = errors.New("reform: DELETE or UPDATE without WHERE clause, pass reform.AllRows to allow it")
+
+type allRows struct{}
+
+// AllRows is a marker argument for DeleteFrom and Exec which allows DELETE and UPDATE statements
+// without WHERE clause for querier with WithRequireWhere:
+//
+//	n, err := q.DeleteFrom(PersonTable, "", reform.AllRows)
+//
+// It is removed from arguments before query execution, so it may be passed to any querier.
+var AllRows = allRows{}
+
+// WithRequireWhere returns a copy of querier which rejects DELETE and UPDATE statements
+// without WHERE clause (for example, DeleteFrom with empty tail) with ErrNoWhere,
+// unless AllRows marker is passed as an argument.
+// That protects from deleting or updating the whole table by mistake.
+//
+// The check is best-effort: query text is only checked for WHERE keyword, so conditions are not analyzed,
+// and statements like "DELETE FROM people WHERE 1 = 1" or with WHERE only in a subquery are allowed.
+// It is not a security measure against untrusted query tails.
+func (q *Querier) WithRequireWhere() *Querier {
+	nq := q.clone()
+	nq.requireWhere = true
+	return nq
+}
+
+var (
+	deleteOrUpdateRE = regexp.MustCompile(`(?is)^\s*(/\*.*?\*/\s*)*(DELETE|UPDATE)\b`)
+	whereRE          = regexp.MustCompile(`(?i)\bWHERE\b`)
+)
+
+// checkWhere removes AllRows marker from args and returns ErrNoWhere
+// if querier requires WHERE clause and query doesn't contain WHERE keyword anywhere.
+func (q *Querier) checkWhere(query string, args []interface{}) ([]interface{}, error) {
+	var all bool
+	for i := 0; i < len(args); i++ {
+		if _, ok := args[i].(allRows); ok {
+			all = true
+			args = append(args[:i:i], args[i+1:]...)
+			i--
+		}
+	}
+
+	if q.requireWhere && !all && deleteOrUpdateRE.MatchString(query) && !whereRE.MatchString(query) {
+		return nil, ErrNoWhere
+	}
+	return args, nil
+}